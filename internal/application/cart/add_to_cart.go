@@ -0,0 +1,216 @@
+package cart
+
+import (
+	domainCart "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/cart"
+	domainProduct "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/product"
+	"github.com/google/uuid"
+)
+
+// AddToCartCommand represents the input for adding a product to a cart.
+// Guests are identified by SessionID, signed-in customers by CustomerID.
+type AddToCartCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	CustomerID string `json:"customer_id,omitempty" validate:"required_without=SessionID"`
+	SessionID  string `json:"session_id,omitempty" validate:"required_without=CustomerID"`
+	ProductID  string `json:"product_id" validate:"required"`
+	Quantity   int    `json:"quantity" validate:"required,min=1"`
+}
+
+// CartResponse represents a cart in responses
+type CartResponse struct {
+	ID             string             `json:"id"`
+	CustomerID     string             `json:"customer_id,omitempty"`
+	SessionID      string             `json:"session_id,omitempty"`
+	Status         string             `json:"status"`
+	Items          []CartItemResponse `json:"items"`
+	EstimatedTotal float64            `json:"estimated_total"`
+	Currency       string             `json:"currency,omitempty"`
+	ExpiresAt      string             `json:"expires_at"`
+	UpdatedAt      string             `json:"updated_at"`
+}
+
+// CartItemResponse represents a cart line in the response
+type CartItemResponse struct {
+	ProductID      string  `json:"product_id"`
+	ProductName    string  `json:"product_name"`
+	Quantity       int     `json:"quantity"`
+	ReferencePrice float64 `json:"reference_price"`
+	Currency       string  `json:"currency"`
+}
+
+// CartRepository defines the interface for cart persistence
+type CartRepository interface {
+	Save(cart *domainCart.Cart) error
+	FindByID(id uuid.UUID) (*domainCart.Cart, error)
+	FindActiveByCustomerID(customerID string) (*domainCart.Cart, error)
+	FindActiveBySessionID(sessionID string) (*domainCart.Cart, error)
+	Update(cart *domainCart.Cart) error
+}
+
+// ProductRepository defines the product persistence needed by carts
+type ProductRepository interface {
+	FindByID(id uuid.UUID) (*domainProduct.Product, error)
+	Update(product *domainProduct.Product) error
+}
+
+// AddToCartUseCase handles adding products to a cart
+type AddToCartUseCase struct {
+	cartRepo    CartRepository
+	productRepo ProductRepository
+}
+
+// NewAddToCartUseCase creates a new instance of AddToCartUseCase
+func NewAddToCartUseCase(cartRepo CartRepository, productRepo ProductRepository) *AddToCartUseCase {
+	return &AddToCartUseCase{
+		cartRepo:    cartRepo,
+		productRepo: productRepo,
+	}
+}
+
+// Execute adds the product to the shopper's cart, creating the cart if needed.
+// Stock is not reserved here; that happens when the cart becomes an order.
+func (uc *AddToCartUseCase) Execute(cmd AddToCartCommand) (*CartResponse, error) {
+	productID, err := uuid.Parse(cmd.ProductID)
+	if err != nil {
+		return nil, domainProduct.ErrProductNotFound
+	}
+
+	p, err := findProduct(uc.productRepo, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.Status.CanBeOrdered() {
+		return nil, domainProduct.ErrProductNotActive
+	}
+
+	existingCart, err := findActiveCart(uc.cartRepo, cmd.CustomerID, cmd.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	isNew := existingCart == nil
+	if isNew {
+		existingCart, err = newCart(cmd.CustomerID, cmd.SessionID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := existingCart.AddItem(p.ID, p.Name, cmd.Quantity, p.Price); err != nil {
+		return nil, err
+	}
+
+	// Save cart
+	if isNew {
+		err = uc.cartRepo.Save(existingCart)
+	} else {
+		err = uc.cartRepo.Update(existingCart)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toCartResponse(existingCart), nil
+}
+
+// newCart creates a customer cart, or a guest cart when no customer is signed in
+func newCart(customerID, sessionID string) (*domainCart.Cart, error) {
+	if customerID != "" {
+		return domainCart.NewCustomerCart(customerID)
+	}
+	return domainCart.NewGuestCart(sessionID)
+}
+
+// findActiveCart loads the shopper's active cart. Expired carts are closed
+// and reported as missing so a fresh cart is started.
+func findActiveCart(cartRepo CartRepository, customerID, sessionID string) (*domainCart.Cart, error) {
+	var existingCart *domainCart.Cart
+	var err error
+	switch {
+	case customerID != "":
+		existingCart, err = cartRepo.FindActiveByCustomerID(customerID)
+	case sessionID != "":
+		existingCart, err = cartRepo.FindActiveBySessionID(sessionID)
+	default:
+		return nil, domainCart.ErrEmptySessionID
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if existingCart == nil {
+		return nil, nil
+	}
+
+	if existingCart.IsExpired() {
+		existingCart.Expire()
+		if err := cartRepo.Update(existingCart); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	return existingCart, nil
+}
+
+// findExistingCart loads the shopper's active cart and fails if there is none
+func findExistingCart(cartRepo CartRepository, customerID, sessionID string) (*domainCart.Cart, error) {
+	existingCart, err := findActiveCart(cartRepo, customerID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingCart == nil {
+		return nil, domainCart.ErrCartNotFound
+	}
+
+	return existingCart, nil
+}
+
+// findProduct loads a product by ID
+func findProduct(productRepo ProductRepository, productID uuid.UUID) (*domainProduct.Product, error) {
+	p, err := productRepo.FindByID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if p == nil {
+		return nil, domainProduct.ErrProductNotFound
+	}
+
+	return p, nil
+}
+
+// toCartResponse converts a cart to its response
+func toCartResponse(c *domainCart.Cart) *CartResponse {
+	items := make([]CartItemResponse, len(c.Items))
+	for i, item := range c.Items {
+		items[i] = CartItemResponse{
+			ProductID:      item.ProductID.String(),
+			ProductName:    item.ProductName,
+			Quantity:       item.Quantity,
+			ReferencePrice: item.ReferencePrice.Amount,
+			Currency:       item.ReferencePrice.Currency,
+		}
+	}
+
+	response := &CartResponse{
+		ID:         c.ID.String(),
+		CustomerID: c.CustomerID,
+		SessionID:  c.SessionID,
+		Status:     string(c.Status),
+		Items:      items,
+		ExpiresAt:  c.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:  c.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+
+	// The estimate is informative only; mixed currencies simply leave it empty
+	if total, err := c.EstimatedTotal(); err == nil {
+		response.EstimatedTotal = total.Amount
+		response.Currency = total.Currency
+	}
+
+	return response
+}
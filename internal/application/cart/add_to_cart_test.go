@@ -0,0 +1,198 @@
+package cart
+
+import (
+	"testing"
+
+	domainCart "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/cart"
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainProduct "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/product"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCartRepository is a mock implementation of CartRepository
+type MockCartRepository struct {
+	mock.Mock
+}
+
+func (m *MockCartRepository) Save(cart *domainCart.Cart) error {
+	args := m.Called(cart)
+	return args.Error(0)
+}
+
+func (m *MockCartRepository) FindByID(id uuid.UUID) (*domainCart.Cart, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainCart.Cart), args.Error(1)
+}
+
+func (m *MockCartRepository) FindActiveByCustomerID(customerID string) (*domainCart.Cart, error) {
+	args := m.Called(customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainCart.Cart), args.Error(1)
+}
+
+func (m *MockCartRepository) FindActiveBySessionID(sessionID string) (*domainCart.Cart, error) {
+	args := m.Called(sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainCart.Cart), args.Error(1)
+}
+
+func (m *MockCartRepository) Update(cart *domainCart.Cart) error {
+	args := m.Called(cart)
+	return args.Error(0)
+}
+
+// MockProductRepository is a mock implementation of ProductRepository
+type MockProductRepository struct {
+	mock.Mock
+}
+
+func (m *MockProductRepository) FindByID(id uuid.UUID) (*domainProduct.Product, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainProduct.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) Update(product *domainProduct.Product) error {
+	args := m.Called(product)
+	return args.Error(0)
+}
+
+// Test helper functions
+
+// createTestProduct creates an active product with the given stock
+func createTestProduct(name string, amount float64, quantity int) *domainProduct.Product {
+	price, _ := domainOrder.NewMoney(amount, "USD")
+	category, _ := domainProduct.NewCategory("Electronics", "", nil)
+	inventory, _ := domainProduct.NewInventory(quantity, 0, 0)
+	p, _ := domainProduct.NewProduct(name, "Description", name+"-SKU", price, category, inventory)
+	_ = p.Activate()
+	return p
+}
+
+// Tests for AddToCartUseCase
+
+func TestAddToCartUseCase(t *testing.T) {
+	t.Run("guest add creates a session cart", func(t *testing.T) {
+		cartRepo := new(MockCartRepository)
+		productRepo := new(MockProductRepository)
+		useCase := NewAddToCartUseCase(cartRepo, productRepo)
+
+		p := createTestProduct("Headphones", 10.0, 5)
+
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		cartRepo.On("FindActiveBySessionID", "session-123").Return(nil, nil)
+		cartRepo.On("Save", mock.AnythingOfType("*cart.Cart")).Return(nil)
+
+		response, err := useCase.Execute(AddToCartCommand{SessionID: "session-123", ProductID: p.ID.String(), Quantity: 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "session-123", response.SessionID)
+		assert.Empty(t, response.CustomerID)
+		assert.Equal(t, 20.0, response.EstimatedTotal)
+		assert.Equal(t, 0, p.GetReservedQuantity())
+
+		cartRepo.AssertExpectations(t)
+	})
+
+	t.Run("customer add updates existing cart", func(t *testing.T) {
+		cartRepo := new(MockCartRepository)
+		productRepo := new(MockProductRepository)
+		useCase := NewAddToCartUseCase(cartRepo, productRepo)
+
+		p := createTestProduct("Headphones", 10.0, 5)
+		existingCart, _ := domainCart.NewCustomerCart("customer123")
+		_ = existingCart.AddItem(p.ID, p.Name, 1, p.Price)
+
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		cartRepo.On("FindActiveByCustomerID", "customer123").Return(existingCart, nil)
+		cartRepo.On("Update", existingCart).Return(nil)
+
+		response, err := useCase.Execute(AddToCartCommand{CustomerID: "customer123", ProductID: p.ID.String(), Quantity: 2})
+
+		assert.NoError(t, err)
+		assert.Len(t, response.Items, 1)
+		assert.Equal(t, 3, response.Items[0].Quantity)
+		cartRepo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("inactive product cannot be added", func(t *testing.T) {
+		cartRepo := new(MockCartRepository)
+		productRepo := new(MockProductRepository)
+		useCase := NewAddToCartUseCase(cartRepo, productRepo)
+
+		p := createTestProduct("Headphones", 10.0, 5)
+		_ = p.Deactivate()
+
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+
+		response, err := useCase.Execute(AddToCartCommand{SessionID: "session-123", ProductID: p.ID.String(), Quantity: 1})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainProduct.ErrProductNotActive, err)
+	})
+
+	t.Run("expired cart is replaced", func(t *testing.T) {
+		cartRepo := new(MockCartRepository)
+		productRepo := new(MockProductRepository)
+		useCase := NewAddToCartUseCase(cartRepo, productRepo)
+
+		p := createTestProduct("Headphones", 10.0, 5)
+		expiredCart, _ := domainCart.NewGuestCart("session-123")
+		expiredCart.ExpiresAt = expiredCart.CreatedAt.AddDate(0, 0, -1)
+
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		cartRepo.On("FindActiveBySessionID", "session-123").Return(expiredCart, nil)
+		cartRepo.On("Update", expiredCart).Return(nil)
+		cartRepo.On("Save", mock.AnythingOfType("*cart.Cart")).Return(nil)
+
+		response, err := useCase.Execute(AddToCartCommand{SessionID: "session-123", ProductID: p.ID.String(), Quantity: 1})
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, expiredCart.ID.String(), response.ID)
+		assert.Equal(t, domainCart.StatusExpired, expiredCart.Status)
+	})
+}
+
+// Tests for UpdateCartItemUseCase
+
+func TestUpdateCartItemUseCase(t *testing.T) {
+	t.Run("zero quantity removes the line", func(t *testing.T) {
+		cartRepo := new(MockCartRepository)
+		useCase := NewUpdateCartItemUseCase(cartRepo)
+
+		p := createTestProduct("Headphones", 10.0, 5)
+		existingCart, _ := domainCart.NewGuestCart("session-123")
+		_ = existingCart.AddItem(p.ID, p.Name, 2, p.Price)
+
+		cartRepo.On("FindActiveBySessionID", "session-123").Return(existingCart, nil)
+		cartRepo.On("Update", existingCart).Return(nil)
+
+		response, err := useCase.Execute(UpdateCartItemCommand{SessionID: "session-123", ProductID: p.ID.String(), Quantity: 0})
+
+		assert.NoError(t, err)
+		assert.Empty(t, response.Items)
+	})
+
+	t.Run("no cart", func(t *testing.T) {
+		cartRepo := new(MockCartRepository)
+		useCase := NewUpdateCartItemUseCase(cartRepo)
+
+		cartRepo.On("FindActiveBySessionID", "session-123").Return(nil, nil)
+
+		response, err := useCase.Execute(UpdateCartItemCommand{SessionID: "session-123", ProductID: uuid.New().String(), Quantity: 1})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainCart.ErrCartNotFound, err)
+	})
+}
@@ -0,0 +1,65 @@
+package cart
+
+import (
+	"errors"
+
+	"github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/application/idempotency"
+	domainCart "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/cart"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// CartService provides high-level cart operations
+type CartService struct {
+	cartRepo    CartRepository
+	idempotency *idempotency.Guard // nil disables idempotency keys
+
+	// Use cases
+	addToCart          *AddToCartUseCase
+	updateCartItem     *UpdateCartItemUseCase
+	getCart            *GetCartUseCase
+	mergeGuestCart     *MergeGuestCartUseCase
+	convertCartToOrder *ConvertCartToOrderUseCase
+}
+
+// NewCartService creates a new instance of CartService
+func NewCartService(cartRepo CartRepository, productRepo ProductRepository, orderRepo OrderRepository, pricer ProductPricer, environment domainPayment.Environment, idempotencyGuard *idempotency.Guard) *CartService {
+	return &CartService{
+		cartRepo:           cartRepo,
+		idempotency:        idempotencyGuard,
+		addToCart:          NewAddToCartUseCase(cartRepo, productRepo),
+		updateCartItem:     NewUpdateCartItemUseCase(cartRepo),
+		getCart:            NewGetCartUseCase(cartRepo),
+		mergeGuestCart:     NewMergeGuestCartUseCase(cartRepo),
+		convertCartToOrder: NewConvertCartToOrderUseCase(cartRepo, productRepo, orderRepo, pricer, environment),
+	}
+}
+
+// AddToCart adds a product to the shopper's cart
+func (s *CartService) AddToCart(cmd AddToCartCommand) (*CartResponse, error) {
+	return idempotency.Run(s.idempotency, "cart.add_to_cart", cmd.IdempotencyKey, cmd, s.addToCart.Execute)
+}
+
+// UpdateCartItem changes the quantity of a cart line (zero removes it)
+func (s *CartService) UpdateCartItem(cmd UpdateCartItemCommand) (*CartResponse, error) {
+	return idempotency.Run(s.idempotency, "cart.update_cart_item", cmd.IdempotencyKey, cmd, s.updateCartItem.Execute)
+}
+
+// GetCart retrieves the shopper's active cart
+func (s *CartService) GetCart(query GetCartQuery) (*CartResponse, error) {
+	return s.getCart.Execute(query)
+}
+
+// ConvertToOrder checks out the customer's cart into an order
+func (s *CartService) ConvertToOrder(cmd ConvertCartToOrderCommand) (*ConvertCartToOrderResponse, error) {
+	return idempotency.Run(s.idempotency, "cart.convert_to_order", cmd.IdempotencyKey, cmd, s.convertCartToOrder.Execute)
+}
+
+// MergeGuestCart folds the session's guest cart into the customer's cart.
+// Having no guest cart is not an error; there is simply nothing to merge.
+func (s *CartService) MergeGuestCart(sessionID, customerID string) error {
+	_, err := s.mergeGuestCart.Execute(MergeGuestCartCommand{SessionID: sessionID, CustomerID: customerID})
+	if errors.Is(err, domainCart.ErrCartNotFound) {
+		return nil
+	}
+	return err
+}
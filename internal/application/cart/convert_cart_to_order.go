@@ -0,0 +1,176 @@
+package cart
+
+import (
+	domainCart "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/cart"
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	domainProduct "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/product"
+)
+
+// ConvertCartToOrderCommand represents the input for checking out a customer's cart
+type ConvertCartToOrderCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	CustomerID string `json:"customer_id" validate:"required"`
+	Currency   string `json:"currency,omitempty"` // Display currency the order is locked to; defaults to the cart's currency
+}
+
+// ConvertCartToOrderResponse represents the order created from a cart
+type ConvertCartToOrderResponse struct {
+	CartID         string  `json:"cart_id"`
+	OrderID        string  `json:"order_id"`
+	OrderStatus    string  `json:"order_status"`
+	TotalAmount    float64 `json:"total_amount"`
+	Currency       string  `json:"currency"`
+	EstimatedTotal float64 `json:"estimated_total"`
+	PriceChanged   bool    `json:"price_changed"` // Order total differs from the cart estimate
+	Sandbox        bool    `json:"sandbox,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+}
+
+// OrderRepository defines the order persistence needed by carts
+type OrderRepository interface {
+	Save(order *domainOrder.Order) error
+}
+
+// ProductPricer prices products in the order's display currency
+type ProductPricer interface {
+	RatesFor(products []*domainProduct.Product, currency string) (map[string]domainOrder.ExchangeRate, error)
+	OrderItemFor(p *domainProduct.Product, quantity int, currency string, rates map[string]domainOrder.ExchangeRate) (domainOrder.OrderItem, *domainOrder.ExchangeRate, error)
+}
+
+// ConvertCartToOrderUseCase turns a customer's cart into an order. In the
+// sandbox environment every order is a test order, paid in testnet coins.
+type ConvertCartToOrderUseCase struct {
+	cartRepo    CartRepository
+	productRepo ProductRepository
+	orderRepo   OrderRepository
+	pricer      ProductPricer
+	environment domainPayment.Environment
+}
+
+// NewConvertCartToOrderUseCase creates a new instance of ConvertCartToOrderUseCase
+func NewConvertCartToOrderUseCase(cartRepo CartRepository, productRepo ProductRepository, orderRepo OrderRepository, pricer ProductPricer, environment domainPayment.Environment) *ConvertCartToOrderUseCase {
+	return &ConvertCartToOrderUseCase{
+		cartRepo:    cartRepo,
+		productRepo: productRepo,
+		orderRepo:   orderRepo,
+		pricer:      pricer,
+		environment: environment,
+	}
+}
+
+// Execute validates every cart line against the current product, creates the
+// order at current prices in the display currency and reserves the stock it needs
+func (uc *ConvertCartToOrderUseCase) Execute(cmd ConvertCartToOrderCommand) (*ConvertCartToOrderResponse, error) {
+	existingCart, err := findExistingCart(uc.cartRepo, cmd.CustomerID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if existingCart.IsEmpty() {
+		return nil, domainCart.ErrEmptyCart
+	}
+
+	estimatedTotal, _ := existingCart.EstimatedTotal()
+
+	currency := cmd.Currency
+	if currency == "" {
+		currency = existingCart.Items[0].ReferencePrice.Currency
+	}
+
+	// Validate every line before touching stock
+	products := make([]*domainProduct.Product, 0, len(existingCart.Items))
+	for _, line := range existingCart.Items {
+		p, err := findProduct(uc.productRepo, line.ProductID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.Status.CanBeOrdered() {
+			return nil, domainProduct.ErrProductNotActive
+		}
+
+		if !p.IsAvailableForOrder(line.Quantity) {
+			return nil, domainProduct.ErrInsufficientStock
+		}
+
+		products = append(products, p)
+	}
+
+	// One rate per source currency prices every line converted from it
+	rates, err := uc.pricer.RatesFor(products, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]domainOrder.OrderItem, 0, len(products))
+	var usedRates []domainOrder.ExchangeRate
+	for i, p := range products {
+		item, rate, err := uc.pricer.OrderItemFor(p, existingCart.Items[i].Quantity, currency, rates)
+		if err != nil {
+			return nil, err
+		}
+		if rate != nil {
+			usedRates = append(usedRates, *rate)
+		}
+
+		items = append(items, item)
+	}
+
+	newOrder, err := domainOrder.NewOrder(cmd.CustomerID, items)
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.environment.IsSandbox() {
+		if err := newOrder.MarkAsSandbox(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Keep the conversion rates the order was priced with
+	for _, rate := range usedRates {
+		if err := newOrder.RecordExchangeRate(rate); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, p := range products {
+		if err := p.ReserveStock(items[i].Quantity); err != nil {
+			return nil, err
+		}
+	}
+	newOrder.MarkStockReserved()
+
+	if err := existingCart.MarkAsConverted(newOrder.ID); err != nil {
+		return nil, err
+	}
+
+	// Save updated aggregates
+	for _, p := range products {
+		if err := uc.productRepo.Update(p); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.orderRepo.Save(newOrder); err != nil {
+		return nil, err
+	}
+
+	if err := uc.cartRepo.Update(existingCart); err != nil {
+		return nil, err
+	}
+
+	return &ConvertCartToOrderResponse{
+		CartID:         existingCart.ID.String(),
+		OrderID:        newOrder.ID.String(),
+		OrderStatus:    string(newOrder.Status),
+		TotalAmount:    newOrder.TotalAmount.Amount,
+		Currency:       newOrder.TotalAmount.Currency,
+		EstimatedTotal: estimatedTotal.Amount,
+		PriceChanged:   estimatedTotal != newOrder.TotalAmount,
+		Sandbox:        newOrder.Sandbox,
+		CreatedAt:      newOrder.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}, nil
+}
@@ -0,0 +1,221 @@
+package cart
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/application/pricing"
+	domainCart "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/cart"
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	domainProduct "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOrderRepository is a mock implementation of OrderRepository
+type MockOrderRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderRepository) Save(order *domainOrder.Order) error {
+	args := m.Called(order)
+	return args.Error(0)
+}
+
+// fixedRateProvider always returns the same exchange rate
+type fixedRateProvider struct {
+	rate domainOrder.ExchangeRate
+}
+
+func (f fixedRateProvider) GetRate(from, to string) (domainOrder.ExchangeRate, error) {
+	return f.rate, nil
+}
+
+// changingRateProvider returns a new rate on every call, like a cache refreshing between calls
+type changingRateProvider struct {
+	calls int
+}
+
+func (c *changingRateProvider) GetRate(from, to string) (domainOrder.ExchangeRate, error) {
+	c.calls++
+	return domainOrder.NewExchangeRate(from, to, 0.9+float64(c.calls)/100, "test", time.Now())
+}
+
+// Tests for ConvertCartToOrderUseCase
+
+func TestConvertCartToOrderUseCase(t *testing.T) {
+	t.Run("convert creates order and reserves stock", func(t *testing.T) {
+		cartRepo := new(MockCartRepository)
+		productRepo := new(MockProductRepository)
+		orderRepo := new(MockOrderRepository)
+		useCase := NewConvertCartToOrderUseCase(cartRepo, productRepo, orderRepo, pricing.NewPricingService(nil), domainPayment.EnvironmentLive)
+
+		p := createTestProduct("Headphones", 10.0, 5)
+		existingCart, _ := domainCart.NewCustomerCart("customer123")
+		_ = existingCart.AddItem(p.ID, p.Name, 2, p.Price)
+
+		cartRepo.On("FindActiveByCustomerID", "customer123").Return(existingCart, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		productRepo.On("Update", p).Return(nil)
+		orderRepo.On("Save", mock.MatchedBy(func(o *domainOrder.Order) bool { return o.StockReserved })).Return(nil)
+		cartRepo.On("Update", existingCart).Return(nil)
+
+		response, err := useCase.Execute(ConvertCartToOrderCommand{CustomerID: "customer123"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, string(domainOrder.StatusCreated), response.OrderStatus)
+		assert.Equal(t, 20.0, response.TotalAmount)
+		assert.False(t, response.PriceChanged)
+		assert.Equal(t, 2, p.GetReservedQuantity())
+		assert.Equal(t, domainCart.StatusConverted, existingCart.Status)
+		assert.Equal(t, response.OrderID, existingCart.ConvertedOrderID.String())
+
+		cartRepo.AssertExpectations(t)
+		productRepo.AssertExpectations(t)
+		orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("sandbox checkout creates a test order", func(t *testing.T) {
+		cartRepo := new(MockCartRepository)
+		productRepo := new(MockProductRepository)
+		orderRepo := new(MockOrderRepository)
+		useCase := NewConvertCartToOrderUseCase(cartRepo, productRepo, orderRepo, pricing.NewPricingService(nil), domainPayment.EnvironmentSandbox)
+
+		p := createTestProduct("Headphones", 10.0, 5)
+		existingCart, _ := domainCart.NewCustomerCart("customer123")
+		_ = existingCart.AddItem(p.ID, p.Name, 1, p.Price)
+
+		cartRepo.On("FindActiveByCustomerID", "customer123").Return(existingCart, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		productRepo.On("Update", p).Return(nil)
+		orderRepo.On("Save", mock.MatchedBy(func(o *domainOrder.Order) bool { return o.Sandbox })).Return(nil)
+		cartRepo.On("Update", existingCart).Return(nil)
+
+		response, err := useCase.Execute(ConvertCartToOrderCommand{CustomerID: "customer123"})
+
+		assert.NoError(t, err)
+		assert.True(t, response.Sandbox)
+		orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("order uses current price, not the reference price", func(t *testing.T) {
+		cartRepo := new(MockCartRepository)
+		productRepo := new(MockProductRepository)
+		orderRepo := new(MockOrderRepository)
+		useCase := NewConvertCartToOrderUseCase(cartRepo, productRepo, orderRepo, pricing.NewPricingService(nil), domainPayment.EnvironmentLive)
+
+		p := createTestProduct("Headphones", 10.0, 5)
+		existingCart, _ := domainCart.NewCustomerCart("customer123")
+		_ = existingCart.AddItem(p.ID, p.Name, 2, p.Price)
+		newPrice, _ := domainOrder.NewMoney(12.0, "USD")
+		_ = p.UpdatePrice(newPrice)
+
+		cartRepo.On("FindActiveByCustomerID", "customer123").Return(existingCart, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		productRepo.On("Update", p).Return(nil)
+		orderRepo.On("Save", mock.AnythingOfType("*order.Order")).Return(nil)
+		cartRepo.On("Update", existingCart).Return(nil)
+
+		response, err := useCase.Execute(ConvertCartToOrderCommand{CustomerID: "customer123"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 24.0, response.TotalAmount)
+		assert.Equal(t, 20.0, response.EstimatedTotal)
+		assert.True(t, response.PriceChanged)
+	})
+
+	t.Run("convert in another currency records the rate", func(t *testing.T) {
+		cartRepo := new(MockCartRepository)
+		productRepo := new(MockProductRepository)
+		orderRepo := new(MockOrderRepository)
+		rate, _ := domainOrder.NewExchangeRate("USD", "EUR", 0.9, "test", time.Now())
+		useCase := NewConvertCartToOrderUseCase(cartRepo, productRepo, orderRepo, pricing.NewPricingService(fixedRateProvider{rate}), domainPayment.EnvironmentLive)
+
+		p := createTestProduct("Headphones", 10.0, 5)
+		existingCart, _ := domainCart.NewCustomerCart("customer123")
+		_ = existingCart.AddItem(p.ID, p.Name, 2, p.Price)
+
+		var savedOrder *domainOrder.Order
+		cartRepo.On("FindActiveByCustomerID", "customer123").Return(existingCart, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		productRepo.On("Update", p).Return(nil)
+		orderRepo.On("Save", mock.AnythingOfType("*order.Order")).Run(func(args mock.Arguments) {
+			savedOrder = args.Get(0).(*domainOrder.Order)
+		}).Return(nil)
+		cartRepo.On("Update", existingCart).Return(nil)
+
+		response, err := useCase.Execute(ConvertCartToOrderCommand{CustomerID: "customer123", Currency: "EUR"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "EUR", response.Currency)
+		assert.Equal(t, 18.0, response.TotalAmount)
+		assert.Equal(t, []domainOrder.ExchangeRate{rate}, savedOrder.ExchangeRates)
+	})
+
+	t.Run("lines from the same currency share one rate", func(t *testing.T) {
+		cartRepo := new(MockCartRepository)
+		productRepo := new(MockProductRepository)
+		orderRepo := new(MockOrderRepository)
+		rates := &changingRateProvider{}
+		useCase := NewConvertCartToOrderUseCase(cartRepo, productRepo, orderRepo, pricing.NewPricingService(rates), domainPayment.EnvironmentLive)
+
+		headphones := createTestProduct("Headphones", 10.0, 5)
+		cable := createTestProduct("Cable", 5.0, 5)
+		existingCart, _ := domainCart.NewCustomerCart("customer123")
+		_ = existingCart.AddItem(headphones.ID, headphones.Name, 1, headphones.Price)
+		_ = existingCart.AddItem(cable.ID, cable.Name, 2, cable.Price)
+
+		var savedOrder *domainOrder.Order
+		cartRepo.On("FindActiveByCustomerID", "customer123").Return(existingCart, nil)
+		productRepo.On("FindByID", headphones.ID).Return(headphones, nil)
+		productRepo.On("FindByID", cable.ID).Return(cable, nil)
+		productRepo.On("Update", mock.Anything).Return(nil)
+		orderRepo.On("Save", mock.AnythingOfType("*order.Order")).Run(func(args mock.Arguments) {
+			savedOrder = args.Get(0).(*domainOrder.Order)
+		}).Return(nil)
+		cartRepo.On("Update", existingCart).Return(nil)
+
+		response, err := useCase.Execute(ConvertCartToOrderCommand{CustomerID: "customer123", Currency: "EUR"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, rates.calls)
+		assert.InDelta(t, 18.2, response.TotalAmount, 1e-9)
+		assert.Len(t, savedOrder.ExchangeRates, 1)
+	})
+
+	t.Run("insufficient stock leaves cart untouched", func(t *testing.T) {
+		cartRepo := new(MockCartRepository)
+		productRepo := new(MockProductRepository)
+		orderRepo := new(MockOrderRepository)
+		useCase := NewConvertCartToOrderUseCase(cartRepo, productRepo, orderRepo, pricing.NewPricingService(nil), domainPayment.EnvironmentLive)
+
+		p := createTestProduct("Headphones", 10.0, 1)
+		existingCart, _ := domainCart.NewCustomerCart("customer123")
+		_ = existingCart.AddItem(p.ID, p.Name, 2, p.Price)
+
+		cartRepo.On("FindActiveByCustomerID", "customer123").Return(existingCart, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+
+		response, err := useCase.Execute(ConvertCartToOrderCommand{CustomerID: "customer123"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainProduct.ErrInsufficientStock, err)
+		assert.Equal(t, domainCart.StatusActive, existingCart.Status)
+		orderRepo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("empty cart", func(t *testing.T) {
+		cartRepo := new(MockCartRepository)
+		useCase := NewConvertCartToOrderUseCase(cartRepo, new(MockProductRepository), new(MockOrderRepository), pricing.NewPricingService(nil), domainPayment.EnvironmentLive)
+
+		existingCart, _ := domainCart.NewCustomerCart("customer123")
+
+		cartRepo.On("FindActiveByCustomerID", "customer123").Return(existingCart, nil)
+
+		response, err := useCase.Execute(ConvertCartToOrderCommand{CustomerID: "customer123"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainCart.ErrEmptyCart, err)
+	})
+}
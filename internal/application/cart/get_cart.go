@@ -0,0 +1,29 @@
+package cart
+
+// GetCartQuery represents the input for retrieving the shopper's cart
+type GetCartQuery struct {
+	CustomerID string `json:"customer_id,omitempty" validate:"required_without=SessionID"`
+	SessionID  string `json:"session_id,omitempty" validate:"required_without=CustomerID"`
+}
+
+// GetCartUseCase handles retrieving the active cart
+type GetCartUseCase struct {
+	cartRepo CartRepository
+}
+
+// NewGetCartUseCase creates a new instance of GetCartUseCase
+func NewGetCartUseCase(cartRepo CartRepository) *GetCartUseCase {
+	return &GetCartUseCase{
+		cartRepo: cartRepo,
+	}
+}
+
+// Execute returns the shopper's active cart
+func (uc *GetCartUseCase) Execute(query GetCartQuery) (*CartResponse, error) {
+	existingCart, err := findExistingCart(uc.cartRepo, query.CustomerID, query.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toCartResponse(existingCart), nil
+}
@@ -0,0 +1,61 @@
+package cart
+
+// MergeGuestCartCommand represents the input for merging a guest cart after sign-in
+type MergeGuestCartCommand struct {
+	SessionID  string `json:"session_id" validate:"required"`
+	CustomerID string `json:"customer_id" validate:"required"`
+}
+
+// MergeGuestCartUseCase folds an anonymous session cart into the customer's cart
+type MergeGuestCartUseCase struct {
+	cartRepo CartRepository
+}
+
+// NewMergeGuestCartUseCase creates a new instance of MergeGuestCartUseCase
+func NewMergeGuestCartUseCase(cartRepo CartRepository) *MergeGuestCartUseCase {
+	return &MergeGuestCartUseCase{
+		cartRepo: cartRepo,
+	}
+}
+
+// Execute merges the session's guest cart into the customer's cart, combining
+// lines for the same product. If the customer has no cart yet, the guest cart
+// is handed over to them instead.
+func (uc *MergeGuestCartUseCase) Execute(cmd MergeGuestCartCommand) (*CartResponse, error) {
+	guestCart, err := findExistingCart(uc.cartRepo, "", cmd.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	customerCart, err := findActiveCart(uc.cartRepo, cmd.CustomerID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if customerCart == nil {
+		if err := guestCart.AssignToCustomer(cmd.CustomerID); err != nil {
+			return nil, err
+		}
+
+		if err := uc.cartRepo.Update(guestCart); err != nil {
+			return nil, err
+		}
+
+		return toCartResponse(guestCart), nil
+	}
+
+	if err := customerCart.Merge(guestCart); err != nil {
+		return nil, err
+	}
+
+	// Save both carts
+	if err := uc.cartRepo.Update(guestCart); err != nil {
+		return nil, err
+	}
+
+	if err := uc.cartRepo.Update(customerCart); err != nil {
+		return nil, err
+	}
+
+	return toCartResponse(customerCart), nil
+}
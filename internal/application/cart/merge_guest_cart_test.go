@@ -0,0 +1,71 @@
+package cart
+
+import (
+	"testing"
+
+	"github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/application/pricing"
+	domainCart "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/cart"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests for MergeGuestCartUseCase
+
+func TestMergeGuestCartUseCase(t *testing.T) {
+	t.Run("merge combines lines with the same product", func(t *testing.T) {
+		cartRepo := new(MockCartRepository)
+		useCase := NewMergeGuestCartUseCase(cartRepo)
+
+		headphones := createTestProduct("Headphones", 10.0, 5)
+		speaker := createTestProduct("Speaker", 25.0, 5)
+		guestCart, _ := domainCart.NewGuestCart("session-123")
+		_ = guestCart.AddItem(headphones.ID, headphones.Name, 1, headphones.Price)
+		_ = guestCart.AddItem(speaker.ID, speaker.Name, 1, speaker.Price)
+		customerCart, _ := domainCart.NewCustomerCart("customer123")
+		_ = customerCart.AddItem(headphones.ID, headphones.Name, 2, headphones.Price)
+
+		cartRepo.On("FindActiveBySessionID", "session-123").Return(guestCart, nil)
+		cartRepo.On("FindActiveByCustomerID", "customer123").Return(customerCart, nil)
+		cartRepo.On("Update", guestCart).Return(nil)
+		cartRepo.On("Update", customerCart).Return(nil)
+
+		response, err := useCase.Execute(MergeGuestCartCommand{SessionID: "session-123", CustomerID: "customer123"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, customerCart.ID.String(), response.ID)
+		assert.Len(t, response.Items, 2)
+		assert.Equal(t, 3, response.Items[0].Quantity)
+		assert.Equal(t, domainCart.StatusMerged, guestCart.Status)
+
+		cartRepo.AssertExpectations(t)
+	})
+
+	t.Run("guest cart is handed over when customer has none", func(t *testing.T) {
+		cartRepo := new(MockCartRepository)
+		useCase := NewMergeGuestCartUseCase(cartRepo)
+
+		guestCart, _ := domainCart.NewGuestCart("session-123")
+
+		cartRepo.On("FindActiveBySessionID", "session-123").Return(guestCart, nil)
+		cartRepo.On("FindActiveByCustomerID", "customer123").Return(nil, nil)
+		cartRepo.On("Update", guestCart).Return(nil)
+
+		response, err := useCase.Execute(MergeGuestCartCommand{SessionID: "session-123", CustomerID: "customer123"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, guestCart.ID.String(), response.ID)
+		assert.Equal(t, "customer123", response.CustomerID)
+		assert.Equal(t, domainCart.StatusActive, guestCart.Status)
+	})
+
+	t.Run("service ignores missing guest cart", func(t *testing.T) {
+		cartRepo := new(MockCartRepository)
+		service := NewCartService(cartRepo, new(MockProductRepository), new(MockOrderRepository), pricing.NewPricingService(nil), domainPayment.EnvironmentLive, nil)
+
+		cartRepo.On("FindActiveBySessionID", "session-123").Return(nil, nil)
+
+		err := service.MergeGuestCart("session-123", "customer123")
+
+		assert.NoError(t, err)
+	})
+}
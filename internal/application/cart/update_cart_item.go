@@ -0,0 +1,52 @@
+package cart
+
+import (
+	domainCart "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/cart"
+	"github.com/google/uuid"
+)
+
+// UpdateCartItemCommand represents the input for changing the quantity of a cart line
+type UpdateCartItemCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	CustomerID string `json:"customer_id,omitempty" validate:"required_without=SessionID"`
+	SessionID  string `json:"session_id,omitempty" validate:"required_without=CustomerID"`
+	ProductID  string `json:"product_id" validate:"required"`
+	Quantity   int    `json:"quantity" validate:"min=0"`
+}
+
+// UpdateCartItemUseCase handles cart line quantity changes
+type UpdateCartItemUseCase struct {
+	cartRepo CartRepository
+}
+
+// NewUpdateCartItemUseCase creates a new instance of UpdateCartItemUseCase
+func NewUpdateCartItemUseCase(cartRepo CartRepository) *UpdateCartItemUseCase {
+	return &UpdateCartItemUseCase{
+		cartRepo: cartRepo,
+	}
+}
+
+// Execute sets the line quantity. A quantity of zero removes the line.
+func (uc *UpdateCartItemUseCase) Execute(cmd UpdateCartItemCommand) (*CartResponse, error) {
+	existingCart, err := findExistingCart(uc.cartRepo, cmd.CustomerID, cmd.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	productID, err := uuid.Parse(cmd.ProductID)
+	if err != nil {
+		return nil, domainCart.ErrItemNotFound
+	}
+
+	if err := existingCart.UpdateItemQuantity(productID, cmd.Quantity); err != nil {
+		return nil, err
+	}
+
+	// Save updated cart
+	if err := uc.cartRepo.Update(existingCart); err != nil {
+		return nil, err
+	}
+
+	return toCartResponse(existingCart), nil
+}
@@ -6,6 +6,8 @@ import (
 
 // AddShippingAddressCommand represents the input for adding a shipping address
 type AddShippingAddressCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
 	CustomerID   string `json:"customer_id" validate:"required"`
 	Label        string `json:"label" validate:"required"`
 	FirstName    string `json:"first_name" validate:"required"`
@@ -1,10 +1,20 @@
 package customer
 
-import domainCustomer "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/customer"
+import (
+	"github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/application/idempotency"
+	domainCustomer "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/customer"
+)
+
+// GuestCartMerger merges an anonymous session cart into a customer's cart
+type GuestCartMerger interface {
+	MergeGuestCart(sessionID, customerID string) error
+}
 
 // CustomerService provides high-level customer operations
 type CustomerService struct {
 	customerRepo CustomerRepository
+	cartMerger   GuestCartMerger    // nil leaves guest carts with their session
+	idempotency  *idempotency.Guard // nil disables idempotency keys
 	
 	// Use cases
 	registerCustomer           *RegisterCustomerUseCase
@@ -17,9 +27,10 @@ type CustomerService struct {
 }
 
 // NewCustomerService creates a new instance of CustomerService
-func NewCustomerService(customerRepo CustomerRepository) *CustomerService {
+func NewCustomerService(customerRepo CustomerRepository, idempotencyGuard *idempotency.Guard) *CustomerService {
 	return &CustomerService{
 		customerRepo:              customerRepo,
+		idempotency:               idempotencyGuard,
 		registerCustomer:          NewRegisterCustomerUseCase(customerRepo),
 		getCustomer:              NewGetCustomerUseCase(customerRepo),
 		updateCustomer:           NewUpdateCustomerUseCase(customerRepo),
@@ -30,9 +41,52 @@ func NewCustomerService(customerRepo CustomerRepository) *CustomerService {
 	}
 }
 
-// RegisterCustomer registers a new customer
+// SetCartMerger sets where guest carts are merged into on registration and login
+func (s *CustomerService) SetCartMerger(cartMerger GuestCartMerger) {
+	s.cartMerger = cartMerger
+}
+
+// RegisterCustomer registers a new customer and takes over their guest cart, if any
 func (s *CustomerService) RegisterCustomer(cmd RegisterCustomerCommand) (*RegisterCustomerResponse, error) {
-	return s.registerCustomer.Execute(cmd)
+	return idempotency.Run(s.idempotency, "customer.register_customer", cmd.IdempotencyKey, cmd, s.registerAndMergeCart)
+}
+
+// registerAndMergeCart registers the customer, then merges the session's guest
+// cart. The customer is saved by then, so a failed merge does not fail the
+// registration: the cart stays with the session and is merged on the next login,
+// and the error is returned as the response's cart merge warning.
+func (s *CustomerService) registerAndMergeCart(cmd RegisterCustomerCommand) (*RegisterCustomerResponse, error) {
+	response, err := s.registerCustomer.Execute(cmd)
+	if err != nil {
+		return nil, err
+	}
+	
+	if cmd.SessionID != "" && s.cartMerger != nil {
+		if err := s.cartMerger.MergeGuestCart(cmd.SessionID, response.ID); err != nil {
+			response.CartMergeWarning = err.Error()
+		}
+	}
+	
+	return response, nil
+}
+
+// MergeGuestCart merges the guest cart of a session into the customer's cart on login
+func (s *CustomerService) MergeGuestCart(sessionID, customerID string) error {
+	// Find customer by ID
+	customer, err := s.customerRepo.FindByID(customerID)
+	if err != nil {
+		return err
+	}
+	
+	if customer == nil {
+		return domainCustomer.ErrCustomerNotFound
+	}
+	
+	if sessionID == "" || s.cartMerger == nil {
+		return nil
+	}
+	
+	return s.cartMerger.MergeGuestCart(sessionID, customer.ID)
 }
 
 // GetCustomer retrieves customer details by ID
@@ -42,27 +96,27 @@ func (s *CustomerService) GetCustomer(query GetCustomerQuery) (*GetCustomerRespo
 
 // UpdateCustomer updates customer information
 func (s *CustomerService) UpdateCustomer(cmd UpdateCustomerCommand) (*UpdateCustomerResponse, error) {
-	return s.updateCustomer.Execute(cmd)
+	return idempotency.Run(s.idempotency, "customer.update_customer", cmd.IdempotencyKey, cmd, s.updateCustomer.Execute)
 }
 
 // AddShippingAddress adds a shipping address to a customer
 func (s *CustomerService) AddShippingAddress(cmd AddShippingAddressCommand) (*AddShippingAddressResponse, error) {
-	return s.addShippingAddress.Execute(cmd)
+	return idempotency.Run(s.idempotency, "customer.add_shipping_address", cmd.IdempotencyKey, cmd, s.addShippingAddress.Execute)
 }
 
 // UpdateShippingAddress updates a shipping address for a customer
 func (s *CustomerService) UpdateShippingAddress(cmd UpdateShippingAddressCommand) (*UpdateShippingAddressResponse, error) {
-	return s.updateShippingAddress.Execute(cmd)
+	return idempotency.Run(s.idempotency, "customer.update_shipping_address", cmd.IdempotencyKey, cmd, s.updateShippingAddress.Execute)
 }
 
 // RemoveShippingAddress removes a shipping address from a customer
 func (s *CustomerService) RemoveShippingAddress(cmd RemoveShippingAddressCommand) (*RemoveShippingAddressResponse, error) {
-	return s.removeShippingAddress.Execute(cmd)
+	return idempotency.Run(s.idempotency, "customer.remove_shipping_address", cmd.IdempotencyKey, cmd, s.removeShippingAddress.Execute)
 }
 
 // SetDefaultShippingAddress sets a shipping address as the default for a customer
 func (s *CustomerService) SetDefaultShippingAddress(cmd SetDefaultShippingAddressCommand) (*SetDefaultShippingAddressResponse, error) {
-	return s.setDefaultShippingAddress.Execute(cmd)
+	return idempotency.Run(s.idempotency, "customer.set_default_shipping_address", cmd.IdempotencyKey, cmd, s.setDefaultShippingAddress.Execute)
 }
 
 // GetCustomerByEmail retrieves customer by email address
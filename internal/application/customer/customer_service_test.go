@@ -9,12 +9,22 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+// MockGuestCartMerger is a mock implementation of GuestCartMerger
+type MockGuestCartMerger struct {
+	mock.Mock
+}
+
+func (m *MockGuestCartMerger) MergeGuestCart(sessionID, customerID string) error {
+	args := m.Called(sessionID, customerID)
+	return args.Error(0)
+}
+
 // Tests for CustomerService
 
 func TestCustomerService(t *testing.T) {
 	t.Run("register customer", func(t *testing.T) {
 		mockRepo := new(MockCustomerRepository)
-		service := NewCustomerService(mockRepo)
+		service := NewCustomerService(mockRepo, nil)
 		
 		cmd := RegisterCustomerCommand{
 			Email:     "test@example.com",
@@ -37,7 +47,7 @@ func TestCustomerService(t *testing.T) {
 	
 	t.Run("get customer", func(t *testing.T) {
 		mockRepo := new(MockCustomerRepository)
-		service := NewCustomerService(mockRepo)
+		service := NewCustomerService(mockRepo, nil)
 		
 		testCustomer := createTestCustomerDomain()
 		query := GetCustomerQuery{ID: testCustomer.ID}
@@ -55,7 +65,7 @@ func TestCustomerService(t *testing.T) {
 	
 	t.Run("get customer by email", func(t *testing.T) {
 		mockRepo := new(MockCustomerRepository)
-		service := NewCustomerService(mockRepo)
+		service := NewCustomerService(mockRepo, nil)
 		
 		testCustomer := createTestCustomerDomain()
 		email := testCustomer.Email.Address
@@ -75,7 +85,7 @@ func TestCustomerService(t *testing.T) {
 	
 	t.Run("get customer by email - not found", func(t *testing.T) {
 		mockRepo := new(MockCustomerRepository)
-		service := NewCustomerService(mockRepo)
+		service := NewCustomerService(mockRepo, nil)
 		
 		email := "notfound@example.com"
 		
@@ -92,7 +102,7 @@ func TestCustomerService(t *testing.T) {
 	
 	t.Run("update customer", func(t *testing.T) {
 		mockRepo := new(MockCustomerRepository)
-		service := NewCustomerService(mockRepo)
+		service := NewCustomerService(mockRepo, nil)
 		
 		testCustomer := createTestCustomerDomain()
 		cmd := UpdateCustomerCommand{
@@ -116,7 +126,7 @@ func TestCustomerService(t *testing.T) {
 	
 	t.Run("deactivate customer", func(t *testing.T) {
 		mockRepo := new(MockCustomerRepository)
-		service := NewCustomerService(mockRepo)
+		service := NewCustomerService(mockRepo, nil)
 		
 		testCustomer := createTestCustomerDomain()
 		
@@ -132,7 +142,7 @@ func TestCustomerService(t *testing.T) {
 	
 	t.Run("activate customer", func(t *testing.T) {
 		mockRepo := new(MockCustomerRepository)
-		service := NewCustomerService(mockRepo)
+		service := NewCustomerService(mockRepo, nil)
 		
 		testCustomer := createTestCustomerDomain()
 		testCustomer.Status = domainCustomer.StatusInactive
@@ -149,7 +159,7 @@ func TestCustomerService(t *testing.T) {
 	
 	t.Run("suspend customer", func(t *testing.T) {
 		mockRepo := new(MockCustomerRepository)
-		service := NewCustomerService(mockRepo)
+		service := NewCustomerService(mockRepo, nil)
 		
 		testCustomer := createTestCustomerDomain()
 		
@@ -165,7 +175,7 @@ func TestCustomerService(t *testing.T) {
 	
 	t.Run("can customer place order - yes", func(t *testing.T) {
 		mockRepo := new(MockCustomerRepository)
-		service := NewCustomerService(mockRepo)
+		service := NewCustomerService(mockRepo, nil)
 		
 		testCustomer := createTestCustomerWithAddress() // Active customer with address
 		
@@ -181,7 +191,7 @@ func TestCustomerService(t *testing.T) {
 	
 	t.Run("can customer place order - no addresses", func(t *testing.T) {
 		mockRepo := new(MockCustomerRepository)
-		service := NewCustomerService(mockRepo)
+		service := NewCustomerService(mockRepo, nil)
 		
 		testCustomer := createTestCustomerDomain() // Active customer without addresses
 		
@@ -197,7 +207,7 @@ func TestCustomerService(t *testing.T) {
 	
 	t.Run("can customer place order - inactive", func(t *testing.T) {
 		mockRepo := new(MockCustomerRepository)
-		service := NewCustomerService(mockRepo)
+		service := NewCustomerService(mockRepo, nil)
 		
 		testCustomer := createTestCustomerWithAddress()
 		testCustomer.Status = domainCustomer.StatusInactive // Inactive customer
@@ -214,7 +224,7 @@ func TestCustomerService(t *testing.T) {
 	
 	t.Run("can customer place order - customer not found", func(t *testing.T) {
 		mockRepo := new(MockCustomerRepository)
-		service := NewCustomerService(mockRepo)
+		service := NewCustomerService(mockRepo, nil)
 		
 		mockRepo.On("FindByID", "non-existent-id").Return(nil, nil)
 		
@@ -229,7 +239,7 @@ func TestCustomerService(t *testing.T) {
 	
 	t.Run("add shipping address", func(t *testing.T) {
 		mockRepo := new(MockCustomerRepository)
-		service := NewCustomerService(mockRepo)
+		service := NewCustomerService(mockRepo, nil)
 		
 		testCustomer := createTestCustomerDomain()
 		cmd := AddShippingAddressCommand{
@@ -258,7 +268,7 @@ func TestCustomerService(t *testing.T) {
 	
 	t.Run("handle repository errors", func(t *testing.T) {
 		mockRepo := new(MockCustomerRepository)
-		service := NewCustomerService(mockRepo)
+		service := NewCustomerService(mockRepo, nil)
 		
 		repoError := errors.New("database connection error")
 		
@@ -272,4 +282,85 @@ func TestCustomerService(t *testing.T) {
 		
 		mockRepo.AssertExpectations(t)
 	})
+	
+	t.Run("register customer merges guest cart", func(t *testing.T) {
+		mockRepo := new(MockCustomerRepository)
+		cartMerger := new(MockGuestCartMerger)
+		service := NewCustomerService(mockRepo, nil)
+		service.SetCartMerger(cartMerger)
+		
+		cmd := RegisterCustomerCommand{
+			Email:     "test@example.com",
+			FirstName: "John",
+			LastName:  "Doe",
+			SessionID: "session-123",
+		}
+		
+		mockRepo.On("ExistsByEmail", cmd.Email).Return(false, nil)
+		mockRepo.On("Save", mock.AnythingOfType("*customer.Customer")).Return(nil)
+		cartMerger.On("MergeGuestCart", "session-123", mock.AnythingOfType("string")).Return(nil)
+		
+		response, err := service.RegisterCustomer(cmd)
+		
+		assert.NoError(t, err)
+		assert.NotNil(t, response)
+		assert.Empty(t, response.CartMergeWarning)
+		cartMerger.AssertCalled(t, "MergeGuestCart", "session-123", response.ID)
+	})
+	
+	t.Run("failed cart merge does not fail registration", func(t *testing.T) {
+		mockRepo := new(MockCustomerRepository)
+		cartMerger := new(MockGuestCartMerger)
+		service := NewCustomerService(mockRepo, nil)
+		service.SetCartMerger(cartMerger)
+		
+		cmd := RegisterCustomerCommand{
+			Email:     "test@example.com",
+			FirstName: "John",
+			LastName:  "Doe",
+			SessionID: "session-123",
+		}
+		
+		mockRepo.On("ExistsByEmail", cmd.Email).Return(false, nil)
+		mockRepo.On("Save", mock.AnythingOfType("*customer.Customer")).Return(nil)
+		cartMerger.On("MergeGuestCart", "session-123", mock.AnythingOfType("string")).Return(errors.New("cart store unavailable"))
+		
+		response, err := service.RegisterCustomer(cmd)
+		
+		assert.NoError(t, err)
+		assert.NotNil(t, response)
+		assert.Equal(t, "cart store unavailable", response.CartMergeWarning)
+		mockRepo.AssertNumberOfCalls(t, "Save", 1)
+	})
+	
+	t.Run("merge guest cart on login", func(t *testing.T) {
+		mockRepo := new(MockCustomerRepository)
+		cartMerger := new(MockGuestCartMerger)
+		service := NewCustomerService(mockRepo, nil)
+		service.SetCartMerger(cartMerger)
+		
+		testCustomer := createTestCustomerDomain()
+		
+		mockRepo.On("FindByID", testCustomer.ID).Return(testCustomer, nil)
+		cartMerger.On("MergeGuestCart", "session-123", testCustomer.ID).Return(nil)
+		
+		err := service.MergeGuestCart("session-123", testCustomer.ID)
+		
+		assert.NoError(t, err)
+		cartMerger.AssertExpectations(t)
+	})
+	
+	t.Run("merge guest cart for unknown customer", func(t *testing.T) {
+		mockRepo := new(MockCustomerRepository)
+		cartMerger := new(MockGuestCartMerger)
+		service := NewCustomerService(mockRepo, nil)
+		service.SetCartMerger(cartMerger)
+		
+		mockRepo.On("FindByID", "unknown").Return(nil, nil)
+		
+		err := service.MergeGuestCart("session-123", "unknown")
+		
+		assert.Equal(t, domainCustomer.ErrCustomerNotFound, err)
+		cartMerger.AssertNotCalled(t, "MergeGuestCart", mock.Anything, mock.Anything)
+	})
 }
@@ -17,6 +17,7 @@ type GetCustomerResponse struct {
 	LastName          string                    `json:"last_name"`
 	Phone             string                    `json:"phone,omitempty"`
 	Status            string                    `json:"status"`
+	TrustLevel        string                    `json:"trust_level"`
 	ShippingAddresses []ShippingAddressResponse `json:"shipping_addresses"`
 	CreatedAt         string                    `json:"created_at"`
 	UpdatedAt         string                    `json:"updated_at"`
@@ -91,6 +92,7 @@ func (uc *GetCustomerUseCase) Execute(query GetCustomerQuery) (*GetCustomerRespo
 		LastName:          customer.LastName,
 		Phone:             customer.Phone,
 		Status:            string(customer.Status),
+		TrustLevel:        string(customer.TrustLevel),
 		ShippingAddresses: addresses,
 		CreatedAt:         customer.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		UpdatedAt:         customer.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
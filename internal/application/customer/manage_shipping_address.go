@@ -4,6 +4,8 @@ import domainCustomer "github.com/dudedani/Go_NowPayment.io_PaymentSystem/intern
 
 // RemoveShippingAddressCommand represents the input for removing a shipping address
 type RemoveShippingAddressCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
 	CustomerID string `json:"customer_id" validate:"required"`
 	AddressID  string `json:"address_id" validate:"required"`
 }
@@ -59,6 +61,8 @@ func (uc *RemoveShippingAddressUseCase) Execute(cmd RemoveShippingAddressCommand
 
 // SetDefaultShippingAddressCommand represents the input for setting a default shipping address
 type SetDefaultShippingAddressCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
 	CustomerID string `json:"customer_id" validate:"required"`
 	AddressID  string `json:"address_id" validate:"required"`
 }
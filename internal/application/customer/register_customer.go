@@ -6,21 +6,25 @@ import (
 
 // RegisterCustomerCommand represents the input for registering a new customer
 type RegisterCustomerCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
 	Email     string `json:"email" validate:"required,email"`
 	FirstName string `json:"first_name" validate:"required"`
 	LastName  string `json:"last_name" validate:"required"`
 	Phone     string `json:"phone,omitempty"`
+	SessionID string `json:"session_id,omitempty"` // Guest session whose cart the customer takes over
 }
 
 // RegisterCustomerResponse represents the output after registering a customer
 type RegisterCustomerResponse struct {
-	ID        string `json:"id"`
-	Email     string `json:"email"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Phone     string `json:"phone,omitempty"`
-	Status    string `json:"status"`
-	CreatedAt string `json:"created_at"`
+	ID               string `json:"id"`
+	Email            string `json:"email"`
+	FirstName        string `json:"first_name"`
+	LastName         string `json:"last_name"`
+	Phone            string `json:"phone,omitempty"`
+	Status           string `json:"status"`
+	CreatedAt        string `json:"created_at"`
+	CartMergeWarning string `json:"cart_merge_warning,omitempty"` // Set when the guest cart stayed with the session
 }
 
 // CustomerRepository defines the interface for customer persistence
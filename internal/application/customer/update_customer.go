@@ -4,6 +4,8 @@ import domainCustomer "github.com/dudedani/Go_NowPayment.io_PaymentSystem/intern
 
 // UpdateCustomerCommand represents the input for updating customer details
 type UpdateCustomerCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
 	ID        string `json:"id" validate:"required"`
 	FirstName string `json:"first_name" validate:"required"`
 	LastName  string `json:"last_name" validate:"required"`
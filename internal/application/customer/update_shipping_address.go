@@ -4,6 +4,8 @@ import domainCustomer "github.com/dudedani/Go_NowPayment.io_PaymentSystem/intern
 
 // UpdateShippingAddressCommand represents the input for updating a shipping address
 type UpdateShippingAddressCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
 	CustomerID   string `json:"customer_id" validate:"required"`
 	AddressID    string `json:"address_id" validate:"required"`
 	Label        string `json:"label" validate:"required"`
@@ -0,0 +1,12 @@
+package idempotency
+
+import "errors"
+
+// Idempotency errors organized by category
+
+// === Idempotency Errors ===
+var (
+	ErrInvalidKey        = errors.New("idempotency key is too long")
+	ErrKeyReused         = errors.New("idempotency key was already used with a different request")
+	ErrRequestInProgress = errors.New("a request with this idempotency key is still being processed")
+)
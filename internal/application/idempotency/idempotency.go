@@ -0,0 +1,142 @@
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// MaxKeyLength is the longest idempotency key accepted
+const MaxKeyLength = 255
+
+// Record is the stored outcome of a command, kept for the key's time to live
+type Record struct {
+	Scope       string // Command the key belongs to, e.g. "payment.create_payment"
+	Key         string
+	Fingerprint string // Hash of the command payload
+	Response    []byte // JSON response; empty while the command runs
+	Completed   bool
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// IsExpired checks if the record outlived its time to live
+func (r Record) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// Store persists idempotency records, shared by every instance of the application
+type Store interface {
+	// Reserve saves the record unless an unexpired record exists for the same
+	// scope and key, in which case that record is returned instead. It must be
+	// atomic (e.g. INSERT ... ON CONFLICT DO UPDATE ... WHERE expires_at <= NOW()).
+	Reserve(record Record) (*Record, error)
+	// Complete stores the response of a reserved record
+	Complete(record Record) error
+	// Release deletes a reservation so the key can be retried
+	Release(scope, key string) error
+}
+
+// Guard runs commands at most once per idempotency key. Replays with the same
+// payload get the first response; failed commands are not stored, so they can
+// be retried with the same key.
+type Guard struct {
+	store Store
+	ttl   time.Duration
+}
+
+// NewGuard creates a new instance of Guard
+func NewGuard(store Store, ttl time.Duration) *Guard {
+	return &Guard{
+		store: store,
+		ttl:   ttl,
+	}
+}
+
+// Run executes the command unless the key was already used. Without a guard
+// or a key the command is simply executed.
+func Run[C any, R any](g *Guard, scope, key string, cmd C, execute func(C) (R, error)) (R, error) {
+	var response R
+
+	if g == nil || key == "" {
+		return execute(cmd)
+	}
+
+	if len(key) > MaxKeyLength {
+		return response, ErrInvalidKey
+	}
+
+	fingerprint, err := Fingerprint(cmd)
+	if err != nil {
+		return response, err
+	}
+
+	now := time.Now()
+	record := Record{
+		Scope:       scope,
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(g.ttl),
+	}
+
+	existing, err := g.store.Reserve(record)
+	if err != nil {
+		return response, err
+	}
+	if existing != nil {
+		return replay[R](existing, fingerprint)
+	}
+
+	response, err = execute(cmd)
+	if err != nil {
+		if releaseErr := g.store.Release(scope, key); releaseErr != nil {
+			return response, releaseErr
+		}
+		return response, err
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		return response, err
+	}
+
+	record.Response = body
+	record.Completed = true
+
+	// If this fails the reservation stays in progress until it expires, so a
+	// retry cannot run the command a second time
+	return response, g.store.Complete(record)
+}
+
+// replay returns the stored response of an earlier request with the same key
+func replay[R any](existing *Record, fingerprint string) (R, error) {
+	var response R
+
+	if existing.Fingerprint != fingerprint {
+		return response, ErrKeyReused
+	}
+
+	if !existing.Completed {
+		return response, ErrRequestInProgress
+	}
+
+	if err := json.Unmarshal(existing.Response, &response); err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// Fingerprint hashes the JSON encoding of a command. Fields tagged json:"-",
+// such as the idempotency key itself, are left out.
+func Fingerprint(cmd interface{}) (string, error) {
+	payload, err := json.Marshal(cmd)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
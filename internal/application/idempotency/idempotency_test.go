@@ -0,0 +1,167 @@
+package idempotency
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryStore is an in-memory Store for tests
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{records: make(map[string]Record)}
+}
+
+func (s *memoryStore) Reserve(record Record) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.records[record.Scope+"/"+record.Key]; ok && !existing.IsExpired(record.CreatedAt) {
+		return &existing, nil
+	}
+	s.records[record.Scope+"/"+record.Key] = record
+	return nil, nil
+}
+
+func (s *memoryStore) Complete(record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[record.Scope+"/"+record.Key] = record
+	return nil
+}
+
+func (s *memoryStore) Release(scope, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, scope+"/"+key)
+	return nil
+}
+
+type testCommand struct {
+	IdempotencyKey string `json:"-"`
+	OrderID        string `json:"order_id"`
+}
+
+type testResponse struct {
+	PaymentID string `json:"payment_id"`
+}
+
+// countingExecute returns a new payment ID on every call
+func countingExecute(calls *int) func(testCommand) (*testResponse, error) {
+	return func(cmd testCommand) (*testResponse, error) {
+		*calls++
+		return &testResponse{PaymentID: fmt.Sprintf("%s-%d", cmd.OrderID, *calls)}, nil
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Run("replay returns the first response", func(t *testing.T) {
+		guard := NewGuard(newMemoryStore(), time.Hour)
+		calls := 0
+		cmd := testCommand{IdempotencyKey: "key-1", OrderID: "order123"}
+
+		first, err1 := Run(guard, "payment.create", cmd.IdempotencyKey, cmd, countingExecute(&calls))
+		second, err2 := Run(guard, "payment.create", cmd.IdempotencyKey, cmd, countingExecute(&calls))
+
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("same key with a different payload is rejected", func(t *testing.T) {
+		guard := NewGuard(newMemoryStore(), time.Hour)
+		calls := 0
+
+		_, _ = Run(guard, "payment.create", "key-1", testCommand{OrderID: "order123"}, countingExecute(&calls))
+		response, err := Run(guard, "payment.create", "key-1", testCommand{OrderID: "order456"}, countingExecute(&calls))
+
+		assert.Nil(t, response)
+		assert.Equal(t, ErrKeyReused, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("keys are scoped per command", func(t *testing.T) {
+		guard := NewGuard(newMemoryStore(), time.Hour)
+		calls := 0
+		cmd := testCommand{OrderID: "order123"}
+
+		_, _ = Run(guard, "payment.create", "key-1", cmd, countingExecute(&calls))
+		_, err := Run(guard, "order.cancel", "key-1", cmd, countingExecute(&calls))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("request still running", func(t *testing.T) {
+		store := newMemoryStore()
+		guard := NewGuard(store, time.Hour)
+		fingerprint, _ := Fingerprint(testCommand{OrderID: "order123"})
+		_, _ = store.Reserve(Record{Scope: "payment.create", Key: "key-1", Fingerprint: fingerprint, CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)})
+		calls := 0
+
+		_, err := Run(guard, "payment.create", "key-1", testCommand{OrderID: "order123"}, countingExecute(&calls))
+
+		assert.Equal(t, ErrRequestInProgress, err)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("failed command can be retried", func(t *testing.T) {
+		guard := NewGuard(newMemoryStore(), time.Hour)
+		failure := errors.New("gateway down")
+		calls := 0
+
+		_, err := Run(guard, "payment.create", "key-1", testCommand{OrderID: "order123"}, func(testCommand) (*testResponse, error) {
+			return nil, failure
+		})
+		response, retryErr := Run(guard, "payment.create", "key-1", testCommand{OrderID: "order123"}, countingExecute(&calls))
+
+		assert.Equal(t, failure, err)
+		assert.NoError(t, retryErr)
+		assert.Equal(t, "order123-1", response.PaymentID)
+	})
+
+	t.Run("expired key runs again", func(t *testing.T) {
+		guard := NewGuard(newMemoryStore(), -time.Second)
+		calls := 0
+		cmd := testCommand{OrderID: "order123"}
+
+		_, _ = Run(guard, "payment.create", "key-1", cmd, countingExecute(&calls))
+		_, _ = Run(guard, "payment.create", "key-1", cmd, countingExecute(&calls))
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("without key or guard the command always runs", func(t *testing.T) {
+		guard := NewGuard(newMemoryStore(), time.Hour)
+		calls := 0
+		cmd := testCommand{OrderID: "order123"}
+
+		_, _ = Run(guard, "payment.create", "", cmd, countingExecute(&calls))
+		_, _ = Run(guard, "payment.create", "", cmd, countingExecute(&calls))
+		_, _ = Run(nil, "payment.create", "key-1", cmd, countingExecute(&calls))
+
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("key too long", func(t *testing.T) {
+		guard := NewGuard(newMemoryStore(), time.Hour)
+		calls := 0
+		key := string(make([]byte, MaxKeyLength+1))
+
+		_, err := Run(guard, "payment.create", key, testCommand{}, countingExecute(&calls))
+
+		assert.Equal(t, ErrInvalidKey, err)
+		assert.Equal(t, 0, calls)
+	})
+}
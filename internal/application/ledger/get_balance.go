@@ -0,0 +1,85 @@
+package ledger
+
+import (
+	"time"
+
+	domainLedger "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/ledger"
+)
+
+// GetBalanceCommand represents a balance query for one currency at the end of a day (UTC)
+type GetBalanceCommand struct {
+	Currency string `json:"currency" validate:"required"`
+	AsOf     string `json:"as_of"` // 2006-01-02, inclusive; defaults to now
+}
+
+// BalanceResponse represents the balance of every account of a currency
+type BalanceResponse struct {
+	Currency        string             `json:"currency"`
+	AsOf            string             `json:"as_of"` // Entries that occurred before this instant are included
+	Accounts        map[string]float64 `json:"accounts"`
+	MerchantBalance float64            `json:"merchant_balance"`
+	Available       float64            `json:"available"` // Merchant balance not held back for refunds payable
+	Entries         int                `json:"entries"`
+}
+
+// GetBalanceUseCase sums up a currency's journal as of a date
+type GetBalanceUseCase struct {
+	ledgerRepo LedgerRepository
+}
+
+// NewGetBalanceUseCase creates a new instance of GetBalanceUseCase
+func NewGetBalanceUseCase(ledgerRepo LedgerRepository) *GetBalanceUseCase {
+	return &GetBalanceUseCase{
+		ledgerRepo: ledgerRepo,
+	}
+}
+
+// Execute returns the balance
+func (uc *GetBalanceUseCase) Execute(cmd GetBalanceCommand) (*BalanceResponse, error) {
+	if cmd.Currency == "" {
+		return nil, domainLedger.ErrEmptyCurrency
+	}
+
+	asOf := time.Now()
+	if cmd.AsOf != "" {
+		day, err := time.Parse("2006-01-02", cmd.AsOf)
+		if err != nil {
+			return nil, domainLedger.ErrInvalidBalanceDate
+		}
+		asOf = day.AddDate(0, 0, 1)
+	}
+
+	balance, err := currentBalance(uc.ledgerRepo, cmd.Currency, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBalanceResponse(balance), nil
+}
+
+// currentBalance loads and sums up a currency's entries that occurred before asOf
+func currentBalance(ledgerRepo LedgerRepository, currency string, asOf time.Time) (*domainLedger.Balance, error) {
+	entries, err := ledgerRepo.FindByCurrency(currency, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	return domainLedger.NewBalance(currency, asOf, entries), nil
+}
+
+// toBalanceResponse maps a balance to its response
+func toBalanceResponse(balance *domainLedger.Balance) *BalanceResponse {
+	accounts := make(map[string]float64, len(balance.Accounts))
+	for account, amount := range balance.Accounts {
+		accounts[string(account)] = amount
+	}
+
+	return &BalanceResponse{
+		Currency:        balance.Currency,
+		AsOf:            balance.AsOf.Format("2006-01-02T15:04:05Z07:00"),
+		Accounts:        accounts,
+		MerchantBalance: balance.MerchantBalance(),
+		Available:       balance.Available(),
+		Entries:         balance.Entries,
+	}
+}
@@ -0,0 +1,57 @@
+package ledger
+
+import (
+	"github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/application/idempotency"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// LedgerService provides the merchant ledger: postings of payment events,
+// balances and payouts
+type LedgerService struct {
+	idempotency *idempotency.Guard // nil disables idempotency keys
+
+	// Use cases
+	recordPayment        *RecordPaymentUseCase
+	getBalance           *GetBalanceUseCase
+	createPayoutBatch    *CreatePayoutBatchUseCase
+	recordWithdrawalSent *RecordWithdrawalSentUseCase
+	cancelPayoutBatch    *CancelPayoutBatchUseCase
+}
+
+// NewLedgerService creates a new instance of LedgerService
+func NewLedgerService(ledgerRepo LedgerRepository, batchRepo PayoutBatchRepository, registry *domainPayment.CryptoRegistry, idempotencyGuard *idempotency.Guard) *LedgerService {
+	return &LedgerService{
+		idempotency:          idempotencyGuard,
+		recordPayment:        NewRecordPaymentUseCase(ledgerRepo),
+		getBalance:           NewGetBalanceUseCase(ledgerRepo),
+		createPayoutBatch:    NewCreatePayoutBatchUseCase(ledgerRepo, batchRepo, registry),
+		recordWithdrawalSent: NewRecordWithdrawalSentUseCase(ledgerRepo, batchRepo, registry),
+		cancelPayoutBatch:    NewCancelPayoutBatchUseCase(batchRepo, registry),
+	}
+}
+
+// RecordPayment posts the entries of a payment's confirmation and refunds.
+// The payment and order services call it after saving a payment.
+func (s *LedgerService) RecordPayment(p *domainPayment.Payment) error {
+	return s.recordPayment.Execute(p)
+}
+
+// GetBalance returns the balance of a currency's accounts as of a date (admin)
+func (s *LedgerService) GetBalance(cmd GetBalanceCommand) (*BalanceResponse, error) {
+	return s.getBalance.Execute(cmd)
+}
+
+// CreatePayoutBatch opens a batch of withdrawals of the merchant balance (admin)
+func (s *LedgerService) CreatePayoutBatch(cmd CreatePayoutBatchCommand) (*PayoutBatchResponse, error) {
+	return idempotency.Run(s.idempotency, "ledger.create_payout_batch", cmd.IdempotencyKey, cmd, s.createPayoutBatch.Execute)
+}
+
+// RecordWithdrawalSent records the on-chain transaction of a withdrawal (admin)
+func (s *LedgerService) RecordWithdrawalSent(cmd RecordWithdrawalSentCommand) (*PayoutBatchResponse, error) {
+	return idempotency.Run(s.idempotency, "ledger.record_withdrawal_sent", cmd.IdempotencyKey, cmd, s.recordWithdrawalSent.Execute)
+}
+
+// CancelPayoutBatch drops a payout batch before anything was sent (admin)
+func (s *LedgerService) CancelPayoutBatch(cmd CancelPayoutBatchCommand) (*PayoutBatchResponse, error) {
+	return idempotency.Run(s.idempotency, "ledger.cancel_payout_batch", cmd.IdempotencyKey, cmd, s.cancelPayoutBatch.Execute)
+}
@@ -0,0 +1,280 @@
+package ledger
+
+import (
+	"time"
+
+	domainLedger "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/ledger"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// CreatePayoutBatchCommand represents the input for withdrawing merchant balance
+type CreatePayoutBatchCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	Currency    string              `json:"currency" validate:"required"`
+	Withdrawals []WithdrawalRequest `json:"withdrawals" validate:"required,min=1,dive"`
+	Actor       string              `json:"actor" validate:"required"`
+}
+
+// WithdrawalRequest is one transfer of a payout batch
+type WithdrawalRequest struct {
+	DestinationAddress string  `json:"destination_address" validate:"required"`
+	Amount             float64 `json:"amount" validate:"required,gt=0"`
+}
+
+// RecordWithdrawalSentCommand represents the on-chain transaction of a withdrawal
+type RecordWithdrawalSentCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	BatchID         string  `json:"batch_id" validate:"required"`
+	Index           int     `json:"index" validate:"min=0"` // Position of the withdrawal in the batch
+	TransactionHash string  `json:"transaction_hash" validate:"required"`
+	NetworkFee      float64 `json:"network_fee" validate:"min=0"`
+}
+
+// CancelPayoutBatchCommand represents the input for dropping a payout batch
+type CancelPayoutBatchCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	BatchID string `json:"batch_id" validate:"required"`
+}
+
+// PayoutBatchResponse represents a payout batch in responses
+type PayoutBatchResponse struct {
+	ID          string               `json:"id"`
+	Currency    string               `json:"currency"`
+	Status      string               `json:"status"`
+	Total       float64              `json:"total"`
+	Withdrawals []WithdrawalResponse `json:"withdrawals"`
+	CreatedBy   string               `json:"created_by"`
+	CreatedAt   string               `json:"created_at"`
+	CompletedAt string               `json:"completed_at,omitempty"`
+}
+
+// WithdrawalResponse represents a withdrawal of a payout batch
+type WithdrawalResponse struct {
+	DestinationAddress string  `json:"destination_address"`
+	Amount             float64 `json:"amount"`
+	NetworkFee         float64 `json:"network_fee"`
+	TransactionHash    string  `json:"transaction_hash,omitempty"`
+	TransactionURL     string  `json:"transaction_url,omitempty"` // Block explorer link
+	SentAt             string  `json:"sent_at,omitempty"`
+}
+
+// PayoutBatchRepository defines the interface for payout batch persistence
+type PayoutBatchRepository interface {
+	Save(batch *domainLedger.PayoutBatch) error
+	FindByID(id string) (*domainLedger.PayoutBatch, error)
+	FindOpenByCurrency(currency string) ([]*domainLedger.PayoutBatch, error)
+	Update(batch *domainLedger.PayoutBatch) error
+}
+
+// CreatePayoutBatchUseCase opens a payout batch. The batch cannot withdraw
+// more than the available balance left by other open batches.
+type CreatePayoutBatchUseCase struct {
+	ledgerRepo LedgerRepository
+	batchRepo  PayoutBatchRepository
+	registry   *domainPayment.CryptoRegistry
+}
+
+// NewCreatePayoutBatchUseCase creates a new instance of CreatePayoutBatchUseCase
+func NewCreatePayoutBatchUseCase(ledgerRepo LedgerRepository, batchRepo PayoutBatchRepository, registry *domainPayment.CryptoRegistry) *CreatePayoutBatchUseCase {
+	return &CreatePayoutBatchUseCase{
+		ledgerRepo: ledgerRepo,
+		batchRepo:  batchRepo,
+		registry:   registry,
+	}
+}
+
+// Execute validates the destinations and opens the batch
+func (uc *CreatePayoutBatchUseCase) Execute(cmd CreatePayoutBatchCommand) (*PayoutBatchResponse, error) {
+	crypto, err := lookupCurrency(uc.registry, cmd.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	withdrawals := make([]domainLedger.Withdrawal, 0, len(cmd.Withdrawals))
+	for _, request := range cmd.Withdrawals {
+		if err := crypto.ValidateAddress(request.DestinationAddress); err != nil {
+			return nil, err
+		}
+		withdrawals = append(withdrawals, domainLedger.Withdrawal{
+			DestinationAddress: request.DestinationAddress,
+			Amount:             request.Amount,
+		})
+	}
+
+	batch, err := domainLedger.NewPayoutBatch(crypto.Symbol, withdrawals, cmd.Actor)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := currentBalance(uc.ledgerRepo, batch.Currency, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	open, err := uc.batchRepo.FindOpenByCurrency(batch.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	// Network fees are only known once sent, so they are not reserved here
+	reserved := batch.Total()
+	for _, other := range open {
+		reserved += other.Unsent()
+	}
+	if reserved > balance.Available() {
+		return nil, domainLedger.ErrInsufficientBalance
+	}
+
+	if err := uc.batchRepo.Save(batch); err != nil {
+		return nil, err
+	}
+
+	return toPayoutBatchResponse(batch, crypto), nil
+}
+
+// RecordWithdrawalSentUseCase records a withdrawal's transaction and posts it to the ledger
+type RecordWithdrawalSentUseCase struct {
+	ledgerRepo LedgerRepository
+	batchRepo  PayoutBatchRepository
+	registry   *domainPayment.CryptoRegistry
+}
+
+// NewRecordWithdrawalSentUseCase creates a new instance of RecordWithdrawalSentUseCase
+func NewRecordWithdrawalSentUseCase(ledgerRepo LedgerRepository, batchRepo PayoutBatchRepository, registry *domainPayment.CryptoRegistry) *RecordWithdrawalSentUseCase {
+	return &RecordWithdrawalSentUseCase{
+		ledgerRepo: ledgerRepo,
+		batchRepo:  batchRepo,
+		registry:   registry,
+	}
+}
+
+// Execute records the transaction, completing the batch once every withdrawal is sent
+func (uc *RecordWithdrawalSentUseCase) Execute(cmd RecordWithdrawalSentCommand) (*PayoutBatchResponse, error) {
+	batch, err := findPayoutBatch(uc.batchRepo, cmd.BatchID)
+	if err != nil {
+		return nil, err
+	}
+
+	crypto, err := lookupCurrency(uc.registry, batch.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := crypto.ValidateTransactionHash(cmd.TransactionHash); err != nil {
+		return nil, err
+	}
+
+	if err := batch.RecordWithdrawalSent(cmd.Index, cmd.TransactionHash, cmd.NetworkFee); err != nil {
+		return nil, err
+	}
+
+	entry, err := domainLedger.NewPayoutSentEntry(batch, cmd.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	// Posted first: a retry after a failed update finds the entry and skips it
+	if err := post(uc.ledgerRepo, entry); err != nil {
+		return nil, err
+	}
+
+	if err := uc.batchRepo.Update(batch); err != nil {
+		return nil, err
+	}
+
+	return toPayoutBatchResponse(batch, crypto), nil
+}
+
+// CancelPayoutBatchUseCase drops a payout batch before anything was sent
+type CancelPayoutBatchUseCase struct {
+	batchRepo PayoutBatchRepository
+	registry  *domainPayment.CryptoRegistry
+}
+
+// NewCancelPayoutBatchUseCase creates a new instance of CancelPayoutBatchUseCase
+func NewCancelPayoutBatchUseCase(batchRepo PayoutBatchRepository, registry *domainPayment.CryptoRegistry) *CancelPayoutBatchUseCase {
+	return &CancelPayoutBatchUseCase{
+		batchRepo: batchRepo,
+		registry:  registry,
+	}
+}
+
+// Execute cancels the batch
+func (uc *CancelPayoutBatchUseCase) Execute(cmd CancelPayoutBatchCommand) (*PayoutBatchResponse, error) {
+	batch, err := findPayoutBatch(uc.batchRepo, cmd.BatchID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := batch.Cancel(); err != nil {
+		return nil, err
+	}
+
+	if err := uc.batchRepo.Update(batch); err != nil {
+		return nil, err
+	}
+
+	crypto, _ := uc.registry.Lookup(batch.Currency)
+	return toPayoutBatchResponse(batch, crypto), nil
+}
+
+// lookupCurrency returns a registered coin; disabled coins can still be paid out
+func lookupCurrency(registry *domainPayment.CryptoRegistry, symbol string) (domainPayment.CryptoCurrency, error) {
+	crypto, ok := registry.Lookup(symbol)
+	if !ok {
+		return domainPayment.CryptoCurrency{}, domainPayment.ErrUnsupportedCrypto
+	}
+
+	return crypto, nil
+}
+
+// findPayoutBatch loads a payout batch, mapping a missing one to ErrPayoutBatchNotFound
+func findPayoutBatch(batchRepo PayoutBatchRepository, batchID string) (*domainLedger.PayoutBatch, error) {
+	batch, err := batchRepo.FindByID(batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	if batch == nil {
+		return nil, domainLedger.ErrPayoutBatchNotFound
+	}
+
+	return batch, nil
+}
+
+// toPayoutBatchResponse maps a payout batch to its response, linking sent
+// withdrawals to the currency's block explorer
+func toPayoutBatchResponse(batch *domainLedger.PayoutBatch, crypto domainPayment.CryptoCurrency) *PayoutBatchResponse {
+	response := &PayoutBatchResponse{
+		ID:          batch.ID,
+		Currency:    batch.Currency,
+		Status:      string(batch.Status),
+		Total:       batch.Total(),
+		Withdrawals: make([]WithdrawalResponse, 0, len(batch.Withdrawals)),
+		CreatedBy:   batch.CreatedBy,
+		CreatedAt:   batch.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+
+	if batch.CompletedAt != nil {
+		response.CompletedAt = batch.CompletedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+
+	for _, withdrawal := range batch.Withdrawals {
+		line := WithdrawalResponse{
+			DestinationAddress: withdrawal.DestinationAddress,
+			Amount:             withdrawal.Amount,
+			NetworkFee:         withdrawal.NetworkFee,
+			TransactionHash:    withdrawal.TransactionHash,
+		}
+		if withdrawal.IsSent() {
+			line.TransactionURL = crypto.TransactionURL(withdrawal.TransactionHash)
+			line.SentAt = withdrawal.SentAt.Format("2006-01-02T15:04:05Z07:00")
+		}
+		response.Withdrawals = append(response.Withdrawals, line)
+	}
+
+	return response
+}
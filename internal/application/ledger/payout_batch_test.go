@@ -0,0 +1,152 @@
+package ledger
+
+import (
+	"testing"
+	"time"
+
+	domainLedger "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/ledger"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPayoutBatchRepository is a mock implementation of PayoutBatchRepository
+type MockPayoutBatchRepository struct {
+	mock.Mock
+}
+
+func (m *MockPayoutBatchRepository) Save(batch *domainLedger.PayoutBatch) error {
+	args := m.Called(batch)
+	return args.Error(0)
+}
+
+func (m *MockPayoutBatchRepository) FindByID(id string) (*domainLedger.PayoutBatch, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainLedger.PayoutBatch), args.Error(1)
+}
+
+func (m *MockPayoutBatchRepository) FindOpenByCurrency(currency string) ([]*domainLedger.PayoutBatch, error) {
+	args := m.Called(currency)
+	return args.Get(0).([]*domainLedger.PayoutBatch), args.Error(1)
+}
+
+func (m *MockPayoutBatchRepository) Update(batch *domainLedger.PayoutBatch) error {
+	args := m.Called(batch)
+	return args.Error(0)
+}
+
+const testPayoutAddress = "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh"
+
+// ledgerWithReceipt returns a journal holding one 0.01 BTC receipt
+func ledgerWithReceipt() []*domainLedger.Entry {
+	receipt, _ := domainLedger.NewPaymentReceivedEntry("pay-1", "BTC", 0.01, time.Now().Add(-time.Hour))
+	return []*domainLedger.Entry{receipt}
+}
+
+// Tests for CreatePayoutBatchUseCase
+
+func TestCreatePayoutBatchUseCase(t *testing.T) {
+	t.Run("batch within the available balance", func(t *testing.T) {
+		ledgerRepo := new(MockLedgerRepository)
+		batchRepo := new(MockPayoutBatchRepository)
+		useCase := NewCreatePayoutBatchUseCase(ledgerRepo, batchRepo, domainPayment.DefaultCryptoRegistry())
+
+		ledgerRepo.On("FindByCurrency", "BTC", mock.Anything).Return(ledgerWithReceipt(), nil)
+		batchRepo.On("FindOpenByCurrency", "BTC").Return([]*domainLedger.PayoutBatch{}, nil)
+		batchRepo.On("Save", mock.Anything).Return(nil)
+
+		response, err := useCase.Execute(CreatePayoutBatchCommand{
+			Currency:    "btc",
+			Withdrawals: []WithdrawalRequest{{DestinationAddress: testPayoutAddress, Amount: 0.006}},
+			Actor:       "admin-1",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "OPEN", response.Status)
+		assert.Equal(t, 0.006, response.Total)
+		batchRepo.AssertExpectations(t)
+	})
+
+	t.Run("open batches reserve the balance", func(t *testing.T) {
+		ledgerRepo := new(MockLedgerRepository)
+		batchRepo := new(MockPayoutBatchRepository)
+		useCase := NewCreatePayoutBatchUseCase(ledgerRepo, batchRepo, domainPayment.DefaultCryptoRegistry())
+
+		open, _ := domainLedger.NewPayoutBatch("BTC", []domainLedger.Withdrawal{{DestinationAddress: testPayoutAddress, Amount: 0.006}}, "admin-1")
+		ledgerRepo.On("FindByCurrency", "BTC", mock.Anything).Return(ledgerWithReceipt(), nil)
+		batchRepo.On("FindOpenByCurrency", "BTC").Return([]*domainLedger.PayoutBatch{open}, nil)
+
+		_, err := useCase.Execute(CreatePayoutBatchCommand{
+			Currency:    "BTC",
+			Withdrawals: []WithdrawalRequest{{DestinationAddress: testPayoutAddress, Amount: 0.006}},
+			Actor:       "admin-1",
+		})
+
+		assert.Equal(t, domainLedger.ErrInsufficientBalance, err)
+		batchRepo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("destination must be an address of the coin", func(t *testing.T) {
+		useCase := NewCreatePayoutBatchUseCase(new(MockLedgerRepository), new(MockPayoutBatchRepository), domainPayment.DefaultCryptoRegistry())
+
+		_, err := useCase.Execute(CreatePayoutBatchCommand{
+			Currency:    "BTC",
+			Withdrawals: []WithdrawalRequest{{DestinationAddress: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", Amount: 0.001}},
+			Actor:       "admin-1",
+		})
+
+		assert.Equal(t, domainPayment.ErrInvalidWalletAddress, err)
+	})
+}
+
+// Tests for RecordWithdrawalSentUseCase
+
+func TestRecordWithdrawalSentUseCase(t *testing.T) {
+	t.Run("sent withdrawal is posted and completes the batch", func(t *testing.T) {
+		ledgerRepo := new(MockLedgerRepository)
+		batchRepo := new(MockPayoutBatchRepository)
+		useCase := NewRecordWithdrawalSentUseCase(ledgerRepo, batchRepo, domainPayment.DefaultCryptoRegistry())
+
+		batch, _ := domainLedger.NewPayoutBatch("BTC", []domainLedger.Withdrawal{{DestinationAddress: testPayoutAddress, Amount: 0.006}}, "admin-1")
+		reference := domainLedger.PayoutSentReference(batch.ID, 0)
+
+		batchRepo.On("FindByID", batch.ID).Return(batch, nil)
+		batchRepo.On("Update", batch).Return(nil)
+		ledgerRepo.On("ExistsByReference", reference).Return(false, nil)
+		ledgerRepo.On("Save", entryWithReference(reference)).Return(nil)
+
+		response, err := useCase.Execute(RecordWithdrawalSentCommand{BatchID: batch.ID, TransactionHash: testRefundTransaction, NetworkFee: 0.00002})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "COMPLETED", response.Status)
+		assert.Contains(t, response.Withdrawals[0].TransactionURL, testRefundTransaction)
+		ledgerRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid transaction hash", func(t *testing.T) {
+		batchRepo := new(MockPayoutBatchRepository)
+		useCase := NewRecordWithdrawalSentUseCase(new(MockLedgerRepository), batchRepo, domainPayment.DefaultCryptoRegistry())
+
+		batch, _ := domainLedger.NewPayoutBatch("BTC", []domainLedger.Withdrawal{{DestinationAddress: testPayoutAddress, Amount: 0.006}}, "admin-1")
+		batchRepo.On("FindByID", batch.ID).Return(batch, nil)
+
+		_, err := useCase.Execute(RecordWithdrawalSentCommand{BatchID: batch.ID, TransactionHash: "not-a-hash"})
+
+		assert.Equal(t, domainPayment.ErrInvalidTransactionHash, err)
+		assert.Equal(t, domainLedger.PayoutBatchOpen, batch.Status)
+	})
+
+	t.Run("batch not found", func(t *testing.T) {
+		batchRepo := new(MockPayoutBatchRepository)
+		useCase := NewRecordWithdrawalSentUseCase(new(MockLedgerRepository), batchRepo, domainPayment.DefaultCryptoRegistry())
+
+		batchRepo.On("FindByID", "missing").Return(nil, nil)
+
+		_, err := useCase.Execute(RecordWithdrawalSentCommand{BatchID: "missing", TransactionHash: testRefundTransaction})
+
+		assert.Equal(t, domainLedger.ErrPayoutBatchNotFound, err)
+	})
+}
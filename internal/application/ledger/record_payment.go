@@ -0,0 +1,125 @@
+package ledger
+
+import (
+	"time"
+
+	domainLedger "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/ledger"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// LedgerRepository defines the interface for the append-only journal.
+// References are unique; entries are never updated or deleted.
+type LedgerRepository interface {
+	Save(entry *domainLedger.Entry) error
+	ExistsByReference(reference string) (bool, error)
+	FindByCurrency(currency string, before time.Time) ([]*domainLedger.Entry, error)
+}
+
+// RecordPaymentUseCase posts the entries a payment's confirmation and refunds
+// call for. Entries already posted are skipped, so it can run after every
+// change to the payment.
+type RecordPaymentUseCase struct {
+	ledgerRepo LedgerRepository
+}
+
+// NewRecordPaymentUseCase creates a new instance of RecordPaymentUseCase
+func NewRecordPaymentUseCase(ledgerRepo LedgerRepository) *RecordPaymentUseCase {
+	return &RecordPaymentUseCase{
+		ledgerRepo: ledgerRepo,
+	}
+}
+
+// Execute posts the payment's missing entries, oldest event first
+func (uc *RecordPaymentUseCase) Execute(p *domainPayment.Payment) error {
+	entries, err := paymentEntries(p)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := post(uc.ledgerRepo, entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// paymentEntries builds every entry of a payment's current state: the receipt
+// once it was confirmed, its fees as first reported and each later change to
+// them, and an issued and a sent entry per refund. Refunds net of fees only return their net amount; the
+// fees kept back stay in the customer receipts.
+func paymentEntries(p *domainPayment.Payment) ([]*domainLedger.Entry, error) {
+	if p.ConfirmedAt == nil {
+		return nil, nil
+	}
+
+	currency := p.GetCryptoSymbol()
+
+	receipt, err := domainLedger.NewPaymentReceivedEntry(p.ID, currency, p.CryptoAmount, *p.ConfirmedAt)
+	if err != nil {
+		return nil, err
+	}
+	entries := []*domainLedger.Entry{receipt}
+
+	if p.FeesRecordedAt != nil {
+		gatewayFee, networkFee := p.InitialFees()
+		if gatewayFee+networkFee > 0 {
+			fees, err := domainLedger.NewPaymentFeesEntry(p.ID, currency, gatewayFee, networkFee, notBefore(*p.FeesRecordedAt, *p.ConfirmedAt))
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, fees)
+		}
+	}
+
+	for i, adjustment := range p.FeeAdjustments {
+		adjusted, err := domainLedger.NewPaymentFeesAdjustedEntry(p.ID, i, currency, adjustment.GatewayFee, adjustment.NetworkFee, notBefore(adjustment.RecordedAt, *p.ConfirmedAt))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, adjusted)
+	}
+
+	for i, refund := range p.Refunds {
+		issued, err := domainLedger.NewRefundIssuedEntry(p.ID, i, currency, refund.NetAmount(), refund.RequestedAt)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, issued)
+
+		if !refund.IsSent() {
+			continue
+		}
+
+		sent, err := domainLedger.NewRefundSentEntry(p.ID, i, currency, refund.NetAmount(), refund.NetworkFee, *refund.SentAt)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, sent)
+	}
+
+	return entries, nil
+}
+
+// notBefore returns t, or earliest when t is before it
+func notBefore(t, earliest time.Time) time.Time {
+	if t.Before(earliest) {
+		return earliest
+	}
+	return t
+}
+
+// post saves an entry unless its reference was already posted
+func post(ledgerRepo LedgerRepository, entry *domainLedger.Entry) error {
+	exists, err := ledgerRepo.ExistsByReference(entry.Reference)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return nil
+	}
+
+	return ledgerRepo.Save(entry)
+}
@@ -0,0 +1,214 @@
+package ledger
+
+import (
+	"testing"
+	"time"
+
+	domainLedger "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/ledger"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLedgerRepository is a mock implementation of LedgerRepository
+type MockLedgerRepository struct {
+	mock.Mock
+}
+
+func (m *MockLedgerRepository) Save(entry *domainLedger.Entry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockLedgerRepository) ExistsByReference(reference string) (bool, error) {
+	args := m.Called(reference)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockLedgerRepository) FindByCurrency(currency string, before time.Time) ([]*domainLedger.Entry, error) {
+	args := m.Called(currency, before)
+	return args.Get(0).([]*domainLedger.Entry), args.Error(1)
+}
+
+const testRefundTransaction = "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16"
+
+// createConfirmedPayment creates a confirmed payment of 0.002 BTC
+func createConfirmedPayment() *domainPayment.Payment {
+	p, _ := domainPayment.NewPayment("order123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+	_ = p.UpdateCryptoAmount(0.002)
+	_ = p.MarkAsConfirmed()
+	return p
+}
+
+// entryWithReference matches the entry posted for a reference
+func entryWithReference(reference string) interface{} {
+	return mock.MatchedBy(func(e *domainLedger.Entry) bool { return e.Reference == reference })
+}
+
+// Tests for RecordPaymentUseCase
+
+func TestRecordPaymentUseCase(t *testing.T) {
+	t.Run("confirmed payment is received", func(t *testing.T) {
+		ledgerRepo := new(MockLedgerRepository)
+		useCase := NewRecordPaymentUseCase(ledgerRepo)
+
+		p := createConfirmedPayment()
+		reference := domainLedger.PaymentReceivedReference(p.ID)
+
+		ledgerRepo.On("ExistsByReference", reference).Return(false, nil)
+		ledgerRepo.On("Save", mock.MatchedBy(func(e *domainLedger.Entry) bool {
+			return e.Reference == reference && e.Currency == "BTC" && e.Amount(domainLedger.AccountMerchantBalance) == 0.002 &&
+				e.OccurredAt.Equal(*p.ConfirmedAt)
+		})).Return(nil)
+
+		err := useCase.Execute(p)
+
+		assert.NoError(t, err)
+		ledgerRepo.AssertExpectations(t)
+	})
+
+	t.Run("reported fees are posted apart from the receipt", func(t *testing.T) {
+		ledgerRepo := new(MockLedgerRepository)
+		useCase := NewRecordPaymentUseCase(ledgerRepo)
+
+		p := createConfirmedPayment()
+		_ = p.RecordFees(0.00001, 0.000005)
+		reference := domainLedger.PaymentFeesReference(p.ID)
+
+		ledgerRepo.On("ExistsByReference", domainLedger.PaymentReceivedReference(p.ID)).Return(true, nil)
+		ledgerRepo.On("ExistsByReference", reference).Return(false, nil)
+		ledgerRepo.On("Save", mock.MatchedBy(func(e *domainLedger.Entry) bool {
+			return e.Reference == reference && e.Amount(domainLedger.AccountGatewayFees) == 0.00001 &&
+				e.Amount(domainLedger.AccountNetworkFees) == 0.000005
+		})).Return(nil)
+
+		err := useCase.Execute(p)
+
+		assert.NoError(t, err)
+		ledgerRepo.AssertExpectations(t)
+	})
+
+	t.Run("later fee report posts an adjustment", func(t *testing.T) {
+		ledgerRepo := new(MockLedgerRepository)
+		useCase := NewRecordPaymentUseCase(ledgerRepo)
+
+		p := createConfirmedPayment()
+		_ = p.RecordFees(0.00001, 0.000005)
+		_ = p.RecordFees(0.00001, 0.000012)
+		reference := domainLedger.PaymentFeesAdjustedReference(p.ID, 0)
+
+		ledgerRepo.On("ExistsByReference", reference).Return(false, nil)
+		ledgerRepo.On("ExistsByReference", mock.Anything).Return(true, nil)
+		ledgerRepo.On("Save", mock.MatchedBy(func(e *domainLedger.Entry) bool {
+			return e.Reference == reference && e.Amount(domainLedger.AccountGatewayFees) == 0 &&
+				e.Amount(domainLedger.AccountNetworkFees) > 0.0000069 && e.Amount(domainLedger.AccountNetworkFees) < 0.0000071
+		})).Return(nil)
+
+		err := useCase.Execute(p)
+
+		assert.NoError(t, err)
+		ledgerRepo.AssertExpectations(t)
+	})
+
+	t.Run("fees first posted as reported before the adjustment", func(t *testing.T) {
+		ledgerRepo := new(MockLedgerRepository)
+		useCase := NewRecordPaymentUseCase(ledgerRepo)
+
+		p := createConfirmedPayment()
+		_ = p.RecordFees(0.00001, 0.000005)
+		_ = p.RecordFees(0.00001, 0.000012)
+		reference := domainLedger.PaymentFeesReference(p.ID)
+
+		ledgerRepo.On("ExistsByReference", reference).Return(false, nil)
+		ledgerRepo.On("ExistsByReference", mock.Anything).Return(true, nil)
+		ledgerRepo.On("Save", mock.MatchedBy(func(e *domainLedger.Entry) bool {
+			return e.Reference == reference && e.Amount(domainLedger.AccountNetworkFees) > 0.0000049 && e.Amount(domainLedger.AccountNetworkFees) < 0.0000051
+		})).Return(nil)
+
+		err := useCase.Execute(p)
+
+		assert.NoError(t, err)
+		ledgerRepo.AssertExpectations(t)
+	})
+
+	t.Run("refund net of fees posts its net amount", func(t *testing.T) {
+		ledgerRepo := new(MockLedgerRepository)
+		useCase := NewRecordPaymentUseCase(ledgerRepo)
+
+		p := createConfirmedPayment()
+		_ = p.RecordFees(0.00002, 0)
+		_ = p.PartialRefundNetOfFees(0.001)
+		reference := domainLedger.RefundIssuedReference(p.ID, 0)
+
+		ledgerRepo.On("ExistsByReference", reference).Return(false, nil)
+		ledgerRepo.On("ExistsByReference", mock.Anything).Return(true, nil)
+		ledgerRepo.On("Save", mock.MatchedBy(func(e *domainLedger.Entry) bool {
+			return e.Reference == reference && e.Amount(domainLedger.AccountRefundsPayable) == 0.00099
+		})).Return(nil)
+
+		err := useCase.Execute(p)
+
+		assert.NoError(t, err)
+		ledgerRepo.AssertExpectations(t)
+	})
+
+	t.Run("sent refund posts only what is missing", func(t *testing.T) {
+		ledgerRepo := new(MockLedgerRepository)
+		useCase := NewRecordPaymentUseCase(ledgerRepo)
+
+		p := createConfirmedPayment()
+		_ = p.PartialRefund(0.0005)
+		_ = p.RecordRefundSent(testRefundTransaction, 0.00002)
+
+		ledgerRepo.On("ExistsByReference", domainLedger.PaymentReceivedReference(p.ID)).Return(true, nil)
+		ledgerRepo.On("ExistsByReference", domainLedger.RefundIssuedReference(p.ID, 0)).Return(true, nil)
+		ledgerRepo.On("ExistsByReference", domainLedger.RefundSentReference(p.ID, 0)).Return(false, nil)
+		ledgerRepo.On("Save", entryWithReference(domainLedger.RefundSentReference(p.ID, 0))).Return(nil)
+
+		err := useCase.Execute(p)
+
+		assert.NoError(t, err)
+		ledgerRepo.AssertNumberOfCalls(t, "Save", 1)
+	})
+
+	t.Run("unconfirmed payment posts nothing", func(t *testing.T) {
+		ledgerRepo := new(MockLedgerRepository)
+		useCase := NewRecordPaymentUseCase(ledgerRepo)
+
+		p, _ := domainPayment.NewPayment("order123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+
+		err := useCase.Execute(p)
+
+		assert.NoError(t, err)
+		ledgerRepo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+}
+
+// Tests for GetBalanceUseCase
+
+func TestGetBalanceUseCase(t *testing.T) {
+	t.Run("balance at the end of the day", func(t *testing.T) {
+		ledgerRepo := new(MockLedgerRepository)
+		useCase := NewGetBalanceUseCase(ledgerRepo)
+
+		endOfDay := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+		receipt, _ := domainLedger.NewPaymentReceivedEntry("pay-1", "BTC", 0.002, endOfDay.Add(-time.Hour))
+		ledgerRepo.On("FindByCurrency", "BTC", endOfDay).Return([]*domainLedger.Entry{receipt}, nil)
+
+		response, err := useCase.Execute(GetBalanceCommand{Currency: "BTC", AsOf: "2026-03-01"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2026-03-02T00:00:00Z", response.AsOf)
+		assert.Equal(t, 0.002, response.MerchantBalance)
+		assert.Equal(t, 0.002, response.Accounts["CUSTOMER_RECEIPTS"])
+		assert.Equal(t, 1, response.Entries)
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		useCase := NewGetBalanceUseCase(new(MockLedgerRepository))
+
+		_, err := useCase.Execute(GetBalanceCommand{Currency: "BTC", AsOf: "01/03/2026"})
+
+		assert.Equal(t, domainLedger.ErrInvalidBalanceDate, err)
+	})
+}
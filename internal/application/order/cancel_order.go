@@ -0,0 +1,328 @@
+package order
+
+import (
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	domainProduct "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/product"
+	"github.com/google/uuid"
+)
+
+// CancelOrderCommand represents the input for cancelling an order
+type CancelOrderCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	OrderID string `json:"order_id" validate:"required"`
+	Actor   string `json:"actor" validate:"required"`
+	Reason  string `json:"reason,omitempty"`
+
+	// RefundAmount optionally limits the refund of a paid order (in crypto).
+	// Zero refunds the full payment.
+	RefundAmount float64 `json:"refund_amount,omitempty"`
+
+	// RefundNetOfFees keeps back the payment's share of gateway and network fees
+	RefundNetOfFees bool `json:"refund_net_of_fees,omitempty"`
+}
+
+// CancelOrderResponse represents the output after cancelling an order
+type CancelOrderResponse struct {
+	OrderID        string  `json:"order_id"`
+	Status         string  `json:"status"`
+	PaymentID      string  `json:"payment_id,omitempty"`
+	PaymentStatus  string  `json:"payment_status,omitempty"`
+	RefundedAmount float64 `json:"refunded_amount,omitempty"`
+	RefundPending  bool    `json:"refund_pending"`
+}
+
+// OrderRepository defines the interface for order persistence
+type OrderRepository interface {
+	Save(order *domainOrder.Order) error
+	FindByID(id uuid.UUID) (*domainOrder.Order, error)
+	Update(order *domainOrder.Order) error
+}
+
+// PaymentRepository defines the interface for payment persistence used by order use cases
+type PaymentRepository interface {
+	FindByID(id string) (*domainPayment.Payment, error)
+	FindByOrderID(orderID string) ([]*domainPayment.Payment, error)
+	Update(payment *domainPayment.Payment) error
+}
+
+// PaymentLedger posts a payment's refunds to the merchant ledger
+type PaymentLedger interface {
+	RecordPayment(p *domainPayment.Payment) error
+}
+
+// ProductRepository defines the interface for product persistence used by order use cases
+type ProductRepository interface {
+	FindByID(id uuid.UUID) (*domainProduct.Product, error)
+	Update(product *domainProduct.Product) error
+}
+
+// CancelOrderUseCase handles order cancellation, refunding paid orders
+type CancelOrderUseCase struct {
+	orderRepo   OrderRepository
+	paymentRepo PaymentRepository
+	productRepo ProductRepository
+	ledger      PaymentLedger // nil disables ledger postings
+}
+
+// NewCancelOrderUseCase creates a new instance of CancelOrderUseCase
+func NewCancelOrderUseCase(orderRepo OrderRepository, paymentRepo PaymentRepository, productRepo ProductRepository, ledger PaymentLedger) *CancelOrderUseCase {
+	return &CancelOrderUseCase{
+		orderRepo:   orderRepo,
+		paymentRepo: paymentRepo,
+		productRepo: productRepo,
+		ledger:      ledger,
+	}
+}
+
+// Execute cancels an order.
+// Unpaid orders, and held orders whose payment failed or expired, are
+// cancelled immediately, closing any payment still open for them. Paid orders
+// get a refund on their payment and stay in CANCELLATION_PENDING_REFUND until
+// the refund is sent. The order's payments are looked up by order, so a
+// payment confirmed before the order was marked paid is refunded too.
+func (uc *CancelOrderUseCase) Execute(cmd CancelOrderCommand) (*CancelOrderResponse, error) {
+	existingOrder, err := findOrder(uc.orderRepo, cmd.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case existingOrder.Status.IsFulfilled():
+		return nil, domainOrder.ErrCannotCancelFulfilledOrder
+	case existingOrder.Status != domainOrder.StatusCreated &&
+		existingOrder.Status != domainOrder.StatusPendingPayment &&
+		existingOrder.Status != domainOrder.StatusPaid &&
+		!existingOrder.IsOnHold():
+		return nil, domainOrder.ErrInvalidStatusTransition
+	}
+
+	payments, err := uc.paymentRepo.FindByOrderID(existingOrder.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// A held order can only be cancelled once an admin failed or expired its payment
+	if existingOrder.IsOnHold() && !holdResolved(payments) {
+		return nil, domainOrder.ErrInvalidStatusTransition
+	}
+
+	// A payment confirmed before the order was marked paid still pays for it
+	if existingOrder.Status == domainOrder.StatusPendingPayment {
+		for _, p := range payments {
+			if p.CanBeRefunded() {
+				if err := existingOrder.MarkAsPaid(p.ID); err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
+	}
+
+	// Release the stock reserved for the order, if any
+	products, err := uc.releaseStock(existingOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	var linkedPayment *domainPayment.Payment
+	refundPending := false
+	if existingOrder.Status == domainOrder.StatusPaid {
+		// Paid orders must refund the customer
+		linkedPayment = paymentByID(payments, *existingOrder.PaymentID)
+		if linkedPayment == nil {
+			return nil, domainPayment.ErrPaymentNotFound
+		}
+
+		switch {
+		case cmd.RefundAmount > 0 && cmd.RefundNetOfFees:
+			err = linkedPayment.PartialRefundNetOfFees(cmd.RefundAmount)
+		case cmd.RefundAmount > 0:
+			err = linkedPayment.PartialRefund(cmd.RefundAmount)
+		case cmd.RefundNetOfFees:
+			err = linkedPayment.RefundNetOfFees()
+		default:
+			err = linkedPayment.Refund()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err := existingOrder.RequestCancellation(cmd.Actor, cmd.Reason); err != nil {
+			return nil, err
+		}
+		refundPending = true
+	} else {
+		if err := existingOrder.TransitionTo(domainOrder.StatusCancelled, cmd.Actor, cmd.Reason); err != nil {
+			return nil, err
+		}
+	}
+
+	// Cancel the payments still open, and refund in full any other payment
+	// confirmed for the order (e.g. the customer paid twice)
+	changed, err := closeOtherPayments(payments, linkedPayment)
+	if err != nil {
+		return nil, err
+	}
+	if linkedPayment != nil {
+		changed = append([]*domainPayment.Payment{linkedPayment}, changed...)
+	}
+
+	// Save updated aggregates
+	for _, p := range products {
+		if err := uc.productRepo.Update(p); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, p := range changed {
+		if err := uc.paymentRepo.Update(p); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.orderRepo.Update(existingOrder); err != nil {
+		return nil, err
+	}
+
+	// The refunds are owed from now on
+	for _, p := range changed {
+		if p.RefundedAmount > 0 {
+			if err := recordInLedger(uc.ledger, p); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Return response
+	response := &CancelOrderResponse{
+		OrderID:       existingOrder.ID.String(),
+		Status:        string(existingOrder.Status),
+		RefundPending: refundPending,
+	}
+	if len(changed) > 0 {
+		response.PaymentID = changed[0].ID
+		response.PaymentStatus = string(changed[0].Status)
+		response.RefundedAmount = changed[0].RefundedAmount
+	}
+
+	return response, nil
+}
+
+// holdResolved checks that no payment of a held order is still under review
+// or was confirmed again
+func holdResolved(payments []*domainPayment.Payment) bool {
+	for _, p := range payments {
+		if p.IsUnderReview() || p.IsCompleted() {
+			return false
+		}
+	}
+	return len(payments) > 0
+}
+
+// closeOtherPayments cancels the open payments and fully refunds the confirmed
+// ones, other than the linked payment. They are only changed in memory; the
+// caller saves them.
+func closeOtherPayments(payments []*domainPayment.Payment, linkedPayment *domainPayment.Payment) ([]*domainPayment.Payment, error) {
+	var changed []*domainPayment.Payment
+
+	for _, p := range payments {
+		var err error
+		switch {
+		case p == linkedPayment:
+			continue
+		case p.CanBeRefunded():
+			err = p.Refund()
+		case p.CanBeCancelled():
+			err = p.Cancel()
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		changed = append(changed, p)
+	}
+
+	return changed, nil
+}
+
+// paymentByID returns the payment with the given ID, or nil
+func paymentByID(payments []*domainPayment.Payment, paymentID string) *domainPayment.Payment {
+	for _, p := range payments {
+		if p.ID == paymentID {
+			return p
+		}
+	}
+	return nil
+}
+
+// releaseStock releases the reserved quantity of every order line. Orders
+// without a reservation release nothing. Products and the order are only
+// changed in memory; the caller saves them.
+func (uc *CancelOrderUseCase) releaseStock(o *domainOrder.Order) ([]*domainProduct.Product, error) {
+	if !o.StockReserved {
+		return nil, nil
+	}
+
+	products := make([]*domainProduct.Product, 0, len(o.Items))
+
+	for _, item := range o.Items {
+		p, err := findProduct(uc.productRepo, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.ReleaseStock(item.Quantity); err != nil {
+			return nil, err
+		}
+
+		products = append(products, p)
+	}
+
+	o.ClearStockReservation()
+	return products, nil
+}
+
+// findOrder parses the order ID and loads the order
+func findOrder(orderRepo OrderRepository, orderID string) (*domainOrder.Order, error) {
+	id, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, domainOrder.ErrInvalidOrderID
+	}
+
+	existingOrder, err := orderRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingOrder == nil {
+		return nil, domainOrder.ErrOrderNotFound
+	}
+
+	return existingOrder, nil
+}
+
+// findPayment loads a payment by ID
+func findPayment(paymentRepo PaymentRepository, paymentID string) (*domainPayment.Payment, error) {
+	existingPayment, err := paymentRepo.FindByID(paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingPayment == nil {
+		return nil, domainPayment.ErrPaymentNotFound
+	}
+
+	return existingPayment, nil
+}
+
+// recordInLedger posts the payment to the ledger, when one is configured
+func recordInLedger(ledger PaymentLedger, p *domainPayment.Payment) error {
+	if ledger == nil {
+		return nil
+	}
+	return ledger.RecordPayment(p)
+}
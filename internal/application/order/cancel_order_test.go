@@ -0,0 +1,451 @@
+package order
+
+import (
+	"errors"
+	"testing"
+
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	domainProduct "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/product"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOrderRepository is a mock implementation of OrderRepository
+type MockOrderRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderRepository) Save(order *domainOrder.Order) error {
+	args := m.Called(order)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) FindByID(id uuid.UUID) (*domainOrder.Order, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainOrder.Order), args.Error(1)
+}
+
+func (m *MockOrderRepository) Update(order *domainOrder.Order) error {
+	args := m.Called(order)
+	return args.Error(0)
+}
+
+// MockPaymentRepository is a mock implementation of PaymentRepository
+type MockPaymentRepository struct {
+	mock.Mock
+}
+
+func (m *MockPaymentRepository) FindByID(id string) (*domainPayment.Payment, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainPayment.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepository) FindByOrderID(orderID string) ([]*domainPayment.Payment, error) {
+	args := m.Called(orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domainPayment.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepository) Update(payment *domainPayment.Payment) error {
+	args := m.Called(payment)
+	return args.Error(0)
+}
+
+// MockProductRepository is a mock implementation of ProductRepository
+type MockProductRepository struct {
+	mock.Mock
+}
+
+func (m *MockProductRepository) FindByID(id uuid.UUID) (*domainProduct.Product, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainProduct.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) Update(product *domainProduct.Product) error {
+	args := m.Called(product)
+	return args.Error(0)
+}
+
+// MockPaymentLedger is a mock implementation of PaymentLedger
+type MockPaymentLedger struct {
+	mock.Mock
+}
+
+func (m *MockPaymentLedger) RecordPayment(p *domainPayment.Payment) error {
+	args := m.Called(p)
+	return args.Error(0)
+}
+
+// Test helper functions
+
+const testWalletAddress = "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh"
+
+// createTestProduct creates an active product with 2 units reserved
+func createTestProduct() *domainProduct.Product {
+	price, _ := domainOrder.NewMoney(10.0, "USD")
+	category, _ := domainProduct.NewCategory("Electronics", "", nil)
+	inventory, _ := domainProduct.NewInventory(10, 0, 1)
+	p, _ := domainProduct.NewProduct("Headphones", "Wireless", "HP-001", price, category, inventory)
+	_ = p.Activate()
+	_ = p.ReserveStock(2)
+	return p
+}
+
+// createTestOrderFor creates an order with 2 units of the given product, reserved at checkout
+func createTestOrderFor(p *domainProduct.Product) *domainOrder.Order {
+	item, _ := domainOrder.NewOrderItemWithSnapshot(p.ID, p.Name, p.SKU, 2, p.Price)
+	o, _ := domainOrder.NewOrder("customer123", []domainOrder.OrderItem{item})
+	o.MarkStockReserved()
+	return o
+}
+
+// createConfirmedPayment creates a confirmed payment for the order and marks the order as paid
+func createConfirmedPayment(o *domainOrder.Order) *domainPayment.Payment {
+	payment, _ := domainPayment.NewPayment(o.ID.String(), o.TotalAmount.Amount, o.TotalAmount.Currency, "BTC", testWalletAddress, 30)
+	_ = payment.UpdateCryptoAmount(0.001)
+	_ = payment.MarkAsConfirmed()
+	_ = o.MarkAsPaid(payment.ID)
+	return payment
+}
+
+// Tests for CancelOrderUseCase
+
+func TestCancelOrderUseCase(t *testing.T) {
+	t.Run("cancel unpaid order releases stock", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		paymentRepo := new(MockPaymentRepository)
+		productRepo := new(MockProductRepository)
+		useCase := NewCancelOrderUseCase(orderRepo, paymentRepo, productRepo, nil)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		paymentRepo.On("FindByOrderID", o.ID.String()).Return(nil, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		productRepo.On("Update", p).Return(nil)
+		orderRepo.On("Update", o).Return(nil)
+
+		response, err := useCase.Execute(CancelOrderCommand{OrderID: o.ID.String(), Actor: "customer123"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, string(domainOrder.StatusCancelled), response.Status)
+		assert.False(t, response.RefundPending)
+		assert.Equal(t, 0, p.GetReservedQuantity())
+		assert.False(t, o.StockReserved)
+		assert.Equal(t, "customer123", o.StatusHistory[len(o.StatusHistory)-1].Actor)
+
+		orderRepo.AssertExpectations(t)
+		productRepo.AssertExpectations(t)
+		paymentRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("cancel order without a reservation releases nothing", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		paymentRepo := new(MockPaymentRepository)
+		productRepo := new(MockProductRepository)
+		useCase := NewCancelOrderUseCase(orderRepo, paymentRepo, productRepo, nil)
+
+		p := createTestProduct()
+		item, _ := domainOrder.NewOrderItemWithSnapshot(p.ID, p.Name, p.SKU, 2, p.Price)
+		o, _ := domainOrder.NewOrder("customer123", []domainOrder.OrderItem{item})
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		paymentRepo.On("FindByOrderID", o.ID.String()).Return(nil, nil)
+		orderRepo.On("Update", o).Return(nil)
+
+		response, err := useCase.Execute(CancelOrderCommand{OrderID: o.ID.String(), Actor: "customer123"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, string(domainOrder.StatusCancelled), response.Status)
+		assert.Equal(t, 2, p.GetReservedQuantity()) // Another order's reservation is untouched
+		productRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+		productRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("cancel paid order refunds payment and waits for refund", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		paymentRepo := new(MockPaymentRepository)
+		productRepo := new(MockProductRepository)
+		ledger := new(MockPaymentLedger)
+		useCase := NewCancelOrderUseCase(orderRepo, paymentRepo, productRepo, ledger)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+		payment := createConfirmedPayment(o)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		paymentRepo.On("FindByOrderID", o.ID.String()).Return([]*domainPayment.Payment{payment}, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		productRepo.On("Update", p).Return(nil)
+		paymentRepo.On("Update", payment).Return(nil)
+		orderRepo.On("Update", o).Return(nil)
+		ledger.On("RecordPayment", payment).Return(nil)
+
+		response, err := useCase.Execute(CancelOrderCommand{OrderID: o.ID.String(), Actor: "customer123", Reason: "changed my mind"})
+
+		assert.NoError(t, err)
+		assert.True(t, response.RefundPending)
+		assert.Equal(t, string(domainOrder.StatusCancellationPendingRefund), response.Status)
+		assert.Equal(t, string(domainPayment.StatusRefunded), response.PaymentStatus)
+		assert.Equal(t, 0.001, response.RefundedAmount)
+		assert.Equal(t, 0, p.GetReservedQuantity())
+
+		orderRepo.AssertExpectations(t)
+		paymentRepo.AssertExpectations(t)
+		productRepo.AssertExpectations(t)
+		ledger.AssertExpectations(t)
+	})
+
+	t.Run("cancel awaiting order cancels its open payment", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		paymentRepo := new(MockPaymentRepository)
+		productRepo := new(MockProductRepository)
+		useCase := NewCancelOrderUseCase(orderRepo, paymentRepo, productRepo, nil)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+		_ = o.MarkAsPendingPayment()
+		payment, _ := domainPayment.NewPayment(o.ID.String(), o.TotalAmount.Amount, o.TotalAmount.Currency, "BTC", testWalletAddress, 30)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		paymentRepo.On("FindByOrderID", o.ID.String()).Return([]*domainPayment.Payment{payment}, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		productRepo.On("Update", p).Return(nil)
+		paymentRepo.On("Update", payment).Return(nil)
+		orderRepo.On("Update", o).Return(nil)
+
+		response, err := useCase.Execute(CancelOrderCommand{OrderID: o.ID.String(), Actor: "customer123"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, string(domainOrder.StatusCancelled), response.Status)
+		assert.Equal(t, string(domainPayment.StatusCancelled), response.PaymentStatus)
+		paymentRepo.AssertExpectations(t)
+	})
+
+	t.Run("payment confirmed before the order was marked paid is refunded", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		paymentRepo := new(MockPaymentRepository)
+		productRepo := new(MockProductRepository)
+		ledger := new(MockPaymentLedger)
+		useCase := NewCancelOrderUseCase(orderRepo, paymentRepo, productRepo, ledger)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+		_ = o.MarkAsPendingPayment()
+		confirmed, _ := domainPayment.NewPayment(o.ID.String(), o.TotalAmount.Amount, o.TotalAmount.Currency, "BTC", testWalletAddress, 30)
+		_ = confirmed.UpdateCryptoAmount(0.001)
+		_ = confirmed.MarkAsConfirmed()
+		open, _ := domainPayment.NewPayment(o.ID.String(), o.TotalAmount.Amount, o.TotalAmount.Currency, "BTC", testWalletAddress, 30)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		paymentRepo.On("FindByOrderID", o.ID.String()).Return([]*domainPayment.Payment{open, confirmed}, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		productRepo.On("Update", p).Return(nil)
+		paymentRepo.On("Update", mock.Anything).Return(nil)
+		orderRepo.On("Update", o).Return(nil)
+		ledger.On("RecordPayment", confirmed).Return(nil)
+
+		response, err := useCase.Execute(CancelOrderCommand{OrderID: o.ID.String(), Actor: "admin-1"})
+
+		assert.NoError(t, err)
+		assert.True(t, response.RefundPending)
+		assert.Equal(t, confirmed.ID, response.PaymentID)
+		assert.Equal(t, string(domainPayment.StatusRefunded), response.PaymentStatus)
+		assert.Equal(t, confirmed.ID, *o.PaymentID)
+		assert.Equal(t, domainPayment.StatusCancelled, open.Status)
+		paymentRepo.AssertNumberOfCalls(t, "Update", 2)
+		ledger.AssertExpectations(t)
+	})
+
+	t.Run("cancel paid order with partial refund", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		paymentRepo := new(MockPaymentRepository)
+		productRepo := new(MockProductRepository)
+		useCase := NewCancelOrderUseCase(orderRepo, paymentRepo, productRepo, nil)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+		payment := createConfirmedPayment(o)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		paymentRepo.On("FindByOrderID", o.ID.String()).Return([]*domainPayment.Payment{payment}, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		productRepo.On("Update", p).Return(nil)
+		paymentRepo.On("Update", payment).Return(nil)
+		orderRepo.On("Update", o).Return(nil)
+
+		response, err := useCase.Execute(CancelOrderCommand{OrderID: o.ID.String(), Actor: "admin-1", RefundAmount: 0.0009})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0.0009, response.RefundedAmount)
+		assert.Equal(t, string(domainPayment.StatusConfirmed), response.PaymentStatus)
+		assert.Equal(t, string(domainOrder.StatusCancellationPendingRefund), response.Status)
+	})
+
+	t.Run("cancel paid order with refund net of fees", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		paymentRepo := new(MockPaymentRepository)
+		productRepo := new(MockProductRepository)
+		useCase := NewCancelOrderUseCase(orderRepo, paymentRepo, productRepo, nil)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+		payment := createConfirmedPayment(o)
+		_ = payment.RecordFees(payment.CryptoAmount*0.01, 0)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		paymentRepo.On("FindByOrderID", o.ID.String()).Return([]*domainPayment.Payment{payment}, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		productRepo.On("Update", p).Return(nil)
+		paymentRepo.On("Update", payment).Return(nil)
+		orderRepo.On("Update", o).Return(nil)
+
+		response, err := useCase.Execute(CancelOrderCommand{OrderID: o.ID.String(), Actor: "admin-1", RefundNetOfFees: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, string(domainPayment.StatusRefunded), response.PaymentStatus)
+		assert.InDelta(t, payment.CryptoAmount*0.99, payment.Refunds[0].NetAmount(), 1e-12)
+	})
+
+	t.Run("order not found", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		useCase := NewCancelOrderUseCase(orderRepo, new(MockPaymentRepository), new(MockProductRepository), nil)
+
+		id := uuid.New()
+		orderRepo.On("FindByID", id).Return(nil, nil)
+
+		response, err := useCase.Execute(CancelOrderCommand{OrderID: id.String(), Actor: "customer123"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainOrder.ErrOrderNotFound, err)
+	})
+
+	t.Run("invalid order ID", func(t *testing.T) {
+		useCase := NewCancelOrderUseCase(new(MockOrderRepository), new(MockPaymentRepository), new(MockProductRepository), nil)
+
+		response, err := useCase.Execute(CancelOrderCommand{OrderID: "not-a-uuid", Actor: "customer123"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainOrder.ErrInvalidOrderID, err)
+	})
+
+	t.Run("cannot cancel fulfilled order", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		useCase := NewCancelOrderUseCase(orderRepo, new(MockPaymentRepository), new(MockProductRepository), nil)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+		_ = createConfirmedPayment(o)
+		_ = o.MarkAsFulfilled()
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+
+		response, err := useCase.Execute(CancelOrderCommand{OrderID: o.ID.String(), Actor: "customer123"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainOrder.ErrCannotCancelFulfilledOrder, err)
+		orderRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("cancel held order once its payment failed", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		paymentRepo := new(MockPaymentRepository)
+		productRepo := new(MockProductRepository)
+		ledger := new(MockPaymentLedger)
+		useCase := NewCancelOrderUseCase(orderRepo, paymentRepo, productRepo, ledger)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+		payment := createConfirmedPayment(o)
+		_ = o.HoldForPaymentReview("CONFIRMATIONS_DROPPED")
+		payment.Status = domainPayment.StatusFailed
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		paymentRepo.On("FindByOrderID", o.ID.String()).Return([]*domainPayment.Payment{payment}, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		productRepo.On("Update", p).Return(nil)
+		paymentRepo.On("Update", payment).Return(nil)
+		orderRepo.On("Update", o).Return(nil)
+
+		response, err := useCase.Execute(CancelOrderCommand{OrderID: o.ID.String(), Actor: "admin-1", Reason: "payment reversed"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, string(domainOrder.StatusCancelled), response.Status)
+		assert.False(t, response.RefundPending)
+		assert.Equal(t, 0, p.GetReservedQuantity())
+		ledger.AssertNotCalled(t, "RecordPayment", mock.Anything)
+	})
+
+	t.Run("cannot cancel held order while its payment is under review", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		paymentRepo := new(MockPaymentRepository)
+		useCase := NewCancelOrderUseCase(orderRepo, paymentRepo, new(MockProductRepository), nil)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+		payment := createConfirmedPayment(o)
+		_ = o.HoldForPaymentReview("CONFIRMATIONS_DROPPED")
+		payment.Status = domainPayment.StatusUnderReview
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		paymentRepo.On("FindByOrderID", o.ID.String()).Return([]*domainPayment.Payment{payment}, nil)
+
+		response, err := useCase.Execute(CancelOrderCommand{OrderID: o.ID.String(), Actor: "admin-1"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainOrder.ErrInvalidStatusTransition, err)
+		orderRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("nothing is saved when refund fails", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		paymentRepo := new(MockPaymentRepository)
+		productRepo := new(MockProductRepository)
+		useCase := NewCancelOrderUseCase(orderRepo, paymentRepo, productRepo, nil)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+		payment := createConfirmedPayment(o)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		paymentRepo.On("FindByOrderID", o.ID.String()).Return([]*domainPayment.Payment{payment}, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+
+		response, err := useCase.Execute(CancelOrderCommand{OrderID: o.ID.String(), Actor: "admin-1", RefundAmount: 1.0})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainPayment.ErrRefundAmountExceedsPayment, err)
+		assert.Equal(t, domainOrder.StatusPaid, o.Status)
+		orderRepo.AssertNotCalled(t, "Update", mock.Anything)
+		paymentRepo.AssertNotCalled(t, "Update", mock.Anything)
+		productRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		useCase := NewCancelOrderUseCase(orderRepo, new(MockPaymentRepository), new(MockProductRepository), nil)
+
+		id := uuid.New()
+		expectedErr := errors.New("database error")
+		orderRepo.On("FindByID", id).Return(nil, expectedErr)
+
+		response, err := useCase.Execute(CancelOrderCommand{OrderID: id.String(), Actor: "customer123"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, expectedErr, err)
+	})
+}
@@ -0,0 +1,122 @@
+package order
+
+import "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/application/idempotency"
+
+// OrderService provides high-level order operations
+type OrderService struct {
+	orderRepo   OrderRepository
+	paymentRepo PaymentRepository
+	productRepo ProductRepository
+	returnRepo  ReturnRequestRepository
+	idempotency *idempotency.Guard // nil disables idempotency keys
+
+	// Use cases
+	cancelOrder              *CancelOrderUseCase
+	recordCancellationRefund *RecordCancellationRefundUseCase
+	requestReturn            *RequestReturnUseCase
+	approveReturn            *ApproveReturnUseCase
+	rejectReturn             *RejectReturnUseCase
+	receiveReturn            *ReceiveReturnUseCase
+	updateItemQuantity       *UpdateOrderItemQuantityUseCase
+	setItemNote              *SetOrderItemNoteUseCase
+	replaceItems             *ReplaceOrderItemsUseCase
+	repriceOrder             *RepriceOrderUseCase
+	confirmOrderPrices       *ConfirmOrderPricesUseCase
+}
+
+// NewOrderService creates a new instance of OrderService
+func NewOrderService(orderRepo OrderRepository, paymentRepo PaymentRepository, productRepo ProductRepository, returnRepo ReturnRequestRepository, notifier PriceChangeNotifier, repricingPolicy RepricingPolicy, ledger PaymentLedger, idempotencyGuard *idempotency.Guard) *OrderService {
+	return &OrderService{
+		orderRepo:                orderRepo,
+		idempotency:              idempotencyGuard,
+		paymentRepo:              paymentRepo,
+		productRepo:              productRepo,
+		returnRepo:               returnRepo,
+		cancelOrder:              NewCancelOrderUseCase(orderRepo, paymentRepo, productRepo, ledger),
+		recordCancellationRefund: NewRecordCancellationRefundUseCase(orderRepo, paymentRepo, ledger),
+		requestReturn:            NewRequestReturnUseCase(orderRepo, returnRepo),
+		approveReturn:            NewApproveReturnUseCase(returnRepo),
+		rejectReturn:             NewRejectReturnUseCase(returnRepo),
+		receiveReturn:            NewReceiveReturnUseCase(orderRepo, paymentRepo, productRepo, returnRepo, ledger),
+		updateItemQuantity:       NewUpdateOrderItemQuantityUseCase(orderRepo, productRepo),
+		setItemNote:              NewSetOrderItemNoteUseCase(orderRepo),
+		replaceItems:             NewReplaceOrderItemsUseCase(orderRepo, productRepo),
+		repriceOrder:             NewRepriceOrderUseCase(orderRepo, productRepo, notifier, repricingPolicy),
+		confirmOrderPrices:       NewConfirmOrderPricesUseCase(orderRepo, productRepo, notifier),
+	}
+}
+
+// CancelOrder cancels an order, refunding it if it was already paid
+func (s *OrderService) CancelOrder(cmd CancelOrderCommand) (*CancelOrderResponse, error) {
+	return idempotency.Run(s.idempotency, "order.cancel_order", cmd.IdempotencyKey, cmd, s.cancelOrder.Execute)
+}
+
+// RecordCancellationRefund records the refund of a cancelled paid order and completes the cancellation
+func (s *OrderService) RecordCancellationRefund(cmd RecordCancellationRefundCommand) (*RecordCancellationRefundResponse, error) {
+	return idempotency.Run(s.idempotency, "order.record_cancellation_refund", cmd.IdempotencyKey, cmd, s.recordCancellationRefund.Execute)
+}
+
+// UpdateItemQuantity sets the quantity of an order line (zero removes it)
+func (s *OrderService) UpdateItemQuantity(cmd UpdateOrderItemQuantityCommand) (*OrderResponse, error) {
+	return idempotency.Run(s.idempotency, "order.update_item_quantity", cmd.IdempotencyKey, cmd, s.updateItemQuantity.Execute)
+}
+
+// SetItemNote attaches a note to an order line
+func (s *OrderService) SetItemNote(cmd SetOrderItemNoteCommand) (*OrderResponse, error) {
+	return idempotency.Run(s.idempotency, "order.set_item_note", cmd.IdempotencyKey, cmd, s.setItemNote.Execute)
+}
+
+// ReplaceItems replaces all lines of an order atomically
+func (s *OrderService) ReplaceItems(cmd ReplaceOrderItemsCommand) (*OrderResponse, error) {
+	return idempotency.Run(s.idempotency, "order.replace_items", cmd.IdempotencyKey, cmd, s.replaceItems.Execute)
+}
+
+// RepriceOrder checks an order for stale prices before checkout
+func (s *OrderService) RepriceOrder(cmd RepriceOrderCommand) (*RepriceOrderResponse, error) {
+	return idempotency.Run(s.idempotency, "order.reprice_order", cmd.IdempotencyKey, cmd, s.repriceOrder.Execute)
+}
+
+// ConfirmOrderPrices applies current prices after the customer accepted the new total
+func (s *OrderService) ConfirmOrderPrices(cmd ConfirmOrderPricesCommand) (*RepriceOrderResponse, error) {
+	return idempotency.Run(s.idempotency, "order.confirm_order_prices", cmd.IdempotencyKey, cmd, s.confirmOrderPrices.Execute)
+}
+
+// RequestReturn opens a return request (RMA) for items of a fulfilled order
+func (s *OrderService) RequestReturn(cmd RequestReturnCommand) (*ReturnRequestResponse, error) {
+	return idempotency.Run(s.idempotency, "order.request_return", cmd.IdempotencyKey, cmd, s.requestReturn.Execute)
+}
+
+// ApproveReturn approves a return request
+func (s *OrderService) ApproveReturn(cmd ApproveReturnCommand) (*ReturnRequestResponse, error) {
+	return idempotency.Run(s.idempotency, "order.approve_return", cmd.IdempotencyKey, cmd, s.approveReturn.Execute)
+}
+
+// RejectReturn rejects a return request
+func (s *OrderService) RejectReturn(cmd RejectReturnCommand) (*ReturnRequestResponse, error) {
+	return idempotency.Run(s.idempotency, "order.reject_return", cmd.IdempotencyKey, cmd, s.rejectReturn.Execute)
+}
+
+// ReceiveReturn restocks returned goods and refunds the customer
+func (s *OrderService) ReceiveReturn(cmd ReceiveReturnCommand) (*ReturnRequestResponse, error) {
+	return idempotency.Run(s.idempotency, "order.receive_return", cmd.IdempotencyKey, cmd, s.receiveReturn.Execute)
+}
+
+// GetOrderReturns lists the return requests of an order
+func (s *OrderService) GetOrderReturns(orderID string) ([]*ReturnRequestResponse, error) {
+	existingOrder, err := findOrder(s.orderRepo, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	returns, err := s.returnRepo.FindByOrderID(existingOrder.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*ReturnRequestResponse, len(returns))
+	for i, r := range returns {
+		responses[i] = toReturnRequestResponse(r, existingOrder)
+	}
+
+	return responses, nil
+}
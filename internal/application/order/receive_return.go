@@ -0,0 +1,131 @@
+package order
+
+import (
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainProduct "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/product"
+)
+
+// refundRoundingTolerance absorbs float rounding when a prorated refund covers the whole remainder
+const refundRoundingTolerance = 1e-12
+
+// ReceiveReturnCommand represents the input for receiving returned goods
+type ReceiveReturnCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	ReturnID string `json:"return_id" validate:"required"`
+	Actor    string `json:"actor" validate:"required"`
+
+	// RefundNetOfFees keeps back the refund's share of gateway and network fees
+	RefundNetOfFees bool `json:"refund_net_of_fees,omitempty"`
+}
+
+// ReceiveReturnUseCase restocks returned goods and refunds the customer
+type ReceiveReturnUseCase struct {
+	orderRepo   OrderRepository
+	paymentRepo PaymentRepository
+	productRepo ProductRepository
+	returnRepo  ReturnRequestRepository
+	ledger      PaymentLedger // nil disables ledger postings
+}
+
+// NewReceiveReturnUseCase creates a new instance of ReceiveReturnUseCase
+func NewReceiveReturnUseCase(orderRepo OrderRepository, paymentRepo PaymentRepository, productRepo ProductRepository, returnRepo ReturnRequestRepository, ledger PaymentLedger) *ReceiveReturnUseCase {
+	return &ReceiveReturnUseCase{
+		orderRepo:   orderRepo,
+		paymentRepo: paymentRepo,
+		productRepo: productRepo,
+		returnRepo:  returnRepo,
+		ledger:      ledger,
+	}
+}
+
+// Execute marks the return as received, puts the goods back in stock and
+// issues a partial crypto refund prorated from the order's original pricing
+func (uc *ReceiveReturnUseCase) Execute(cmd ReceiveReturnCommand) (*ReturnRequestResponse, error) {
+	returnRequest, err := findReturnRequest(uc.returnRepo, cmd.ReturnID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := returnRequest.MarkAsReceived(); err != nil {
+		return nil, err
+	}
+
+	existingOrder, err := findOrder(uc.orderRepo, returnRequest.OrderID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if existingOrder.PaymentID == nil {
+		return nil, domainOrder.ErrEmptyPaymentID
+	}
+
+	linkedPayment, err := findPayment(uc.paymentRepo, *existingOrder.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Restock returned goods
+	products := make([]*domainProduct.Product, 0, len(returnRequest.Items))
+	for _, item := range returnRequest.Items {
+		p, err := findProduct(uc.productRepo, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.AddStock(item.Quantity); err != nil {
+			return nil, err
+		}
+
+		products = append(products, p)
+	}
+
+	// Refund the prorated crypto amount, never more than what is left
+	cryptoRefund := returnRequest.ProratedCryptoRefund(linkedPayment.Amount, linkedPayment.CryptoAmount)
+	remaining := linkedPayment.GetRemainingRefundableAmount()
+	if cryptoRefund > remaining-refundRoundingTolerance {
+		cryptoRefund = remaining
+	}
+
+	refund := linkedPayment.PartialRefund
+	if cmd.RefundNetOfFees {
+		refund = linkedPayment.PartialRefundNetOfFees
+	}
+
+	if err := refund(cryptoRefund); err != nil {
+		return nil, err
+	}
+
+	if err := returnRequest.MarkAsRefunded(cryptoRefund); err != nil {
+		return nil, err
+	}
+
+	if err := existingOrder.RecordReturn(returnRequest, cmd.Actor); err != nil {
+		return nil, err
+	}
+
+	// Save updated aggregates
+	for _, p := range products {
+		if err := uc.productRepo.Update(p); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.paymentRepo.Update(linkedPayment); err != nil {
+		return nil, err
+	}
+
+	if err := uc.returnRepo.Update(returnRequest); err != nil {
+		return nil, err
+	}
+
+	if err := uc.orderRepo.Update(existingOrder); err != nil {
+		return nil, err
+	}
+
+	if err := recordInLedger(uc.ledger, linkedPayment); err != nil {
+		return nil, err
+	}
+
+	return toReturnRequestResponse(returnRequest, existingOrder), nil
+}
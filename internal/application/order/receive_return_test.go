@@ -0,0 +1,105 @@
+package order
+
+import (
+	"testing"
+
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Tests for ReceiveReturnUseCase
+
+func TestReceiveReturnUseCase(t *testing.T) {
+	t.Run("receive restocks and issues prorated refund", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		paymentRepo := new(MockPaymentRepository)
+		productRepo := new(MockProductRepository)
+		returnRepo := new(MockReturnRequestRepository)
+		useCase := NewReceiveReturnUseCase(orderRepo, paymentRepo, productRepo, returnRepo, nil)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)           // 2 x 10 USD
+		payment := createConfirmedPayment(o) // 20 USD paid as 0.001 BTC
+		fulfillTestOrder(p, o)
+		rma, _ := domainOrder.NewReturnRequest(o, []domainOrder.ReturnItem{{ProductID: p.ID, Quantity: 1, Reason: "damaged"}}, nil)
+		_ = rma.Approve()
+		stockBefore := p.GetTotalQuantity()
+
+		returnRepo.On("FindByID", rma.ID).Return(rma, nil)
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		paymentRepo.On("FindByID", payment.ID).Return(payment, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		productRepo.On("Update", p).Return(nil)
+		paymentRepo.On("Update", payment).Return(nil)
+		returnRepo.On("Update", rma).Return(nil)
+		orderRepo.On("Update", o).Return(nil)
+
+		response, err := useCase.Execute(ReceiveReturnCommand{ReturnID: rma.ID.String(), Actor: "warehouse-1"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, string(domainOrder.ReturnStatusRefunded), response.Status)
+		assert.InDelta(t, 0.0005, response.CryptoRefundAmount, 1e-12)
+		assert.Equal(t, string(domainOrder.StatusPartiallyRefunded), response.OrderStatus)
+		assert.Equal(t, stockBefore+1, p.GetTotalQuantity())
+		assert.InDelta(t, 0.0005, payment.RefundedAmount, 1e-12)
+		assert.Equal(t, domainPayment.StatusConfirmed, payment.Status)
+
+		orderRepo.AssertExpectations(t)
+		paymentRepo.AssertExpectations(t)
+		productRepo.AssertExpectations(t)
+		returnRepo.AssertExpectations(t)
+	})
+
+	t.Run("returning everything refunds the full payment", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		paymentRepo := new(MockPaymentRepository)
+		productRepo := new(MockProductRepository)
+		returnRepo := new(MockReturnRequestRepository)
+		useCase := NewReceiveReturnUseCase(orderRepo, paymentRepo, productRepo, returnRepo, nil)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+		payment := createConfirmedPayment(o)
+		fulfillTestOrder(p, o)
+		rma, _ := domainOrder.NewReturnRequest(o, []domainOrder.ReturnItem{{ProductID: p.ID, Quantity: 2, Reason: "damaged"}}, nil)
+		_ = rma.Approve()
+
+		returnRepo.On("FindByID", rma.ID).Return(rma, nil)
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		paymentRepo.On("FindByID", payment.ID).Return(payment, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		productRepo.On("Update", p).Return(nil)
+		paymentRepo.On("Update", payment).Return(nil)
+		returnRepo.On("Update", rma).Return(nil)
+		orderRepo.On("Update", o).Return(nil)
+
+		response, err := useCase.Execute(ReceiveReturnCommand{ReturnID: rma.ID.String(), Actor: "warehouse-1"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, string(domainOrder.StatusRefunded), response.OrderStatus)
+		assert.Equal(t, domainPayment.StatusRefunded, payment.Status)
+		assert.Equal(t, 0.001, payment.RefundedAmount)
+	})
+
+	t.Run("return must be approved first", func(t *testing.T) {
+		returnRepo := new(MockReturnRequestRepository)
+		orderRepo := new(MockOrderRepository)
+		useCase := NewReceiveReturnUseCase(orderRepo, new(MockPaymentRepository), new(MockProductRepository), returnRepo, nil)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+		_ = createConfirmedPayment(o)
+		fulfillTestOrder(p, o)
+		rma, _ := domainOrder.NewReturnRequest(o, []domainOrder.ReturnItem{{ProductID: p.ID, Quantity: 1, Reason: "damaged"}}, nil)
+
+		returnRepo.On("FindByID", rma.ID).Return(rma, nil)
+
+		response, err := useCase.Execute(ReceiveReturnCommand{ReturnID: rma.ID.String(), Actor: "warehouse-1"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainOrder.ErrInvalidReturnTransition, err)
+		orderRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+	})
+}
@@ -0,0 +1,96 @@
+package order
+
+import (
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+)
+
+// RecordCancellationRefundCommand represents the input for recording a sent cancellation refund
+type RecordCancellationRefundCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	OrderID         string  `json:"order_id" validate:"required"`
+	TransactionHash string  `json:"transaction_hash" validate:"required"`
+	NetworkFee      float64 `json:"network_fee,omitempty" validate:"min=0"` // Crypto fee paid to send the refund
+	Actor           string  `json:"actor,omitempty"`
+}
+
+// RecordCancellationRefundResponse represents the output after recording the refund
+type RecordCancellationRefundResponse struct {
+	OrderID               string  `json:"order_id"`
+	Status                string  `json:"status"`
+	PaymentID             string  `json:"payment_id"`
+	RefundedAmount        float64 `json:"refunded_amount"`
+	RefundTransactionHash string  `json:"refund_transaction_hash"`
+	RefundTransactionURL  string  `json:"refund_transaction_url,omitempty"` // Block explorer link
+}
+
+// RecordCancellationRefundUseCase completes a cancellation once its refund is
+// on-chain and posts the sent refund to the ledger
+type RecordCancellationRefundUseCase struct {
+	orderRepo   OrderRepository
+	paymentRepo PaymentRepository
+	ledger      PaymentLedger // nil disables ledger postings
+}
+
+// NewRecordCancellationRefundUseCase creates a new instance of RecordCancellationRefundUseCase
+func NewRecordCancellationRefundUseCase(orderRepo OrderRepository, paymentRepo PaymentRepository, ledger PaymentLedger) *RecordCancellationRefundUseCase {
+	return &RecordCancellationRefundUseCase{
+		orderRepo:   orderRepo,
+		paymentRepo: paymentRepo,
+		ledger:      ledger,
+	}
+}
+
+// Execute records the refund transaction hash and moves the order to CANCELLED
+func (uc *RecordCancellationRefundUseCase) Execute(cmd RecordCancellationRefundCommand) (*RecordCancellationRefundResponse, error) {
+	existingOrder, err := findOrder(uc.orderRepo, cmd.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !existingOrder.IsAwaitingCancellationRefund() {
+		return nil, domainOrder.ErrInvalidStatusTransition
+	}
+
+	if existingOrder.PaymentID == nil {
+		return nil, domainOrder.ErrEmptyPaymentID
+	}
+
+	linkedPayment, err := findPayment(uc.paymentRepo, *existingOrder.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Record refund on the payment
+	if err := linkedPayment.RecordRefundSent(cmd.TransactionHash, cmd.NetworkFee); err != nil {
+		return nil, err
+	}
+
+	// Finish the cancellation
+	if err := existingOrder.CompleteCancellation(cmd.Actor); err != nil {
+		return nil, err
+	}
+
+	// Save updated aggregates
+	if err := uc.paymentRepo.Update(linkedPayment); err != nil {
+		return nil, err
+	}
+
+	if err := uc.orderRepo.Update(existingOrder); err != nil {
+		return nil, err
+	}
+
+	if err := recordInLedger(uc.ledger, linkedPayment); err != nil {
+		return nil, err
+	}
+
+	// Return response
+	return &RecordCancellationRefundResponse{
+		OrderID:               existingOrder.ID.String(),
+		Status:                string(existingOrder.Status),
+		PaymentID:             linkedPayment.ID,
+		RefundedAmount:        linkedPayment.RefundedAmount,
+		RefundTransactionHash: linkedPayment.RefundTransactionHash,
+		RefundTransactionURL:  linkedPayment.RefundTransactionURL(),
+	}, nil
+}
@@ -0,0 +1,112 @@
+package order
+
+import (
+	"testing"
+
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Tests for RecordCancellationRefundUseCase
+
+func TestRecordCancellationRefundUseCase(t *testing.T) {
+	t.Run("record refund completes cancellation", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		paymentRepo := new(MockPaymentRepository)
+		useCase := NewRecordCancellationRefundUseCase(orderRepo, paymentRepo, nil)
+
+		o := createTestOrderFor(createTestProduct())
+		payment := createConfirmedPayment(o)
+		_ = payment.Refund()
+		_ = o.RequestCancellation("customer123", "")
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		paymentRepo.On("FindByID", payment.ID).Return(payment, nil)
+		paymentRepo.On("Update", payment).Return(nil)
+		orderRepo.On("Update", o).Return(nil)
+
+		response, err := useCase.Execute(RecordCancellationRefundCommand{
+			OrderID:         o.ID.String(),
+			TransactionHash: "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16",
+			Actor:           "admin-1",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, string(domainOrder.StatusCancelled), response.Status)
+		assert.Equal(t, "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16", response.RefundTransactionHash)
+		assert.Contains(t, response.RefundTransactionURL, response.RefundTransactionHash)
+		assert.Equal(t, 0.001, response.RefundedAmount)
+
+		orderRepo.AssertExpectations(t)
+		paymentRepo.AssertExpectations(t)
+	})
+
+	t.Run("sent refund is posted to the ledger with its network fee", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		paymentRepo := new(MockPaymentRepository)
+		ledger := new(MockPaymentLedger)
+		useCase := NewRecordCancellationRefundUseCase(orderRepo, paymentRepo, ledger)
+
+		o := createTestOrderFor(createTestProduct())
+		payment := createConfirmedPayment(o)
+		_ = payment.Refund()
+		_ = o.RequestCancellation("customer123", "")
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		paymentRepo.On("FindByID", payment.ID).Return(payment, nil)
+		paymentRepo.On("Update", payment).Return(nil)
+		orderRepo.On("Update", o).Return(nil)
+		ledger.On("RecordPayment", mock.MatchedBy(func(p *domainPayment.Payment) bool {
+			return p.ID == payment.ID && p.Refunds[0].IsSent() && p.Refunds[0].NetworkFee == 0.00002
+		})).Return(nil)
+
+		_, err := useCase.Execute(RecordCancellationRefundCommand{
+			OrderID:         o.ID.String(),
+			TransactionHash: "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16",
+			NetworkFee:      0.00002,
+		})
+
+		assert.NoError(t, err)
+		ledger.AssertExpectations(t)
+	})
+
+	t.Run("order must be awaiting refund", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		paymentRepo := new(MockPaymentRepository)
+		useCase := NewRecordCancellationRefundUseCase(orderRepo, paymentRepo, nil)
+
+		o := createTestOrderFor(createTestProduct())
+		_ = createConfirmedPayment(o)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+
+		response, err := useCase.Execute(RecordCancellationRefundCommand{OrderID: o.ID.String(), TransactionHash: "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainOrder.ErrInvalidStatusTransition, err)
+		paymentRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+	})
+
+	t.Run("empty transaction hash keeps order pending", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		paymentRepo := new(MockPaymentRepository)
+		useCase := NewRecordCancellationRefundUseCase(orderRepo, paymentRepo, nil)
+
+		o := createTestOrderFor(createTestProduct())
+		payment := createConfirmedPayment(o)
+		_ = payment.Refund()
+		_ = o.RequestCancellation("customer123", "")
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		paymentRepo.On("FindByID", payment.ID).Return(payment, nil)
+
+		response, err := useCase.Execute(RecordCancellationRefundCommand{OrderID: o.ID.String()})
+
+		assert.Nil(t, response)
+		assert.Error(t, err)
+		assert.Equal(t, domainOrder.StatusCancellationPendingRefund, o.Status)
+		orderRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
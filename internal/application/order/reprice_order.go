@@ -0,0 +1,277 @@
+package order
+
+import (
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainProduct "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/product"
+	"github.com/google/uuid"
+)
+
+// RepricingPolicy decides what happens when product prices changed after items were added
+type RepricingPolicy string
+
+const (
+	RepricingPolicyAutoUpdate          RepricingPolicy = "AUTO_UPDATE"          // Apply current prices silently
+	RepricingPolicyRequireConfirmation RepricingPolicy = "REQUIRE_CONFIRMATION" // Customer must accept the new total
+)
+
+// IsValid checks if the repricing policy is supported
+func (p RepricingPolicy) IsValid() bool {
+	return p == RepricingPolicyAutoUpdate || p == RepricingPolicyRequireConfirmation
+}
+
+// PriceChangeNotifier delivers price-changed notifications to the customer
+type PriceChangeNotifier interface {
+	NotifyPriceChanged(notification PriceChangedNotification) error
+}
+
+// PriceChangedNotification is sent whenever stale prices are detected on an order
+type PriceChangedNotification struct {
+	OrderID    string                `json:"order_id"`
+	CustomerID string                `json:"customer_id"`
+	Changes    []PriceChangeResponse `json:"changes"`
+	OldTotal   float64               `json:"old_total"`
+	NewTotal   float64               `json:"new_total"`
+	Currency   string                `json:"currency"`
+	Applied    bool                  `json:"applied"` // false while confirmation is pending
+}
+
+// PriceChangeResponse represents a repriced order line
+type PriceChangeResponse struct {
+	ProductID    string  `json:"product_id"`
+	ProductName  string  `json:"product_name,omitempty"`
+	Quantity     int     `json:"quantity"`
+	OldUnitPrice float64 `json:"old_unit_price"`
+	NewUnitPrice float64 `json:"new_unit_price"`
+	Currency     string  `json:"currency"`
+}
+
+// RepriceOrderCommand represents the input for checking an order's prices before checkout
+type RepriceOrderCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	OrderID    string `json:"order_id" validate:"required"`
+	CustomerID string `json:"customer_id" validate:"required"`
+}
+
+// RepriceOrderResponse represents the outcome of a repricing check
+type RepriceOrderResponse struct {
+	Order                *OrderResponse        `json:"order"`
+	Changes              []PriceChangeResponse `json:"changes"`
+	OldTotal             float64               `json:"old_total"`
+	NewTotal             float64               `json:"new_total"`
+	Currency             string                `json:"currency"`
+	RequiresConfirmation bool                  `json:"requires_confirmation"`
+}
+
+// RepriceOrderUseCase detects stale prices before checkout and handles them per policy
+type RepriceOrderUseCase struct {
+	orderRepo   OrderRepository
+	productRepo ProductRepository
+	notifier    PriceChangeNotifier
+	policy      RepricingPolicy
+}
+
+// NewRepriceOrderUseCase creates a new instance of RepriceOrderUseCase.
+// An unknown policy falls back to requiring confirmation.
+func NewRepriceOrderUseCase(orderRepo OrderRepository, productRepo ProductRepository, notifier PriceChangeNotifier, policy RepricingPolicy) *RepriceOrderUseCase {
+	if !policy.IsValid() {
+		policy = RepricingPolicyRequireConfirmation
+	}
+
+	return &RepriceOrderUseCase{
+		orderRepo:   orderRepo,
+		productRepo: productRepo,
+		notifier:    notifier,
+		policy:      policy,
+	}
+}
+
+// Execute compares the order's captured prices with current product prices.
+// Under AUTO_UPDATE the new prices are applied; under REQUIRE_CONFIRMATION the
+// order is left as is until the customer confirms the new total.
+func (uc *RepriceOrderUseCase) Execute(cmd RepriceOrderCommand) (*RepriceOrderResponse, error) {
+	existingOrder, err := findCustomerOrder(uc.orderRepo, cmd.OrderID, cmd.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, newTotal, err := detectPriceChanges(uc.productRepo, existingOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	oldTotal := existingOrder.TotalAmount
+	if len(changes) == 0 {
+		return toRepriceOrderResponse(existingOrder, changes, oldTotal, newTotal, false), nil
+	}
+
+	if uc.policy == RepricingPolicyRequireConfirmation {
+		notification := toPriceChangedNotification(existingOrder, changes, oldTotal, newTotal, false)
+		if err := uc.notifier.NotifyPriceChanged(notification); err != nil {
+			return nil, err
+		}
+
+		return toRepriceOrderResponse(existingOrder, changes, oldTotal, newTotal, true), nil
+	}
+
+	if err := applyPriceChanges(uc.orderRepo, uc.notifier, existingOrder, changes); err != nil {
+		return nil, err
+	}
+
+	return toRepriceOrderResponse(existingOrder, changes, oldTotal, newTotal, false), nil
+}
+
+// ConfirmOrderPricesCommand represents the customer accepting the repriced total
+type ConfirmOrderPricesCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	OrderID       string  `json:"order_id" validate:"required"`
+	CustomerID    string  `json:"customer_id" validate:"required"`
+	ExpectedTotal float64 `json:"expected_total" validate:"required,gt=0"`
+}
+
+// ConfirmOrderPricesUseCase applies current prices once the customer accepted them
+type ConfirmOrderPricesUseCase struct {
+	orderRepo   OrderRepository
+	productRepo ProductRepository
+	notifier    PriceChangeNotifier
+}
+
+// NewConfirmOrderPricesUseCase creates a new instance of ConfirmOrderPricesUseCase
+func NewConfirmOrderPricesUseCase(orderRepo OrderRepository, productRepo ProductRepository, notifier PriceChangeNotifier) *ConfirmOrderPricesUseCase {
+	return &ConfirmOrderPricesUseCase{
+		orderRepo:   orderRepo,
+		productRepo: productRepo,
+		notifier:    notifier,
+	}
+}
+
+// Execute applies the current prices if they still add up to the total the
+// customer confirmed. If prices moved again, confirmation is required anew.
+func (uc *ConfirmOrderPricesUseCase) Execute(cmd ConfirmOrderPricesCommand) (*RepriceOrderResponse, error) {
+	existingOrder, err := findCustomerOrder(uc.orderRepo, cmd.OrderID, cmd.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, newTotal, err := detectPriceChanges(uc.productRepo, existingOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	if !newTotal.HasAmount(cmd.ExpectedTotal) {
+		return nil, domainOrder.ErrPriceConfirmationRequired
+	}
+
+	oldTotal := existingOrder.TotalAmount
+	if len(changes) > 0 {
+		if err := applyPriceChanges(uc.orderRepo, uc.notifier, existingOrder, changes); err != nil {
+			return nil, err
+		}
+	}
+
+	return toRepriceOrderResponse(existingOrder, changes, oldTotal, newTotal, false), nil
+}
+
+// detectPriceChanges loads the current product prices and returns the stale
+// lines of the order together with the total after repricing
+func detectPriceChanges(productRepo ProductRepository, o *domainOrder.Order) ([]domainOrder.PriceChange, domainOrder.Money, error) {
+	if o.Status != domainOrder.StatusCreated {
+		return nil, domainOrder.Money{}, domainOrder.ErrCannotModifyOrder
+	}
+
+	currentPrices := make(map[uuid.UUID]domainOrder.Money, len(o.Items))
+	for _, item := range o.Items {
+		p, err := findProduct(productRepo, item.ProductID)
+		if err != nil {
+			return nil, domainOrder.Money{}, err
+		}
+
+		if price, ok := priceInOrderCurrency(p, o); ok {
+			currentPrices[item.ProductID] = price
+		}
+	}
+
+	changes := o.DetectPriceChanges(currentPrices)
+	newTotal, err := o.RepricedTotal(changes)
+	if err != nil {
+		return nil, domainOrder.Money{}, err
+	}
+
+	return changes, newTotal, nil
+}
+
+// priceInOrderCurrency returns the product's current price in the order currency,
+// from the price list or converted at the rate recorded on the order
+func priceInOrderCurrency(p *domainProduct.Product, o *domainOrder.Order) (domainOrder.Money, bool) {
+	if price, ok := p.PriceIn(o.Currency()); ok {
+		return price, true
+	}
+
+	rate, ok := o.ExchangeRateFrom(p.Price.Currency)
+	if !ok {
+		return domainOrder.Money{}, false
+	}
+
+	price, err := rate.Convert(p.Price)
+	if err != nil {
+		return domainOrder.Money{}, false
+	}
+	return price, true
+}
+
+// applyPriceChanges reprices the order, saves it and notifies the customer
+func applyPriceChanges(orderRepo OrderRepository, notifier PriceChangeNotifier, o *domainOrder.Order, changes []domainOrder.PriceChange) error {
+	oldTotal := o.TotalAmount
+	if err := o.ApplyPriceChanges(changes); err != nil {
+		return err
+	}
+
+	// Save updated order
+	if err := orderRepo.Update(o); err != nil {
+		return err
+	}
+
+	return notifier.NotifyPriceChanged(toPriceChangedNotification(o, changes, oldTotal, o.TotalAmount, true))
+}
+
+// toPriceChangeResponses converts domain price changes to their response
+func toPriceChangeResponses(changes []domainOrder.PriceChange) []PriceChangeResponse {
+	responses := make([]PriceChangeResponse, len(changes))
+	for i, change := range changes {
+		responses[i] = PriceChangeResponse{
+			ProductID:    change.ProductID.String(),
+			ProductName:  change.ProductName,
+			Quantity:     change.Quantity,
+			OldUnitPrice: change.OldUnitPrice.Amount,
+			NewUnitPrice: change.NewUnitPrice.Amount,
+			Currency:     change.NewUnitPrice.Currency,
+		}
+	}
+	return responses
+}
+
+// toPriceChangedNotification builds the notification sent to the customer
+func toPriceChangedNotification(o *domainOrder.Order, changes []domainOrder.PriceChange, oldTotal, newTotal domainOrder.Money, applied bool) PriceChangedNotification {
+	return PriceChangedNotification{
+		OrderID:    o.ID.String(),
+		CustomerID: o.CustomerID,
+		Changes:    toPriceChangeResponses(changes),
+		OldTotal:   oldTotal.Amount,
+		NewTotal:   newTotal.Amount,
+		Currency:   newTotal.Currency,
+		Applied:    applied,
+	}
+}
+
+// toRepriceOrderResponse builds the response of a repricing check
+func toRepriceOrderResponse(o *domainOrder.Order, changes []domainOrder.PriceChange, oldTotal, newTotal domainOrder.Money, requiresConfirmation bool) *RepriceOrderResponse {
+	return &RepriceOrderResponse{
+		Order:                toOrderResponse(o),
+		Changes:              toPriceChangeResponses(changes),
+		OldTotal:             oldTotal.Amount,
+		NewTotal:             newTotal.Amount,
+		Currency:             newTotal.Currency,
+		RequiresConfirmation: requiresConfirmation,
+	}
+}
@@ -0,0 +1,217 @@
+package order
+
+import (
+	"testing"
+	"time"
+
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPriceChangeNotifier is a mock implementation of PriceChangeNotifier
+type MockPriceChangeNotifier struct {
+	mock.Mock
+}
+
+func (m *MockPriceChangeNotifier) NotifyPriceChanged(notification PriceChangedNotification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+// Tests for RepriceOrderUseCase
+
+func TestRepriceOrderUseCase(t *testing.T) {
+	t.Run("auto update applies new prices and notifies", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		productRepo := new(MockProductRepository)
+		notifier := new(MockPriceChangeNotifier)
+		useCase := NewRepriceOrderUseCase(orderRepo, productRepo, notifier, RepricingPolicyAutoUpdate)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p) // 2 x 10 USD
+		newPrice, _ := domainOrder.NewMoney(12.0, "USD")
+		_ = p.UpdatePrice(newPrice)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		orderRepo.On("Update", o).Return(nil)
+		notifier.On("NotifyPriceChanged", mock.MatchedBy(func(n PriceChangedNotification) bool {
+			return n.Applied && n.OldTotal == 20.0 && n.NewTotal == 24.0 && n.Changes[0].ProductName == "Headphones"
+		})).Return(nil)
+
+		response, err := useCase.Execute(RepriceOrderCommand{OrderID: o.ID.String(), CustomerID: o.CustomerID})
+
+		assert.NoError(t, err)
+		assert.False(t, response.RequiresConfirmation)
+		assert.Len(t, response.Changes, 1)
+		assert.Equal(t, 24.0, response.Order.TotalAmount)
+		assert.Equal(t, 12.0, o.Items[0].UnitPrice.Amount)
+
+		orderRepo.AssertExpectations(t)
+		notifier.AssertExpectations(t)
+	})
+
+	t.Run("require confirmation leaves order unchanged", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		productRepo := new(MockProductRepository)
+		notifier := new(MockPriceChangeNotifier)
+		useCase := NewRepriceOrderUseCase(orderRepo, productRepo, notifier, RepricingPolicyRequireConfirmation)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+		newPrice, _ := domainOrder.NewMoney(12.0, "USD")
+		_ = p.UpdatePrice(newPrice)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		notifier.On("NotifyPriceChanged", mock.MatchedBy(func(n PriceChangedNotification) bool {
+			return !n.Applied && n.NewTotal == 24.0
+		})).Return(nil)
+
+		response, err := useCase.Execute(RepriceOrderCommand{OrderID: o.ID.String(), CustomerID: o.CustomerID})
+
+		assert.NoError(t, err)
+		assert.True(t, response.RequiresConfirmation)
+		assert.Equal(t, 20.0, response.OldTotal)
+		assert.Equal(t, 24.0, response.NewTotal)
+		assert.Equal(t, 20.0, o.TotalAmount.Amount)
+		orderRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("converted lines reprice at the recorded rate", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		productRepo := new(MockProductRepository)
+		notifier := new(MockPriceChangeNotifier)
+		useCase := NewRepriceOrderUseCase(orderRepo, productRepo, notifier, RepricingPolicyRequireConfirmation)
+
+		p := createTestProduct() // 10 USD
+		rate, _ := domainOrder.NewExchangeRate("USD", "EUR", 0.9, "test", time.Now())
+		eurPrice, _ := rate.Convert(p.Price)
+		item, _ := domainOrder.NewOrderItemWithSnapshot(p.ID, p.Name, p.SKU, 2, eurPrice)
+		o, _ := domainOrder.NewOrder("customer123", []domainOrder.OrderItem{item})
+		_ = o.RecordExchangeRate(rate)
+		newPrice, _ := domainOrder.NewMoney(20.0, "USD")
+		_ = p.UpdatePrice(newPrice)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		notifier.On("NotifyPriceChanged", mock.Anything).Return(nil)
+
+		response, err := useCase.Execute(RepriceOrderCommand{OrderID: o.ID.String(), CustomerID: o.CustomerID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 18.0, response.OldTotal)
+		assert.Equal(t, 36.0, response.NewTotal)
+		assert.Equal(t, "EUR", response.Currency)
+	})
+
+	t.Run("no changes sends no notification", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		productRepo := new(MockProductRepository)
+		notifier := new(MockPriceChangeNotifier)
+		useCase := NewRepriceOrderUseCase(orderRepo, productRepo, notifier, RepricingPolicyAutoUpdate)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+
+		response, err := useCase.Execute(RepriceOrderCommand{OrderID: o.ID.String(), CustomerID: o.CustomerID})
+
+		assert.NoError(t, err)
+		assert.Empty(t, response.Changes)
+		assert.Equal(t, response.OldTotal, response.NewTotal)
+		notifier.AssertNotCalled(t, "NotifyPriceChanged", mock.Anything)
+	})
+}
+
+// Tests for ConfirmOrderPricesUseCase
+
+func TestConfirmOrderPricesUseCase(t *testing.T) {
+	t.Run("confirm applies the accepted total", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		productRepo := new(MockProductRepository)
+		notifier := new(MockPriceChangeNotifier)
+		useCase := NewConfirmOrderPricesUseCase(orderRepo, productRepo, notifier)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+		newPrice, _ := domainOrder.NewMoney(12.0, "USD")
+		_ = p.UpdatePrice(newPrice)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		orderRepo.On("Update", o).Return(nil)
+		notifier.On("NotifyPriceChanged", mock.Anything).Return(nil)
+
+		response, err := useCase.Execute(ConfirmOrderPricesCommand{OrderID: o.ID.String(), CustomerID: o.CustomerID, ExpectedTotal: 24.0})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 24.0, response.Order.TotalAmount)
+		orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("prices moved again since confirmation", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		productRepo := new(MockProductRepository)
+		useCase := NewConfirmOrderPricesUseCase(orderRepo, productRepo, new(MockPriceChangeNotifier))
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+		newPrice, _ := domainOrder.NewMoney(13.0, "USD")
+		_ = p.UpdatePrice(newPrice)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+
+		response, err := useCase.Execute(ConfirmOrderPricesCommand{OrderID: o.ID.String(), CustomerID: o.CustomerID, ExpectedTotal: 24.0})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainOrder.ErrPriceConfirmationRequired, err)
+		assert.Equal(t, 20.0, o.TotalAmount.Amount)
+		orderRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("confirmed total is compared in cents", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		productRepo := new(MockProductRepository)
+		notifier := new(MockPriceChangeNotifier)
+		useCase := NewConfirmOrderPricesUseCase(orderRepo, productRepo, notifier)
+
+		p := createTestProduct()
+		other := createOtherTestProduct(5)
+		first, _ := domainOrder.NewOrderItemWithSnapshot(p.ID, p.Name, p.SKU, 2, p.Price)
+		second, _ := domainOrder.NewOrderItemWithSnapshot(other.ID, other.Name, other.SKU, 1, other.Price)
+		o, _ := domainOrder.NewOrder("customer123", []domainOrder.OrderItem{first, second})
+		_ = p.UpdatePrice(domainOrder.Money{Amount: 0.05, Currency: "USD"})
+		_ = other.UpdatePrice(domainOrder.Money{Amount: 0.2, Currency: "USD"}) // 2 x 0.05 + 0.2 sums to 0.30000000000000004
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		productRepo.On("FindByID", other.ID).Return(other, nil)
+		orderRepo.On("Update", o).Return(nil)
+		notifier.On("NotifyPriceChanged", mock.Anything).Return(nil)
+
+		response, err := useCase.Execute(ConfirmOrderPricesCommand{OrderID: o.ID.String(), CustomerID: o.CustomerID, ExpectedTotal: 0.3})
+
+		assert.NoError(t, err)
+		assert.InDelta(t, 0.3, response.Order.TotalAmount, 1e-9)
+	})
+
+	t.Run("cannot reprice after checkout", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		useCase := NewConfirmOrderPricesUseCase(orderRepo, new(MockProductRepository), new(MockPriceChangeNotifier))
+
+		o := createTestOrderFor(createTestProduct())
+		_ = o.MarkAsPendingPayment()
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+
+		response, err := useCase.Execute(ConfirmOrderPricesCommand{OrderID: o.ID.String(), CustomerID: o.CustomerID, ExpectedTotal: 20.0})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainOrder.ErrCannotModifyOrder, err)
+	})
+}
@@ -0,0 +1,158 @@
+package order
+
+import (
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	"github.com/google/uuid"
+)
+
+// RequestReturnCommand represents the input for requesting a return (RMA)
+type RequestReturnCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	OrderID    string              `json:"order_id" validate:"required"`
+	CustomerID string              `json:"customer_id" validate:"required"`
+	Items      []RequestReturnItem `json:"items" validate:"required,min=1"`
+}
+
+// RequestReturnItem represents a single order line to return
+type RequestReturnItem struct {
+	ProductID string `json:"product_id" validate:"required"`
+	Quantity  int    `json:"quantity" validate:"required,min=1"`
+	Reason    string `json:"reason" validate:"required"`
+}
+
+// ReturnRequestResponse represents a return request in responses
+type ReturnRequestResponse struct {
+	ID                 string               `json:"id"`
+	OrderID            string               `json:"order_id"`
+	Status             string               `json:"status"`
+	Items              []ReturnItemResponse `json:"items"`
+	RefundAmount       float64              `json:"refund_amount"`
+	RefundCurrency     string               `json:"refund_currency"`
+	CryptoRefundAmount float64              `json:"crypto_refund_amount,omitempty"`
+	RejectionReason    string               `json:"rejection_reason,omitempty"`
+	OrderStatus        string               `json:"order_status,omitempty"`
+	CreatedAt          string               `json:"created_at"`
+	UpdatedAt          string               `json:"updated_at"`
+}
+
+// ReturnItemResponse represents a returned line in the response
+type ReturnItemResponse struct {
+	ProductID    string  `json:"product_id"`
+	Quantity     int     `json:"quantity"`
+	Reason       string  `json:"reason"`
+	RefundAmount float64 `json:"refund_amount"`
+}
+
+// ReturnRequestRepository defines the interface for return request persistence
+type ReturnRequestRepository interface {
+	Save(returnRequest *domainOrder.ReturnRequest) error
+	FindByID(id uuid.UUID) (*domainOrder.ReturnRequest, error)
+	FindByOrderID(orderID uuid.UUID) ([]*domainOrder.ReturnRequest, error)
+	Update(returnRequest *domainOrder.ReturnRequest) error
+}
+
+// RequestReturnUseCase handles customers requesting to return items of a fulfilled order
+type RequestReturnUseCase struct {
+	orderRepo  OrderRepository
+	returnRepo ReturnRequestRepository
+}
+
+// NewRequestReturnUseCase creates a new instance of RequestReturnUseCase
+func NewRequestReturnUseCase(orderRepo OrderRepository, returnRepo ReturnRequestRepository) *RequestReturnUseCase {
+	return &RequestReturnUseCase{
+		orderRepo:  orderRepo,
+		returnRepo: returnRepo,
+	}
+}
+
+// Execute creates a return request for the given order lines
+func (uc *RequestReturnUseCase) Execute(cmd RequestReturnCommand) (*ReturnRequestResponse, error) {
+	// Customers can only return their own orders
+	existingOrder, err := findCustomerOrder(uc.orderRepo, cmd.OrderID, cmd.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]domainOrder.ReturnItem, 0, len(cmd.Items))
+	for _, item := range cmd.Items {
+		productID, err := uuid.Parse(item.ProductID)
+		if err != nil {
+			return nil, domainOrder.ErrItemNotFound
+		}
+
+		items = append(items, domainOrder.ReturnItem{
+			ProductID: productID,
+			Quantity:  item.Quantity,
+			Reason:    item.Reason,
+		})
+	}
+
+	// Existing returns hold quantities that cannot be claimed again
+	existingReturns, err := uc.returnRepo.FindByOrderID(existingOrder.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	returnRequest, err := domainOrder.NewReturnRequest(existingOrder, items, existingReturns)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save return request
+	if err := uc.returnRepo.Save(returnRequest); err != nil {
+		return nil, err
+	}
+
+	return toReturnRequestResponse(returnRequest, existingOrder), nil
+}
+
+// findReturnRequest parses the return ID and loads the return request
+func findReturnRequest(returnRepo ReturnRequestRepository, returnID string) (*domainOrder.ReturnRequest, error) {
+	id, err := uuid.Parse(returnID)
+	if err != nil {
+		return nil, domainOrder.ErrReturnNotFound
+	}
+
+	returnRequest, err := returnRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if returnRequest == nil {
+		return nil, domainOrder.ErrReturnNotFound
+	}
+
+	return returnRequest, nil
+}
+
+// toReturnRequestResponse converts a return request to its response
+func toReturnRequestResponse(r *domainOrder.ReturnRequest, o *domainOrder.Order) *ReturnRequestResponse {
+	items := make([]ReturnItemResponse, len(r.Items))
+	for i, item := range r.Items {
+		items[i] = ReturnItemResponse{
+			ProductID:    item.ProductID.String(),
+			Quantity:     item.Quantity,
+			Reason:       item.Reason,
+			RefundAmount: item.RefundAmount.Amount,
+		}
+	}
+
+	response := &ReturnRequestResponse{
+		ID:                 r.ID.String(),
+		OrderID:            r.OrderID.String(),
+		Status:             string(r.Status),
+		Items:              items,
+		RefundAmount:       r.RefundAmount.Amount,
+		RefundCurrency:     r.RefundAmount.Currency,
+		CryptoRefundAmount: r.CryptoRefundAmount,
+		RejectionReason:    r.RejectionReason,
+		CreatedAt:          r.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:          r.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if o != nil {
+		response.OrderStatus = string(o.Status)
+	}
+
+	return response
+}
@@ -0,0 +1,125 @@
+package order
+
+import (
+	"testing"
+
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainProduct "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/product"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReturnRequestRepository is a mock implementation of ReturnRequestRepository
+type MockReturnRequestRepository struct {
+	mock.Mock
+}
+
+func (m *MockReturnRequestRepository) Save(returnRequest *domainOrder.ReturnRequest) error {
+	args := m.Called(returnRequest)
+	return args.Error(0)
+}
+
+func (m *MockReturnRequestRepository) FindByID(id uuid.UUID) (*domainOrder.ReturnRequest, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainOrder.ReturnRequest), args.Error(1)
+}
+
+func (m *MockReturnRequestRepository) FindByOrderID(orderID uuid.UUID) ([]*domainOrder.ReturnRequest, error) {
+	args := m.Called(orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domainOrder.ReturnRequest), args.Error(1)
+}
+
+func (m *MockReturnRequestRepository) Update(returnRequest *domainOrder.ReturnRequest) error {
+	args := m.Called(returnRequest)
+	return args.Error(0)
+}
+
+// fulfillTestOrder fulfils a paid order and removes the shipped units from stock
+func fulfillTestOrder(p *domainProduct.Product, o *domainOrder.Order) {
+	_ = o.MarkAsFulfilled()
+	_ = p.FulfillStock(2)
+}
+
+// Tests for RequestReturnUseCase
+
+func TestRequestReturnUseCase(t *testing.T) {
+	t.Run("successfully request return", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		returnRepo := new(MockReturnRequestRepository)
+		useCase := NewRequestReturnUseCase(orderRepo, returnRepo)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+		_ = createConfirmedPayment(o)
+		fulfillTestOrder(p, o)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		returnRepo.On("FindByOrderID", o.ID).Return([]*domainOrder.ReturnRequest{}, nil)
+		returnRepo.On("Save", mock.AnythingOfType("*order.ReturnRequest")).Return(nil)
+
+		response, err := useCase.Execute(RequestReturnCommand{
+			OrderID:    o.ID.String(),
+			CustomerID: o.CustomerID,
+			Items:      []RequestReturnItem{{ProductID: p.ID.String(), Quantity: 1, Reason: "damaged"}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, string(domainOrder.ReturnStatusRequested), response.Status)
+		assert.Equal(t, 10.0, response.RefundAmount)
+		assert.Equal(t, "USD", response.RefundCurrency)
+		assert.Len(t, response.Items, 1)
+
+		orderRepo.AssertExpectations(t)
+		returnRepo.AssertExpectations(t)
+	})
+
+	t.Run("cannot return another customer's order", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		returnRepo := new(MockReturnRequestRepository)
+		useCase := NewRequestReturnUseCase(orderRepo, returnRepo)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+
+		response, err := useCase.Execute(RequestReturnCommand{
+			OrderID:    o.ID.String(),
+			CustomerID: "someone-else",
+			Items:      []RequestReturnItem{{ProductID: p.ID.String(), Quantity: 1, Reason: "damaged"}},
+		})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainOrder.ErrOrderNotFound, err)
+		returnRepo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("cannot return unfulfilled order", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		returnRepo := new(MockReturnRequestRepository)
+		useCase := NewRequestReturnUseCase(orderRepo, returnRepo)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		returnRepo.On("FindByOrderID", o.ID).Return([]*domainOrder.ReturnRequest{}, nil)
+
+		response, err := useCase.Execute(RequestReturnCommand{
+			OrderID:    o.ID.String(),
+			CustomerID: o.CustomerID,
+			Items:      []RequestReturnItem{{ProductID: p.ID.String(), Quantity: 1, Reason: "damaged"}},
+		})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainOrder.ErrOrderNotReturnable, err)
+		returnRepo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+}
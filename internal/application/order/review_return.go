@@ -0,0 +1,78 @@
+package order
+
+// ApproveReturnCommand represents the input for approving a return request
+type ApproveReturnCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	ReturnID string `json:"return_id" validate:"required"`
+}
+
+// ApproveReturnUseCase handles merchants approving return requests
+type ApproveReturnUseCase struct {
+	returnRepo ReturnRequestRepository
+}
+
+// NewApproveReturnUseCase creates a new instance of ApproveReturnUseCase
+func NewApproveReturnUseCase(returnRepo ReturnRequestRepository) *ApproveReturnUseCase {
+	return &ApproveReturnUseCase{
+		returnRepo: returnRepo,
+	}
+}
+
+// Execute approves a requested return
+func (uc *ApproveReturnUseCase) Execute(cmd ApproveReturnCommand) (*ReturnRequestResponse, error) {
+	returnRequest, err := findReturnRequest(uc.returnRepo, cmd.ReturnID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := returnRequest.Approve(); err != nil {
+		return nil, err
+	}
+
+	// Save updated return request
+	if err := uc.returnRepo.Update(returnRequest); err != nil {
+		return nil, err
+	}
+
+	return toReturnRequestResponse(returnRequest, nil), nil
+}
+
+// RejectReturnCommand represents the input for rejecting a return request
+type RejectReturnCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	ReturnID string `json:"return_id" validate:"required"`
+	Reason   string `json:"reason" validate:"required"`
+}
+
+// RejectReturnUseCase handles merchants rejecting return requests
+type RejectReturnUseCase struct {
+	returnRepo ReturnRequestRepository
+}
+
+// NewRejectReturnUseCase creates a new instance of RejectReturnUseCase
+func NewRejectReturnUseCase(returnRepo ReturnRequestRepository) *RejectReturnUseCase {
+	return &RejectReturnUseCase{
+		returnRepo: returnRepo,
+	}
+}
+
+// Execute rejects a requested return
+func (uc *RejectReturnUseCase) Execute(cmd RejectReturnCommand) (*ReturnRequestResponse, error) {
+	returnRequest, err := findReturnRequest(uc.returnRepo, cmd.ReturnID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := returnRequest.Reject(cmd.Reason); err != nil {
+		return nil, err
+	}
+
+	// Save updated return request
+	if err := uc.returnRepo.Update(returnRequest); err != nil {
+		return nil, err
+	}
+
+	return toReturnRequestResponse(returnRequest, nil), nil
+}
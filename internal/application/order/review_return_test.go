@@ -0,0 +1,61 @@
+package order
+
+import (
+	"testing"
+
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests for ApproveReturnUseCase and RejectReturnUseCase
+
+func TestReviewReturnUseCases(t *testing.T) {
+	t.Run("approve return", func(t *testing.T) {
+		returnRepo := new(MockReturnRequestRepository)
+		useCase := NewApproveReturnUseCase(returnRepo)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+		_ = createConfirmedPayment(o)
+		fulfillTestOrder(p, o)
+		rma, _ := domainOrder.NewReturnRequest(o, []domainOrder.ReturnItem{{ProductID: p.ID, Quantity: 1, Reason: "damaged"}}, nil)
+
+		returnRepo.On("FindByID", rma.ID).Return(rma, nil)
+		returnRepo.On("Update", rma).Return(nil)
+
+		response, err := useCase.Execute(ApproveReturnCommand{ReturnID: rma.ID.String()})
+
+		assert.NoError(t, err)
+		assert.Equal(t, string(domainOrder.ReturnStatusApproved), response.Status)
+		returnRepo.AssertExpectations(t)
+	})
+
+	t.Run("reject return", func(t *testing.T) {
+		returnRepo := new(MockReturnRequestRepository)
+		useCase := NewRejectReturnUseCase(returnRepo)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+		_ = createConfirmedPayment(o)
+		fulfillTestOrder(p, o)
+		rma, _ := domainOrder.NewReturnRequest(o, []domainOrder.ReturnItem{{ProductID: p.ID, Quantity: 1, Reason: "damaged"}}, nil)
+
+		returnRepo.On("FindByID", rma.ID).Return(rma, nil)
+		returnRepo.On("Update", rma).Return(nil)
+
+		response, err := useCase.Execute(RejectReturnCommand{ReturnID: rma.ID.String(), Reason: "outside return window"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, string(domainOrder.ReturnStatusRejected), response.Status)
+		assert.Equal(t, "outside return window", response.RejectionReason)
+	})
+
+	t.Run("return not found", func(t *testing.T) {
+		useCase := NewApproveReturnUseCase(new(MockReturnRequestRepository))
+
+		response, err := useCase.Execute(ApproveReturnCommand{ReturnID: "bad-id"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainOrder.ErrReturnNotFound, err)
+	})
+}
@@ -0,0 +1,372 @@
+package order
+
+import (
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainProduct "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/product"
+	"github.com/google/uuid"
+)
+
+// OrderResponse represents an order and its lines in responses
+type OrderResponse struct {
+	ID          string              `json:"id"`
+	CustomerID  string              `json:"customer_id"`
+	Status      string              `json:"status"`
+	Items       []OrderItemResponse `json:"items"`
+	TotalAmount float64             `json:"total_amount"`
+	Currency    string              `json:"currency"`
+	Sandbox     bool                `json:"sandbox,omitempty"` // Test order, paid in testnet coins
+	UpdatedAt   string              `json:"updated_at"`
+}
+
+// OrderItemResponse represents an order line in the response
+type OrderItemResponse struct {
+	ProductID   string  `json:"product_id"`
+	ProductName string  `json:"product_name,omitempty"`
+	ProductSKU  string  `json:"product_sku,omitempty"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	Subtotal    float64 `json:"subtotal"`
+	Note        string  `json:"note,omitempty"`
+}
+
+// UpdateOrderItemQuantityCommand represents the input for setting the quantity of an order line
+type UpdateOrderItemQuantityCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	OrderID    string `json:"order_id" validate:"required"`
+	CustomerID string `json:"customer_id" validate:"required"`
+	ProductID  string `json:"product_id" validate:"required"`
+	Quantity   int    `json:"quantity" validate:"min=0"`
+}
+
+// UpdateOrderItemQuantityUseCase handles quantity changes of a single order line
+type UpdateOrderItemQuantityUseCase struct {
+	orderRepo   OrderRepository
+	productRepo ProductRepository
+}
+
+// NewUpdateOrderItemQuantityUseCase creates a new instance of UpdateOrderItemQuantityUseCase
+func NewUpdateOrderItemQuantityUseCase(orderRepo OrderRepository, productRepo ProductRepository) *UpdateOrderItemQuantityUseCase {
+	return &UpdateOrderItemQuantityUseCase{
+		orderRepo:   orderRepo,
+		productRepo: productRepo,
+	}
+}
+
+// Execute sets the line quantity, adjusting the product's reserved stock.
+// A quantity of zero removes the line.
+func (uc *UpdateOrderItemQuantityUseCase) Execute(cmd UpdateOrderItemQuantityCommand) (*OrderResponse, error) {
+	existingOrder, err := findCustomerOrder(uc.orderRepo, cmd.OrderID, cmd.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	productID, err := uuid.Parse(cmd.ProductID)
+	if err != nil {
+		return nil, domainOrder.ErrItemNotFound
+	}
+
+	currentQuantity := 0
+	for _, item := range existingOrder.Items {
+		if item.ProductID == productID {
+			currentQuantity = item.Quantity
+		}
+	}
+	if currentQuantity == 0 {
+		return nil, domainOrder.ErrItemNotFound
+	}
+
+	p, err := findProduct(uc.productRepo, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Adjust reserved stock for the difference
+	if existingOrder.StockReserved {
+		if err := adjustReservation(p, cmd.Quantity-currentQuantity); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := existingOrder.UpdateItemQuantity(productID, cmd.Quantity); err != nil {
+		return nil, err
+	}
+
+	// Save updated aggregates
+	if existingOrder.StockReserved {
+		if err := uc.productRepo.Update(p); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.orderRepo.Update(existingOrder); err != nil {
+		return nil, err
+	}
+
+	return toOrderResponse(existingOrder), nil
+}
+
+// SetOrderItemNoteCommand represents the input for attaching a note to an order line
+type SetOrderItemNoteCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	OrderID    string `json:"order_id" validate:"required"`
+	CustomerID string `json:"customer_id" validate:"required"`
+	ProductID  string `json:"product_id" validate:"required"`
+	Note       string `json:"note" validate:"max=500"`
+}
+
+// SetOrderItemNoteUseCase handles line notes
+type SetOrderItemNoteUseCase struct {
+	orderRepo OrderRepository
+}
+
+// NewSetOrderItemNoteUseCase creates a new instance of SetOrderItemNoteUseCase
+func NewSetOrderItemNoteUseCase(orderRepo OrderRepository) *SetOrderItemNoteUseCase {
+	return &SetOrderItemNoteUseCase{
+		orderRepo: orderRepo,
+	}
+}
+
+// Execute attaches (or clears) a note on an order line
+func (uc *SetOrderItemNoteUseCase) Execute(cmd SetOrderItemNoteCommand) (*OrderResponse, error) {
+	existingOrder, err := findCustomerOrder(uc.orderRepo, cmd.OrderID, cmd.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	productID, err := uuid.Parse(cmd.ProductID)
+	if err != nil {
+		return nil, domainOrder.ErrItemNotFound
+	}
+
+	if err := existingOrder.SetItemNote(productID, cmd.Note); err != nil {
+		return nil, err
+	}
+
+	// Save updated order
+	if err := uc.orderRepo.Update(existingOrder); err != nil {
+		return nil, err
+	}
+
+	return toOrderResponse(existingOrder), nil
+}
+
+// ReplaceOrderItemsCommand represents the input for replacing all lines of an order
+type ReplaceOrderItemsCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	OrderID    string           `json:"order_id" validate:"required"`
+	CustomerID string           `json:"customer_id" validate:"required"`
+	Items      []OrderItemInput `json:"items" validate:"required,min=1"`
+}
+
+// OrderItemInput represents a requested order line
+type OrderItemInput struct {
+	ProductID string `json:"product_id" validate:"required"`
+	Quantity  int    `json:"quantity" validate:"required,min=1"`
+	Note      string `json:"note,omitempty"`
+}
+
+// ReplaceOrderItemsUseCase handles bulk replacement of order lines
+type ReplaceOrderItemsUseCase struct {
+	orderRepo   OrderRepository
+	productRepo ProductRepository
+}
+
+// NewReplaceOrderItemsUseCase creates a new instance of ReplaceOrderItemsUseCase
+func NewReplaceOrderItemsUseCase(orderRepo OrderRepository, productRepo ProductRepository) *ReplaceOrderItemsUseCase {
+	return &ReplaceOrderItemsUseCase{
+		orderRepo:   orderRepo,
+		productRepo: productRepo,
+	}
+}
+
+// Execute replaces all lines of the order. Availability of every product is
+// checked before anything changes, so either all lines are replaced or none.
+func (uc *ReplaceOrderItemsUseCase) Execute(cmd ReplaceOrderItemsCommand) (*OrderResponse, error) {
+	existingOrder, err := findCustomerOrder(uc.orderRepo, cmd.OrderID, cmd.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingOrder.Status != domainOrder.StatusCreated {
+		return nil, domainOrder.ErrCannotModifyOrder
+	}
+
+	// Current lines and quantities
+	current := make(map[uuid.UUID]domainOrder.OrderItem, len(existingOrder.Items))
+	for _, item := range existingOrder.Items {
+		current[item.ProductID] = item
+	}
+
+	// Build the replacement lines and the reservation changes they need
+	items := make([]domainOrder.OrderItem, 0, len(cmd.Items))
+	deltas := make(map[uuid.UUID]int)
+	products := make(map[uuid.UUID]*domainProduct.Product)
+	for _, input := range cmd.Items {
+		productID, err := uuid.Parse(input.ProductID)
+		if err != nil {
+			return nil, domainOrder.ErrItemNotFound
+		}
+
+		if _, loaded := products[productID]; loaded {
+			return nil, domainOrder.ErrDuplicateItem
+		}
+
+		p, err := findProduct(uc.productRepo, productID)
+		if err != nil {
+			return nil, err
+		}
+		products[productID] = p
+
+		// Existing lines keep their captured snapshot, new lines snapshot the product now in the order currency
+		var item domainOrder.OrderItem
+		if existing, ok := current[productID]; ok {
+			item, err = domainOrder.NewOrderItemWithSnapshot(productID, existing.ProductName, existing.ProductSKU, input.Quantity, existing.UnitPrice)
+		} else {
+			price, ok := priceInOrderCurrency(p, existingOrder)
+			if !ok {
+				return nil, domainOrder.ErrExchangeRateUnavailable
+			}
+			item, err = domainOrder.NewOrderItemWithSnapshot(productID, p.Name, p.SKU, input.Quantity, price)
+		}
+		if err != nil {
+			return nil, err
+		}
+		item.Note = input.Note
+
+		items = append(items, item)
+		deltas[productID] = input.Quantity - current[productID].Quantity
+	}
+
+	// Lines that disappear release their whole reservation
+	for productID, item := range current {
+		if _, kept := products[productID]; kept {
+			continue
+		}
+
+		p, err := findProduct(uc.productRepo, productID)
+		if err != nil {
+			return nil, err
+		}
+		products[productID] = p
+		deltas[productID] = -item.Quantity
+	}
+
+	// Orders without a reservation have no stock to adjust
+	if !existingOrder.StockReserved {
+		clear(deltas)
+	}
+
+	// Check availability for every increase before changing anything
+	for productID, delta := range deltas {
+		if delta > 0 && !products[productID].IsAvailableForOrder(delta) {
+			return nil, availabilityError(products[productID])
+		}
+	}
+
+	if err := existingOrder.ReplaceItems(items); err != nil {
+		return nil, err
+	}
+
+	for productID, delta := range deltas {
+		if err := adjustReservation(products[productID], delta); err != nil {
+			return nil, err
+		}
+	}
+
+	// Save updated aggregates
+	for productID, delta := range deltas {
+		if delta == 0 {
+			continue
+		}
+		if err := uc.productRepo.Update(products[productID]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.orderRepo.Update(existingOrder); err != nil {
+		return nil, err
+	}
+
+	return toOrderResponse(existingOrder), nil
+}
+
+// adjustReservation reserves (positive delta) or releases (negative delta) product stock
+func adjustReservation(p *domainProduct.Product, delta int) error {
+	switch {
+	case delta > 0:
+		if !p.IsAvailableForOrder(delta) {
+			return availabilityError(p)
+		}
+		return p.ReserveStock(delta)
+	case delta < 0:
+		return p.ReleaseStock(-delta)
+	default:
+		return nil
+	}
+}
+
+// availabilityError explains why a product cannot be ordered
+func availabilityError(p *domainProduct.Product) error {
+	if !p.Status.CanBeOrdered() {
+		return domainProduct.ErrProductNotActive
+	}
+	return domainProduct.ErrInsufficientStock
+}
+
+// findCustomerOrder loads an order and checks it belongs to the customer
+func findCustomerOrder(orderRepo OrderRepository, orderID, customerID string) (*domainOrder.Order, error) {
+	existingOrder, err := findOrder(orderRepo, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingOrder.CustomerID != customerID {
+		return nil, domainOrder.ErrOrderNotFound
+	}
+
+	return existingOrder, nil
+}
+
+// findProduct loads a product by ID
+func findProduct(productRepo ProductRepository, productID uuid.UUID) (*domainProduct.Product, error) {
+	p, err := productRepo.FindByID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if p == nil {
+		return nil, domainProduct.ErrProductNotFound
+	}
+
+	return p, nil
+}
+
+// toOrderResponse converts an order to its response
+func toOrderResponse(o *domainOrder.Order) *OrderResponse {
+	items := make([]OrderItemResponse, len(o.Items))
+	for i, item := range o.Items {
+		items[i] = OrderItemResponse{
+			ProductID:   item.ProductID.String(),
+			ProductName: item.ProductName,
+			ProductSKU:  item.ProductSKU,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice.Amount,
+			Subtotal:    item.Subtotal.Amount,
+			Note:        item.Note,
+		}
+	}
+
+	return &OrderResponse{
+		ID:          o.ID.String(),
+		CustomerID:  o.CustomerID,
+		Status:      string(o.Status),
+		Items:       items,
+		TotalAmount: o.TotalAmount.Amount,
+		Currency:    o.TotalAmount.Currency,
+		Sandbox:     o.Sandbox,
+		UpdatedAt:   o.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
@@ -0,0 +1,214 @@
+package order
+
+import (
+	"testing"
+
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainProduct "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/product"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// createOtherTestProduct creates a second active product with nothing reserved
+func createOtherTestProduct(quantity int) *domainProduct.Product {
+	price, _ := domainOrder.NewMoney(25.0, "USD")
+	category, _ := domainProduct.NewCategory("Electronics", "", nil)
+	inventory, _ := domainProduct.NewInventory(quantity, 0, 0)
+	p, _ := domainProduct.NewProduct("Speaker", "Bluetooth", "SP-001", price, category, inventory)
+	_ = p.Activate()
+	return p
+}
+
+// Tests for UpdateOrderItemQuantityUseCase
+
+func TestUpdateOrderItemQuantityUseCase(t *testing.T) {
+	t.Run("increase quantity reserves more stock", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		productRepo := new(MockProductRepository)
+		useCase := NewUpdateOrderItemQuantityUseCase(orderRepo, productRepo)
+
+		p := createTestProduct() // 2 of 10 reserved
+		o := createTestOrderFor(p)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		productRepo.On("Update", p).Return(nil)
+		orderRepo.On("Update", o).Return(nil)
+
+		response, err := useCase.Execute(UpdateOrderItemQuantityCommand{
+			OrderID:    o.ID.String(),
+			CustomerID: o.CustomerID,
+			ProductID:  p.ID.String(),
+			Quantity:   5,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, response.Items[0].Quantity)
+		assert.Equal(t, 50.0, response.TotalAmount)
+		assert.Equal(t, 5, p.GetReservedQuantity())
+
+		orderRepo.AssertExpectations(t)
+		productRepo.AssertExpectations(t)
+	})
+
+	t.Run("decrease quantity releases stock", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		productRepo := new(MockProductRepository)
+		useCase := NewUpdateOrderItemQuantityUseCase(orderRepo, productRepo)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		productRepo.On("Update", p).Return(nil)
+		orderRepo.On("Update", o).Return(nil)
+
+		response, err := useCase.Execute(UpdateOrderItemQuantityCommand{
+			OrderID:    o.ID.String(),
+			CustomerID: o.CustomerID,
+			ProductID:  p.ID.String(),
+			Quantity:   1,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, response.Items[0].Quantity)
+		assert.Equal(t, 1, p.GetReservedQuantity())
+	})
+
+	t.Run("cannot exceed available stock", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		productRepo := new(MockProductRepository)
+		useCase := NewUpdateOrderItemQuantityUseCase(orderRepo, productRepo)
+
+		p := createTestProduct() // 8 available
+		o := createTestOrderFor(p)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+
+		response, err := useCase.Execute(UpdateOrderItemQuantityCommand{
+			OrderID:    o.ID.String(),
+			CustomerID: o.CustomerID,
+			ProductID:  p.ID.String(),
+			Quantity:   11,
+		})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainProduct.ErrInsufficientStock, err)
+		assert.Equal(t, 2, o.Items[0].Quantity)
+		orderRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
+
+// Tests for SetOrderItemNoteUseCase
+
+func TestSetOrderItemNoteUseCase(t *testing.T) {
+	t.Run("set note", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		useCase := NewSetOrderItemNoteUseCase(orderRepo)
+
+		p := createTestProduct()
+		o := createTestOrderFor(p)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		orderRepo.On("Update", o).Return(nil)
+
+		response, err := useCase.Execute(SetOrderItemNoteCommand{
+			OrderID:    o.ID.String(),
+			CustomerID: o.CustomerID,
+			ProductID:  p.ID.String(),
+			Note:       "Gift wrap please",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Gift wrap please", response.Items[0].Note)
+	})
+}
+
+// Tests for ReplaceOrderItemsUseCase
+
+func TestReplaceOrderItemsUseCase(t *testing.T) {
+	t.Run("replace lines adjusts reservations", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		productRepo := new(MockProductRepository)
+		useCase := NewReplaceOrderItemsUseCase(orderRepo, productRepo)
+
+		p := createTestProduct() // 2 reserved by the order
+		other := createOtherTestProduct(5)
+		o := createTestOrderFor(p)
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		productRepo.On("FindByID", other.ID).Return(other, nil)
+		productRepo.On("Update", mock.AnythingOfType("*product.Product")).Return(nil)
+		orderRepo.On("Update", o).Return(nil)
+
+		response, err := useCase.Execute(ReplaceOrderItemsCommand{
+			OrderID:    o.ID.String(),
+			CustomerID: o.CustomerID,
+			Items:      []OrderItemInput{{ProductID: other.ID.String(), Quantity: 2, Note: "blue"}},
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, response.Items, 1)
+		assert.Equal(t, 50.0, response.TotalAmount)
+		assert.Equal(t, 0, p.GetReservedQuantity())
+		assert.Equal(t, 2, other.GetReservedQuantity())
+		productRepo.AssertNumberOfCalls(t, "Update", 2)
+	})
+
+	t.Run("fails atomically when one product is unavailable", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		productRepo := new(MockProductRepository)
+		useCase := NewReplaceOrderItemsUseCase(orderRepo, productRepo)
+
+		p := createTestProduct()
+		other := createOtherTestProduct(1)
+		o := createTestOrderFor(p)
+		originalTotal := o.TotalAmount
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		productRepo.On("FindByID", p.ID).Return(p, nil)
+		productRepo.On("FindByID", other.ID).Return(other, nil)
+
+		response, err := useCase.Execute(ReplaceOrderItemsCommand{
+			OrderID:    o.ID.String(),
+			CustomerID: o.CustomerID,
+			Items: []OrderItemInput{
+				{ProductID: p.ID.String(), Quantity: 4},
+				{ProductID: other.ID.String(), Quantity: 3},
+			},
+		})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainProduct.ErrInsufficientStock, err)
+		assert.Equal(t, originalTotal, o.TotalAmount)
+		assert.Equal(t, 2, p.GetReservedQuantity())
+		assert.Equal(t, 0, other.GetReservedQuantity())
+		productRepo.AssertNotCalled(t, "Update", mock.Anything)
+		orderRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("unknown product", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		productRepo := new(MockProductRepository)
+		useCase := NewReplaceOrderItemsUseCase(orderRepo, productRepo)
+
+		o := createTestOrderFor(createTestProduct())
+		missing := uuid.New()
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		productRepo.On("FindByID", missing).Return(nil, nil)
+
+		response, err := useCase.Execute(ReplaceOrderItemsCommand{
+			OrderID:    o.ID.String(),
+			CustomerID: o.CustomerID,
+			Items:      []OrderItemInput{{ProductID: missing.String(), Quantity: 1}},
+		})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainProduct.ErrProductNotFound, err)
+	})
+}
@@ -0,0 +1,441 @@
+package payment
+
+import (
+	"errors"
+	"time"
+
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// AdminPaymentActionCommand represents the input for a manual action on a payment
+type AdminPaymentActionCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	PaymentID     string  `json:"payment_id" validate:"required"`
+	Action        string  `json:"action" validate:"required"` // CONFIRM, FAIL, EXPIRE, EXTEND_EXPIRY, REFUND
+	Actor         string  `json:"actor" validate:"required"`
+	ReasonCode    string  `json:"reason_code" validate:"required"`
+	Note          string  `json:"note,omitempty"`
+	RefundAmount  float64 `json:"refund_amount,omitempty"`  // Crypto amount, REFUND only
+	NetOfFees     bool    `json:"net_of_fees,omitempty"`    // REFUND only: keep back the payment's fees pro rata
+	ExtendMinutes int     `json:"extend_minutes,omitempty"` // EXTEND_EXPIRY only
+}
+
+// DecideAdminPaymentActionCommand represents a second admin approving or rejecting an action
+type DecideAdminPaymentActionCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	ActionID string `json:"action_id" validate:"required"`
+	Actor    string `json:"actor" validate:"required"`
+}
+
+// AdminPaymentActionResponse represents an admin action and the payment's resulting status
+type AdminPaymentActionResponse struct {
+	ActionID      string `json:"action_id"`
+	PaymentID     string `json:"payment_id"`
+	Action        string `json:"action"`
+	Status        string `json:"status"` // PENDING_APPROVAL, EXECUTED, REJECTED or FAILED
+	ReasonCode    string `json:"reason_code"`
+	Note          string `json:"note,omitempty"`
+	RequestedBy   string `json:"requested_by"`
+	ApprovedBy    string `json:"approved_by,omitempty"`
+	PaymentStatus string `json:"payment_status"`
+	RequestedAt   string `json:"requested_at"`
+	DecidedAt     string `json:"decided_at,omitempty"`
+}
+
+// PaymentAuditEntryResponse represents one line of a payment's audit log
+type PaymentAuditEntryResponse struct {
+	ActionID     string  `json:"action_id"`
+	Action       string  `json:"action"`
+	Event        string  `json:"event"`
+	Actor        string  `json:"actor"`
+	RequestedBy  string  `json:"requested_by"`
+	ApprovedBy   string  `json:"approved_by,omitempty"`
+	ReasonCode   string  `json:"reason_code"`
+	Note         string  `json:"note,omitempty"`
+	FromStatus   string  `json:"from_status"`
+	ToStatus     string  `json:"to_status"`
+	RefundAmount float64 `json:"refund_amount,omitempty"`
+	NetOfFees    bool    `json:"net_of_fees,omitempty"`
+	ExpiresAt    string  `json:"expires_at"`
+	CreatedAt    string  `json:"created_at"`
+}
+
+// AdminActionRepository defines the interface for admin action request persistence
+type AdminActionRepository interface {
+	Save(request *domainPayment.AdminActionRequest) error
+	FindByID(id string) (*domainPayment.AdminActionRequest, error)
+	Update(request *domainPayment.AdminActionRequest) error
+}
+
+// PaymentAuditLog is the append-only log of admin actions on payments.
+// Entries are never updated or deleted.
+type PaymentAuditLog interface {
+	Append(entry domainPayment.PaymentAuditEntry) error
+	FindByPaymentID(paymentID string) ([]domainPayment.PaymentAuditEntry, error)
+}
+
+// AdminPaymentActionUseCase runs a manual action on a payment, or files it for
+// approval when the approval policy requires a second admin. Confirming a
+// payment held for review resumes its order, as when the gateway confirms it,
+// and refunding a payment refunds its order.
+type AdminPaymentActionUseCase struct {
+	paymentRepo PaymentRepository
+	orderRepo   OrderRepository
+	actionRepo  AdminActionRepository
+	auditLog    PaymentAuditLog
+	policy      domainPayment.AdminApprovalPolicy
+	ledger      PaymentLedger // nil disables ledger postings
+}
+
+// NewAdminPaymentActionUseCase creates a new instance of AdminPaymentActionUseCase
+func NewAdminPaymentActionUseCase(paymentRepo PaymentRepository, orderRepo OrderRepository, actionRepo AdminActionRepository, auditLog PaymentAuditLog, policy domainPayment.AdminApprovalPolicy, ledger PaymentLedger) *AdminPaymentActionUseCase {
+	return &AdminPaymentActionUseCase{
+		paymentRepo: paymentRepo,
+		orderRepo:   orderRepo,
+		actionRepo:  actionRepo,
+		auditLog:    auditLog,
+		policy:      policy,
+		ledger:      ledger,
+	}
+}
+
+// Execute runs or files the admin action
+func (uc *AdminPaymentActionUseCase) Execute(cmd AdminPaymentActionCommand) (*AdminPaymentActionResponse, error) {
+	existingPayment, err := findPayment(uc.paymentRepo, cmd.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	action := domainPayment.AdminAction(cmd.Action)
+	params := domainPayment.AdminActionParams{
+		RefundAmount: cmd.RefundAmount,
+		NetOfFees:    cmd.NetOfFees,
+		Extension:    time.Duration(cmd.ExtendMinutes) * time.Minute,
+	}
+
+	request, err := domainPayment.NewAdminActionRequest(
+		existingPayment.ID,
+		action,
+		params,
+		domainPayment.ReasonCode(cmd.ReasonCode),
+		cmd.Note,
+		cmd.Actor,
+		uc.policy.RequiresApproval(existingPayment, action, params),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if !request.RequiresApproval {
+		if err := executeAdminAction(request, nil, existingPayment, uc.paymentRepo, uc.orderRepo, uc.actionRepo, uc.auditLog, uc.ledger, request.RequestedBy); err != nil {
+			return nil, err
+		}
+		return toAdminPaymentActionResponse(request, existingPayment), nil
+	}
+
+	if err := uc.actionRepo.Save(request); err != nil {
+		return nil, err
+	}
+
+	// A filed request without its audit entry is closed rather than left to approve
+	if err := uc.auditLog.Append(domainPayment.NewPaymentAuditEntry(request, request.RequestedBy, existingPayment.Status, existingPayment)); err != nil {
+		filed := *request
+		_ = filed.Abandon()
+		return nil, rollbackAdminAction(uc.actionRepo, request, filed, err)
+	}
+
+	return toAdminPaymentActionResponse(request, existingPayment), nil
+}
+
+// ApproveAdminPaymentActionUseCase lets a second admin approve and run a filed action
+type ApproveAdminPaymentActionUseCase struct {
+	paymentRepo PaymentRepository
+	orderRepo   OrderRepository
+	actionRepo  AdminActionRepository
+	auditLog    PaymentAuditLog
+	ledger      PaymentLedger // nil disables ledger postings
+}
+
+// NewApproveAdminPaymentActionUseCase creates a new instance of ApproveAdminPaymentActionUseCase
+func NewApproveAdminPaymentActionUseCase(paymentRepo PaymentRepository, orderRepo OrderRepository, actionRepo AdminActionRepository, auditLog PaymentAuditLog, ledger PaymentLedger) *ApproveAdminPaymentActionUseCase {
+	return &ApproveAdminPaymentActionUseCase{
+		paymentRepo: paymentRepo,
+		orderRepo:   orderRepo,
+		actionRepo:  actionRepo,
+		auditLog:    auditLog,
+		ledger:      ledger,
+	}
+}
+
+// Execute approves and runs the action
+func (uc *ApproveAdminPaymentActionUseCase) Execute(cmd DecideAdminPaymentActionCommand) (*AdminPaymentActionResponse, error) {
+	request, err := findAdminAction(uc.actionRepo, cmd.ActionID)
+	if err != nil {
+		return nil, err
+	}
+
+	filed := *request
+	if err := request.Approve(cmd.Actor); err != nil {
+		return nil, err
+	}
+
+	existingPayment, err := findPayment(uc.paymentRepo, request.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := executeAdminAction(request, &filed, existingPayment, uc.paymentRepo, uc.orderRepo, uc.actionRepo, uc.auditLog, uc.ledger, request.ApprovedBy); err != nil {
+		return nil, err
+	}
+
+	return toAdminPaymentActionResponse(request, existingPayment), nil
+}
+
+// RejectAdminPaymentActionUseCase lets a second admin turn down a filed action
+type RejectAdminPaymentActionUseCase struct {
+	paymentRepo PaymentRepository
+	actionRepo  AdminActionRepository
+	auditLog    PaymentAuditLog
+}
+
+// NewRejectAdminPaymentActionUseCase creates a new instance of RejectAdminPaymentActionUseCase
+func NewRejectAdminPaymentActionUseCase(paymentRepo PaymentRepository, actionRepo AdminActionRepository, auditLog PaymentAuditLog) *RejectAdminPaymentActionUseCase {
+	return &RejectAdminPaymentActionUseCase{
+		paymentRepo: paymentRepo,
+		actionRepo:  actionRepo,
+		auditLog:    auditLog,
+	}
+}
+
+// Execute rejects the action; the payment is left unchanged
+func (uc *RejectAdminPaymentActionUseCase) Execute(cmd DecideAdminPaymentActionCommand) (*AdminPaymentActionResponse, error) {
+	request, err := findAdminAction(uc.actionRepo, cmd.ActionID)
+	if err != nil {
+		return nil, err
+	}
+
+	existingPayment, err := findPayment(uc.paymentRepo, request.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := request.Reject(cmd.Actor); err != nil {
+		return nil, err
+	}
+
+	if err := uc.actionRepo.Update(request); err != nil {
+		return nil, err
+	}
+
+	if err := uc.auditLog.Append(domainPayment.NewPaymentAuditEntry(request, request.ApprovedBy, existingPayment.Status, existingPayment)); err != nil {
+		return nil, err
+	}
+
+	return toAdminPaymentActionResponse(request, existingPayment), nil
+}
+
+// ListPaymentAuditLogUseCase lists the admin actions recorded for a payment
+type ListPaymentAuditLogUseCase struct {
+	auditLog PaymentAuditLog
+}
+
+// NewListPaymentAuditLogUseCase creates a new instance of ListPaymentAuditLogUseCase
+func NewListPaymentAuditLogUseCase(auditLog PaymentAuditLog) *ListPaymentAuditLogUseCase {
+	return &ListPaymentAuditLogUseCase{
+		auditLog: auditLog,
+	}
+}
+
+// Execute lists the payment's audit log, oldest first
+func (uc *ListPaymentAuditLogUseCase) Execute(paymentID string) ([]PaymentAuditEntryResponse, error) {
+	if paymentID == "" {
+		return nil, domainPayment.ErrEmptyPaymentID
+	}
+
+	entries, err := uc.auditLog.FindByPaymentID(paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]PaymentAuditEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		responses = append(responses, PaymentAuditEntryResponse{
+			ActionID:     entry.ActionID,
+			Action:       string(entry.Action),
+			Event:        string(entry.Event),
+			Actor:        entry.Actor,
+			RequestedBy:  entry.RequestedBy,
+			ApprovedBy:   entry.ApprovedBy,
+			ReasonCode:   string(entry.ReasonCode),
+			Note:         entry.Note,
+			FromStatus:   string(entry.FromStatus),
+			ToStatus:     string(entry.ToStatus),
+			RefundAmount: entry.RefundAmount,
+			NetOfFees:    entry.NetOfFees,
+			ExpiresAt:    entry.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+			CreatedAt:    entry.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		})
+	}
+
+	return responses, nil
+}
+
+// executeAdminAction runs the action on the payment and saves the request,
+// the payment and the audit entry, in that order: the request goes first so a
+// second approval finds it decided. When a later write fails the earlier ones
+// are undone, so no payment change is left without its audit entry. A request
+// that was filed for approval goes back to filed; a new one is abandoned.
+// Once all three are saved the payment is posted to the ledger and its order
+// follows it: a held order resumes, a refunded one is marked refunded and a
+// newly confirmed one is marked paid, on behalf of actor.
+func executeAdminAction(request *domainPayment.AdminActionRequest, filed *domainPayment.AdminActionRequest, p *domainPayment.Payment, paymentRepo PaymentRepository, orderRepo OrderRepository, actionRepo AdminActionRepository, auditLog PaymentAuditLog, ledger PaymentLedger, actor string) error {
+	wasHeld := p.IsUnderReview()
+	wasCompleted := p.IsCompleted()
+	before := *p
+
+	rollback := *request
+	if filed != nil {
+		rollback = *filed
+	} else if err := rollback.Abandon(); err != nil {
+		return err
+	}
+
+	if err := request.Execute(p); err != nil {
+		return err
+	}
+
+	saveRequest := actionRepo.Save
+	if filed != nil {
+		saveRequest = actionRepo.Update
+	}
+	if err := saveRequest(request); err != nil {
+		*p = before
+		return err
+	}
+
+	if err := paymentRepo.Update(p); err != nil {
+		*p = before
+		return rollbackAdminAction(actionRepo, request, rollback, err)
+	}
+
+	if err := auditLog.Append(domainPayment.NewPaymentAuditEntry(request, actor, before.Status, p)); err != nil {
+		*p = before
+		if restoreErr := paymentRepo.Update(p); restoreErr != nil {
+			err = errors.Join(err, restoreErr)
+		}
+		return rollbackAdminAction(actionRepo, request, rollback, err)
+	}
+
+	if request.Action.MovesFunds() {
+		if err := recordInLedger(ledger, p); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case wasHeld:
+		return releaseOrderHold(orderRepo, p, actor)
+	case request.Action == domainPayment.AdminActionRefund:
+		return refundOrder(orderRepo, p, actor)
+	case !wasCompleted:
+		return markOrderPaid(orderRepo, p)
+	}
+	return nil
+}
+
+// rollbackAdminAction stores the request as it is in rollback after a failed
+// write, and returns that write's error along with any error from the rollback
+func rollbackAdminAction(actionRepo AdminActionRepository, request *domainPayment.AdminActionRequest, rollback domainPayment.AdminActionRequest, err error) error {
+	*request = rollback
+	if rollbackErr := actionRepo.Update(request); rollbackErr != nil {
+		return errors.Join(err, rollbackErr)
+	}
+	return err
+}
+
+// refundOrder moves the order paid by a payment an admin refunded to
+// PARTIALLY_REFUNDED, or REFUNDED once the whole payment has gone back. An
+// order paid by another payment, or one whose state machine does not allow a
+// refund (e.g. a cancellation already waiting on it), is left unchanged.
+func refundOrder(orderRepo OrderRepository, p *domainPayment.Payment, actor string) error {
+	existingOrder, err := findPaymentOrder(orderRepo, p.OrderID)
+	if err != nil {
+		return err
+	}
+
+	if existingOrder.PaymentID == nil || *existingOrder.PaymentID != p.ID {
+		return nil
+	}
+
+	target := domainOrder.StatusPartiallyRefunded
+	if p.IsFullyRefunded() {
+		target = domainOrder.StatusRefunded
+	}
+
+	if !existingOrder.CanTransitionTo(target) {
+		return nil
+	}
+
+	if err := existingOrder.TransitionTo(target, actor, "payment refunded"); err != nil {
+		return err
+	}
+
+	return orderRepo.Update(existingOrder)
+}
+
+// findPayment loads a payment, mapping a missing one to ErrPaymentNotFound
+func findPayment(paymentRepo PaymentRepository, paymentID string) (*domainPayment.Payment, error) {
+	if paymentID == "" {
+		return nil, domainPayment.ErrEmptyPaymentID
+	}
+
+	existingPayment, err := paymentRepo.FindByID(paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingPayment == nil {
+		return nil, domainPayment.ErrPaymentNotFound
+	}
+
+	return existingPayment, nil
+}
+
+// findAdminAction loads an admin action request, mapping a missing one to ErrAdminActionNotFound
+func findAdminAction(actionRepo AdminActionRepository, actionID string) (*domainPayment.AdminActionRequest, error) {
+	request, err := actionRepo.FindByID(actionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request == nil {
+		return nil, domainPayment.ErrAdminActionNotFound
+	}
+
+	return request, nil
+}
+
+// toAdminPaymentActionResponse maps an admin action request to its response
+func toAdminPaymentActionResponse(request *domainPayment.AdminActionRequest, p *domainPayment.Payment) *AdminPaymentActionResponse {
+	response := &AdminPaymentActionResponse{
+		ActionID:      request.ID,
+		PaymentID:     request.PaymentID,
+		Action:        string(request.Action),
+		Status:        string(request.Status),
+		ReasonCode:    string(request.ReasonCode),
+		Note:          request.Note,
+		RequestedBy:   request.RequestedBy,
+		ApprovedBy:    request.ApprovedBy,
+		PaymentStatus: string(p.Status),
+		RequestedAt:   request.RequestedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+
+	if request.DecidedAt != nil {
+		response.DecidedAt = request.DecidedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+
+	return response
+}
@@ -0,0 +1,502 @@
+package payment
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAdminActionRepository is a mock implementation of AdminActionRepository
+type MockAdminActionRepository struct {
+	mock.Mock
+}
+
+func (m *MockAdminActionRepository) Save(request *domainPayment.AdminActionRequest) error {
+	args := m.Called(request)
+	return args.Error(0)
+}
+
+func (m *MockAdminActionRepository) FindByID(id string) (*domainPayment.AdminActionRequest, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainPayment.AdminActionRequest), args.Error(1)
+}
+
+func (m *MockAdminActionRepository) Update(request *domainPayment.AdminActionRequest) error {
+	args := m.Called(request)
+	return args.Error(0)
+}
+
+// MockPaymentAuditLog is a mock implementation of PaymentAuditLog
+type MockPaymentAuditLog struct {
+	mock.Mock
+}
+
+func (m *MockPaymentAuditLog) Append(entry domainPayment.PaymentAuditEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockPaymentAuditLog) FindByPaymentID(paymentID string) ([]domainPayment.PaymentAuditEntry, error) {
+	args := m.Called(paymentID)
+	return args.Get(0).([]domainPayment.PaymentAuditEntry), args.Error(1)
+}
+
+var testApprovalPolicy = domainPayment.AdminApprovalPolicy{Currency: "USD", Threshold: 50}
+
+// createTestConfirmedPayment creates a confirmed 100 USD payment of 0.002 BTC
+func createTestConfirmedPayment() *domainPayment.Payment {
+	p := createTestPayment()
+	_ = p.UpdateCryptoAmount(0.002)
+	_ = p.MarkAsConfirmed()
+	return p
+}
+
+// newPaidOrderRepository creates an order paid by a confirmed 0.002 BTC
+// payment and returns a repository holding that order
+func newPaidOrderRepository() (*MockOrderRepository, *domainOrder.Order, *domainPayment.Payment) {
+	o, p := createPaidOrder()
+	_ = p.MarkAsConfirmed()
+
+	orderRepo := new(MockOrderRepository)
+	orderRepo.On("FindByID", o.ID).Return(o, nil)
+	orderRepo.On("Update", o).Return(nil)
+
+	return orderRepo, o, p
+}
+
+// Tests for AdminPaymentActionUseCase
+
+func TestAdminPaymentActionUseCase(t *testing.T) {
+	t.Run("action below the threshold runs at once", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		actionRepo := new(MockAdminActionRepository)
+		auditLog := new(MockPaymentAuditLog)
+		ledger := new(MockPaymentLedger)
+		orderRepo, o, p := newPaidOrderRepository()
+		useCase := NewAdminPaymentActionUseCase(paymentRepo, orderRepo, actionRepo, auditLog, testApprovalPolicy, ledger)
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		ledger.On("RecordPayment", p).Return(nil)
+		actionRepo.On("Save", mock.Anything).Return(nil)
+		auditLog.On("Append", mock.MatchedBy(func(e domainPayment.PaymentAuditEntry) bool {
+			return e.Event == domainPayment.AdminActionExecuted && e.Actor == "admin-1" &&
+				e.FromStatus == domainPayment.StatusConfirmed && e.RefundAmount == 0.0005
+		})).Return(nil)
+
+		response, err := useCase.Execute(AdminPaymentActionCommand{
+			PaymentID:    p.ID,
+			Action:       "REFUND",
+			Actor:        "admin-1",
+			ReasonCode:   "CUSTOMER_REQUEST",
+			RefundAmount: 0.0005,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "EXECUTED", response.Status)
+		assert.Equal(t, 0.0005, p.RefundedAmount)
+		assert.Equal(t, domainOrder.StatusPartiallyRefunded, o.Status)
+		auditLog.AssertExpectations(t)
+		ledger.AssertExpectations(t)
+	})
+
+	t.Run("failed audit entry undoes the action and closes the request", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		actionRepo := new(MockAdminActionRepository)
+		auditLog := new(MockPaymentAuditLog)
+		ledger := new(MockPaymentLedger)
+		orderRepo, o, p := newPaidOrderRepository()
+		useCase := NewAdminPaymentActionUseCase(paymentRepo, orderRepo, actionRepo, auditLog, testApprovalPolicy, ledger)
+
+		var saved *domainPayment.AdminActionRequest
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		actionRepo.On("Save", mock.Anything).Run(func(args mock.Arguments) {
+			saved = args.Get(0).(*domainPayment.AdminActionRequest)
+		}).Return(nil)
+		actionRepo.On("Update", mock.Anything).Return(nil)
+		auditLog.On("Append", mock.Anything).Return(errors.New("audit log unavailable"))
+
+		response, err := useCase.Execute(AdminPaymentActionCommand{
+			PaymentID:    p.ID,
+			Action:       "REFUND",
+			Actor:        "admin-1",
+			ReasonCode:   "CUSTOMER_REQUEST",
+			RefundAmount: 0.0005,
+		})
+
+		assert.Nil(t, response)
+		assert.EqualError(t, err, "audit log unavailable")
+		assert.Equal(t, domainPayment.StatusConfirmed, p.Status)
+		assert.Zero(t, p.RefundedAmount)
+		assert.Equal(t, domainPayment.AdminActionFailed, saved.Status)
+		assert.Equal(t, domainOrder.StatusPaid, o.Status)
+		paymentRepo.AssertNumberOfCalls(t, "Update", 2)
+		actionRepo.AssertCalled(t, "Update", saved)
+		ledger.AssertNotCalled(t, "RecordPayment", mock.Anything)
+		orderRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("action above the threshold waits for approval", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		actionRepo := new(MockAdminActionRepository)
+		auditLog := new(MockPaymentAuditLog)
+		useCase := NewAdminPaymentActionUseCase(paymentRepo, new(MockOrderRepository), actionRepo, auditLog, testApprovalPolicy, nil)
+
+		p := createTestPayment()
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		actionRepo.On("Save", mock.Anything).Return(nil)
+		auditLog.On("Append", mock.MatchedBy(func(e domainPayment.PaymentAuditEntry) bool {
+			return e.Event == domainPayment.AdminActionPendingApproval && e.ToStatus == domainPayment.StatusPending
+		})).Return(nil)
+
+		response, err := useCase.Execute(AdminPaymentActionCommand{
+			PaymentID:  p.ID,
+			Action:     "CONFIRM",
+			Actor:      "admin-1",
+			ReasonCode: "VERIFIED_ON_CHAIN",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "PENDING_APPROVAL", response.Status)
+		assert.Equal(t, "PENDING", response.PaymentStatus)
+		paymentRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("actor and reason are required", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		actionRepo := new(MockAdminActionRepository)
+		auditLog := new(MockPaymentAuditLog)
+		useCase := NewAdminPaymentActionUseCase(paymentRepo, new(MockOrderRepository), actionRepo, auditLog, testApprovalPolicy, nil)
+
+		p := createTestPayment()
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+
+		_, errActor := useCase.Execute(AdminPaymentActionCommand{PaymentID: p.ID, Action: "FAIL", ReasonCode: "FRAUD_SUSPECTED"})
+		_, errReason := useCase.Execute(AdminPaymentActionCommand{PaymentID: p.ID, Action: "FAIL", Actor: "admin-1"})
+
+		assert.Equal(t, domainPayment.ErrActorRequired, errActor)
+		assert.Equal(t, domainPayment.ErrInvalidReasonCode, errReason)
+		assert.Equal(t, domainPayment.StatusPending, p.Status)
+		actionRepo.AssertNotCalled(t, "Save", mock.Anything)
+		auditLog.AssertNotCalled(t, "Append", mock.Anything)
+	})
+
+	t.Run("extend expiry", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		actionRepo := new(MockAdminActionRepository)
+		auditLog := new(MockPaymentAuditLog)
+		ledger := new(MockPaymentLedger)
+		useCase := NewAdminPaymentActionUseCase(paymentRepo, new(MockOrderRepository), actionRepo, auditLog, testApprovalPolicy, ledger)
+
+		p := createTestPayment()
+		deadline := p.ExpiresAt
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		actionRepo.On("Save", mock.Anything).Return(nil)
+		auditLog.On("Append", mock.Anything).Return(nil)
+
+		response, err := useCase.Execute(AdminPaymentActionCommand{PaymentID: p.ID, Action: "EXTEND_EXPIRY", Actor: "admin-1", ReasonCode: "LATE_PAYMENT", ExtendMinutes: 30})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "EXECUTED", response.Status)
+		assert.Equal(t, deadline.Add(30*time.Minute), p.ExpiresAt)
+		ledger.AssertNotCalled(t, "RecordPayment", mock.Anything)
+	})
+
+	t.Run("confirming a payment marks its order paid", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		actionRepo := new(MockAdminActionRepository)
+		auditLog := new(MockPaymentAuditLog)
+		p := createTestPayment()
+		orderRepo, o := newAwaitingOrderRepository(p)
+		useCase := NewAdminPaymentActionUseCase(paymentRepo, orderRepo, actionRepo, auditLog, domainPayment.AdminApprovalPolicy{}, nil)
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		actionRepo.On("Save", mock.Anything).Return(nil)
+		auditLog.On("Append", mock.Anything).Return(nil)
+
+		response, err := useCase.Execute(AdminPaymentActionCommand{
+			PaymentID:  p.ID,
+			Action:     "CONFIRM",
+			Actor:      "admin-1",
+			ReasonCode: "VERIFIED_ON_CHAIN",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "CONFIRMED", response.PaymentStatus)
+		assert.Equal(t, domainOrder.StatusPaid, o.Status)
+		assert.Equal(t, p.ID, *o.PaymentID)
+	})
+
+	t.Run("confirming a held payment resumes its order", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		orderRepo := new(MockOrderRepository)
+		actionRepo := new(MockAdminActionRepository)
+		auditLog := new(MockPaymentAuditLog)
+		useCase := NewAdminPaymentActionUseCase(paymentRepo, orderRepo, actionRepo, auditLog, domainPayment.AdminApprovalPolicy{}, nil)
+
+		o, p := createPaidOrder()
+		_ = p.UpdateConfirmations(0)
+		_ = o.HoldForPaymentReview("CONFIRMATIONS_DROPPED")
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		orderRepo.On("Update", o).Return(nil)
+		actionRepo.On("Save", mock.Anything).Return(nil)
+		auditLog.On("Append", mock.Anything).Return(nil)
+
+		response, err := useCase.Execute(AdminPaymentActionCommand{
+			PaymentID:  p.ID,
+			Action:     "CONFIRM",
+			Actor:      "admin-1",
+			ReasonCode: "VERIFIED_ON_CHAIN",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "CONFIRMED", response.PaymentStatus)
+		assert.Equal(t, domainOrder.StatusPaid, o.Status)
+		assert.Equal(t, "admin-1", o.StatusHistory[len(o.StatusHistory)-1].Actor)
+		orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("failing a held payment leaves its order on hold", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		orderRepo := new(MockOrderRepository)
+		actionRepo := new(MockAdminActionRepository)
+		auditLog := new(MockPaymentAuditLog)
+		useCase := NewAdminPaymentActionUseCase(paymentRepo, orderRepo, actionRepo, auditLog, testApprovalPolicy, nil)
+
+		o, p := createPaidOrder()
+		_ = p.UpdateConfirmations(0)
+		_ = o.HoldForPaymentReview("CONFIRMATIONS_DROPPED")
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		actionRepo.On("Save", mock.Anything).Return(nil)
+		auditLog.On("Append", mock.Anything).Return(nil)
+
+		response, err := useCase.Execute(AdminPaymentActionCommand{
+			PaymentID:  p.ID,
+			Action:     "FAIL",
+			Actor:      "admin-1",
+			ReasonCode: "GATEWAY_ERROR",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "FAILED", response.PaymentStatus)
+		assert.Equal(t, domainOrder.StatusOnHold, o.Status)
+		orderRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("confirming a failed payment always waits for approval", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		actionRepo := new(MockAdminActionRepository)
+		auditLog := new(MockPaymentAuditLog)
+		useCase := NewAdminPaymentActionUseCase(paymentRepo, new(MockOrderRepository), actionRepo, auditLog, domainPayment.AdminApprovalPolicy{}, nil)
+
+		p := createTestPayment()
+		_ = p.MarkAsFailed()
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		actionRepo.On("Save", mock.Anything).Return(nil)
+		auditLog.On("Append", mock.Anything).Return(nil)
+
+		response, err := useCase.Execute(AdminPaymentActionCommand{
+			PaymentID:  p.ID,
+			Action:     "CONFIRM",
+			Actor:      "admin-1",
+			ReasonCode: "VERIFIED_ON_CHAIN",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "PENDING_APPROVAL", response.Status)
+		assert.Equal(t, "FAILED", response.PaymentStatus)
+		paymentRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
+
+// Tests for ApproveAdminPaymentActionUseCase and RejectAdminPaymentActionUseCase
+
+func TestDecideAdminPaymentActionUseCase(t *testing.T) {
+	createPendingRefund := func(p *domainPayment.Payment) *domainPayment.AdminActionRequest {
+		request, _ := domainPayment.NewAdminActionRequest(p.ID, domainPayment.AdminActionRefund, domainPayment.AdminActionParams{RefundAmount: 0.002}, domainPayment.ReasonDuplicatePayment, "", "admin-1", true)
+		return request
+	}
+
+	t.Run("second admin approves and runs the action", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		actionRepo := new(MockAdminActionRepository)
+		auditLog := new(MockPaymentAuditLog)
+		ledger := new(MockPaymentLedger)
+		orderRepo, o, p := newPaidOrderRepository()
+		useCase := NewApproveAdminPaymentActionUseCase(paymentRepo, orderRepo, actionRepo, auditLog, ledger)
+
+		request := createPendingRefund(p)
+
+		actionRepo.On("FindByID", request.ID).Return(request, nil)
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		ledger.On("RecordPayment", p).Return(nil)
+		actionRepo.On("Update", request).Return(nil)
+		auditLog.On("Append", mock.MatchedBy(func(e domainPayment.PaymentAuditEntry) bool {
+			return e.Actor == "admin-2" && e.RequestedBy == "admin-1" && e.ToStatus == domainPayment.StatusRefunded
+		})).Return(nil)
+
+		response, err := useCase.Execute(DecideAdminPaymentActionCommand{ActionID: request.ID, Actor: "admin-2"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "EXECUTED", response.Status)
+		assert.Equal(t, "admin-2", response.ApprovedBy)
+		assert.Equal(t, "REFUNDED", response.PaymentStatus)
+		assert.Equal(t, domainOrder.StatusRefunded, o.Status)
+		assert.Equal(t, "admin-2", o.StatusHistory[len(o.StatusHistory)-1].Actor)
+		auditLog.AssertExpectations(t)
+		ledger.AssertExpectations(t)
+	})
+
+	t.Run("failed audit entry undoes the refund so a second approval runs it once", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		actionRepo := new(MockAdminActionRepository)
+		auditLog := new(MockPaymentAuditLog)
+		ledger := new(MockPaymentLedger)
+		orderRepo, o, p := newPaidOrderRepository()
+		useCase := NewApproveAdminPaymentActionUseCase(paymentRepo, orderRepo, actionRepo, auditLog, ledger)
+
+		request := createPendingRefund(p)
+
+		actionRepo.On("FindByID", request.ID).Return(request, nil)
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		actionRepo.On("Update", request).Return(nil)
+		auditLog.On("Append", mock.Anything).Return(errors.New("audit log unavailable")).Once()
+		auditLog.On("Append", mock.Anything).Return(nil).Once()
+		ledger.On("RecordPayment", p).Return(nil).Once()
+
+		_, err := useCase.Execute(DecideAdminPaymentActionCommand{ActionID: request.ID, Actor: "admin-2"})
+
+		assert.EqualError(t, err, "audit log unavailable")
+		assert.Equal(t, domainPayment.AdminActionPendingApproval, request.Status)
+		assert.Empty(t, request.ApprovedBy)
+		assert.Equal(t, domainPayment.StatusConfirmed, p.Status)
+		assert.Zero(t, p.RefundedAmount)
+		assert.Equal(t, domainOrder.StatusPaid, o.Status)
+		ledger.AssertNotCalled(t, "RecordPayment", mock.Anything)
+
+		response, err := useCase.Execute(DecideAdminPaymentActionCommand{ActionID: request.ID, Actor: "admin-2"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "EXECUTED", response.Status)
+		assert.Equal(t, 0.002, p.RefundedAmount)
+		assert.Len(t, p.Refunds, 1)
+		assert.Equal(t, domainOrder.StatusRefunded, o.Status)
+		ledger.AssertExpectations(t)
+	})
+
+	t.Run("failed request update leaves the payment unchanged", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		actionRepo := new(MockAdminActionRepository)
+		auditLog := new(MockPaymentAuditLog)
+		orderRepo, _, p := newPaidOrderRepository()
+		useCase := NewApproveAdminPaymentActionUseCase(paymentRepo, orderRepo, actionRepo, auditLog, nil)
+
+		request := createPendingRefund(p)
+
+		actionRepo.On("FindByID", request.ID).Return(request, nil)
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		actionRepo.On("Update", request).Return(errors.New("database unavailable"))
+
+		_, err := useCase.Execute(DecideAdminPaymentActionCommand{ActionID: request.ID, Actor: "admin-2"})
+
+		assert.EqualError(t, err, "database unavailable")
+		assert.Equal(t, domainPayment.StatusConfirmed, p.Status)
+		assert.Zero(t, p.RefundedAmount)
+		paymentRepo.AssertNotCalled(t, "Update", mock.Anything)
+		auditLog.AssertNotCalled(t, "Append", mock.Anything)
+	})
+
+	t.Run("requester cannot approve", func(t *testing.T) {
+		actionRepo := new(MockAdminActionRepository)
+		auditLog := new(MockPaymentAuditLog)
+		useCase := NewApproveAdminPaymentActionUseCase(new(MockPaymentRepository), new(MockOrderRepository), actionRepo, auditLog, nil)
+
+		request := createPendingRefund(createTestConfirmedPayment())
+		actionRepo.On("FindByID", request.ID).Return(request, nil)
+
+		response, err := useCase.Execute(DecideAdminPaymentActionCommand{ActionID: request.ID, Actor: "admin-1"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainPayment.ErrSelfApproval, err)
+		auditLog.AssertNotCalled(t, "Append", mock.Anything)
+	})
+
+	t.Run("second admin rejects the action", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		actionRepo := new(MockAdminActionRepository)
+		auditLog := new(MockPaymentAuditLog)
+		useCase := NewRejectAdminPaymentActionUseCase(paymentRepo, actionRepo, auditLog)
+
+		p := createTestConfirmedPayment()
+		request := createPendingRefund(p)
+
+		actionRepo.On("FindByID", request.ID).Return(request, nil)
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		actionRepo.On("Update", request).Return(nil)
+		auditLog.On("Append", mock.MatchedBy(func(e domainPayment.PaymentAuditEntry) bool {
+			return e.Event == domainPayment.AdminActionRejected && e.Actor == "admin-2"
+		})).Return(nil)
+
+		response, err := useCase.Execute(DecideAdminPaymentActionCommand{ActionID: request.ID, Actor: "admin-2"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "REJECTED", response.Status)
+		assert.Equal(t, "CONFIRMED", response.PaymentStatus)
+		paymentRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("unknown action", func(t *testing.T) {
+		actionRepo := new(MockAdminActionRepository)
+		useCase := NewApproveAdminPaymentActionUseCase(new(MockPaymentRepository), new(MockOrderRepository), actionRepo, new(MockPaymentAuditLog), nil)
+
+		actionRepo.On("FindByID", "missing").Return(nil, nil)
+
+		_, err := useCase.Execute(DecideAdminPaymentActionCommand{ActionID: "missing", Actor: "admin-2"})
+
+		assert.Equal(t, domainPayment.ErrAdminActionNotFound, err)
+	})
+}
+
+// Tests for ListPaymentAuditLogUseCase
+
+func TestListPaymentAuditLogUseCase(t *testing.T) {
+	auditLog := new(MockPaymentAuditLog)
+	useCase := NewListPaymentAuditLogUseCase(auditLog)
+
+	p := createTestPayment()
+	request, _ := domainPayment.NewAdminActionRequest(p.ID, domainPayment.AdminActionFail, domainPayment.AdminActionParams{}, domainPayment.ReasonOther, "paid twice by mistake", "admin-1", false)
+	_ = request.Execute(p)
+	entry := domainPayment.NewPaymentAuditEntry(request, "admin-1", domainPayment.StatusPending, p)
+
+	auditLog.On("FindByPaymentID", p.ID).Return([]domainPayment.PaymentAuditEntry{entry}, nil)
+
+	responses, err := useCase.Execute(p.ID)
+
+	assert.NoError(t, err)
+	assert.Len(t, responses, 1)
+	assert.Equal(t, "FAIL", responses[0].Action)
+	assert.Equal(t, "PENDING", responses[0].FromStatus)
+	assert.Equal(t, "FAILED", responses[0].ToStatus)
+	assert.Equal(t, "paid twice by mistake", responses[0].Note)
+}
@@ -0,0 +1,94 @@
+package payment
+
+import (
+	"strings"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// CreateInvoiceCommand represents the input for an invoice the customer can pay in any allowed coin
+type CreateInvoiceCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	OrderID        string   `json:"order_id" validate:"required,uuid"`
+	AllowedCryptos []string `json:"allowed_cryptos"` // Defaults to every active coin
+	SuccessURL     string   `json:"success_url" validate:"omitempty,url"`
+	CancelURL      string   `json:"cancel_url" validate:"omitempty,url"`
+}
+
+// InvoiceResponse represents an invoice with the link to its payment page
+type InvoiceResponse struct {
+	InvoiceID      string   `json:"invoice_id"`
+	OrderID        string   `json:"order_id"`
+	InvoiceURL     string   `json:"invoice_url"`
+	Amount         float64  `json:"amount"`
+	Currency       string   `json:"currency"`
+	AllowedCryptos []string `json:"allowed_cryptos"`
+	ExpiresAt      string   `json:"expires_at"`
+	SuccessURL     string   `json:"success_url,omitempty"`
+	CancelURL      string   `json:"cancel_url,omitempty"`
+}
+
+// InvoiceRepository defines the interface for invoice persistence
+type InvoiceRepository interface {
+	Save(invoice *domainPayment.Invoice) error
+	FindByID(id string) (*domainPayment.Invoice, error)
+	Update(invoice *domainPayment.Invoice) error
+}
+
+// CreateInvoiceUseCase creates an invoice for an order awaiting payment. No
+// gateway payment is opened until the customer picks a coin.
+type CreateInvoiceUseCase struct {
+	invoiceRepo       InvoiceRepository
+	orderRepo         OrderRepository
+	registry          *domainPayment.CryptoRegistry
+	pageURL           string // Base URL of the hosted payment page
+	expirationMinutes int
+}
+
+// NewCreateInvoiceUseCase creates a new instance of CreateInvoiceUseCase
+func NewCreateInvoiceUseCase(invoiceRepo InvoiceRepository, orderRepo OrderRepository, registry *domainPayment.CryptoRegistry, pageURL string, expirationMinutes int) *CreateInvoiceUseCase {
+	return &CreateInvoiceUseCase{
+		invoiceRepo:       invoiceRepo,
+		orderRepo:         orderRepo,
+		registry:          registry,
+		pageURL:           strings.TrimSuffix(pageURL, "/"),
+		expirationMinutes: expirationMinutes,
+	}
+}
+
+// Execute creates the invoice
+func (uc *CreateInvoiceUseCase) Execute(cmd CreateInvoiceCommand) (*InvoiceResponse, error) {
+	existingOrder, err := findOrderAwaitingPayment(uc.orderRepo, cmd.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowedCryptos := cmd.AllowedCryptos
+	if len(allowedCryptos) == 0 {
+		for _, crypto := range uc.registry.Active() {
+			allowedCryptos = append(allowedCryptos, crypto.Symbol)
+		}
+	}
+
+	invoice, err := domainPayment.NewInvoice(cmd.OrderID, existingOrder.TotalAmount.Amount, existingOrder.TotalAmount.Currency, allowedCryptos, cmd.SuccessURL, cmd.CancelURL, uc.expirationMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.invoiceRepo.Save(invoice); err != nil {
+		return nil, err
+	}
+
+	return &InvoiceResponse{
+		InvoiceID:      invoice.ID,
+		OrderID:        invoice.OrderID,
+		InvoiceURL:     uc.pageURL + "/" + invoice.ID,
+		Amount:         invoice.Amount,
+		Currency:       invoice.Currency,
+		AllowedCryptos: invoice.AllowedCryptos,
+		ExpiresAt:      invoice.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+		SuccessURL:     invoice.SuccessURL,
+		CancelURL:      invoice.CancelURL,
+	}, nil
+}
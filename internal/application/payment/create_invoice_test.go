@@ -0,0 +1,102 @@
+package payment
+
+import (
+	"testing"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockInvoiceRepository is a mock implementation of InvoiceRepository
+type MockInvoiceRepository struct {
+	mock.Mock
+}
+
+func (m *MockInvoiceRepository) Save(invoice *domainPayment.Invoice) error {
+	args := m.Called(invoice)
+	return args.Error(0)
+}
+
+func (m *MockInvoiceRepository) FindByID(id string) (*domainPayment.Invoice, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainPayment.Invoice), args.Error(1)
+}
+
+func (m *MockInvoiceRepository) Update(invoice *domainPayment.Invoice) error {
+	args := m.Called(invoice)
+	return args.Error(0)
+}
+
+// Tests for CreateInvoiceUseCase
+
+func TestCreateInvoiceUseCase(t *testing.T) {
+	t.Run("invoice with allowed coins", func(t *testing.T) {
+		invoiceRepo := new(MockInvoiceRepository)
+		orderRepo := new(MockOrderRepository)
+		useCase := NewCreateInvoiceUseCase(invoiceRepo, orderRepo, createTestRegistry(), "https://pay.shop.example/invoices/", 60)
+
+		o := createPendingPaymentOrder()
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		invoiceRepo.On("Save", mock.AnythingOfType("*payment.Invoice")).Return(nil)
+
+		response, err := useCase.Execute(CreateInvoiceCommand{
+			OrderID:        o.ID.String(),
+			AllowedCryptos: []string{"btc", "eth"},
+			SuccessURL:     "https://shop.example/thanks",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://pay.shop.example/invoices/"+response.InvoiceID, response.InvoiceURL)
+		assert.Equal(t, 100.0, response.Amount)
+		assert.Equal(t, "USD", response.Currency)
+		assert.Equal(t, []string{"BTC", "ETH"}, response.AllowedCryptos)
+		invoiceRepo.AssertExpectations(t)
+	})
+
+	t.Run("defaults to active coins", func(t *testing.T) {
+		invoiceRepo := new(MockInvoiceRepository)
+		orderRepo := new(MockOrderRepository)
+		registry := createTestRegistry()
+		useCase := NewCreateInvoiceUseCase(invoiceRepo, orderRepo, registry, "https://pay.shop.example/invoices", 60)
+
+		o := createPendingPaymentOrder()
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		invoiceRepo.On("Save", mock.AnythingOfType("*payment.Invoice")).Return(nil)
+
+		response, err := useCase.Execute(CreateInvoiceCommand{OrderID: o.ID.String()})
+
+		assert.NoError(t, err)
+		assert.Len(t, response.AllowedCryptos, len(registry.Active()))
+	})
+
+	t.Run("paid order", func(t *testing.T) {
+		invoiceRepo := new(MockInvoiceRepository)
+		orderRepo := new(MockOrderRepository)
+		useCase := NewCreateInvoiceUseCase(invoiceRepo, orderRepo, createTestRegistry(), "https://pay.shop.example/invoices", 60)
+
+		o, _ := createPaidOrder()
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+
+		response, err := useCase.Execute(CreateInvoiceCommand{OrderID: o.ID.String(), AllowedCryptos: []string{"BTC"}})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainPayment.ErrOrderAlreadyPaid, err)
+		invoiceRepo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("invalid redirect", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		useCase := NewCreateInvoiceUseCase(new(MockInvoiceRepository), orderRepo, createTestRegistry(), "https://pay.shop.example/invoices", 60)
+
+		o := createPendingPaymentOrder()
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+
+		_, err := useCase.Execute(CreateInvoiceCommand{OrderID: o.ID.String(), AllowedCryptos: []string{"BTC"}, CancelURL: "/cart"})
+
+		assert.Equal(t, domainPayment.ErrInvalidRedirectURL, err)
+	})
+}
@@ -0,0 +1,314 @@
+package payment
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/application/idempotency"
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/google/uuid"
+)
+
+// CreatePaymentCommand represents the input for paying an order in crypto
+type CreatePaymentCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+	OrderID        string `json:"order_id" validate:"required,uuid"`
+	CryptoCurrency string `json:"crypto_currency" validate:"required"`
+}
+
+// PaymentGateway opens payments at the payment gateway
+type PaymentGateway interface {
+	CreatePayment(request domainPayment.GatewayPaymentRequest) (domainPayment.GatewayPayment, error)
+}
+
+// providerPaymentScope is the idempotency scope of provider payments being opened
+const providerPaymentScope = "payment.provider_payment"
+
+// CreatePaymentUseCase opens a payment for an order awaiting payment at the
+// provider routed for the coin, records it with the deposit address and
+// amount the provider returned, and links it to the order.
+//
+// The provider payment is reserved in the opening store, shared by every
+// instance, before it is opened. A retry for the same order and coin records
+// the provider payment an earlier attempt opened but could not record, or
+// returns the payment it recorded, instead of opening a second one.
+type CreatePaymentUseCase struct {
+	paymentRepo       PaymentRepository
+	orderRepo         OrderRepository
+	providers         *PaymentProviders
+	scopes            ConfirmationScopeResolver
+	feePolicy         domainPayment.FeePolicy
+	openings          idempotency.Store // nil disables reuse on retries
+	expirationMinutes int
+}
+
+// providerOpening is a provider payment opened for an order and coin, kept in
+// the opening store until the provider payment expires
+type providerOpening struct {
+	Gateway   *domainPayment.GatewayPayment `json:"gateway,omitempty"`    // nil until the provider opened it
+	PaymentID string                        `json:"payment_id,omitempty"` // Set once the payment is recorded
+
+	record idempotency.Record
+}
+
+// NewCreatePaymentUseCase creates a new instance of CreatePaymentUseCase
+func NewCreatePaymentUseCase(paymentRepo PaymentRepository, orderRepo OrderRepository, providers *PaymentProviders, scopes ConfirmationScopeResolver, feePolicy domainPayment.FeePolicy, openings idempotency.Store, expirationMinutes int) *CreatePaymentUseCase {
+	return &CreatePaymentUseCase{
+		paymentRepo:       paymentRepo,
+		orderRepo:         orderRepo,
+		providers:         providers,
+		scopes:            scopes,
+		feePolicy:         feePolicy,
+		openings:          openings,
+		expirationMinutes: expirationMinutes,
+	}
+}
+
+// Execute creates the payment
+func (uc *CreatePaymentUseCase) Execute(cmd CreatePaymentCommand) (*PaymentResponse, error) {
+	newPayment, err := uc.open(cmd.OrderID, cmd.CryptoCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPaymentResponse(newPayment), nil
+}
+
+// open opens and saves a provider payment for the order in the given coin
+func (uc *CreatePaymentUseCase) open(orderID string, cryptoSymbol string) (*domainPayment.Payment, error) {
+	existingOrder, err := findOrderAwaitingPayment(uc.orderRepo, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	crypto, err := domainPayment.GetCryptoCurrencyBySymbol(cryptoSymbol)
+	if err != nil {
+		return nil, err
+	}
+
+	// Testnet coins must never pay a live order, nor mainnet coins a test order
+	if domainPayment.EnvironmentOf(crypto).IsSandbox() != existingOrder.Sandbox {
+		return nil, domainPayment.ErrEnvironmentMismatch
+	}
+
+	scope, err := uc.scopes.ResolveConfirmationScope(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := uc.providers.ForCurrency(crypto.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	key := provider.Name() + ":" + orderID + ":" + crypto.Symbol
+	opening, err := uc.reserveOpening(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if opening.PaymentID != "" {
+		recorded, err := uc.paymentRepo.FindByID(opening.PaymentID)
+		if err != nil {
+			return nil, err
+		}
+
+		if recorded != nil && recorded.IsPending() {
+			if err := uc.linkOrder(existingOrder, recorded); err != nil {
+				return nil, err
+			}
+			return recorded, nil
+		}
+
+		// The recorded payment was closed early, e.g. failed by an admin
+		if err := uc.releaseOpening(opening); err != nil {
+			return nil, err
+		}
+		if opening, err = uc.reserveOpening(key); err != nil {
+			return nil, err
+		}
+		if opening.Gateway != nil {
+			return nil, idempotency.ErrRequestInProgress
+		}
+	}
+
+	// The payment expires with the provider payment, which may have been
+	// opened by an earlier attempt
+	expirationMinutes := uc.expirationMinutes
+	if opening.Gateway == nil {
+		opened, err := provider.CreatePayment(domainPayment.GatewayPaymentRequest{
+			OrderID:       orderID,
+			PriceAmount:   existingOrder.TotalAmount.Amount,
+			PriceCurrency: existingOrder.TotalAmount.Currency,
+			PayCurrency:   crypto.Symbol,
+			FeeSurcharge:  uc.feePolicy.Surcharge(existingOrder.TotalAmount.Amount),
+		})
+		if err != nil {
+			if releaseErr := uc.releaseOpening(opening); releaseErr != nil {
+				return nil, releaseErr
+			}
+			return nil, err
+		}
+
+		// If this fails the opening stays in progress until it expires, which
+		// still keeps a retry from opening a second provider payment
+		opening.Gateway = &opened
+		_ = uc.completeOpening(opening)
+	} else {
+		expirationMinutes = int(time.Until(opening.record.CreatedAt.Add(time.Duration(uc.expirationMinutes)*time.Minute)) / time.Minute)
+	}
+
+	newPayment, err := uc.record(orderID, existingOrder, crypto, provider.Name(), *opening.Gateway, scope, expirationMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	// If this fails a retry records the provider payment again, under a new ID
+	opening.PaymentID = newPayment.ID
+	_ = uc.completeOpening(opening)
+
+	if err := uc.linkOrder(existingOrder, newPayment); err != nil {
+		return nil, err
+	}
+
+	return newPayment, nil
+}
+
+// linkOrder links the payment to its order, unless it already is. A retry
+// returns the recorded payment and links it again if this failed.
+func (uc *CreatePaymentUseCase) linkOrder(existingOrder *domainOrder.Order, p *domainPayment.Payment) error {
+	if existingOrder.PaymentID != nil && *existingOrder.PaymentID == p.ID {
+		return nil
+	}
+
+	if err := existingOrder.LinkPayment(p.ID); err != nil {
+		return err
+	}
+
+	return uc.orderRepo.Update(existingOrder)
+}
+
+// record creates and saves the payment for a provider payment
+func (uc *CreatePaymentUseCase) record(orderID string, existingOrder *domainOrder.Order, crypto domainPayment.CryptoCurrency, providerName string, opened domainPayment.GatewayPayment, scope domainPayment.ConfirmationScope, expirationMinutes int) (*domainPayment.Payment, error) {
+	newPayment, err := domainPayment.NewPayment(orderID, existingOrder.TotalAmount.Amount, existingOrder.TotalAmount.Currency, crypto.Symbol, opened.PayAddress, expirationMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := newPayment.SetProviderReference(providerName, opened.GatewayPaymentID); err != nil {
+		return nil, err
+	}
+
+	if err := newPayment.SetConfirmationScope(scope); err != nil {
+		return nil, err
+	}
+
+	if err := newPayment.UpdateCryptoAmount(opened.PayAmount); err != nil {
+		return nil, err
+	}
+
+	// The provider priced the customer-borne fee into the amount it expects,
+	// so the gateway's status matches the amount the customer is shown
+	if err := newPayment.ApplyIncludedFeePolicy(uc.feePolicy); err != nil {
+		return nil, err
+	}
+
+	if err := uc.paymentRepo.Save(newPayment); err != nil {
+		return nil, err
+	}
+
+	return newPayment, nil
+}
+
+// reserveOpening reserves the key for opening a provider payment, or returns
+// the opening an earlier attempt stored under it. An opening is kept a minute
+// less than the provider payment, so a reused one has at least a minute left.
+func (uc *CreatePaymentUseCase) reserveOpening(key string) (*providerOpening, error) {
+	now := time.Now()
+	record := idempotency.Record{
+		Scope:     providerPaymentScope,
+		Key:       key,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Duration(uc.expirationMinutes-1) * time.Minute),
+	}
+
+	if uc.openings == nil {
+		return &providerOpening{record: record}, nil
+	}
+
+	existing, err := uc.openings.Reserve(record)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing == nil {
+		return &providerOpening{record: record}, nil
+	}
+
+	// Another attempt is opening the provider payment, or stopped before it
+	// could tell whether the provider opened one
+	if !existing.Completed {
+		return nil, idempotency.ErrRequestInProgress
+	}
+
+	opening := &providerOpening{record: *existing}
+	if err := json.Unmarshal(existing.Response, opening); err != nil {
+		return nil, err
+	}
+
+	return opening, nil
+}
+
+// completeOpening stores the provider payment and the recorded payment of an opening
+func (uc *CreatePaymentUseCase) completeOpening(opening *providerOpening) error {
+	if uc.openings == nil {
+		return nil
+	}
+
+	body, err := json.Marshal(opening)
+	if err != nil {
+		return err
+	}
+
+	record := opening.record
+	record.Response = body
+	record.Completed = true
+
+	return uc.openings.Complete(record)
+}
+
+// releaseOpening deletes an opening so a new provider payment can be opened
+func (uc *CreatePaymentUseCase) releaseOpening(opening *providerOpening) error {
+	if uc.openings == nil {
+		return nil
+	}
+
+	return uc.openings.Release(opening.record.Scope, opening.record.Key)
+}
+
+// findOrderAwaitingPayment loads an order and checks that it can be paid
+func findOrderAwaitingPayment(orderRepo OrderRepository, orderID string) (*domainOrder.Order, error) {
+	id, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, domainPayment.ErrEmptyOrderID
+	}
+
+	existingOrder, err := orderRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingOrder == nil {
+		return nil, domainOrder.ErrOrderNotFound
+	}
+
+	switch existingOrder.Status {
+	case domainOrder.StatusPendingPayment:
+		return existingOrder, nil
+	case domainOrder.StatusPaid:
+		return nil, domainPayment.ErrOrderAlreadyPaid
+	default:
+		return nil, domainPayment.ErrOrderNotAwaitingPayment
+	}
+}
@@ -0,0 +1,338 @@
+package payment
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/application/idempotency"
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// createPendingPaymentOrder creates an order that went through checkout and awaits payment
+func createPendingPaymentOrder() *domainOrder.Order {
+	price, _ := domainOrder.NewMoney(100.0, "USD")
+	item, _ := domainOrder.NewOrderItem(uuid.New(), 1, price)
+	o, _ := domainOrder.NewOrder("customer123", []domainOrder.OrderItem{item})
+	_ = o.MarkAsPendingPayment()
+	return o
+}
+
+// memoryOpeningStore is an in-memory idempotency.Store for tests
+type memoryOpeningStore struct {
+	mu      sync.Mutex
+	records map[string]idempotency.Record
+}
+
+func newMemoryOpeningStore() *memoryOpeningStore {
+	return &memoryOpeningStore{records: make(map[string]idempotency.Record)}
+}
+
+func (s *memoryOpeningStore) Reserve(record idempotency.Record) (*idempotency.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.records[record.Scope+"/"+record.Key]; ok && !existing.IsExpired(record.CreatedAt) {
+		return &existing, nil
+	}
+	s.records[record.Scope+"/"+record.Key] = record
+	return nil, nil
+}
+
+func (s *memoryOpeningStore) Complete(record idempotency.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[record.Scope+"/"+record.Key] = record
+	return nil
+}
+
+func (s *memoryOpeningStore) Release(scope, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, scope+"/"+key)
+	return nil
+}
+
+// age moves every record back in time, as if it was stored earlier
+func (s *memoryOpeningStore) age(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, record := range s.records {
+		record.CreatedAt = record.CreatedAt.Add(-d)
+		record.ExpiresAt = record.ExpiresAt.Add(-d)
+		s.records[key] = record
+	}
+}
+
+// Tests for CreatePaymentUseCase
+
+func TestCreatePaymentUseCase(t *testing.T) {
+	opened := domainPayment.GatewayPayment{
+		GatewayPaymentID: "np-123",
+		PayAddress:       "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh",
+		PayAmount:        0.0015,
+	}
+
+	t.Run("opens and records the payment", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		orderRepo := new(MockOrderRepository)
+		gateway := newMockPaymentProvider("nowpayments")
+		useCase := NewCreatePaymentUseCase(paymentRepo, orderRepo, providersOf(gateway), newTestScopeResolver(), domainPayment.DefaultFeePolicy(), nil, 30)
+
+		o := createPendingPaymentOrder()
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		orderRepo.On("Update", o).Return(nil)
+		gateway.On("CreatePayment", domainPayment.GatewayPaymentRequest{
+			OrderID:       o.ID.String(),
+			PriceAmount:   100.0,
+			PriceCurrency: "USD",
+			PayCurrency:   "BTC",
+		}).Return(opened, nil)
+		paymentRepo.On("Save", mock.MatchedBy(func(p *domainPayment.Payment) bool {
+			return p.Provider == "nowpayments" && p.ProviderReference == "np-123" && p.OrderID == o.ID.String()
+		})).Return(nil)
+
+		response, err := useCase.Execute(CreatePaymentCommand{OrderID: o.ID.String(), CryptoCurrency: "btc"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, opened.PayAddress, response.WalletAddress)
+		assert.Equal(t, 0.0015, response.CryptoAmount)
+		assert.Equal(t, "PENDING", response.Status)
+		assert.Equal(t, response.PaymentID, *o.PaymentID)
+		paymentRepo.AssertExpectations(t)
+	})
+
+	t.Run("customer-borne fee is priced in by the provider", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		orderRepo := new(MockOrderRepository)
+		gateway := newMockPaymentProvider("nowpayments")
+		policy, _ := domainPayment.NewFeePolicy(domainPayment.FeeBearerCustomer, 0.005)
+		useCase := NewCreatePaymentUseCase(paymentRepo, orderRepo, providersOf(gateway), newTestScopeResolver(), policy, nil, 30)
+
+		o := createPendingPaymentOrder()
+		withFee := domainPayment.GatewayPayment{GatewayPaymentID: "np-124", PayAddress: opened.PayAddress, PayAmount: 0.002}
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		orderRepo.On("Update", o).Return(nil)
+		gateway.On("CreatePayment", mock.MatchedBy(func(request domainPayment.GatewayPaymentRequest) bool {
+			return request.PriceAmount == 100.0 && math.Abs(request.FeeSurcharge-100.0/0.995+100.0) < 1e-9
+		})).Return(withFee, nil)
+		paymentRepo.On("Save", mock.Anything).Return(nil)
+
+		response, err := useCase.Execute(CreatePaymentCommand{OrderID: o.ID.String(), CryptoCurrency: "BTC"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0.002, response.CryptoAmount) // What the gateway expects
+		assert.InDelta(t, 0.00001, response.FeeSurcharge, 1e-12)
+		gateway.AssertExpectations(t)
+	})
+
+	t.Run("paid order", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		gateway := newMockPaymentProvider("nowpayments")
+		useCase := NewCreatePaymentUseCase(new(MockPaymentRepository), orderRepo, providersOf(gateway), newTestScopeResolver(), domainPayment.DefaultFeePolicy(), nil, 30)
+
+		o, _ := createPaidOrder()
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		orderRepo.On("Update", o).Return(nil)
+
+		response, err := useCase.Execute(CreatePaymentCommand{OrderID: o.ID.String(), CryptoCurrency: "BTC"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainPayment.ErrOrderAlreadyPaid, err)
+		gateway.AssertNotCalled(t, "CreatePayment", mock.Anything)
+	})
+
+	t.Run("order not checked out", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		gateway := newMockPaymentProvider("nowpayments")
+		useCase := NewCreatePaymentUseCase(new(MockPaymentRepository), orderRepo, providersOf(gateway), newTestScopeResolver(), domainPayment.DefaultFeePolicy(), nil, 30)
+
+		o := createPendingPaymentOrder()
+		o.Status = domainOrder.StatusCreated
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		orderRepo.On("Update", o).Return(nil)
+
+		_, err := useCase.Execute(CreatePaymentCommand{OrderID: o.ID.String(), CryptoCurrency: "BTC"})
+
+		assert.Equal(t, domainPayment.ErrOrderNotAwaitingPayment, err)
+		gateway.AssertNotCalled(t, "CreatePayment", mock.Anything)
+	})
+
+	t.Run("mainnet coin cannot pay a test order", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		gateway := newMockPaymentProvider("nowpayments")
+		useCase := NewCreatePaymentUseCase(new(MockPaymentRepository), orderRepo, providersOf(gateway), newTestScopeResolver(), domainPayment.DefaultFeePolicy(), nil, 30)
+
+		o := createPendingPaymentOrder()
+		_ = o.MarkAsSandbox()
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		orderRepo.On("Update", o).Return(nil)
+
+		_, err := useCase.Execute(CreatePaymentCommand{OrderID: o.ID.String(), CryptoCurrency: "BTC"})
+
+		assert.Equal(t, domainPayment.ErrEnvironmentMismatch, err)
+		gateway.AssertNotCalled(t, "CreatePayment", mock.Anything)
+	})
+
+	t.Run("testnet coin cannot pay a live order", func(t *testing.T) {
+		previous := domainPayment.DefaultCryptoRegistry()
+		defer domainPayment.SetDefaultCryptoRegistry(previous)
+		registry, _ := domainPayment.NewCryptoRegistry(domainPayment.DefaultTestnetCryptoCurrencies())
+		domainPayment.SetDefaultCryptoRegistry(registry)
+
+		orderRepo := new(MockOrderRepository)
+		gateway := newMockPaymentProvider("nowpayments")
+		useCase := NewCreatePaymentUseCase(new(MockPaymentRepository), orderRepo, providersOf(gateway), newTestScopeResolver(), domainPayment.DefaultFeePolicy(), nil, 30)
+
+		o := createPendingPaymentOrder()
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		orderRepo.On("Update", o).Return(nil)
+
+		_, err := useCase.Execute(CreatePaymentCommand{OrderID: o.ID.String(), CryptoCurrency: "BTC"})
+
+		assert.Equal(t, domainPayment.ErrEnvironmentMismatch, err)
+		gateway.AssertNotCalled(t, "CreatePayment", mock.Anything)
+	})
+
+	t.Run("retry after a failed save records the payment already opened", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		orderRepo := new(MockOrderRepository)
+		gateway := newMockPaymentProvider("nowpayments")
+		openings := newMemoryOpeningStore()
+		useCase := NewCreatePaymentUseCase(paymentRepo, orderRepo, providersOf(gateway), newTestScopeResolver(), domainPayment.DefaultFeePolicy(), openings, 30)
+		saveErr := errors.New("database down")
+
+		o := createPendingPaymentOrder()
+		var saved *domainPayment.Payment
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		orderRepo.On("Update", o).Return(nil)
+		gateway.On("CreatePayment", mock.Anything).Return(opened, nil).Once()
+		paymentRepo.On("Save", mock.Anything).Return(saveErr).Once()
+		paymentRepo.On("Save", mock.MatchedBy(func(p *domainPayment.Payment) bool {
+			return p.ProviderReference == "np-123"
+		})).Run(func(args mock.Arguments) {
+			saved = args.Get(0).(*domainPayment.Payment)
+		}).Return(nil)
+
+		_, err := useCase.Execute(CreatePaymentCommand{OrderID: o.ID.String(), CryptoCurrency: "BTC"})
+		assert.Equal(t, saveErr, err)
+
+		// The provider payment was opened 10 minutes before the retry
+		openings.age(10 * time.Minute)
+
+		response, err := useCase.Execute(CreatePaymentCommand{OrderID: o.ID.String(), CryptoCurrency: "BTC"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, opened.PayAddress, response.WalletAddress)
+		assert.False(t, saved.ExpiresAt.After(time.Now().Add(20*time.Minute)), "expires with the provider payment")
+		assert.WithinDuration(t, time.Now().Add(20*time.Minute), saved.ExpiresAt, 2*time.Minute)
+		gateway.AssertNumberOfCalls(t, "CreatePayment", 1)
+	})
+
+	t.Run("retry on another instance records the payment already opened", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		orderRepo := new(MockOrderRepository)
+		gateway := newMockPaymentProvider("nowpayments")
+		openings := newMemoryOpeningStore()
+		first := NewCreatePaymentUseCase(paymentRepo, orderRepo, providersOf(gateway), newTestScopeResolver(), domainPayment.DefaultFeePolicy(), openings, 30)
+		second := NewCreatePaymentUseCase(paymentRepo, orderRepo, providersOf(gateway), newTestScopeResolver(), domainPayment.DefaultFeePolicy(), openings, 30)
+
+		o := createPendingPaymentOrder()
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		orderRepo.On("Update", o).Return(nil)
+		gateway.On("CreatePayment", mock.Anything).Return(opened, nil).Once()
+		paymentRepo.On("Save", mock.Anything).Return(errors.New("database down")).Once()
+		paymentRepo.On("Save", mock.Anything).Return(nil)
+
+		_, err := first.Execute(CreatePaymentCommand{OrderID: o.ID.String(), CryptoCurrency: "BTC"})
+		assert.Error(t, err)
+
+		response, err := second.Execute(CreatePaymentCommand{OrderID: o.ID.String(), CryptoCurrency: "BTC"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, opened.PayAddress, response.WalletAddress)
+		gateway.AssertNumberOfCalls(t, "CreatePayment", 1)
+	})
+
+	t.Run("retry after a recorded payment returns it", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		orderRepo := new(MockOrderRepository)
+		gateway := newMockPaymentProvider("nowpayments")
+		useCase := NewCreatePaymentUseCase(paymentRepo, orderRepo, providersOf(gateway), newTestScopeResolver(), domainPayment.DefaultFeePolicy(), newMemoryOpeningStore(), 30)
+
+		o := createPendingPaymentOrder()
+		var saved *domainPayment.Payment
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		orderRepo.On("Update", o).Return(nil)
+		gateway.On("CreatePayment", mock.Anything).Return(opened, nil).Once()
+		paymentRepo.On("Save", mock.Anything).Run(func(args mock.Arguments) {
+			saved = args.Get(0).(*domainPayment.Payment)
+		}).Return(nil).Once()
+
+		first, err := useCase.Execute(CreatePaymentCommand{OrderID: o.ID.String(), CryptoCurrency: "BTC"})
+		assert.NoError(t, err)
+		paymentRepo.On("FindByID", first.PaymentID).Return(saved, nil)
+
+		second, err := useCase.Execute(CreatePaymentCommand{OrderID: o.ID.String(), CryptoCurrency: "BTC"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, first.PaymentID, second.PaymentID)
+		gateway.AssertNumberOfCalls(t, "CreatePayment", 1)
+		paymentRepo.AssertNumberOfCalls(t, "Save", 1)
+	})
+
+	t.Run("payment still being opened elsewhere", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		gateway := newMockPaymentProvider("nowpayments")
+		openings := newMemoryOpeningStore()
+		useCase := NewCreatePaymentUseCase(new(MockPaymentRepository), orderRepo, providersOf(gateway), newTestScopeResolver(), domainPayment.DefaultFeePolicy(), openings, 30)
+
+		o := createPendingPaymentOrder()
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		orderRepo.On("Update", o).Return(nil)
+		now := time.Now()
+		_, _ = openings.Reserve(idempotency.Record{
+			Scope:     providerPaymentScope,
+			Key:       "nowpayments:" + o.ID.String() + ":BTC",
+			CreatedAt: now,
+			ExpiresAt: now.Add(29 * time.Minute),
+		})
+
+		_, err := useCase.Execute(CreatePaymentCommand{OrderID: o.ID.String(), CryptoCurrency: "BTC"})
+
+		assert.Equal(t, idempotency.ErrRequestInProgress, err)
+		gateway.AssertNotCalled(t, "CreatePayment", mock.Anything)
+	})
+
+	t.Run("gateway error", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		orderRepo := new(MockOrderRepository)
+		gateway := newMockPaymentProvider("nowpayments")
+		useCase := NewCreatePaymentUseCase(paymentRepo, orderRepo, providersOf(gateway), newTestScopeResolver(), domainPayment.DefaultFeePolicy(), newMemoryOpeningStore(), 30)
+		gatewayErr := errors.New("gateway down")
+
+		o := createPendingPaymentOrder()
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		orderRepo.On("Update", o).Return(nil)
+		gateway.On("CreatePayment", mock.Anything).Return(domainPayment.GatewayPayment{}, gatewayErr)
+
+		_, err1 := useCase.Execute(CreatePaymentCommand{OrderID: o.ID.String(), CryptoCurrency: "BTC"})
+		_, err2 := useCase.Execute(CreatePaymentCommand{OrderID: o.ID.String(), CryptoCurrency: "BTC"})
+
+		assert.Equal(t, gatewayErr, err1)
+		assert.Equal(t, gatewayErr, err2) // The opening was released, so the retry reached the gateway
+		gateway.AssertNumberOfCalls(t, "CreatePayment", 2)
+		paymentRepo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+}
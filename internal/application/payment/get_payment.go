@@ -0,0 +1,131 @@
+package payment
+
+import (
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// GetPaymentCommand represents the input for retrieving a payment
+type GetPaymentCommand struct {
+	PaymentID string `json:"payment_id" validate:"required"`
+}
+
+// PaymentRefundResponse represents a refund issued on a payment
+type PaymentRefundResponse struct {
+	Amount           float64 `json:"amount"`
+	FeesWithheld     float64 `json:"fees_withheld,omitempty"` // Kept back from a net-of-fees refund
+	NetAmount        float64 `json:"net_amount"`              // Sent back to the customer
+	TransactionHash  string  `json:"transaction_hash,omitempty"`
+	TransactionURL   string  `json:"transaction_url,omitempty"`
+	ProviderRefundID string  `json:"provider_refund_id,omitempty"` // Set when the provider sends the refund
+	RequestedAt      string  `json:"requested_at"`
+	SentAt           string  `json:"sent_at,omitempty"`
+}
+
+// PaymentResponse represents a payment with its on-chain details
+type PaymentResponse struct {
+	PaymentID               string                  `json:"payment_id"`
+	OrderID                 string                  `json:"order_id"`
+	Environment             string                  `json:"environment"` // LIVE, or SANDBOX for testnet coins
+	Status                  string                  `json:"status"`
+	ExpiresAt               string                  `json:"expires_at"`
+	Amount                  float64                 `json:"amount"`
+	Currency                string                  `json:"currency"`
+	CryptoAmount            float64                 `json:"crypto_amount"`
+	CryptoCurrency          string                  `json:"crypto_currency"`
+	Network                 string                  `json:"network"`
+	WalletAddress           string                  `json:"wallet_address"`
+	Provider                string                  `json:"provider,omitempty"`
+	ProviderReference       string                  `json:"provider_reference,omitempty"` // Payment ID at the provider
+	PaymentURI              string                  `json:"payment_uri,omitempty"`        // BIP21, EIP-681 or ripple: link for wallets
+	TransactionHash         string                  `json:"transaction_hash,omitempty"`
+	TransactionURL          string                  `json:"transaction_url,omitempty"`
+	ReplacedTransactionHash string                  `json:"replaced_transaction_hash,omitempty"`
+	Confirmations           int                     `json:"confirmations"`
+	RequiredConfirmations   int                     `json:"required_confirmations"`
+	ZeroConfRisk            bool                    `json:"zero_conf_risk"`
+	HoldReason              string                  `json:"hold_reason,omitempty"` // Set while the payment is under review
+	FeeBearer               string                  `json:"fee_bearer"`
+	FeeSurcharge            float64                 `json:"fee_surcharge,omitempty"` // Customer-borne fee included in crypto_amount
+	GatewayFee              float64                 `json:"gateway_fee"`
+	NetworkFee              float64                 `json:"network_fee"`
+	NetAmount               float64                 `json:"net_amount"` // Crypto the merchant receives after fees
+	RefundedAmount          float64                 `json:"refunded_amount"`
+	RefundTransactionHash   string                  `json:"refund_transaction_hash,omitempty"`
+	RefundTransactionURL    string                  `json:"refund_transaction_url,omitempty"`
+	Refunds                 []PaymentRefundResponse `json:"refunds"`
+}
+
+// GetPaymentUseCase retrieves a payment with block explorer links
+type GetPaymentUseCase struct {
+	paymentRepo PaymentRepository
+}
+
+// NewGetPaymentUseCase creates a new instance of GetPaymentUseCase
+func NewGetPaymentUseCase(paymentRepo PaymentRepository) *GetPaymentUseCase {
+	return &GetPaymentUseCase{
+		paymentRepo: paymentRepo,
+	}
+}
+
+// Execute retrieves the payment
+func (uc *GetPaymentUseCase) Execute(cmd GetPaymentCommand) (*PaymentResponse, error) {
+	p, err := uc.paymentRepo.FindByID(cmd.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPaymentResponse(p), nil
+}
+
+// toPaymentResponse maps a payment to its response
+func toPaymentResponse(p *domainPayment.Payment) *PaymentResponse {
+	refunds := make([]PaymentRefundResponse, 0, len(p.Refunds))
+	for _, refund := range p.Refunds {
+		response := PaymentRefundResponse{
+			Amount:           refund.Amount,
+			FeesWithheld:     refund.FeesWithheld,
+			NetAmount:        refund.NetAmount(),
+			TransactionHash:  refund.TransactionHash,
+			TransactionURL:   p.CryptoCurrency.TransactionURL(refund.TransactionHash),
+			ProviderRefundID: refund.ProviderReference,
+			RequestedAt:      refund.RequestedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+		if refund.SentAt != nil {
+			response.SentAt = refund.SentAt.Format("2006-01-02T15:04:05Z07:00")
+		}
+		refunds = append(refunds, response)
+	}
+
+	return &PaymentResponse{
+		PaymentID:               p.ID,
+		OrderID:                 p.OrderID,
+		Environment:             string(p.Environment),
+		Status:                  string(p.Status),
+		ExpiresAt:               p.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+		Amount:                  p.Amount,
+		Currency:                p.Currency,
+		CryptoAmount:            p.CryptoAmount,
+		CryptoCurrency:          p.CryptoCurrency.Symbol,
+		Network:                 p.CryptoCurrency.Network.String(),
+		WalletAddress:           p.GetWalletAddress(),
+		Provider:                p.Provider,
+		ProviderReference:       p.ProviderReference,
+		PaymentURI:              paymentURI(p),
+		TransactionHash:         p.TransactionHash,
+		TransactionURL:          p.TransactionURL(),
+		ReplacedTransactionHash: p.ReplacedTransactionHash,
+		Confirmations:           p.Confirmations,
+		RequiredConfirmations:   p.RequiredConfirmations,
+		ZeroConfRisk:            p.ZeroConfRisk,
+		HoldReason:              string(p.HoldReason),
+		FeeBearer:               string(p.FeeBearer),
+		FeeSurcharge:            p.FeeSurcharge,
+		GatewayFee:              p.GatewayFee,
+		NetworkFee:              p.NetworkFee,
+		NetAmount:               p.NetAmount(),
+		RefundedAmount:          p.RefundedAmount,
+		RefundTransactionHash:   p.RefundTransactionHash,
+		RefundTransactionURL:    p.RefundTransactionURL(),
+		Refunds:                 refunds,
+	}
+}
@@ -0,0 +1,65 @@
+package payment
+
+import (
+	"errors"
+	"testing"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests for GetPaymentUseCase
+
+func TestGetPaymentUseCase(t *testing.T) {
+	t.Run("payment with explorer links", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		useCase := NewGetPaymentUseCase(paymentRepo)
+
+		p := createTestPayment()
+		_ = p.UpdateCryptoAmount(0.001)
+		_ = p.MarkAsConfirming("4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")
+		p.Status = domainPayment.StatusConfirmed
+		_ = p.PartialRefund(0.0004)
+		_ = p.SetRefundTransactionHash("a1075db55d416d3ca199f55b6084e2115b9345e16c5cf302fc80e9d5fbf5d48d")
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+
+		response, err := useCase.Execute(GetPaymentCommand{PaymentID: p.ID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "bitcoin", response.Network)
+		assert.Equal(t, "https://mempool.space/tx/4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b", response.TransactionURL)
+		assert.Equal(t, "https://mempool.space/tx/a1075db55d416d3ca199f55b6084e2115b9345e16c5cf302fc80e9d5fbf5d48d", response.RefundTransactionURL)
+		assert.Len(t, response.Refunds, 1)
+		assert.Equal(t, response.RefundTransactionURL, response.Refunds[0].TransactionURL)
+		assert.NotEmpty(t, response.Refunds[0].SentAt)
+	})
+
+	t.Run("pending payment has no links", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		useCase := NewGetPaymentUseCase(paymentRepo)
+
+		p := createTestPayment()
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+
+		response, err := useCase.Execute(GetPaymentCommand{PaymentID: p.ID})
+
+		assert.NoError(t, err)
+		assert.Empty(t, response.TransactionURL)
+		assert.Empty(t, response.RefundTransactionURL)
+		assert.Empty(t, response.Refunds)
+	})
+
+	t.Run("payment not found", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		useCase := NewGetPaymentUseCase(paymentRepo)
+		notFound := errors.New("payment not found")
+
+		paymentRepo.On("FindByID", "missing").Return(nil, notFound)
+
+		response, err := useCase.Execute(GetPaymentCommand{PaymentID: "missing"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, notFound, err)
+	})
+}
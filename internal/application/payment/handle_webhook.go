@@ -0,0 +1,59 @@
+package payment
+
+import (
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// HandleWebhookCommand represents a webhook call received from a payment provider
+type HandleWebhookCommand struct {
+	Provider  string // From the webhook URL, e.g. /webhooks/nowpayments
+	Payload   []byte // Raw request body, exactly as signed
+	Signature string // From the provider's signature header
+}
+
+// PaymentReferenceFinder defines the interface for loading a payment by its ID at a provider
+type PaymentReferenceFinder interface {
+	FindByProviderReference(provider string, reference string) (*domainPayment.Payment, error)
+}
+
+// HandleWebhookUseCase verifies a provider's webhook and applies the payment
+// state it reports through TrackConfirmationsUseCase, the path the reconciler
+// takes too
+type HandleWebhookUseCase struct {
+	providers *PaymentProviders
+	finder    PaymentReferenceFinder
+	tracker   *TrackConfirmationsUseCase
+}
+
+// NewHandleWebhookUseCase creates a new instance of HandleWebhookUseCase
+func NewHandleWebhookUseCase(providers *PaymentProviders, finder PaymentReferenceFinder, tracker *TrackConfirmationsUseCase) *HandleWebhookUseCase {
+	return &HandleWebhookUseCase{
+		providers: providers,
+		finder:    finder,
+		tracker:   tracker,
+	}
+}
+
+// Execute verifies the webhook and applies it
+func (uc *HandleWebhookUseCase) Execute(cmd HandleWebhookCommand) (*PaymentResponse, error) {
+	provider, err := uc.providers.Get(cmd.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := provider.VerifyWebhook(cmd.Payload, cmd.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	existingPayment, err := uc.finder.FindByProviderReference(provider.Name(), remote.GatewayPaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingPayment == nil {
+		return nil, domainPayment.ErrPaymentNotFound
+	}
+
+	return uc.tracker.Execute(toTrackConfirmationsCommand(existingPayment.ID, remote))
+}
@@ -0,0 +1,81 @@
+package payment
+
+import (
+	"testing"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPaymentReferenceFinder is a mock implementation of PaymentReferenceFinder
+type MockPaymentReferenceFinder struct {
+	mock.Mock
+}
+
+func (m *MockPaymentReferenceFinder) FindByProviderReference(provider string, reference string) (*domainPayment.Payment, error) {
+	args := m.Called(provider, reference)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainPayment.Payment), args.Error(1)
+}
+
+// Tests for HandleWebhookUseCase
+
+func TestHandleWebhookUseCase(t *testing.T) {
+	payload := []byte(`{"payment_id":5077125051,"payment_status":"confirming"}`)
+
+	t.Run("verified webhook is applied", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		finder := new(MockPaymentReferenceFinder)
+		provider := newMockPaymentProvider("nowpayments")
+		tracker := NewTrackConfirmationsUseCase(paymentRepo, new(MockOrderRepository), new(MockOperatorAlerter), nil)
+		useCase := NewHandleWebhookUseCase(providersOf(provider), finder, tracker)
+
+		p := createGatewayPayment("5077125051")
+
+		provider.On("VerifyWebhook", payload, "sig").Return(domainPayment.GatewayPaymentStatus{
+			GatewayPaymentID: "5077125051",
+			Status:           domainPayment.GatewayStatusConfirming,
+			TransactionHash:  testTransactionHash,
+		}, nil)
+		finder.On("FindByProviderReference", "nowpayments", "5077125051").Return(p, nil)
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+
+		response, err := useCase.Execute(HandleWebhookCommand{Provider: "nowpayments", Payload: payload, Signature: "sig"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "CONFIRMING", response.Status)
+		assert.Equal(t, testTransactionHash, response.TransactionHash)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		finder := new(MockPaymentReferenceFinder)
+		provider := newMockPaymentProvider("nowpayments")
+		useCase := NewHandleWebhookUseCase(providersOf(provider), finder, nil)
+
+		provider.On("VerifyWebhook", payload, "forged").Return(domainPayment.GatewayPaymentStatus{}, domainPayment.ErrWebhookValidationFailed)
+
+		_, err := useCase.Execute(HandleWebhookCommand{Provider: "nowpayments", Payload: payload, Signature: "forged"})
+
+		assert.Equal(t, domainPayment.ErrWebhookValidationFailed, err)
+		finder.AssertNotCalled(t, "FindByProviderReference", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown provider or payment", func(t *testing.T) {
+		finder := new(MockPaymentReferenceFinder)
+		provider := newMockPaymentProvider("nowpayments")
+		useCase := NewHandleWebhookUseCase(providersOf(provider), finder, nil)
+
+		provider.On("VerifyWebhook", payload, "sig").Return(domainPayment.GatewayPaymentStatus{GatewayPaymentID: "5077125051"}, nil)
+		finder.On("FindByProviderReference", "nowpayments", "5077125051").Return(nil, nil)
+
+		_, errProvider := useCase.Execute(HandleWebhookCommand{Provider: "manual", Payload: payload, Signature: "sig"})
+		_, errPayment := useCase.Execute(HandleWebhookCommand{Provider: "nowpayments", Payload: payload, Signature: "sig"})
+
+		assert.Equal(t, domainPayment.ErrUnknownProvider, errProvider)
+		assert.Equal(t, domainPayment.ErrPaymentNotFound, errPayment)
+	})
+}
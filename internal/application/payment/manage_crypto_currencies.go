@@ -0,0 +1,199 @@
+package payment
+
+import (
+	"strings"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// CryptoCurrencyResponse represents a cryptocurrency in the registry
+type CryptoCurrencyResponse struct {
+	Symbol                string  `json:"symbol"`
+	Asset                 string  `json:"asset"`
+	Name                  string  `json:"name"`
+	Network               string  `json:"network"`
+	ContractAddress       string  `json:"contract_address,omitempty"`
+	Decimals              int     `json:"decimals"`
+	MinAmount             float64 `json:"min_amount"`
+	RequiredConfirmations int     `json:"required_confirmations"`
+	IsStablecoin          bool    `json:"is_stablecoin"`
+	PeggedTo              string  `json:"pegged_to,omitempty"`
+	Testnet               bool    `json:"testnet"`
+	ExplorerTxURL         string  `json:"explorer_tx_url,omitempty"`
+	IsActive              bool    `json:"is_active"`
+}
+
+// SetCryptoCurrencyActiveCommand represents the input for enabling or disabling a coin
+type SetCryptoCurrencyActiveCommand struct {
+	Symbol string `json:"symbol" validate:"required"`
+	Active bool   `json:"active"`
+}
+
+// SyncCryptoCurrenciesResponse represents the outcome of a sync with the provider's coin list
+type SyncCryptoCurrenciesResponse struct {
+	Disabled []string `json:"disabled"` // Coins the provider no longer offers
+}
+
+// CryptoCurrencyRepository defines the interface for cryptocurrency configuration persistence
+type CryptoCurrencyRepository interface {
+	FindAll() ([]domainPayment.CryptoCurrency, error)
+	Save(crypto domainPayment.CryptoCurrency) error // Insert or update by symbol
+}
+
+// CryptoCurrencyCatalog lists the coins the payment provider currently accepts
+// (e.g. the NowPayments currencies endpoint)
+type CryptoCurrencyCatalog interface {
+	ListAvailableCurrencies() ([]string, error)
+}
+
+// LoadCryptoRegistry builds the registry from stored configuration.
+// An empty store is seeded with the built-in coin list.
+func LoadCryptoRegistry(cryptoRepo CryptoCurrencyRepository) (*domainPayment.CryptoRegistry, error) {
+	cryptos, err := cryptoRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cryptos) == 0 {
+		cryptos = domainPayment.DefaultCryptoCurrencies()
+		for _, crypto := range cryptos {
+			if err := cryptoRepo.Save(crypto); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return domainPayment.NewCryptoRegistry(cryptos)
+}
+
+// ListCryptoCurrenciesUseCase lists every registered coin, including disabled ones
+type ListCryptoCurrenciesUseCase struct {
+	registry *domainPayment.CryptoRegistry
+}
+
+// NewListCryptoCurrenciesUseCase creates a new instance of ListCryptoCurrenciesUseCase
+func NewListCryptoCurrenciesUseCase(registry *domainPayment.CryptoRegistry) *ListCryptoCurrenciesUseCase {
+	return &ListCryptoCurrenciesUseCase{
+		registry: registry,
+	}
+}
+
+// Execute lists the registered cryptocurrencies
+func (uc *ListCryptoCurrenciesUseCase) Execute() []CryptoCurrencyResponse {
+	cryptos := uc.registry.All()
+
+	responses := make([]CryptoCurrencyResponse, 0, len(cryptos))
+	for _, crypto := range cryptos {
+		responses = append(responses, toCryptoCurrencyResponse(crypto))
+	}
+
+	return responses
+}
+
+// SetCryptoCurrencyActiveUseCase enables or disables a coin at runtime
+type SetCryptoCurrencyActiveUseCase struct {
+	cryptoRepo CryptoCurrencyRepository
+	registry   *domainPayment.CryptoRegistry
+}
+
+// NewSetCryptoCurrencyActiveUseCase creates a new instance of SetCryptoCurrencyActiveUseCase
+func NewSetCryptoCurrencyActiveUseCase(cryptoRepo CryptoCurrencyRepository, registry *domainPayment.CryptoRegistry) *SetCryptoCurrencyActiveUseCase {
+	return &SetCryptoCurrencyActiveUseCase{
+		cryptoRepo: cryptoRepo,
+		registry:   registry,
+	}
+}
+
+// Execute enables or disables a coin.
+// Payments already created keep their coin; only new payments are affected.
+func (uc *SetCryptoCurrencyActiveUseCase) Execute(cmd SetCryptoCurrencyActiveCommand) (*CryptoCurrencyResponse, error) {
+	crypto, ok := uc.registry.Lookup(cmd.Symbol)
+	if !ok {
+		return nil, domainPayment.ErrUnsupportedCrypto
+	}
+
+	crypto.IsActive = cmd.Active
+
+	// Persist first so the registry never holds a change the store lost
+	if err := uc.cryptoRepo.Save(crypto); err != nil {
+		return nil, err
+	}
+
+	updated, err := uc.registry.SetActive(crypto.Symbol, cmd.Active)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toCryptoCurrencyResponse(updated)
+	return &response, nil
+}
+
+// SyncCryptoCurrenciesUseCase disables coins the payment provider stopped accepting
+type SyncCryptoCurrenciesUseCase struct {
+	cryptoRepo CryptoCurrencyRepository
+	registry   *domainPayment.CryptoRegistry
+	catalog    CryptoCurrencyCatalog
+}
+
+// NewSyncCryptoCurrenciesUseCase creates a new instance of SyncCryptoCurrenciesUseCase
+func NewSyncCryptoCurrenciesUseCase(cryptoRepo CryptoCurrencyRepository, registry *domainPayment.CryptoRegistry, catalog CryptoCurrencyCatalog) *SyncCryptoCurrenciesUseCase {
+	return &SyncCryptoCurrenciesUseCase{
+		cryptoRepo: cryptoRepo,
+		registry:   registry,
+		catalog:    catalog,
+	}
+}
+
+// Execute syncs the registry with the provider's currency list.
+// Coins are never enabled by a sync: new coins need decimals, minimums and
+// confirmations configured, and coins disabled by an admin stay disabled.
+func (uc *SyncCryptoCurrenciesUseCase) Execute() (*SyncCryptoCurrenciesResponse, error) {
+	available, err := uc.catalog.ListAvailableCurrencies()
+	if err != nil {
+		return nil, err
+	}
+
+	offered := make(map[string]bool, len(available))
+	for _, symbol := range available {
+		offered[strings.ToUpper(strings.TrimSpace(symbol))] = true
+	}
+
+	response := &SyncCryptoCurrenciesResponse{Disabled: []string{}}
+	for _, crypto := range uc.registry.Active() {
+		if offered[crypto.Symbol] {
+			continue
+		}
+
+		crypto.IsActive = false
+		if err := uc.cryptoRepo.Save(crypto); err != nil {
+			return nil, err
+		}
+
+		if _, err := uc.registry.SetActive(crypto.Symbol, false); err != nil {
+			return nil, err
+		}
+
+		response.Disabled = append(response.Disabled, crypto.Symbol)
+	}
+
+	return response, nil
+}
+
+// toCryptoCurrencyResponse maps a cryptocurrency to its response
+func toCryptoCurrencyResponse(crypto domainPayment.CryptoCurrency) CryptoCurrencyResponse {
+	return CryptoCurrencyResponse{
+		Symbol:                crypto.Symbol,
+		Asset:                 crypto.GetAsset(),
+		Name:                  crypto.Name,
+		Network:               crypto.Network.String(),
+		ContractAddress:       crypto.ContractAddress,
+		Decimals:              crypto.Decimals,
+		MinAmount:             crypto.MinAmount,
+		RequiredConfirmations: crypto.RequiredConfirmations,
+		IsStablecoin:          crypto.IsStablecoin,
+		PeggedTo:              crypto.PeggedTo,
+		Testnet:               crypto.Testnet,
+		ExplorerTxURL:         crypto.ExplorerTxURL,
+		IsActive:              crypto.IsActive,
+	}
+}
@@ -0,0 +1,161 @@
+package payment
+
+import (
+	"errors"
+	"testing"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCryptoCurrencyRepository is a mock implementation of CryptoCurrencyRepository
+type MockCryptoCurrencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockCryptoCurrencyRepository) FindAll() ([]domainPayment.CryptoCurrency, error) {
+	args := m.Called()
+	return args.Get(0).([]domainPayment.CryptoCurrency), args.Error(1)
+}
+
+func (m *MockCryptoCurrencyRepository) Save(crypto domainPayment.CryptoCurrency) error {
+	args := m.Called(crypto)
+	return args.Error(0)
+}
+
+// MockCryptoCurrencyCatalog is a mock implementation of CryptoCurrencyCatalog
+type MockCryptoCurrencyCatalog struct {
+	mock.Mock
+}
+
+func (m *MockCryptoCurrencyCatalog) ListAvailableCurrencies() ([]string, error) {
+	args := m.Called()
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// Test helper functions
+
+func createTestRegistry() *domainPayment.CryptoRegistry {
+	registry, _ := domainPayment.NewCryptoRegistry(domainPayment.DefaultCryptoCurrencies())
+	return registry
+}
+
+// Tests for LoadCryptoRegistry
+
+func TestLoadCryptoRegistry(t *testing.T) {
+	t.Run("load stored configuration", func(t *testing.T) {
+		cryptoRepo := new(MockCryptoCurrencyRepository)
+		usdt := domainPayment.CryptoCurrency{Symbol: "USDT", Name: "Tether", Network: domainPayment.NetworkTron, Decimals: 6, MinAmount: 1.0, RequiredConfirmations: 20, IsActive: true}
+
+		cryptoRepo.On("FindAll").Return([]domainPayment.CryptoCurrency{usdt}, nil)
+
+		registry, err := LoadCryptoRegistry(cryptoRepo)
+
+		assert.NoError(t, err)
+		assert.Len(t, registry.All(), 1)
+		_, err = registry.Get("USDT")
+		assert.NoError(t, err)
+		cryptoRepo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("empty store is seeded with defaults", func(t *testing.T) {
+		cryptoRepo := new(MockCryptoCurrencyRepository)
+
+		cryptoRepo.On("FindAll").Return([]domainPayment.CryptoCurrency{}, nil)
+		cryptoRepo.On("Save", mock.Anything).Return(nil)
+
+		registry, err := LoadCryptoRegistry(cryptoRepo)
+
+		assert.NoError(t, err)
+		assert.Len(t, registry.All(), len(domainPayment.DefaultCryptoCurrencies()))
+		cryptoRepo.AssertNumberOfCalls(t, "Save", len(domainPayment.DefaultCryptoCurrencies()))
+	})
+}
+
+// Tests for SetCryptoCurrencyActiveUseCase
+
+func TestSetCryptoCurrencyActiveUseCase(t *testing.T) {
+	t.Run("disable coin", func(t *testing.T) {
+		cryptoRepo := new(MockCryptoCurrencyRepository)
+		registry := createTestRegistry()
+		useCase := NewSetCryptoCurrencyActiveUseCase(cryptoRepo, registry)
+
+		cryptoRepo.On("Save", mock.MatchedBy(func(c domainPayment.CryptoCurrency) bool {
+			return c.Symbol == "DOGE" && !c.IsActive
+		})).Return(nil)
+
+		response, err := useCase.Execute(SetCryptoCurrencyActiveCommand{Symbol: "doge", Active: false})
+
+		assert.NoError(t, err)
+		assert.False(t, response.IsActive)
+		_, err = registry.Get("DOGE")
+		assert.Equal(t, domainPayment.ErrUnsupportedCrypto, err)
+		cryptoRepo.AssertExpectations(t)
+	})
+
+	t.Run("registry unchanged when save fails", func(t *testing.T) {
+		cryptoRepo := new(MockCryptoCurrencyRepository)
+		registry := createTestRegistry()
+		useCase := NewSetCryptoCurrencyActiveUseCase(cryptoRepo, registry)
+		saveErr := errors.New("database down")
+
+		cryptoRepo.On("Save", mock.Anything).Return(saveErr)
+
+		response, err := useCase.Execute(SetCryptoCurrencyActiveCommand{Symbol: "DOGE", Active: false})
+
+		assert.Nil(t, response)
+		assert.Equal(t, saveErr, err)
+		_, err = registry.Get("DOGE")
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown coin", func(t *testing.T) {
+		useCase := NewSetCryptoCurrencyActiveUseCase(new(MockCryptoCurrencyRepository), createTestRegistry())
+
+		response, err := useCase.Execute(SetCryptoCurrencyActiveCommand{Symbol: "XYZ", Active: true})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainPayment.ErrUnsupportedCrypto, err)
+	})
+}
+
+// Tests for SyncCryptoCurrenciesUseCase
+
+func TestSyncCryptoCurrenciesUseCase(t *testing.T) {
+	t.Run("coins no longer offered are disabled", func(t *testing.T) {
+		cryptoRepo := new(MockCryptoCurrencyRepository)
+		catalog := new(MockCryptoCurrencyCatalog)
+		registry := createTestRegistry()
+		_, _ = registry.SetActive("LTC", false)
+		useCase := NewSyncCryptoCurrenciesUseCase(cryptoRepo, registry, catalog)
+
+		catalog.On("ListAvailableCurrencies").Return([]string{"btc", "eth", "bch", "xrp", "ltc", "usdttrc20", "usdterc20", "usdc", "sol"}, nil)
+		cryptoRepo.On("Save", mock.MatchedBy(func(c domainPayment.CryptoCurrency) bool {
+			return c.Symbol == "DOGE" && !c.IsActive
+		})).Return(nil)
+
+		response, err := useCase.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"DOGE"}, response.Disabled)
+		_, found := registry.Lookup("SOL")
+		assert.False(t, found) // Unconfigured coins are not added
+		_, err = registry.Get("LTC")
+		assert.Equal(t, domainPayment.ErrUnsupportedCrypto, err) // Admin choice is kept
+		cryptoRepo.AssertExpectations(t)
+	})
+
+	t.Run("catalog error", func(t *testing.T) {
+		catalog := new(MockCryptoCurrencyCatalog)
+		useCase := NewSyncCryptoCurrenciesUseCase(new(MockCryptoCurrencyRepository), createTestRegistry(), catalog)
+		catalogErr := errors.New("provider down")
+
+		catalog.On("ListAvailableCurrencies").Return([]string{}, catalogErr)
+
+		response, err := useCase.Execute()
+
+		assert.Nil(t, response)
+		assert.Equal(t, catalogErr, err)
+	})
+}
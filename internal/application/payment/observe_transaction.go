@@ -0,0 +1,106 @@
+package payment
+
+import (
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// TransactionWatcher is the port chain watchers report transactions to
+// self-custody deposit addresses through: a local node, or a fixture feed in
+// tests. Watchers report a transaction again whenever its confirmations change.
+type TransactionWatcher interface {
+	ObserveTransaction(tx domainPayment.ObservedTransaction) error
+}
+
+// DepositAddressBook defines the interface for self-custody deposit addresses and their HD wallets
+type DepositAddressBook interface {
+	FindByAddress(address string) (*domainPayment.DepositAddress, error)
+	FindWallet(cryptoSymbol string) (*domainPayment.HDWallet, error)
+	// SaveUsage stores the address's first use and the wallet's last used index in one transaction
+	SaveUsage(wallet *domainPayment.HDWallet, deposit *domainPayment.DepositAddress) error
+}
+
+// ObserveTransactionUseCase applies transactions seen on chain to
+// self-custody payments. The first transaction to a deposit address marks its
+// index used, which makes room under the wallet's gap limit; the update is
+// then applied through TrackConfirmationsUseCase, the path gateway webhooks
+// take. An underpaying transaction is not applied: the payment stays pending
+// and ErrInsufficientAmount is returned for operators to follow up.
+type ObserveTransactionUseCase struct {
+	addresses DepositAddressBook
+	finder    PaymentReferenceFinder
+	provider  string // Provider name the self-custody payments are opened under
+	tracker   *TrackConfirmationsUseCase
+}
+
+// NewObserveTransactionUseCase creates a new instance of ObserveTransactionUseCase
+func NewObserveTransactionUseCase(addresses DepositAddressBook, finder PaymentReferenceFinder, provider string, tracker *TrackConfirmationsUseCase) *ObserveTransactionUseCase {
+	return &ObserveTransactionUseCase{
+		addresses: addresses,
+		finder:    finder,
+		provider:  provider,
+		tracker:   tracker,
+	}
+}
+
+// ObserveTransaction implements TransactionWatcher
+func (uc *ObserveTransactionUseCase) ObserveTransaction(tx domainPayment.ObservedTransaction) error {
+	_, err := uc.Execute(tx)
+	return err
+}
+
+// Execute applies the observed transaction to the payment of its deposit address
+func (uc *ObserveTransactionUseCase) Execute(tx domainPayment.ObservedTransaction) (*PaymentResponse, error) {
+	deposit, err := uc.addresses.FindByAddress(tx.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	if deposit == nil {
+		return nil, domainPayment.ErrUnknownDepositAddress
+	}
+
+	if !tx.Dropped && !deposit.IsUsed() {
+		if err := uc.markUsed(deposit); err != nil {
+			return nil, err
+		}
+	}
+
+	existingPayment, err := uc.finder.FindByProviderReference(uc.provider, deposit.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingPayment == nil {
+		return nil, domainPayment.ErrPaymentNotFound
+	}
+
+	if !tx.Dropped && existingPayment.ValidateAmount(tx.Amount) == domainPayment.ErrInsufficientAmount {
+		return nil, domainPayment.ErrInsufficientAmount
+	}
+
+	return uc.tracker.Execute(TrackConfirmationsCommand{
+		PaymentID:       existingPayment.ID,
+		TransactionHash: tx.TransactionHash,
+		Confirmations:   tx.Confirmations,
+		Dropped:         tx.Dropped,
+	})
+}
+
+// markUsed records the first transaction to a deposit address on the address and its wallet
+func (uc *ObserveTransactionUseCase) markUsed(deposit *domainPayment.DepositAddress) error {
+	wallet, err := uc.addresses.FindWallet(deposit.CryptoSymbol)
+	if err != nil {
+		return err
+	}
+
+	if wallet == nil {
+		return domainPayment.ErrHDWalletNotFound
+	}
+
+	if err := wallet.MarkIndexUsed(deposit.Index); err != nil {
+		return err
+	}
+
+	deposit.MarkUsed()
+	return uc.addresses.SaveUsage(wallet, deposit)
+}
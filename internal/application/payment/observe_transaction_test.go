@@ -0,0 +1,146 @@
+package payment
+
+import (
+	"testing"
+
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testDerivedAddress = "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu"
+
+// MockDepositAddressBook is a mock implementation of DepositAddressBook
+type MockDepositAddressBook struct {
+	mock.Mock
+}
+
+func (m *MockDepositAddressBook) FindByAddress(address string) (*domainPayment.DepositAddress, error) {
+	args := m.Called(address)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainPayment.DepositAddress), args.Error(1)
+}
+
+func (m *MockDepositAddressBook) FindWallet(cryptoSymbol string) (*domainPayment.HDWallet, error) {
+	args := m.Called(cryptoSymbol)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainPayment.HDWallet), args.Error(1)
+}
+
+func (m *MockDepositAddressBook) SaveUsage(wallet *domainPayment.HDWallet, deposit *domainPayment.DepositAddress) error {
+	args := m.Called(wallet, deposit)
+	return args.Error(0)
+}
+
+// createDerivedDeposit returns a wallet that handed out its first address, and that address
+func createDerivedDeposit(t *testing.T) (*domainPayment.HDWallet, *domainPayment.DepositAddress) {
+	wallet, err := domainPayment.NewHDWallet("BTC", "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs", domainPayment.PurposeNativeSegwit, 0, 0)
+	require.NoError(t, err)
+	index, _ := wallet.ReserveIndex()
+	deposit, err := domainPayment.NewDepositAddress(wallet, index, testDerivedAddress, "order123")
+	require.NoError(t, err)
+	return wallet, deposit
+}
+
+// createSelfCustodyPayment returns a payment of 0.002 BTC opened on the derived address
+func createSelfCustodyPayment() *domainPayment.Payment {
+	p := createTestPayment()
+	_ = p.UpdateCryptoAmount(0.002)
+	_ = p.SetProviderReference("selfcustody", testDerivedAddress)
+	return p
+}
+
+// Tests for ObserveTransactionUseCase
+
+func TestObserveTransactionUseCase(t *testing.T) {
+	t.Run("first transaction marks the address used and the payment confirming", func(t *testing.T) {
+		addresses := new(MockDepositAddressBook)
+		finder := new(MockPaymentReferenceFinder)
+		paymentRepo := new(MockPaymentRepository)
+		tracker := NewTrackConfirmationsUseCase(paymentRepo, new(MockOrderRepository), new(MockOperatorAlerter), nil)
+		useCase := NewObserveTransactionUseCase(addresses, finder, "selfcustody", tracker)
+
+		wallet, deposit := createDerivedDeposit(t)
+		p := createSelfCustodyPayment()
+
+		addresses.On("FindByAddress", testDerivedAddress).Return(deposit, nil)
+		addresses.On("FindWallet", "BTC").Return(wallet, nil)
+		addresses.On("SaveUsage", wallet, deposit).Return(nil)
+		finder.On("FindByProviderReference", "selfcustody", testDerivedAddress).Return(p, nil)
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+
+		response, err := useCase.Execute(domainPayment.ObservedTransaction{Address: testDerivedAddress, TransactionHash: testTransactionHash, Amount: 0.002})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "CONFIRMING", response.Status)
+		assert.Equal(t, testTransactionHash, response.TransactionHash)
+		assert.True(t, deposit.IsUsed())
+		assert.Equal(t, 0, wallet.LastUsedIndex)
+	})
+
+	t.Run("later reports update the confirmations", func(t *testing.T) {
+		addresses := new(MockDepositAddressBook)
+		finder := new(MockPaymentReferenceFinder)
+		paymentRepo := new(MockPaymentRepository)
+		p := createSelfCustodyPayment()
+		orderRepo, o := newAwaitingOrderRepository(p)
+		tracker := NewTrackConfirmationsUseCase(paymentRepo, orderRepo, new(MockOperatorAlerter), nil)
+		useCase := NewObserveTransactionUseCase(addresses, finder, "selfcustody", tracker)
+
+		_, deposit := createDerivedDeposit(t)
+		deposit.MarkUsed()
+		_ = p.MarkAsConfirming(testTransactionHash)
+
+		addresses.On("FindByAddress", testDerivedAddress).Return(deposit, nil)
+		finder.On("FindByProviderReference", "selfcustody", testDerivedAddress).Return(p, nil)
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+
+		response, err := useCase.Execute(domainPayment.ObservedTransaction{Address: testDerivedAddress, TransactionHash: testTransactionHash, Amount: 0.002, Confirmations: p.RequiredConfirmations})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "CONFIRMED", response.Status)
+		assert.Equal(t, domainOrder.StatusPaid, o.Status)
+		addresses.AssertNotCalled(t, "SaveUsage", mock.Anything, mock.Anything)
+	})
+
+	t.Run("underpayment is not applied", func(t *testing.T) {
+		addresses := new(MockDepositAddressBook)
+		finder := new(MockPaymentReferenceFinder)
+		paymentRepo := new(MockPaymentRepository)
+		tracker := NewTrackConfirmationsUseCase(paymentRepo, new(MockOrderRepository), new(MockOperatorAlerter), nil)
+		useCase := NewObserveTransactionUseCase(addresses, finder, "selfcustody", tracker)
+
+		wallet, deposit := createDerivedDeposit(t)
+		p := createSelfCustodyPayment()
+
+		addresses.On("FindByAddress", testDerivedAddress).Return(deposit, nil)
+		addresses.On("FindWallet", "BTC").Return(wallet, nil)
+		addresses.On("SaveUsage", wallet, deposit).Return(nil)
+		finder.On("FindByProviderReference", "selfcustody", testDerivedAddress).Return(p, nil)
+
+		_, err := useCase.Execute(domainPayment.ObservedTransaction{Address: testDerivedAddress, TransactionHash: testTransactionHash, Amount: 0.001})
+
+		assert.Equal(t, domainPayment.ErrInsufficientAmount, err)
+		assert.True(t, deposit.IsUsed(), "the funds still arrived")
+		paymentRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("address not derived here", func(t *testing.T) {
+		addresses := new(MockDepositAddressBook)
+		useCase := NewObserveTransactionUseCase(addresses, new(MockPaymentReferenceFinder), "selfcustody", nil)
+
+		addresses.On("FindByAddress", "bc1qother").Return(nil, nil)
+
+		err := useCase.ObserveTransaction(domainPayment.ObservedTransaction{Address: "bc1qother", TransactionHash: testTransactionHash})
+
+		assert.Equal(t, domainPayment.ErrUnknownDepositAddress, err)
+	})
+}
@@ -0,0 +1,171 @@
+package payment
+
+import (
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// GetPaymentPageCommand represents the input for rendering an invoice's payment page
+type GetPaymentPageCommand struct {
+	InvoiceID string `json:"invoice_id" validate:"required"`
+}
+
+// SelectInvoiceCryptoCommand represents the coin the customer picked on the payment page
+type SelectInvoiceCryptoCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	InvoiceID      string `json:"invoice_id" validate:"required"`
+	CryptoCurrency string `json:"crypto_currency" validate:"required"`
+}
+
+// PaymentPageResponse is the view model of the hosted payment page. Clients poll
+// it for the live status and follow RedirectURL once it is set.
+type PaymentPageResponse struct {
+	InvoiceID          string   `json:"invoice_id"`
+	OrderID            string   `json:"order_id"`
+	Status             string   `json:"status"`
+	Amount             float64  `json:"amount"`
+	Currency           string   `json:"currency"`
+	AllowedCryptos     []string `json:"allowed_cryptos"`
+	ExpiresAt          string   `json:"expires_at"`           // The payment's deadline once a coin is picked
+	SecondsUntilExpiry int64    `json:"seconds_until_expiry"` // Countdown; 0 once expired
+	SuccessURL         string   `json:"success_url,omitempty"`
+	CancelURL          string   `json:"cancel_url,omitempty"`
+	RedirectURL        string   `json:"redirect_url,omitempty"` // Set once the invoice is paid, expired or failed
+
+	// Set once the customer picked a coin
+	PaymentID             string  `json:"payment_id,omitempty"`
+	PaymentStatus         string  `json:"payment_status,omitempty"`
+	CryptoCurrency        string  `json:"crypto_currency,omitempty"`
+	Network               string  `json:"network,omitempty"`
+	CryptoAmount          float64 `json:"crypto_amount,omitempty"`
+	WalletAddress         string  `json:"wallet_address,omitempty"`
+	PaymentURI            string  `json:"payment_uri,omitempty"` // Also rendered as a QR code
+	Confirmations         int     `json:"confirmations"`
+	RequiredConfirmations int     `json:"required_confirmations"`
+	TransactionURL        string  `json:"transaction_url,omitempty"`
+}
+
+// GetPaymentPageUseCase builds the payment page of an invoice
+type GetPaymentPageUseCase struct {
+	invoiceRepo InvoiceRepository
+	paymentRepo PaymentRepository
+}
+
+// NewGetPaymentPageUseCase creates a new instance of GetPaymentPageUseCase
+func NewGetPaymentPageUseCase(invoiceRepo InvoiceRepository, paymentRepo PaymentRepository) *GetPaymentPageUseCase {
+	return &GetPaymentPageUseCase{
+		invoiceRepo: invoiceRepo,
+		paymentRepo: paymentRepo,
+	}
+}
+
+// Execute builds the payment page
+func (uc *GetPaymentPageUseCase) Execute(cmd GetPaymentPageCommand) (*PaymentPageResponse, error) {
+	invoice, err := findInvoice(uc.invoiceRepo, cmd.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var p *domainPayment.Payment
+	if invoice.HasPayment() {
+		if p, err = uc.paymentRepo.FindByID(invoice.PaymentID); err != nil {
+			return nil, err
+		}
+	}
+
+	return toPaymentPageResponse(invoice, p), nil
+}
+
+// SelectInvoiceCryptoUseCase opens the payment for the coin the customer picked
+type SelectInvoiceCryptoUseCase struct {
+	invoiceRepo   InvoiceRepository
+	createPayment *CreatePaymentUseCase
+}
+
+// NewSelectInvoiceCryptoUseCase creates a new instance of SelectInvoiceCryptoUseCase
+func NewSelectInvoiceCryptoUseCase(invoiceRepo InvoiceRepository, createPayment *CreatePaymentUseCase) *SelectInvoiceCryptoUseCase {
+	return &SelectInvoiceCryptoUseCase{
+		invoiceRepo:   invoiceRepo,
+		createPayment: createPayment,
+	}
+}
+
+// Execute creates the payment and attaches it to the invoice
+func (uc *SelectInvoiceCryptoUseCase) Execute(cmd SelectInvoiceCryptoCommand) (*PaymentPageResponse, error) {
+	invoice, err := findInvoice(uc.invoiceRepo, cmd.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check before opening a gateway payment that would be left unused
+	if err := invoice.CanSelectCrypto(cmd.CryptoCurrency); err != nil {
+		return nil, err
+	}
+
+	p, err := uc.createPayment.open(invoice.OrderID, cmd.CryptoCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := invoice.AttachPayment(p); err != nil {
+		return nil, err
+	}
+
+	if err := uc.invoiceRepo.Update(invoice); err != nil {
+		return nil, err
+	}
+
+	return toPaymentPageResponse(invoice, p), nil
+}
+
+// findInvoice loads an invoice, mapping a missing one to ErrInvoiceNotFound
+func findInvoice(invoiceRepo InvoiceRepository, invoiceID string) (*domainPayment.Invoice, error) {
+	invoice, err := invoiceRepo.FindByID(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice == nil {
+		return nil, domainPayment.ErrInvoiceNotFound
+	}
+
+	return invoice, nil
+}
+
+// toPaymentPageResponse maps an invoice and its payment, nil until a coin is picked, to the page
+func toPaymentPageResponse(invoice *domainPayment.Invoice, p *domainPayment.Payment) *PaymentPageResponse {
+	status := invoice.Status(p)
+
+	response := &PaymentPageResponse{
+		InvoiceID:          invoice.ID,
+		OrderID:            invoice.OrderID,
+		Status:             string(status),
+		Amount:             invoice.Amount,
+		Currency:           invoice.Currency,
+		AllowedCryptos:     invoice.AllowedCryptos,
+		ExpiresAt:          invoice.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+		SecondsUntilExpiry: int64(invoice.GetTimeUntilExpiry().Seconds()),
+		SuccessURL:         invoice.SuccessURL,
+		CancelURL:          invoice.CancelURL,
+		RedirectURL:        invoice.RedirectURL(status),
+	}
+
+	if p == nil {
+		return response
+	}
+
+	response.ExpiresAt = p.ExpiresAt.Format("2006-01-02T15:04:05Z07:00")
+	response.SecondsUntilExpiry = int64(p.GetTimeUntilExpiry().Seconds())
+	response.PaymentID = p.ID
+	response.PaymentStatus = string(p.Status)
+	response.CryptoCurrency = p.GetCryptoSymbol()
+	response.Network = p.CryptoCurrency.Network.String()
+	response.CryptoAmount = p.CryptoAmount
+	response.WalletAddress = p.GetWalletAddress()
+	response.PaymentURI = paymentURI(p)
+	response.Confirmations = p.Confirmations
+	response.RequiredConfirmations = p.RequiredConfirmations
+	response.TransactionURL = p.TransactionURL()
+
+	return response
+}
@@ -0,0 +1,143 @@
+package payment
+
+import (
+	"testing"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// createTestInvoice creates an invoice for the order payable in BTC or ETH
+func createTestInvoice(orderID string) *domainPayment.Invoice {
+	invoice, _ := domainPayment.NewInvoice(orderID, 100.0, "USD", []string{"BTC", "ETH"}, "https://shop.example/thanks", "https://shop.example/cart", 60)
+	return invoice
+}
+
+// Tests for GetPaymentPageUseCase
+
+func TestGetPaymentPageUseCase(t *testing.T) {
+	t.Run("awaiting coin selection", func(t *testing.T) {
+		invoiceRepo := new(MockInvoiceRepository)
+		paymentRepo := new(MockPaymentRepository)
+		useCase := NewGetPaymentPageUseCase(invoiceRepo, paymentRepo)
+
+		invoice := createTestInvoice("order123")
+		invoiceRepo.On("FindByID", invoice.ID).Return(invoice, nil)
+
+		response, err := useCase.Execute(GetPaymentPageCommand{InvoiceID: invoice.ID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "AWAITING_SELECTION", response.Status)
+		assert.Equal(t, []string{"BTC", "ETH"}, response.AllowedCryptos)
+		assert.InDelta(t, 3600, response.SecondsUntilExpiry, 1)
+		assert.Empty(t, response.RedirectURL)
+		assert.Empty(t, response.WalletAddress)
+		paymentRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+	})
+
+	t.Run("confirmed payment redirects to success", func(t *testing.T) {
+		invoiceRepo := new(MockInvoiceRepository)
+		paymentRepo := new(MockPaymentRepository)
+		useCase := NewGetPaymentPageUseCase(invoiceRepo, paymentRepo)
+
+		invoice := createTestInvoice("order123")
+		p := createTestPayment()
+		_ = invoice.AttachPayment(p)
+		_ = p.UpdateCryptoAmount(0.0015)
+		_ = p.MarkAsConfirmed()
+
+		invoiceRepo.On("FindByID", invoice.ID).Return(invoice, nil)
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+
+		response, err := useCase.Execute(GetPaymentPageCommand{InvoiceID: invoice.ID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "PAID", response.Status)
+		assert.Equal(t, "CONFIRMED", response.PaymentStatus)
+		assert.Equal(t, "BTC", response.CryptoCurrency)
+		assert.Equal(t, p.GetWalletAddress(), response.WalletAddress)
+		assert.Equal(t, "https://shop.example/thanks", response.RedirectURL)
+	})
+
+	t.Run("invoice not found", func(t *testing.T) {
+		invoiceRepo := new(MockInvoiceRepository)
+		useCase := NewGetPaymentPageUseCase(invoiceRepo, new(MockPaymentRepository))
+
+		invoiceRepo.On("FindByID", "missing").Return(nil, nil)
+
+		_, err := useCase.Execute(GetPaymentPageCommand{InvoiceID: "missing"})
+
+		assert.Equal(t, domainPayment.ErrInvoiceNotFound, err)
+	})
+}
+
+// Tests for SelectInvoiceCryptoUseCase
+
+func TestSelectInvoiceCryptoUseCase(t *testing.T) {
+	opened := domainPayment.GatewayPayment{
+		GatewayPaymentID: "np-123",
+		PayAddress:       "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+		PayAmount:        0.05,
+	}
+
+	t.Run("creates the payment in the picked coin", func(t *testing.T) {
+		invoiceRepo := new(MockInvoiceRepository)
+		paymentRepo := new(MockPaymentRepository)
+		orderRepo := new(MockOrderRepository)
+		gateway := newMockPaymentProvider("nowpayments")
+		useCase := NewSelectInvoiceCryptoUseCase(invoiceRepo, NewCreatePaymentUseCase(paymentRepo, orderRepo, providersOf(gateway), newTestScopeResolver(), domainPayment.DefaultFeePolicy(), nil, 30))
+
+		o := createPendingPaymentOrder()
+		invoice := createTestInvoice(o.ID.String())
+
+		invoiceRepo.On("FindByID", invoice.ID).Return(invoice, nil)
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		orderRepo.On("Update", o).Return(nil)
+		gateway.On("CreatePayment", mock.MatchedBy(func(r domainPayment.GatewayPaymentRequest) bool {
+			return r.PayCurrency == "ETH"
+		})).Return(opened, nil)
+		paymentRepo.On("Save", mock.AnythingOfType("*payment.Payment")).Return(nil)
+		invoiceRepo.On("Update", invoice).Return(nil)
+
+		response, err := useCase.Execute(SelectInvoiceCryptoCommand{InvoiceID: invoice.ID, CryptoCurrency: "eth"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "AWAITING_PAYMENT", response.Status)
+		assert.Equal(t, "ETH", response.CryptoCurrency)
+		assert.Equal(t, opened.PayAddress, response.WalletAddress)
+		assert.Equal(t, 0.05, response.CryptoAmount)
+		assert.InDelta(t, 1800, response.SecondsUntilExpiry, 1)
+		assert.Equal(t, invoice.PaymentID, response.PaymentID)
+		invoiceRepo.AssertExpectations(t)
+	})
+
+	t.Run("coin not allowed", func(t *testing.T) {
+		invoiceRepo := new(MockInvoiceRepository)
+		gateway := newMockPaymentProvider("nowpayments")
+		useCase := NewSelectInvoiceCryptoUseCase(invoiceRepo, NewCreatePaymentUseCase(new(MockPaymentRepository), new(MockOrderRepository), providersOf(gateway), newTestScopeResolver(), domainPayment.DefaultFeePolicy(), nil, 30))
+
+		invoice := createTestInvoice("order123")
+		invoiceRepo.On("FindByID", invoice.ID).Return(invoice, nil)
+
+		_, err := useCase.Execute(SelectInvoiceCryptoCommand{InvoiceID: invoice.ID, CryptoCurrency: "LTC"})
+
+		assert.Equal(t, domainPayment.ErrCryptoNotAllowed, err)
+		gateway.AssertNotCalled(t, "CreatePayment", mock.Anything)
+	})
+
+	t.Run("coin already picked", func(t *testing.T) {
+		invoiceRepo := new(MockInvoiceRepository)
+		gateway := newMockPaymentProvider("nowpayments")
+		useCase := NewSelectInvoiceCryptoUseCase(invoiceRepo, NewCreatePaymentUseCase(new(MockPaymentRepository), new(MockOrderRepository), providersOf(gateway), newTestScopeResolver(), domainPayment.DefaultFeePolicy(), nil, 30))
+
+		invoice := createTestInvoice("order123")
+		_ = invoice.AttachPayment(createTestPayment())
+		invoiceRepo.On("FindByID", invoice.ID).Return(invoice, nil)
+
+		_, err := useCase.Execute(SelectInvoiceCryptoCommand{InvoiceID: invoice.ID, CryptoCurrency: "ETH"})
+
+		assert.Equal(t, domainPayment.ErrInvoiceCryptoSelected, err)
+		gateway.AssertNotCalled(t, "CreatePayment", mock.Anything)
+	})
+}
@@ -0,0 +1,54 @@
+package payment
+
+import (
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// PaymentProvider is a payment gateway that opens payments, reports their
+// state, sends refunds and signs its webhooks. Operations a provider cannot
+// perform return ErrProviderNotSupported.
+type PaymentProvider interface {
+	Name() string
+	PaymentGateway
+	PaymentStatusGateway
+	RefundPayment(request domainPayment.GatewayRefundRequest) (domainPayment.GatewayRefund, error)
+	VerifyWebhook(payload []byte, signature string) (domainPayment.GatewayPaymentStatus, error)
+}
+
+// PaymentProviders holds the configured payment providers and picks the one
+// that opens each payment
+type PaymentProviders struct {
+	providers map[string]PaymentProvider
+	routing   domainPayment.ProviderRouting
+}
+
+// NewPaymentProviders creates a new instance of PaymentProviders. Every
+// provider the routing names must be given.
+func NewPaymentProviders(routing domainPayment.ProviderRouting, providers ...PaymentProvider) (*PaymentProviders, error) {
+	byName := make(map[string]PaymentProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+
+	for _, name := range routing.Providers() {
+		if _, ok := byName[name]; !ok {
+			return nil, domainPayment.ErrUnknownProvider
+		}
+	}
+
+	return &PaymentProviders{providers: byName, routing: routing}, nil
+}
+
+// ForCurrency returns the provider that opens payments in a cryptocurrency
+func (pp *PaymentProviders) ForCurrency(cryptoSymbol string) (PaymentProvider, error) {
+	return pp.Get(pp.routing.ProviderFor(cryptoSymbol))
+}
+
+// Get returns a provider by name, e.g. the one that opened a payment
+func (pp *PaymentProviders) Get(name string) (PaymentProvider, error) {
+	provider, ok := pp.providers[name]
+	if !ok {
+		return nil, domainPayment.ErrUnknownProvider
+	}
+	return provider, nil
+}
@@ -0,0 +1,85 @@
+package payment
+
+import (
+	"testing"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockPaymentProvider is a mock implementation of PaymentProvider
+type MockPaymentProvider struct {
+	mock.Mock
+	name string
+}
+
+// newMockPaymentProvider creates a mock provider with the given name
+func newMockPaymentProvider(name string) *MockPaymentProvider {
+	return &MockPaymentProvider{name: name}
+}
+
+func (m *MockPaymentProvider) Name() string {
+	return m.name
+}
+
+func (m *MockPaymentProvider) CreatePayment(request domainPayment.GatewayPaymentRequest) (domainPayment.GatewayPayment, error) {
+	args := m.Called(request)
+	return args.Get(0).(domainPayment.GatewayPayment), args.Error(1)
+}
+
+func (m *MockPaymentProvider) GetPaymentStatus(gatewayPaymentID string) (domainPayment.GatewayPaymentStatus, error) {
+	args := m.Called(gatewayPaymentID)
+	return args.Get(0).(domainPayment.GatewayPaymentStatus), args.Error(1)
+}
+
+func (m *MockPaymentProvider) RefundPayment(request domainPayment.GatewayRefundRequest) (domainPayment.GatewayRefund, error) {
+	args := m.Called(request)
+	return args.Get(0).(domainPayment.GatewayRefund), args.Error(1)
+}
+
+func (m *MockPaymentProvider) VerifyWebhook(payload []byte, signature string) (domainPayment.GatewayPaymentStatus, error) {
+	args := m.Called(payload, signature)
+	return args.Get(0).(domainPayment.GatewayPaymentStatus), args.Error(1)
+}
+
+// providersOf routes every payment to a single provider
+func providersOf(provider PaymentProvider) *PaymentProviders {
+	providers, _ := NewPaymentProviders(domainPayment.SingleProviderRouting(provider.Name()), provider)
+	return providers
+}
+
+// Tests for PaymentProviders
+
+func TestPaymentProviders(t *testing.T) {
+	t.Run("routes coins to their provider", func(t *testing.T) {
+		nowPayments := newMockPaymentProvider("nowpayments")
+		manual := newMockPaymentProvider("manual")
+		routing, _ := domainPayment.NewProviderRouting("nowpayments", map[string]string{"USDTTRC20": "manual"})
+
+		providers, err := NewPaymentProviders(routing, nowPayments, manual)
+		require.NoError(t, err)
+
+		routed, _ := providers.ForCurrency("usdttrc20")
+		fallback, _ := providers.ForCurrency("BTC")
+		assert.Equal(t, "manual", routed.Name())
+		assert.Equal(t, "nowpayments", fallback.Name())
+	})
+
+	t.Run("routing names a provider that is not configured", func(t *testing.T) {
+		routing, _ := domainPayment.NewProviderRouting("nowpayments", map[string]string{"BTC": "manual"})
+
+		_, err := NewPaymentProviders(routing, newMockPaymentProvider("nowpayments"))
+
+		assert.Equal(t, domainPayment.ErrUnknownProvider, err)
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		providers := providersOf(newMockPaymentProvider("nowpayments"))
+
+		_, err := providers.Get("stripe")
+
+		assert.Equal(t, domainPayment.ErrUnknownProvider, err)
+	})
+}
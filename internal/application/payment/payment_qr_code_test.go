@@ -0,0 +1,90 @@
+package payment
+
+import (
+	"testing"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockQRCodeRenderer is a mock implementation of QRCodeRenderer
+type MockQRCodeRenderer struct {
+	mock.Mock
+}
+
+func (m *MockQRCodeRenderer) RenderPNG(content string, size int) ([]byte, error) {
+	args := m.Called(content, size)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockQRCodeRenderer) RenderSVG(content string, size int) ([]byte, error) {
+	args := m.Called(content, size)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+// Tests for GetPaymentQRCodeUseCase
+
+func TestGetPaymentQRCodeUseCase(t *testing.T) {
+	t.Run("png of the payment uri", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		renderer := new(MockQRCodeRenderer)
+		useCase := NewGetPaymentQRCodeUseCase(paymentRepo, renderer)
+
+		p := createTestPayment()
+		_ = p.UpdateCryptoAmount(0.0015)
+		uri := "bitcoin:bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh?amount=0.0015&label=Order%20order123"
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		renderer.On("RenderPNG", uri, 256).Return([]byte("png"), nil)
+
+		response, err := useCase.Execute(GetPaymentQRCodeCommand{PaymentID: p.ID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, uri, response.Content)
+		assert.Equal(t, "image/png", response.ContentType)
+		assert.Equal(t, []byte("png"), response.Image)
+	})
+
+	t.Run("svg size is capped", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		renderer := new(MockQRCodeRenderer)
+		useCase := NewGetPaymentQRCodeUseCase(paymentRepo, renderer)
+
+		p := createTestPayment()
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		renderer.On("RenderSVG", mock.Anything, 1024).Return([]byte("<svg/>"), nil)
+
+		response, err := useCase.Execute(GetPaymentQRCodeCommand{PaymentID: p.ID, Format: "SVG", Size: 5000})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "image/svg+xml", response.ContentType)
+	})
+
+	t.Run("network without uri scheme encodes the address", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		renderer := new(MockQRCodeRenderer)
+		useCase := NewGetPaymentQRCodeUseCase(paymentRepo, renderer)
+
+		p, _ := domainPayment.NewPayment("order123", 100.0, "USD", "USDTTRC20", "TJRabPrwbZy45sbavfcjinPJC18kjpRTv8", 30)
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		renderer.On("RenderPNG", "TJRabPrwbZy45sbavfcjinPJC18kjpRTv8", 256).Return([]byte("png"), nil)
+
+		response, err := useCase.Execute(GetPaymentQRCodeCommand{PaymentID: p.ID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "TJRabPrwbZy45sbavfcjinPJC18kjpRTv8", response.Content)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		useCase := NewGetPaymentQRCodeUseCase(paymentRepo, new(MockQRCodeRenderer))
+
+		p := createTestPayment()
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+
+		_, err := useCase.Execute(GetPaymentQRCodeCommand{PaymentID: p.ID, Format: "gif"})
+
+		assert.Equal(t, domainPayment.ErrUnsupportedQRCodeFormat, err)
+	})
+}
@@ -0,0 +1,199 @@
+package payment
+
+import (
+	"time"
+
+	"github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/application/idempotency"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// PaymentService provides high-level payment operations
+type PaymentService struct {
+	paymentRepo PaymentRepository
+	idempotency *idempotency.Guard // nil disables idempotency keys
+
+	// Use cases
+	createPayment           *CreatePaymentUseCase
+	createInvoice           *CreateInvoiceUseCase
+	selectInvoiceCrypto     *SelectInvoiceCryptoUseCase
+	getPaymentPage          *GetPaymentPageUseCase
+	getPayment              *GetPaymentUseCase
+	getPaymentQRCode        *GetPaymentQRCodeUseCase
+	quotePayment            *QuotePaymentUseCase
+	trackConfirmations      *TrackConfirmationsUseCase
+	handleWebhook           *HandleWebhookUseCase
+	sendRefund              *SendRefundUseCase
+	simulatePayment         *SimulatePaymentUseCase
+	adminAction             *AdminPaymentActionUseCase
+	approveAdminAction      *ApproveAdminPaymentActionUseCase
+	rejectAdminAction       *RejectAdminPaymentActionUseCase
+	listAuditLog            *ListPaymentAuditLogUseCase
+	listCryptoCurrencies    *ListCryptoCurrenciesUseCase
+	setCryptoCurrencyActive *SetCryptoCurrencyActiveUseCase
+	syncCryptoCurrencies    *SyncCryptoCurrenciesUseCase
+}
+
+// PaymentServiceDeps holds the dependencies and settings of PaymentService
+type PaymentServiceDeps struct {
+	// Persistence
+	PaymentRepo PaymentRepository
+	OrderRepo   OrderRepository
+	InvoiceRepo InvoiceRepository
+	CryptoRepo  CryptoCurrencyRepository
+	ActionRepo  AdminActionRepository
+	AuditLog    PaymentAuditLog
+
+	// Coins and providers
+	Registry        *domainPayment.CryptoRegistry
+	Catalog         CryptoCurrencyCatalog
+	Providers       *PaymentProviders
+	ReferenceFinder PaymentReferenceFinder
+	RateProvider    CryptoRateProvider
+	QRRenderer      QRCodeRenderer
+
+	// Policies and collaborators
+	ScopeResolver    ConfirmationScopeResolver
+	ApprovalPolicy   domainPayment.AdminApprovalPolicy
+	FeePolicy        domainPayment.FeePolicy
+	Alerter          OperatorAlerter
+	Ledger           PaymentLedger      // nil disables ledger postings
+	IdempotencyGuard *idempotency.Guard // nil disables idempotency keys
+
+	// Provider payments being opened, shared by every instance so a retry
+	// never opens a second one; nil disables reuse on retries
+	ProviderPaymentStore idempotency.Store
+
+	// Settings
+	PaymentPageURL    string        // Base URL of the hosted payment page
+	ExpirationMinutes int           // Lifetime of payments and invoices
+	QuoteLockWindow   time.Duration // How long a quoted crypto amount is held
+	MaxSlippage       float64       // Largest requote change accepted without a new quote
+}
+
+// NewPaymentService creates a new instance of PaymentService
+func NewPaymentService(deps PaymentServiceDeps) *PaymentService {
+	createPayment := NewCreatePaymentUseCase(deps.PaymentRepo, deps.OrderRepo, deps.Providers, deps.ScopeResolver, deps.FeePolicy, deps.ProviderPaymentStore, deps.ExpirationMinutes)
+	trackConfirmations := NewTrackConfirmationsUseCase(deps.PaymentRepo, deps.OrderRepo, deps.Alerter, deps.Ledger)
+
+	return &PaymentService{
+		paymentRepo:             deps.PaymentRepo,
+		idempotency:             deps.IdempotencyGuard,
+		createPayment:           createPayment,
+		createInvoice:           NewCreateInvoiceUseCase(deps.InvoiceRepo, deps.OrderRepo, deps.Registry, deps.PaymentPageURL, deps.ExpirationMinutes),
+		selectInvoiceCrypto:     NewSelectInvoiceCryptoUseCase(deps.InvoiceRepo, createPayment),
+		getPaymentPage:          NewGetPaymentPageUseCase(deps.InvoiceRepo, deps.PaymentRepo),
+		getPayment:              NewGetPaymentUseCase(deps.PaymentRepo),
+		getPaymentQRCode:        NewGetPaymentQRCodeUseCase(deps.PaymentRepo, deps.QRRenderer),
+		quotePayment:            NewQuotePaymentUseCase(deps.PaymentRepo, deps.RateProvider, deps.ScopeResolver, deps.FeePolicy, deps.QuoteLockWindow, deps.MaxSlippage),
+		trackConfirmations:      trackConfirmations,
+		handleWebhook:           NewHandleWebhookUseCase(deps.Providers, deps.ReferenceFinder, trackConfirmations),
+		sendRefund:              NewSendRefundUseCase(deps.PaymentRepo, deps.Providers, deps.Ledger),
+		simulatePayment:         NewSimulatePaymentUseCase(deps.PaymentRepo, trackConfirmations),
+		adminAction:             NewAdminPaymentActionUseCase(deps.PaymentRepo, deps.OrderRepo, deps.ActionRepo, deps.AuditLog, deps.ApprovalPolicy, deps.Ledger),
+		approveAdminAction:      NewApproveAdminPaymentActionUseCase(deps.PaymentRepo, deps.OrderRepo, deps.ActionRepo, deps.AuditLog, deps.Ledger),
+		rejectAdminAction:       NewRejectAdminPaymentActionUseCase(deps.PaymentRepo, deps.ActionRepo, deps.AuditLog),
+		listAuditLog:            NewListPaymentAuditLogUseCase(deps.AuditLog),
+		listCryptoCurrencies:    NewListCryptoCurrenciesUseCase(deps.Registry),
+		setCryptoCurrencyActive: NewSetCryptoCurrencyActiveUseCase(deps.CryptoRepo, deps.Registry),
+		syncCryptoCurrencies:    NewSyncCryptoCurrenciesUseCase(deps.CryptoRepo, deps.Registry, deps.Catalog),
+	}
+}
+
+// CreatePayment opens a payment for an order at the provider routed for its coin. Retries with the same
+// idempotency key return the first payment instead of opening another one.
+func (s *PaymentService) CreatePayment(cmd CreatePaymentCommand) (*PaymentResponse, error) {
+	return idempotency.Run(s.idempotency, "payment.create_payment", cmd.IdempotencyKey, cmd, s.createPayment.Execute)
+}
+
+// CreateInvoice creates an invoice the customer pays from the hosted payment page
+func (s *PaymentService) CreateInvoice(cmd CreateInvoiceCommand) (*InvoiceResponse, error) {
+	return idempotency.Run(s.idempotency, "payment.create_invoice", cmd.IdempotencyKey, cmd, s.createInvoice.Execute)
+}
+
+// SelectInvoiceCrypto opens the invoice's payment in the coin the customer picked
+func (s *PaymentService) SelectInvoiceCrypto(cmd SelectInvoiceCryptoCommand) (*PaymentPageResponse, error) {
+	return idempotency.Run(s.idempotency, "payment.select_invoice_crypto", cmd.IdempotencyKey, cmd, s.selectInvoiceCrypto.Execute)
+}
+
+// GetPaymentPage returns the payment page of an invoice with its live status
+func (s *PaymentService) GetPaymentPage(cmd GetPaymentPageCommand) (*PaymentPageResponse, error) {
+	return s.getPaymentPage.Execute(cmd)
+}
+
+// GetPayment retrieves a payment with its block explorer links
+func (s *PaymentService) GetPayment(cmd GetPaymentCommand) (*PaymentResponse, error) {
+	return s.getPayment.Execute(cmd)
+}
+
+// GetPaymentQRCode renders the payment's payment URI as a QR code for mobile wallets
+func (s *PaymentService) GetPaymentQRCode(cmd GetPaymentQRCodeCommand) (*PaymentQRCodeResponse, error) {
+	return s.getPaymentQRCode.Execute(cmd)
+}
+
+// QuotePayment locks a crypto amount for a payment
+func (s *PaymentService) QuotePayment(cmd QuotePaymentCommand) (*QuotePaymentResponse, error) {
+	return idempotency.Run(s.idempotency, "payment.quote_payment", cmd.IdempotencyKey, cmd, s.quotePayment.Execute)
+}
+
+// TrackConfirmations applies a gateway transaction update, holding the payment and
+// its order when the transaction is reorganised away, replaced or dropped
+func (s *PaymentService) TrackConfirmations(cmd TrackConfirmationsCommand) (*PaymentResponse, error) {
+	return s.trackConfirmations.Execute(cmd)
+}
+
+// HandleWebhook verifies a provider's webhook and applies the payment state it
+// reports. Providers retry webhooks until they are acknowledged, so the same
+// state may be applied more than once.
+func (s *PaymentService) HandleWebhook(cmd HandleWebhookCommand) (*PaymentResponse, error) {
+	return s.handleWebhook.Execute(cmd)
+}
+
+// SendRefund has the payment's provider send its pending refund (admin)
+func (s *PaymentService) SendRefund(cmd SendRefundCommand) (*PaymentResponse, error) {
+	return idempotency.Run(s.idempotency, "payment.send_refund", cmd.IdempotencyKey, cmd, s.sendRefund.Execute)
+}
+
+// SimulatePayment plays gateway updates on a sandbox payment (sandbox mode only)
+func (s *PaymentService) SimulatePayment(cmd SimulatePaymentCommand) (*SimulatePaymentResponse, error) {
+	return idempotency.Run(s.idempotency, "payment.simulate_payment", cmd.IdempotencyKey, cmd, s.simulatePayment.Execute)
+}
+
+// AdminPaymentAction runs a manual action on a payment, or files it for approval (admin)
+func (s *PaymentService) AdminPaymentAction(cmd AdminPaymentActionCommand) (*AdminPaymentActionResponse, error) {
+	return idempotency.Run(s.idempotency, "payment.admin_payment_action", cmd.IdempotencyKey, cmd, s.adminAction.Execute)
+}
+
+// ApproveAdminPaymentAction approves and runs another admin's action (admin)
+func (s *PaymentService) ApproveAdminPaymentAction(cmd DecideAdminPaymentActionCommand) (*AdminPaymentActionResponse, error) {
+	return idempotency.Run(s.idempotency, "payment.approve_admin_payment_action", cmd.IdempotencyKey, cmd, s.approveAdminAction.Execute)
+}
+
+// RejectAdminPaymentAction rejects another admin's action (admin)
+func (s *PaymentService) RejectAdminPaymentAction(cmd DecideAdminPaymentActionCommand) (*AdminPaymentActionResponse, error) {
+	return idempotency.Run(s.idempotency, "payment.reject_admin_payment_action", cmd.IdempotencyKey, cmd, s.rejectAdminAction.Execute)
+}
+
+// ListPaymentAuditLog lists the admin actions recorded for a payment (admin)
+func (s *PaymentService) ListPaymentAuditLog(paymentID string) ([]PaymentAuditEntryResponse, error) {
+	return s.listAuditLog.Execute(paymentID)
+}
+
+// ListCryptoCurrencies lists every registered coin (admin)
+func (s *PaymentService) ListCryptoCurrencies() []CryptoCurrencyResponse {
+	return s.listCryptoCurrencies.Execute()
+}
+
+// EnableCryptoCurrency starts accepting a coin for new payments (admin)
+func (s *PaymentService) EnableCryptoCurrency(symbol string) (*CryptoCurrencyResponse, error) {
+	return s.setCryptoCurrencyActive.Execute(SetCryptoCurrencyActiveCommand{Symbol: symbol, Active: true})
+}
+
+// DisableCryptoCurrency stops accepting a coin for new payments (admin)
+func (s *PaymentService) DisableCryptoCurrency(symbol string) (*CryptoCurrencyResponse, error) {
+	return s.setCryptoCurrencyActive.Execute(SetCryptoCurrencyActiveCommand{Symbol: symbol, Active: false})
+}
+
+// SyncCryptoCurrencies disables coins the payment provider no longer offers
+func (s *PaymentService) SyncCryptoCurrencies() (*SyncCryptoCurrenciesResponse, error) {
+	return s.syncCryptoCurrencies.Execute()
+}
@@ -0,0 +1,87 @@
+package payment
+
+import (
+	"testing"
+
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Tests for NewPaymentService wiring
+
+func TestNewPaymentService(t *testing.T) {
+	t.Run("create payment uses the wired provider and repositories", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		orderRepo := new(MockOrderRepository)
+		gateway := newMockPaymentProvider("nowpayments")
+		service := NewPaymentService(PaymentServiceDeps{
+			PaymentRepo:       paymentRepo,
+			OrderRepo:         orderRepo,
+			Providers:         providersOf(gateway),
+			ScopeResolver:     newTestScopeResolver(),
+			FeePolicy:         domainPayment.DefaultFeePolicy(),
+			ExpirationMinutes: 30,
+		})
+
+		o := createPendingPaymentOrder()
+
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		orderRepo.On("Update", o).Return(nil)
+		gateway.On("CreatePayment", mock.Anything).Return(domainPayment.GatewayPayment{
+			GatewayPaymentID: "np-123",
+			PayAddress:       "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh",
+			PayAmount:        0.0015,
+		}, nil)
+		paymentRepo.On("Save", mock.MatchedBy(func(p *domainPayment.Payment) bool {
+			return p.Provider == "nowpayments" && p.OrderID == o.ID.String()
+		})).Return(nil)
+
+		response, err := service.CreatePayment(CreatePaymentCommand{OrderID: o.ID.String(), CryptoCurrency: "btc"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "PENDING", response.Status)
+		assert.NotNil(t, response.ExpiresAt)
+		gateway.AssertExpectations(t)
+		paymentRepo.AssertExpectations(t)
+	})
+
+	t.Run("admin actions reach the order repository and audit log", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		orderRepo := new(MockOrderRepository)
+		actionRepo := new(MockAdminActionRepository)
+		auditLog := new(MockPaymentAuditLog)
+		service := NewPaymentService(PaymentServiceDeps{
+			PaymentRepo: paymentRepo,
+			OrderRepo:   orderRepo,
+			ActionRepo:  actionRepo,
+			AuditLog:    auditLog,
+		})
+
+		o, p := createPaidOrder()
+		_ = p.UpdateConfirmations(0)
+		_ = o.HoldForPaymentReview("CONFIRMATIONS_DROPPED")
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		orderRepo.On("Update", o).Return(nil)
+		actionRepo.On("Save", mock.Anything).Return(nil)
+		auditLog.On("Append", mock.Anything).Return(nil)
+
+		response, err := service.AdminPaymentAction(AdminPaymentActionCommand{
+			PaymentID:  p.ID,
+			Action:     "CONFIRM",
+			Actor:      "admin-1",
+			ReasonCode: "VERIFIED_ON_CHAIN",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "EXECUTED", response.Status)
+		assert.Equal(t, domainOrder.StatusPaid, o.Status)
+		actionRepo.AssertExpectations(t)
+		auditLog.AssertExpectations(t)
+		orderRepo.AssertExpectations(t)
+	})
+}
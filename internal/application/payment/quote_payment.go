@@ -0,0 +1,170 @@
+package payment
+
+import (
+	"time"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// QuotePaymentCommand represents the input for quoting a payment in crypto
+type QuotePaymentCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	PaymentID string `json:"payment_id" validate:"required"`
+}
+
+// QuotePaymentResponse represents the locked quote applied to a payment
+type QuotePaymentResponse struct {
+	PaymentID      string  `json:"payment_id"`
+	QuoteID        string  `json:"quote_id"`
+	FiatAmount     float64 `json:"fiat_amount"`
+	FiatCurrency   string  `json:"fiat_currency"`
+	CryptoAmount   float64 `json:"crypto_amount"` // Includes fee_surcharge
+	CryptoCurrency string  `json:"crypto_currency"`
+	FeeSurcharge   float64 `json:"fee_surcharge,omitempty"`
+	Rate           float64 `json:"rate"`
+	Source         string  `json:"source"`
+	QuotedAt       string  `json:"quoted_at"`
+	ExpiresAt      string  `json:"expires_at"`
+
+	RequiredConfirmations int  `json:"required_confirmations"`
+	ZeroConfRisk          bool `json:"zero_conf_risk"` // Accepted from the mempool, flagged for review
+}
+
+// PaymentRepository defines the interface for payment persistence
+type PaymentRepository interface {
+	Save(payment *domainPayment.Payment) error
+	FindByID(id string) (*domainPayment.Payment, error)
+	Update(payment *domainPayment.Payment) error
+}
+
+// CryptoRateProvider estimates how much crypto a fiat amount buys
+// (e.g. the NowPayments estimate endpoint or a static rate table)
+type CryptoRateProvider interface {
+	Name() string
+	EstimateCryptoAmount(fiatAmount float64, fiatCurrency string, cryptoSymbol string) (float64, error)
+}
+
+// ConfirmationScopeResolver looks up what an order contains and who placed it,
+// so the confirmation policy can apply category and trust level overrides. It
+// also returns the exchange rates recorded on the order, so orders in another
+// currency than the policy's are valued in the policy's currency.
+type ConfirmationScopeResolver interface {
+	ResolveConfirmationScope(orderID string) (domainPayment.ConfirmationScope, error)
+}
+
+// QuotePaymentUseCase computes a payment's crypto amount from a locked quote
+type QuotePaymentUseCase struct {
+	paymentRepo PaymentRepository
+	provider    CryptoRateProvider
+	scopes      ConfirmationScopeResolver
+	feePolicy   domainPayment.FeePolicy
+	lockWindow  time.Duration
+	maxSlippage float64
+}
+
+// NewQuotePaymentUseCase creates a new instance of QuotePaymentUseCase.
+// Quotes are locked for lockWindow (never beyond the payment's expiry); a
+// re-quote is rejected when the rate moved more than maxSlippage (0.01 = 1%).
+// The fee policy adds customer-borne fees on top of the quoted amount.
+func NewQuotePaymentUseCase(paymentRepo PaymentRepository, provider CryptoRateProvider, scopes ConfirmationScopeResolver, feePolicy domainPayment.FeePolicy, lockWindow time.Duration, maxSlippage float64) *QuotePaymentUseCase {
+	return &QuotePaymentUseCase{
+		paymentRepo: paymentRepo,
+		provider:    provider,
+		scopes:      scopes,
+		feePolicy:   feePolicy,
+		lockWindow:  lockWindow,
+		maxSlippage: maxSlippage,
+	}
+}
+
+// Execute quotes a payment.
+// While the current quote is locked it is returned unchanged, so the customer
+// always sees the same crypto amount within the lock window.
+func (uc *QuotePaymentUseCase) Execute(cmd QuotePaymentCommand) (*QuotePaymentResponse, error) {
+	if cmd.PaymentID == "" {
+		return nil, domainPayment.ErrEmptyPaymentID
+	}
+
+	existingPayment, err := uc.paymentRepo.FindByID(cmd.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingPayment == nil {
+		return nil, domainPayment.ErrPaymentNotFound
+	}
+
+	if existingPayment.IsQuoteLocked() {
+		return toQuotePaymentResponse(existingPayment), nil
+	}
+
+	cryptoAmount, err := uc.provider.EstimateCryptoAmount(existingPayment.Amount, existingPayment.Currency, existingPayment.GetCryptoSymbol())
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := domainPayment.NewCryptoQuote(
+		existingPayment.Amount,
+		existingPayment.Currency,
+		cryptoAmount,
+		existingPayment.CryptoCurrency,
+		uc.provider.Name(),
+		existingPayment.QuoteLockUntil(uc.lockWindow),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := existingPayment.Requote(quote, uc.maxSlippage); err != nil {
+		return nil, err
+	}
+
+	if err := existingPayment.ApplyFeePolicy(uc.feePolicy); err != nil {
+		return nil, err
+	}
+
+	// Required confirmations depend on the order's categories and the customer's trust level
+	scope, err := uc.scopes.ResolveConfirmationScope(existingPayment.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := existingPayment.SetConfirmationScope(scope); err != nil {
+		return nil, err
+	}
+
+	if err := uc.paymentRepo.Update(existingPayment); err != nil {
+		return nil, err
+	}
+
+	return toQuotePaymentResponse(existingPayment), nil
+}
+
+// toQuotePaymentResponse maps a quoted payment to its response
+func toQuotePaymentResponse(p *domainPayment.Payment) *QuotePaymentResponse {
+	response := &QuotePaymentResponse{
+		PaymentID:      p.ID,
+		QuoteID:        p.QuoteID,
+		FiatAmount:     p.Amount,
+		FiatCurrency:   p.Currency,
+		CryptoAmount:   p.CryptoAmount,
+		CryptoCurrency: p.GetCryptoSymbol(),
+		FeeSurcharge:   p.FeeSurcharge,
+		Rate:           p.QuoteRate,
+		Source:         p.QuoteSource,
+
+		RequiredConfirmations: p.RequiredConfirmations,
+		ZeroConfRisk:          p.ZeroConfRisk,
+	}
+
+	if p.QuotedAt != nil {
+		response.QuotedAt = p.QuotedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+
+	if p.QuoteExpiresAt != nil {
+		response.ExpiresAt = p.QuoteExpiresAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+
+	return response
+}
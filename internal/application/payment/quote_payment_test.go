@@ -0,0 +1,281 @@
+package payment
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPaymentRepository is a mock implementation of PaymentRepository
+type MockPaymentRepository struct {
+	mock.Mock
+}
+
+func (m *MockPaymentRepository) Save(payment *domainPayment.Payment) error {
+	args := m.Called(payment)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepository) FindByID(id string) (*domainPayment.Payment, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainPayment.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepository) Update(payment *domainPayment.Payment) error {
+	args := m.Called(payment)
+	return args.Error(0)
+}
+
+// MockCryptoRateProvider is a mock implementation of CryptoRateProvider
+type MockCryptoRateProvider struct {
+	mock.Mock
+}
+
+func (m *MockCryptoRateProvider) Name() string {
+	return "mock"
+}
+
+func (m *MockCryptoRateProvider) EstimateCryptoAmount(fiatAmount float64, fiatCurrency string, cryptoSymbol string) (float64, error) {
+	args := m.Called(fiatAmount, fiatCurrency, cryptoSymbol)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+// MockConfirmationScopeResolver is a mock implementation of ConfirmationScopeResolver
+type MockConfirmationScopeResolver struct {
+	mock.Mock
+}
+
+func (m *MockConfirmationScopeResolver) ResolveConfirmationScope(orderID string) (domainPayment.ConfirmationScope, error) {
+	args := m.Called(orderID)
+	return args.Get(0).(domainPayment.ConfirmationScope), args.Error(1)
+}
+
+// Test helper functions
+
+// newTestScopeResolver returns a resolver that finds no overrides for any order
+func newTestScopeResolver() *MockConfirmationScopeResolver {
+	resolver := new(MockConfirmationScopeResolver)
+	resolver.On("ResolveConfirmationScope", mock.Anything).Return(domainPayment.ConfirmationScope{}, nil)
+	return resolver
+}
+
+func createTestPayment() *domainPayment.Payment {
+	p, _ := domainPayment.NewPayment("order123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+	return p
+}
+
+// Tests for QuotePaymentUseCase
+
+func TestQuotePaymentUseCase(t *testing.T) {
+	t.Run("first quote sets the crypto amount", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		provider := new(MockCryptoRateProvider)
+		useCase := NewQuotePaymentUseCase(paymentRepo, provider, newTestScopeResolver(), domainPayment.DefaultFeePolicy(), 10*time.Minute, 0.02)
+
+		p := createTestPayment()
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		provider.On("EstimateCryptoAmount", 100.0, "USD", "BTC").Return(0.002, nil)
+		paymentRepo.On("Update", p).Return(nil)
+
+		response, err := useCase.Execute(QuotePaymentCommand{PaymentID: p.ID})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, response.QuoteID)
+		assert.Equal(t, 0.002, response.CryptoAmount)
+		assert.Equal(t, 50000.0, response.Rate)
+		assert.Equal(t, "mock", response.Source)
+		assert.Equal(t, 0.002, p.CryptoAmount)
+		assert.WithinDuration(t, time.Now().Add(10*time.Minute), *p.QuoteExpiresAt, time.Second)
+
+		paymentRepo.AssertExpectations(t)
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("customer-borne fee is added to the quote", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		provider := new(MockCryptoRateProvider)
+		policy, _ := domainPayment.NewFeePolicy(domainPayment.FeeBearerCustomer, 0.005)
+		useCase := NewQuotePaymentUseCase(paymentRepo, provider, newTestScopeResolver(), policy, 10*time.Minute, 0.02)
+
+		p := createTestPayment()
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		provider.On("EstimateCryptoAmount", 100.0, "USD", "BTC").Return(0.00199, nil)
+		paymentRepo.On("Update", p).Return(nil)
+
+		response, err := useCase.Execute(QuotePaymentCommand{PaymentID: p.ID})
+
+		assert.NoError(t, err)
+		assert.InDelta(t, 0.002, response.CryptoAmount, 1e-12)
+		assert.InDelta(t, 0.00001, response.FeeSurcharge, 1e-12)
+		assert.InDelta(t, 100/0.00199, response.Rate, 1e-6)
+	})
+
+	t.Run("locked quote is returned unchanged", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		provider := new(MockCryptoRateProvider)
+		useCase := NewQuotePaymentUseCase(paymentRepo, provider, newTestScopeResolver(), domainPayment.DefaultFeePolicy(), 10*time.Minute, 0.02)
+
+		p := createTestPayment()
+		quote, _ := domainPayment.NewCryptoQuote(100.0, "USD", 0.002, p.CryptoCurrency, "mock", p.QuoteLockUntil(time.Minute))
+		_ = p.ApplyQuote(quote)
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+
+		response, err := useCase.Execute(QuotePaymentCommand{PaymentID: p.ID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, quote.ID, response.QuoteID)
+		provider.AssertNotCalled(t, "EstimateCryptoAmount", mock.Anything, mock.Anything, mock.Anything)
+		paymentRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("lock window never outlives the payment", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		provider := new(MockCryptoRateProvider)
+		useCase := NewQuotePaymentUseCase(paymentRepo, provider, newTestScopeResolver(), domainPayment.DefaultFeePolicy(), 2*time.Hour, 0.02)
+
+		p := createTestPayment()
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		provider.On("EstimateCryptoAmount", 100.0, "USD", "BTC").Return(0.002, nil)
+		paymentRepo.On("Update", p).Return(nil)
+
+		_, err := useCase.Execute(QuotePaymentCommand{PaymentID: p.ID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, p.ExpiresAt, *p.QuoteExpiresAt)
+	})
+
+	t.Run("requote within slippage replaces the quote", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		provider := new(MockCryptoRateProvider)
+		useCase := NewQuotePaymentUseCase(paymentRepo, provider, newTestScopeResolver(), domainPayment.DefaultFeePolicy(), 10*time.Minute, 0.02)
+
+		p := createTestPayment()
+		expireQuote(p, 0.002)
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		provider.On("EstimateCryptoAmount", 100.0, "USD", "BTC").Return(0.00199, nil)
+		paymentRepo.On("Update", p).Return(nil)
+
+		response, err := useCase.Execute(QuotePaymentCommand{PaymentID: p.ID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0.00199, response.CryptoAmount)
+		assert.True(t, p.IsQuoteLocked())
+	})
+
+	t.Run("requote beyond slippage is rejected", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		provider := new(MockCryptoRateProvider)
+		useCase := NewQuotePaymentUseCase(paymentRepo, provider, newTestScopeResolver(), domainPayment.DefaultFeePolicy(), 10*time.Minute, 0.02)
+
+		p := createTestPayment()
+		expireQuote(p, 0.002)
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		provider.On("EstimateCryptoAmount", 100.0, "USD", "BTC").Return(0.0025, nil)
+
+		response, err := useCase.Execute(QuotePaymentCommand{PaymentID: p.ID})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainPayment.ErrSlippageExceeded, err)
+		assert.Equal(t, 0.002, p.CryptoAmount)
+		paymentRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		provider := new(MockCryptoRateProvider)
+		useCase := NewQuotePaymentUseCase(paymentRepo, provider, newTestScopeResolver(), domainPayment.DefaultFeePolicy(), 10*time.Minute, 0.02)
+
+		p := createTestPayment()
+		providerErr := errors.New("provider down")
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		provider.On("EstimateCryptoAmount", 100.0, "USD", "BTC").Return(0.0, providerErr)
+
+		response, err := useCase.Execute(QuotePaymentCommand{PaymentID: p.ID})
+
+		assert.Nil(t, response)
+		assert.Equal(t, providerErr, err)
+	})
+
+	t.Run("payment not found", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		useCase := NewQuotePaymentUseCase(paymentRepo, new(MockCryptoRateProvider), newTestScopeResolver(), domainPayment.DefaultFeePolicy(), 10*time.Minute, 0.02)
+
+		paymentRepo.On("FindByID", "missing").Return(nil, nil)
+
+		response, err := useCase.Execute(QuotePaymentCommand{PaymentID: "missing"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainPayment.ErrPaymentNotFound, err)
+	})
+
+	t.Run("confirmations follow the order scope", func(t *testing.T) {
+		previous := domainPayment.DefaultConfirmationPolicy()
+		defer func() { _ = domainPayment.SetDefaultConfirmationPolicy(previous) }()
+		_ = domainPayment.SetDefaultConfirmationPolicy(&domainPayment.ConfirmationPolicy{
+			Currency:    "USD",
+			Default:     domainPayment.ConfirmationTable{{UpTo: 0, Scale: 1}},
+			TrustLevels: map[string]domainPayment.ConfirmationTable{"TRUSTED": {{UpTo: 500, Scale: 0}, {UpTo: 0, Scale: 1}}},
+		})
+
+		paymentRepo := new(MockPaymentRepository)
+		provider := new(MockCryptoRateProvider)
+		resolver := new(MockConfirmationScopeResolver)
+		useCase := NewQuotePaymentUseCase(paymentRepo, provider, resolver, domainPayment.DefaultFeePolicy(), 10*time.Minute, 0.02)
+
+		p := createTestPayment()
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		provider.On("EstimateCryptoAmount", 100.0, "USD", "BTC").Return(0.002, nil)
+		resolver.On("ResolveConfirmationScope", "order123").Return(domainPayment.ConfirmationScope{TrustLevel: "TRUSTED"}, nil)
+		paymentRepo.On("Update", p).Return(nil)
+
+		response, err := useCase.Execute(QuotePaymentCommand{PaymentID: p.ID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, response.RequiredConfirmations)
+		assert.True(t, response.ZeroConfRisk)
+		resolver.AssertExpectations(t)
+	})
+
+	t.Run("scope lookup error", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		provider := new(MockCryptoRateProvider)
+		resolver := new(MockConfirmationScopeResolver)
+		useCase := NewQuotePaymentUseCase(paymentRepo, provider, resolver, domainPayment.DefaultFeePolicy(), 10*time.Minute, 0.02)
+		lookupErr := errors.New("order not found")
+
+		p := createTestPayment()
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		provider.On("EstimateCryptoAmount", 100.0, "USD", "BTC").Return(0.002, nil)
+		resolver.On("ResolveConfirmationScope", "order123").Return(domainPayment.ConfirmationScope{}, lookupErr)
+
+		response, err := useCase.Execute(QuotePaymentCommand{PaymentID: p.ID})
+
+		assert.Nil(t, response)
+		assert.Equal(t, lookupErr, err)
+		paymentRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
+
+// expireQuote applies a quote and moves its lock window into the past
+func expireQuote(p *domainPayment.Payment, cryptoAmount float64) {
+	quote, _ := domainPayment.NewCryptoQuote(p.Amount, p.Currency, cryptoAmount, p.CryptoCurrency, "mock", p.QuoteLockUntil(time.Minute))
+	_ = p.ApplyQuote(quote)
+	expired := time.Now().Add(-time.Second)
+	p.QuoteExpiresAt = &expired
+}
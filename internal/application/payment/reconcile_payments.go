@@ -0,0 +1,293 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// ReconcilerConfig holds the polling settings of the payment reconciler
+type ReconcilerConfig struct {
+	Interval      time.Duration // Quiet time before a payment is polled, and the first backoff step
+	MaxBackoff    time.Duration // Longest wait between two polls of the same payment
+	MaxConcurrent int           // Gateway calls in flight at once
+}
+
+// StatusDisagreement records a payment whose state differs from the gateway's
+type StatusDisagreement struct {
+	PaymentID        string `json:"payment_id"`
+	GatewayPaymentID string `json:"gateway_payment_id"`
+	LocalStatus      string `json:"local_status"`
+	GatewayStatus    string `json:"gateway_status"`
+	Error            string `json:"error,omitempty"` // Set when the gateway state could not be applied
+}
+
+// ReconcileResult summarises one reconciliation pass
+type ReconcileResult struct {
+	Polled        int `json:"polled"`
+	Updated       int `json:"updated"`
+	Disagreements int `json:"disagreements"`
+	GatewayErrors int `json:"gateway_errors"`
+}
+
+// NonFinalPaymentFinder defines the interface for loading payments that can still change
+type NonFinalPaymentFinder interface {
+	FindNonFinal() ([]*domainPayment.Payment, error)
+}
+
+// PaymentStatusGateway defines the interface for reading a payment's state from the gateway
+type PaymentStatusGateway interface {
+	GetPaymentStatus(gatewayPaymentID string) (domainPayment.GatewayPaymentStatus, error)
+}
+
+// ReconciliationLogger records disagreements found by the reconciler, and
+// passes that could not run
+type ReconciliationLogger interface {
+	LogStatusDisagreement(disagreement StatusDisagreement)
+	LogReconcileError(err error)
+}
+
+// pollSchedule tracks the backoff of one payment
+type pollSchedule struct {
+	attempts   int // Polls in a row that changed nothing
+	nextPollAt time.Time
+}
+
+// PaymentReconciler polls each payment's provider for payments whose webhooks
+// may have been missed. Gateway states are applied through
+// TrackConfirmationsUseCase, the same path webhooks take. Payments that do not
+// change are polled less and less often, and payments of providers that
+// cannot report a state are left to admins.
+type PaymentReconciler struct {
+	finder    NonFinalPaymentFinder
+	providers *PaymentProviders
+	tracker   *TrackConfirmationsUseCase
+	logger    ReconciliationLogger
+	config    ReconcilerConfig
+
+	mu        sync.Mutex
+	schedules map[string]*pollSchedule
+}
+
+// NewPaymentReconciler creates a new instance of PaymentReconciler
+func NewPaymentReconciler(finder NonFinalPaymentFinder, providers *PaymentProviders, tracker *TrackConfirmationsUseCase, logger ReconciliationLogger, config ReconcilerConfig) *PaymentReconciler {
+	if config.MaxConcurrent < 1 {
+		config.MaxConcurrent = 1
+	}
+	if config.MaxBackoff < config.Interval {
+		config.MaxBackoff = config.Interval
+	}
+
+	return &PaymentReconciler{
+		finder:    finder,
+		providers: providers,
+		tracker:   tracker,
+		logger:    logger,
+		config:    config,
+		schedules: make(map[string]*pollSchedule),
+	}
+}
+
+// Run reconciles every interval until the context is cancelled. A pass that
+// fails is logged and retried on the next tick.
+func (r *PaymentReconciler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			if _, err := r.Execute(now); err != nil {
+				r.logger.LogReconcileError(err)
+			}
+		}
+	}
+}
+
+// Execute polls the gateway for every non-final payment that is due
+func (r *PaymentReconciler) Execute(now time.Time) (*ReconcileResult, error) {
+	payments, err := r.finder.FindNonFinal()
+	if err != nil {
+		return nil, err
+	}
+
+	due := r.duePayments(payments, now)
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		result ReconcileResult
+	)
+	slots := make(chan struct{}, r.config.MaxConcurrent)
+
+	for _, p := range due {
+		wg.Add(1)
+		slots <- struct{}{}
+
+		go func(p *domainPayment.Payment) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			updated, disagreed, err := r.reconcile(p)
+			r.reschedule(p.ID, now, updated)
+
+			mu.Lock()
+			defer mu.Unlock()
+			result.Polled++
+			if err != nil {
+				result.GatewayErrors++
+			}
+			if updated {
+				result.Updated++
+			}
+			if disagreed {
+				result.Disagreements++
+			}
+		}(p)
+	}
+
+	wg.Wait()
+
+	return &result, nil
+}
+
+// duePayments returns the payments to poll now. Payments that were updated
+// within the last interval, e.g. by a webhook, are left alone.
+func (r *PaymentReconciler) duePayments(payments []*domainPayment.Payment, now time.Time) []*domainPayment.Payment {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tracked := make(map[string]bool, len(payments))
+	var due []*domainPayment.Payment
+
+	for _, p := range payments {
+		// Payments never registered with a provider have nothing to poll
+		if p.ProviderReference == "" || p.Status.IsFinal() {
+			continue
+		}
+		tracked[p.ID] = true
+
+		if now.Before(p.UpdatedAt.Add(r.config.Interval)) {
+			continue
+		}
+		if schedule, ok := r.schedules[p.ID]; ok && now.Before(schedule.nextPollAt) {
+			continue
+		}
+		due = append(due, p)
+	}
+
+	// Forget payments that were finalised
+	for id := range r.schedules {
+		if !tracked[id] {
+			delete(r.schedules, id)
+		}
+	}
+
+	return due
+}
+
+// reschedule sets the next poll of a payment. The wait doubles after every
+// poll that changed nothing and resets once the payment moves.
+func (r *PaymentReconciler) reschedule(paymentID string, now time.Time, updated bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	schedule, ok := r.schedules[paymentID]
+	if !ok {
+		schedule = &pollSchedule{}
+		r.schedules[paymentID] = schedule
+	}
+
+	if updated {
+		schedule.attempts = 0
+	} else {
+		schedule.attempts++
+	}
+
+	wait := r.config.Interval
+	for i := 0; i < schedule.attempts && wait < r.config.MaxBackoff; i++ {
+		wait *= 2
+	}
+	schedule.nextPollAt = now.Add(min(wait, r.config.MaxBackoff))
+}
+
+// reconcile applies the gateway's view of one payment. Every payment out of
+// sync with its gateway is logged as a disagreement before it is applied, so
+// a missed webhook the poll corrects is on record too. A second entry follows
+// when the state cannot be applied, or is applied but our rules do not follow
+// it (e.g. a refund reported by the gateway or a payment we hold for review).
+func (r *PaymentReconciler) reconcile(p *domainPayment.Payment) (updated bool, disagreed bool, err error) {
+	provider, err := r.providers.Get(p.Provider)
+	if err != nil {
+		return false, false, err
+	}
+
+	remote, err := provider.GetPaymentStatus(p.ProviderReference)
+	if errors.Is(err, domainPayment.ErrProviderNotSupported) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+
+	expected := remote.Status.PaymentStatus()
+	localStatus := p.Status
+	localConfirmations := p.Confirmations
+
+	inSync := localStatus == expected &&
+		remote.Confirmations <= localConfirmations &&
+		(remote.TransactionHash == "" || remote.TransactionHash == p.TransactionHash) &&
+		!hasNewFees(p, remote)
+	if inSync {
+		return false, false, nil
+	}
+
+	disagreement := StatusDisagreement{
+		PaymentID:        p.ID,
+		GatewayPaymentID: p.ProviderReference,
+		LocalStatus:      string(localStatus),
+		GatewayStatus:    string(remote.Status),
+	}
+	r.logger.LogStatusDisagreement(disagreement)
+
+	response, applyErr := r.tracker.Execute(toTrackConfirmationsCommand(p.ID, remote))
+	if applyErr != nil {
+		disagreement.Error = applyErr.Error()
+		r.logger.LogStatusDisagreement(disagreement)
+		return false, true, nil
+	}
+
+	updated = response.Status != string(localStatus) || response.Confirmations != localConfirmations
+
+	if response.Status != string(expected) && response.Status != string(localStatus) {
+		disagreement.LocalStatus = response.Status
+		r.logger.LogStatusDisagreement(disagreement)
+	}
+
+	return updated, true, nil
+}
+
+// hasNewFees checks if the gateway reports fees the payment does not have yet
+func hasNewFees(p *domainPayment.Payment, remote domainPayment.GatewayPaymentStatus) bool {
+	if remote.GatewayFee == 0 && remote.NetworkFee == 0 {
+		return false
+	}
+	return remote.GatewayFee != p.GatewayFee || remote.NetworkFee != p.NetworkFee
+}
+
+// toTrackConfirmationsCommand turns a provider's view of a payment into a transaction update
+func toTrackConfirmationsCommand(paymentID string, remote domainPayment.GatewayPaymentStatus) TrackConfirmationsCommand {
+	return TrackConfirmationsCommand{
+		PaymentID:       paymentID,
+		TransactionHash: remote.TransactionHash,
+		Confirmations:   remote.Confirmations,
+		GatewayStatus:   string(remote.Status),
+		GatewayFee:      remote.GatewayFee,
+		NetworkFee:      remote.NetworkFee,
+	}
+}
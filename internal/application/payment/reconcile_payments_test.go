@@ -0,0 +1,308 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockNonFinalPaymentFinder is a mock implementation of NonFinalPaymentFinder
+type MockNonFinalPaymentFinder struct {
+	mock.Mock
+}
+
+func (m *MockNonFinalPaymentFinder) FindNonFinal() ([]*domainPayment.Payment, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domainPayment.Payment), args.Error(1)
+}
+
+// MockReconciliationLogger is a mock implementation of ReconciliationLogger
+type MockReconciliationLogger struct {
+	mock.Mock
+}
+
+func (m *MockReconciliationLogger) LogStatusDisagreement(disagreement StatusDisagreement) {
+	m.Called(disagreement)
+}
+
+func (m *MockReconciliationLogger) LogReconcileError(err error) {
+	m.Called(err)
+}
+
+// slowGateway reports every payment as waiting and records how many calls overlap
+type slowGateway struct {
+	PaymentProvider // Only GetPaymentStatus is called
+
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (g *slowGateway) Name() string {
+	return "nowpayments"
+}
+
+func (g *slowGateway) GetPaymentStatus(gatewayPaymentID string) (domainPayment.GatewayPaymentStatus, error) {
+	g.mu.Lock()
+	g.inFlight++
+	g.peak = max(g.peak, g.inFlight)
+	g.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	g.mu.Lock()
+	g.inFlight--
+	g.mu.Unlock()
+
+	return domainPayment.GatewayPaymentStatus{GatewayPaymentID: gatewayPaymentID, Status: domainPayment.GatewayStatusWaiting}, nil
+}
+
+var testReconcilerConfig = ReconcilerConfig{Interval: time.Minute, MaxBackoff: 8 * time.Minute, MaxConcurrent: 2}
+
+// createGatewayPayment creates a quoted payment registered with the gateway
+func createGatewayPayment(gatewayPaymentID string) *domainPayment.Payment {
+	p := createTestPayment()
+	_ = p.UpdateCryptoAmount(0.002)
+	_ = p.SetProviderReference("nowpayments", gatewayPaymentID)
+	return p
+}
+
+// Tests for PaymentReconciler
+
+func TestPaymentReconciler(t *testing.T) {
+	later := time.Now().Add(time.Hour)
+
+	t.Run("missed webhook is applied", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		finder := new(MockNonFinalPaymentFinder)
+		gateway := newMockPaymentProvider("nowpayments")
+		logger := new(MockReconciliationLogger)
+		p := createGatewayPayment("np-1")
+		orderRepo, o := newAwaitingOrderRepository(p)
+		tracker := NewTrackConfirmationsUseCase(paymentRepo, orderRepo, new(MockOperatorAlerter), nil)
+		reconciler := NewPaymentReconciler(finder, providersOf(gateway), tracker, logger, testReconcilerConfig)
+
+		finder.On("FindNonFinal").Return([]*domainPayment.Payment{p}, nil)
+		gateway.On("GetPaymentStatus", "np-1").Return(domainPayment.GatewayPaymentStatus{Status: domainPayment.GatewayStatusFinished, TransactionHash: testTransactionHash}, nil)
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		logger.On("LogStatusDisagreement", StatusDisagreement{
+			PaymentID:        p.ID,
+			GatewayPaymentID: "np-1",
+			LocalStatus:      "PENDING",
+			GatewayStatus:    "finished",
+		}).Return()
+
+		result, err := reconciler.Execute(later)
+
+		assert.NoError(t, err)
+		assert.Equal(t, ReconcileResult{Polled: 1, Updated: 1, Disagreements: 1}, *result)
+		assert.Equal(t, domainPayment.StatusConfirmed, p.Status)
+		assert.Equal(t, testTransactionHash, p.TransactionHash)
+		assert.Equal(t, domainOrder.StatusPaid, o.Status)
+		logger.AssertExpectations(t)
+		logger.AssertNumberOfCalls(t, "LogStatusDisagreement", 1)
+	})
+
+	t.Run("unchanged payment backs off", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		finder := new(MockNonFinalPaymentFinder)
+		gateway := newMockPaymentProvider("nowpayments")
+		tracker := NewTrackConfirmationsUseCase(paymentRepo, new(MockOrderRepository), new(MockOperatorAlerter), nil)
+		reconciler := NewPaymentReconciler(finder, providersOf(gateway), tracker, new(MockReconciliationLogger), testReconcilerConfig)
+
+		p := createGatewayPayment("np-1")
+
+		finder.On("FindNonFinal").Return([]*domainPayment.Payment{p}, nil)
+		gateway.On("GetPaymentStatus", "np-1").Return(domainPayment.GatewayPaymentStatus{Status: domainPayment.GatewayStatusWaiting}, nil)
+
+		polls := func(at time.Time) int {
+			result, _ := reconciler.Execute(at)
+			return result.Polled
+		}
+
+		assert.Equal(t, 1, polls(later))
+		assert.Equal(t, 0, polls(later.Add(time.Minute)))
+		assert.Equal(t, 1, polls(later.Add(2*time.Minute)))
+		assert.Equal(t, 0, polls(later.Add(5*time.Minute)))
+		assert.Equal(t, 1, polls(later.Add(6*time.Minute)))
+		gateway.AssertNumberOfCalls(t, "GetPaymentStatus", 3)
+		paymentRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("backoff is capped", func(t *testing.T) {
+		finder := new(MockNonFinalPaymentFinder)
+		gateway := newMockPaymentProvider("nowpayments")
+		tracker := NewTrackConfirmationsUseCase(new(MockPaymentRepository), new(MockOrderRepository), new(MockOperatorAlerter), nil)
+		reconciler := NewPaymentReconciler(finder, providersOf(gateway), tracker, new(MockReconciliationLogger), testReconcilerConfig)
+
+		p := createGatewayPayment("np-1")
+
+		finder.On("FindNonFinal").Return([]*domainPayment.Payment{p}, nil)
+		gateway.On("GetPaymentStatus", "np-1").Return(domainPayment.GatewayPaymentStatus{}, errors.New("gateway down"))
+
+		at := later
+		for i := 0; i < 6; i++ {
+			result, _ := reconciler.Execute(at)
+			assert.Equal(t, 1, result.GatewayErrors)
+			at = at.Add(8 * time.Minute)
+		}
+	})
+
+	t.Run("disagreement is logged", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		finder := new(MockNonFinalPaymentFinder)
+		gateway := newMockPaymentProvider("nowpayments")
+		logger := new(MockReconciliationLogger)
+		tracker := NewTrackConfirmationsUseCase(paymentRepo, new(MockOrderRepository), new(MockOperatorAlerter), nil)
+		reconciler := NewPaymentReconciler(finder, providersOf(gateway), tracker, logger, testReconcilerConfig)
+
+		p := createGatewayPayment("np-1")
+		_ = p.MarkAsConfirming(testTransactionHash)
+
+		finder.On("FindNonFinal").Return([]*domainPayment.Payment{p}, nil)
+		gateway.On("GetPaymentStatus", "np-1").Return(domainPayment.GatewayPaymentStatus{Status: domainPayment.GatewayStatusRefunded}, nil)
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		logger.On("LogStatusDisagreement", StatusDisagreement{
+			PaymentID:        p.ID,
+			GatewayPaymentID: "np-1",
+			LocalStatus:      "CONFIRMING",
+			GatewayStatus:    "refunded",
+		}).Return()
+
+		result, err := reconciler.Execute(later)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Disagreements)
+		assert.Equal(t, domainPayment.StatusConfirming, p.Status)
+		logger.AssertExpectations(t)
+		logger.AssertNumberOfCalls(t, "LogStatusDisagreement", 1)
+	})
+
+	t.Run("rejected transition is logged", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		finder := new(MockNonFinalPaymentFinder)
+		gateway := newMockPaymentProvider("nowpayments")
+		logger := new(MockReconciliationLogger)
+		tracker := NewTrackConfirmationsUseCase(paymentRepo, new(MockOrderRepository), new(MockOperatorAlerter), nil)
+		reconciler := NewPaymentReconciler(finder, providersOf(gateway), tracker, logger, testReconcilerConfig)
+
+		p := createGatewayPayment("np-1")
+
+		finder.On("FindNonFinal").Return([]*domainPayment.Payment{p}, nil)
+		gateway.On("GetPaymentStatus", "np-1").Return(domainPayment.GatewayPaymentStatus{Status: domainPayment.GatewayStatusConfirming, TransactionHash: "not-a-hash"}, nil)
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		logger.On("LogStatusDisagreement", mock.MatchedBy(func(d StatusDisagreement) bool {
+			return d.LocalStatus == "PENDING" && d.GatewayStatus == "confirming" && d.Error == ""
+		})).Return().Once()
+		logger.On("LogStatusDisagreement", mock.MatchedBy(func(d StatusDisagreement) bool {
+			return d.LocalStatus == "PENDING" && d.GatewayStatus == "confirming" && d.Error != ""
+		})).Return().Once()
+
+		result, err := reconciler.Execute(later)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Disagreements)
+		assert.Equal(t, domainPayment.StatusPending, p.Status)
+		logger.AssertExpectations(t)
+	})
+
+	t.Run("recently updated and unregistered payments are skipped", func(t *testing.T) {
+		finder := new(MockNonFinalPaymentFinder)
+		gateway := newMockPaymentProvider("nowpayments")
+		tracker := NewTrackConfirmationsUseCase(new(MockPaymentRepository), new(MockOrderRepository), new(MockOperatorAlerter), nil)
+		reconciler := NewPaymentReconciler(finder, providersOf(gateway), tracker, new(MockReconciliationLogger), testReconcilerConfig)
+
+		finder.On("FindNonFinal").Return([]*domainPayment.Payment{createGatewayPayment("np-1"), createTestPayment()}, nil)
+
+		result, err := reconciler.Execute(time.Now())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Polled)
+		gateway.AssertNotCalled(t, "GetPaymentStatus", mock.Anything)
+	})
+
+	t.Run("providers without a payment status are left to admins", func(t *testing.T) {
+		finder := new(MockNonFinalPaymentFinder)
+		gateway := newMockPaymentProvider("manual")
+		tracker := NewTrackConfirmationsUseCase(new(MockPaymentRepository), new(MockOrderRepository), new(MockOperatorAlerter), nil)
+		reconciler := NewPaymentReconciler(finder, providersOf(gateway), tracker, new(MockReconciliationLogger), testReconcilerConfig)
+
+		p := createTestPayment()
+		_ = p.SetProviderReference("manual", "MT-1")
+
+		finder.On("FindNonFinal").Return([]*domainPayment.Payment{p}, nil)
+		gateway.On("GetPaymentStatus", "MT-1").Return(domainPayment.GatewayPaymentStatus{}, domainPayment.ErrProviderNotSupported)
+
+		result, err := reconciler.Execute(later)
+
+		assert.NoError(t, err)
+		assert.Equal(t, ReconcileResult{Polled: 1}, *result)
+		assert.Equal(t, domainPayment.StatusPending, p.Status)
+	})
+
+	t.Run("concurrent gateway calls are capped", func(t *testing.T) {
+		finder := new(MockNonFinalPaymentFinder)
+		gateway := &slowGateway{}
+		tracker := NewTrackConfirmationsUseCase(new(MockPaymentRepository), new(MockOrderRepository), new(MockOperatorAlerter), nil)
+		reconciler := NewPaymentReconciler(finder, providersOf(gateway), tracker, new(MockReconciliationLogger), testReconcilerConfig)
+
+		var payments []*domainPayment.Payment
+		for i := 0; i < 6; i++ {
+			payments = append(payments, createGatewayPayment(fmt.Sprintf("np-%d", i)))
+		}
+		finder.On("FindNonFinal").Return(payments, nil)
+
+		result, err := reconciler.Execute(later)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 6, result.Polled)
+		assert.Equal(t, 2, gateway.peak)
+	})
+
+	t.Run("finder error", func(t *testing.T) {
+		finder := new(MockNonFinalPaymentFinder)
+		tracker := NewTrackConfirmationsUseCase(new(MockPaymentRepository), new(MockOrderRepository), new(MockOperatorAlerter), nil)
+		reconciler := NewPaymentReconciler(finder, providersOf(newMockPaymentProvider("nowpayments")), tracker, new(MockReconciliationLogger), testReconcilerConfig)
+		findErr := errors.New("database down")
+
+		finder.On("FindNonFinal").Return(nil, findErr)
+
+		result, err := reconciler.Execute(later)
+
+		assert.Nil(t, result)
+		assert.Equal(t, findErr, err)
+	})
+
+	t.Run("run logs a failed pass and keeps going", func(t *testing.T) {
+		finder := new(MockNonFinalPaymentFinder)
+		logger := new(MockReconciliationLogger)
+		tracker := NewTrackConfirmationsUseCase(new(MockPaymentRepository), new(MockOrderRepository), new(MockOperatorAlerter), nil)
+		config := ReconcilerConfig{Interval: time.Millisecond, MaxBackoff: time.Millisecond, MaxConcurrent: 1}
+		reconciler := NewPaymentReconciler(finder, providersOf(newMockPaymentProvider("nowpayments")), tracker, logger, config)
+		findErr := errors.New("database down")
+		ctx, cancel := context.WithCancel(context.Background())
+
+		finder.On("FindNonFinal").Return(nil, findErr).Once()
+		finder.On("FindNonFinal").Return([]*domainPayment.Payment{}, nil).Run(func(mock.Arguments) { cancel() })
+		logger.On("LogReconcileError", findErr).Return()
+
+		err := reconciler.Run(ctx)
+
+		assert.Equal(t, context.Canceled, err)
+		logger.AssertExpectations(t)
+		assert.GreaterOrEqual(t, len(finder.Calls), 2)
+	})
+}
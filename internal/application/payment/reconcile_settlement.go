@@ -0,0 +1,215 @@
+package payment
+
+import (
+	"time"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// ReconcileSettlementCommand represents the period to reconcile, in whole days (UTC)
+type ReconcileSettlementCommand struct {
+	From string `json:"from" validate:"required"` // 2006-01-02
+	To   string `json:"to"`                       // Inclusive; defaults to From
+}
+
+// SettlementSummaryResponse counts the reconciled payments per result
+type SettlementSummaryResponse struct {
+	Total            int `json:"total"`
+	Matched          int `json:"matched"`
+	MissingLocally   int `json:"missing_locally"`
+	MissingRemotely  int `json:"missing_remotely"`
+	AmountMismatches int `json:"amount_mismatches"`
+	StatusMismatches int `json:"status_mismatches"`
+	Discrepancies    int `json:"discrepancies"`
+}
+
+// SettlementLineResponse represents a payment that did not reconcile
+type SettlementLineResponse struct {
+	Result           string  `json:"result"`
+	PaymentID        string  `json:"payment_id,omitempty"`
+	GatewayPaymentID string  `json:"gateway_payment_id,omitempty"`
+	LocalStatus      string  `json:"local_status,omitempty"`
+	GatewayStatus    string  `json:"gateway_status,omitempty"`
+	LocalAmount      float64 `json:"local_amount"`
+	LocalCurrency    string  `json:"local_currency,omitempty"`
+	GatewayAmount    float64 `json:"gateway_amount"`
+	GatewayCurrency  string  `json:"gateway_currency,omitempty"`
+}
+
+// ReconcileSettlementResponse represents the outcome of a settlement reconciliation
+type ReconcileSettlementResponse struct {
+	From          string                    `json:"from"`
+	To            string                    `json:"to"`
+	ReportFile    string                    `json:"report_file"`
+	Summary       SettlementSummaryResponse `json:"summary"`
+	Discrepancies []SettlementLineResponse  `json:"discrepancies"`
+}
+
+// SettlementAlert tells operators that a settlement period did not reconcile
+type SettlementAlert struct {
+	From       string                    `json:"from"`
+	To         string                    `json:"to"`
+	ReportFile string                    `json:"report_file"`
+	Summary    SettlementSummaryResponse `json:"summary"`
+}
+
+// SettlementPaymentFinder defines the interface for loading the payments of a period
+type SettlementPaymentFinder interface {
+	FindCreatedBetween(from, to time.Time) ([]*domainPayment.Payment, error)
+}
+
+// GatewayPaymentLister defines the interface for the gateway's payment listing,
+// read from its API or from an exported file
+type GatewayPaymentLister interface {
+	ListPayments(from, to time.Time) ([]domainPayment.GatewayPaymentRecord, error)
+}
+
+// SettlementAlerter notifies operators about settlement periods that did not reconcile
+type SettlementAlerter interface {
+	AlertSettlementDiscrepancies(alert SettlementAlert) error
+}
+
+// SettlementReportStore defines the interface for saving settlement reports
+type SettlementReportStore interface {
+	Save(report *domainPayment.SettlementReport) (string, error) // Returns where the report was written
+}
+
+// ReconcileSettlementUseCase compares our payments with the gateway's records,
+// writes a report and alerts operators when anything does not match. Only the
+// payments opened at the listing's provider are compared. Both sides are loaded
+// with a margin around the period, so a payment the two sides date on
+// different days still finds its counterpart.
+type ReconcileSettlementUseCase struct {
+	finder   SettlementPaymentFinder
+	lister   GatewayPaymentLister
+	provider string // Name of the provider the lister reads from
+	store    SettlementReportStore
+	alerter  SettlementAlerter
+}
+
+// NewReconcileSettlementUseCase creates a new instance of ReconcileSettlementUseCase
+func NewReconcileSettlementUseCase(finder SettlementPaymentFinder, lister GatewayPaymentLister, provider string, store SettlementReportStore, alerter SettlementAlerter) *ReconcileSettlementUseCase {
+	return &ReconcileSettlementUseCase{
+		finder:   finder,
+		lister:   lister,
+		provider: provider,
+		store:    store,
+		alerter:  alerter,
+	}
+}
+
+// Execute reconciles the period
+func (uc *ReconcileSettlementUseCase) Execute(cmd ReconcileSettlementCommand) (*ReconcileSettlementResponse, error) {
+	from, to, err := parseSettlementPeriod(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	margin := domainPayment.SettlementMatchMargin
+	payments, err := uc.finder.FindCreatedBetween(from.Add(-margin), to.Add(margin))
+	if err != nil {
+		return nil, err
+	}
+	payments = paymentsOpenedAt(payments, uc.provider)
+
+	records, err := uc.lister.ListPayments(from.Add(-margin), to.Add(margin))
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := domainPayment.ReconcileSettlement(from, to, payments, records)
+	if err != nil {
+		return nil, err
+	}
+
+	reportFile, err := uc.store.Save(report)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toReconcileSettlementResponse(report, reportFile)
+
+	if response.Summary.Discrepancies > 0 {
+		err := uc.alerter.AlertSettlementDiscrepancies(SettlementAlert{
+			From:       response.From,
+			To:         response.To,
+			ReportFile: reportFile,
+			Summary:    response.Summary,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+// paymentsOpenedAt keeps the payments opened at a provider
+func paymentsOpenedAt(payments []*domainPayment.Payment, provider string) []*domainPayment.Payment {
+	opened := make([]*domainPayment.Payment, 0, len(payments))
+	for _, p := range payments {
+		if p.Provider == provider {
+			opened = append(opened, p)
+		}
+	}
+	return opened
+}
+
+// parseSettlementPeriod turns the inclusive day range into [from, to)
+func parseSettlementPeriod(cmd ReconcileSettlementCommand) (time.Time, time.Time, error) {
+	from, err := time.Parse("2006-01-02", cmd.From)
+	if err != nil {
+		return time.Time{}, time.Time{}, domainPayment.ErrInvalidSettlementPeriod
+	}
+
+	to := from
+	if cmd.To != "" {
+		if to, err = time.Parse("2006-01-02", cmd.To); err != nil {
+			return time.Time{}, time.Time{}, domainPayment.ErrInvalidSettlementPeriod
+		}
+	}
+
+	to = to.AddDate(0, 0, 1)
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, domainPayment.ErrInvalidSettlementPeriod
+	}
+
+	return from, to, nil
+}
+
+// toReconcileSettlementResponse maps a report to its response, listing only discrepancies
+func toReconcileSettlementResponse(report *domainPayment.SettlementReport, reportFile string) *ReconcileSettlementResponse {
+	discrepancies := make([]SettlementLineResponse, 0, report.Summary.Discrepancies())
+	for _, line := range report.Lines {
+		if line.Result == domainPayment.SettlementMatched {
+			continue
+		}
+		discrepancies = append(discrepancies, SettlementLineResponse{
+			Result:           string(line.Result),
+			PaymentID:        line.PaymentID,
+			GatewayPaymentID: line.GatewayPaymentID,
+			LocalStatus:      string(line.LocalStatus),
+			GatewayStatus:    string(line.GatewayStatus),
+			LocalAmount:      line.LocalAmount,
+			LocalCurrency:    line.LocalCurrency,
+			GatewayAmount:    line.GatewayAmount,
+			GatewayCurrency:  line.GatewayCurrency,
+		})
+	}
+
+	return &ReconcileSettlementResponse{
+		From:       report.From.Format("2006-01-02"),
+		To:         report.To.AddDate(0, 0, -1).Format("2006-01-02"),
+		ReportFile: reportFile,
+		Summary: SettlementSummaryResponse{
+			Total:            report.Summary.Total,
+			Matched:          report.Summary.Matched,
+			MissingLocally:   report.Summary.MissingLocally,
+			MissingRemotely:  report.Summary.MissingRemotely,
+			AmountMismatches: report.Summary.AmountMismatches,
+			StatusMismatches: report.Summary.StatusMismatches,
+			Discrepancies:    report.Summary.Discrepancies(),
+		},
+		Discrepancies: discrepancies,
+	}
+}
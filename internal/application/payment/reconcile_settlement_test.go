@@ -0,0 +1,159 @@
+package payment
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSettlementPaymentFinder is a mock implementation of SettlementPaymentFinder
+type MockSettlementPaymentFinder struct {
+	mock.Mock
+}
+
+func (m *MockSettlementPaymentFinder) FindCreatedBetween(from, to time.Time) ([]*domainPayment.Payment, error) {
+	args := m.Called(from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domainPayment.Payment), args.Error(1)
+}
+
+// MockGatewayPaymentLister is a mock implementation of GatewayPaymentLister
+type MockGatewayPaymentLister struct {
+	mock.Mock
+}
+
+func (m *MockGatewayPaymentLister) ListPayments(from, to time.Time) ([]domainPayment.GatewayPaymentRecord, error) {
+	args := m.Called(from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domainPayment.GatewayPaymentRecord), args.Error(1)
+}
+
+// MockSettlementReportStore is a mock implementation of SettlementReportStore
+type MockSettlementReportStore struct {
+	mock.Mock
+}
+
+func (m *MockSettlementReportStore) Save(report *domainPayment.SettlementReport) (string, error) {
+	args := m.Called(report)
+	return args.String(0), args.Error(1)
+}
+
+// MockSettlementAlerter is a mock implementation of SettlementAlerter
+type MockSettlementAlerter struct {
+	mock.Mock
+}
+
+func (m *MockSettlementAlerter) AlertSettlementDiscrepancies(alert SettlementAlert) error {
+	args := m.Called(alert)
+	return args.Error(0)
+}
+
+// Tests for ReconcileSettlementUseCase
+
+func TestReconcileSettlementUseCase(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	margin := domainPayment.SettlementMatchMargin
+
+	createConfirmedGatewayPayment := func(gatewayPaymentID string) *domainPayment.Payment {
+		p := createGatewayPayment(gatewayPaymentID)
+		_ = p.MarkAsConfirmed()
+		p.CreatedAt = from.Add(12 * time.Hour)
+		return p
+	}
+	finished := func(gatewayPaymentID string, amount float64) domainPayment.GatewayPaymentRecord {
+		return domainPayment.GatewayPaymentRecord{GatewayPaymentID: gatewayPaymentID, Status: domainPayment.GatewayStatusFinished, PriceAmount: amount, PriceCurrency: "usd", PayCurrency: "BTC", ActuallyPaid: 0.002}
+	}
+
+	t.Run("discrepancies are reported and alerted", func(t *testing.T) {
+		finder := new(MockSettlementPaymentFinder)
+		lister := new(MockGatewayPaymentLister)
+		store := new(MockSettlementReportStore)
+		alerter := new(MockSettlementAlerter)
+		useCase := NewReconcileSettlementUseCase(finder, lister, "nowpayments", store, alerter)
+
+		finder.On("FindCreatedBetween", from.Add(-margin), to.Add(margin)).Return([]*domainPayment.Payment{
+			createConfirmedGatewayPayment("np-1"),
+			createConfirmedGatewayPayment("np-2"),
+		}, nil)
+		lister.On("ListPayments", from.Add(-margin), to.Add(margin)).Return([]domainPayment.GatewayPaymentRecord{
+			finished("np-1", 100.0),
+			finished("np-2", 80.0),
+			finished("np-3", 20.0),
+		}, nil)
+		store.On("Save", mock.Anything).Return("reports/settlement_2024-03-01_2024-03-01.csv", nil)
+		alerter.On("AlertSettlementDiscrepancies", mock.MatchedBy(func(a SettlementAlert) bool {
+			return a.From == "2024-03-01" && a.Summary.Discrepancies == 2 && a.ReportFile == "reports/settlement_2024-03-01_2024-03-01.csv"
+		})).Return(nil)
+
+		response, err := useCase.Execute(ReconcileSettlementCommand{From: "2024-03-01"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-03-01", response.To)
+		assert.Equal(t, SettlementSummaryResponse{Total: 3, Matched: 1, MissingLocally: 1, AmountMismatches: 1, Discrepancies: 2}, response.Summary)
+		assert.Len(t, response.Discrepancies, 2)
+		assert.Equal(t, "AMOUNT_MISMATCH", response.Discrepancies[0].Result)
+		assert.Equal(t, "MISSING_LOCALLY", response.Discrepancies[1].Result)
+		alerter.AssertExpectations(t)
+	})
+
+	t.Run("clean period is not alerted", func(t *testing.T) {
+		finder := new(MockSettlementPaymentFinder)
+		lister := new(MockGatewayPaymentLister)
+		store := new(MockSettlementReportStore)
+		alerter := new(MockSettlementAlerter)
+		useCase := NewReconcileSettlementUseCase(finder, lister, "nowpayments", store, alerter)
+
+		weekEnd := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+		manualTransfer := createTestPayment()
+		_ = manualTransfer.SetProviderReference("manual", "MT-1")
+		_ = manualTransfer.MarkAsConfirmed()
+
+		finder.On("FindCreatedBetween", from.Add(-margin), weekEnd.Add(margin)).Return([]*domainPayment.Payment{createConfirmedGatewayPayment("np-1"), manualTransfer}, nil)
+		lister.On("ListPayments", from.Add(-margin), weekEnd.Add(margin)).Return([]domainPayment.GatewayPaymentRecord{finished("np-1", 100.0)}, nil)
+		store.On("Save", mock.Anything).Return("settlement.csv", nil)
+
+		response, err := useCase.Execute(ReconcileSettlementCommand{From: "2024-03-01", To: "2024-03-07"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, response.Summary.Total)
+		assert.Equal(t, 0, response.Summary.Discrepancies)
+		assert.Empty(t, response.Discrepancies)
+		alerter.AssertNotCalled(t, "AlertSettlementDiscrepancies", mock.Anything)
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		useCase := NewReconcileSettlementUseCase(new(MockSettlementPaymentFinder), new(MockGatewayPaymentLister), "nowpayments", new(MockSettlementReportStore), new(MockSettlementAlerter))
+
+		_, errFormat := useCase.Execute(ReconcileSettlementCommand{From: "01/03/2024"})
+		_, errOrder := useCase.Execute(ReconcileSettlementCommand{From: "2024-03-07", To: "2024-03-01"})
+
+		assert.Equal(t, domainPayment.ErrInvalidSettlementPeriod, errFormat)
+		assert.Equal(t, domainPayment.ErrInvalidSettlementPeriod, errOrder)
+	})
+
+	t.Run("gateway listing error", func(t *testing.T) {
+		finder := new(MockSettlementPaymentFinder)
+		lister := new(MockGatewayPaymentLister)
+		store := new(MockSettlementReportStore)
+		useCase := NewReconcileSettlementUseCase(finder, lister, "nowpayments", store, new(MockSettlementAlerter))
+		listErr := errors.New("gateway down")
+
+		finder.On("FindCreatedBetween", from.Add(-margin), to.Add(margin)).Return([]*domainPayment.Payment{}, nil)
+		lister.On("ListPayments", from.Add(-margin), to.Add(margin)).Return(nil, listErr)
+
+		response, err := useCase.Execute(ReconcileSettlementCommand{From: "2024-03-01"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, listErr, err)
+		store.AssertNotCalled(t, "Save", mock.Anything)
+	})
+}
@@ -0,0 +1,97 @@
+package payment
+
+import (
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// SendRefundCommand represents the input for having the provider send a payment's pending refund
+type SendRefundCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	PaymentID     string `json:"payment_id" validate:"required"`
+	RefundAddress string `json:"refund_address" validate:"required"`
+}
+
+// SendRefundUseCase asks the provider that opened a payment to send its
+// pending refund, once: a refund the provider accepted is not requested
+// again while it waits to be broadcast. Providers that cannot send refunds return
+// ErrProviderNotSupported, and the refund is sent by hand and recorded with
+// its transaction hash as before.
+type SendRefundUseCase struct {
+	paymentRepo PaymentRepository
+	providers   *PaymentProviders
+	ledger      PaymentLedger // nil disables ledger postings
+}
+
+// NewSendRefundUseCase creates a new instance of SendRefundUseCase
+func NewSendRefundUseCase(paymentRepo PaymentRepository, providers *PaymentProviders, ledger PaymentLedger) *SendRefundUseCase {
+	return &SendRefundUseCase{
+		paymentRepo: paymentRepo,
+		providers:   providers,
+		ledger:      ledger,
+	}
+}
+
+// Execute sends the refund
+func (uc *SendRefundUseCase) Execute(cmd SendRefundCommand) (*PaymentResponse, error) {
+	existingPayment, err := uc.paymentRepo.FindByID(cmd.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingPayment == nil {
+		return nil, domainPayment.ErrPaymentNotFound
+	}
+
+	if !existingPayment.HasPendingRefund() {
+		return nil, domainPayment.ErrRefundAlreadyProcessed
+	}
+
+	// The provider already accepted it and sends it later
+	if existingPayment.HasRequestedRefund() {
+		return nil, domainPayment.ErrRefundAlreadyRequested
+	}
+
+	if err := existingPayment.ValidateRefundAddress(cmd.RefundAddress); err != nil {
+		return nil, err
+	}
+
+	provider, err := uc.providers.Get(existingPayment.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := existingPayment.Refunds[len(existingPayment.Refunds)-1]
+	refund, err := provider.RefundPayment(domainPayment.GatewayRefundRequest{
+		GatewayPaymentID: existingPayment.ProviderReference,
+		Address:          cmd.RefundAddress,
+		Currency:         existingPayment.GetCryptoSymbol(),
+		Amount:           pending.NetAmount(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if refund.GatewayRefundID != "" {
+		if err := existingPayment.RecordRefundRequested(refund.GatewayRefundID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Most providers broadcast later; the hash is then recorded like a refund sent by hand
+	if refund.TransactionHash != "" {
+		if err := existingPayment.RecordRefundSent(refund.TransactionHash, refund.NetworkFee); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.paymentRepo.Update(existingPayment); err != nil {
+		return nil, err
+	}
+
+	if err := recordInLedger(uc.ledger, existingPayment); err != nil {
+		return nil, err
+	}
+
+	return toPaymentResponse(existingPayment), nil
+}
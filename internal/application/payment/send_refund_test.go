@@ -0,0 +1,123 @@
+package payment
+
+import (
+	"testing"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testRefundAddress = "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"
+
+// createRefundedGatewayPayment creates a confirmed gateway payment with a pending 0.001 BTC refund
+func createRefundedGatewayPayment() *domainPayment.Payment {
+	p := createGatewayPayment("np-1")
+	_ = p.MarkAsConfirmed()
+	_ = p.PartialRefund(0.001)
+	return p
+}
+
+// Tests for SendRefundUseCase
+
+func TestSendRefundUseCase(t *testing.T) {
+	t.Run("provider accepts the refund", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		provider := newMockPaymentProvider("nowpayments")
+		ledger := new(MockPaymentLedger)
+		useCase := NewSendRefundUseCase(paymentRepo, providersOf(provider), ledger)
+
+		p := createRefundedGatewayPayment()
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		provider.On("RefundPayment", domainPayment.GatewayRefundRequest{
+			GatewayPaymentID: "np-1",
+			Address:          testRefundAddress,
+			Currency:         "BTC",
+			Amount:           0.001,
+		}).Return(domainPayment.GatewayRefund{GatewayRefundID: "5000000000"}, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		ledger.On("RecordPayment", p).Return(nil)
+
+		response, err := useCase.Execute(SendRefundCommand{PaymentID: p.ID, RefundAddress: testRefundAddress})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "5000000000", response.Refunds[0].ProviderRefundID)
+		assert.Empty(t, response.Refunds[0].TransactionHash)
+		ledger.AssertExpectations(t)
+	})
+
+	t.Run("broadcast refund is recorded as sent", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		provider := newMockPaymentProvider("nowpayments")
+		useCase := NewSendRefundUseCase(paymentRepo, providersOf(provider), nil)
+
+		p := createRefundedGatewayPayment()
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		provider.On("RefundPayment", mock.Anything).Return(domainPayment.GatewayRefund{TransactionHash: testReplacementTransaction, NetworkFee: 0.00002}, nil)
+		paymentRepo.On("Update", p).Return(nil)
+
+		_, err := useCase.Execute(SendRefundCommand{PaymentID: p.ID, RefundAddress: testRefundAddress})
+
+		assert.NoError(t, err)
+		assert.False(t, p.HasPendingRefund())
+		assert.Equal(t, 0.00002, p.Refunds[0].NetworkFee)
+	})
+
+	t.Run("accepted refund is not requested again", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		provider := newMockPaymentProvider("nowpayments")
+		useCase := NewSendRefundUseCase(paymentRepo, providersOf(provider), nil)
+
+		p := createRefundedGatewayPayment()
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		provider.On("RefundPayment", mock.Anything).Return(domainPayment.GatewayRefund{GatewayRefundID: "5000000000"}, nil)
+		paymentRepo.On("Update", p).Return(nil)
+
+		_, errFirst := useCase.Execute(SendRefundCommand{IdempotencyKey: "key-1", PaymentID: p.ID, RefundAddress: testRefundAddress})
+		_, errSecond := useCase.Execute(SendRefundCommand{IdempotencyKey: "key-2", PaymentID: p.ID, RefundAddress: testRefundAddress})
+
+		assert.NoError(t, errFirst)
+		assert.Equal(t, domainPayment.ErrRefundAlreadyRequested, errSecond)
+		provider.AssertNumberOfCalls(t, "RefundPayment", 1)
+		assert.Equal(t, "5000000000", p.Refunds[0].ProviderReference)
+	})
+
+	t.Run("provider that cannot send refunds", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		provider := newMockPaymentProvider("nowpayments")
+		useCase := NewSendRefundUseCase(paymentRepo, providersOf(provider), nil)
+
+		p := createRefundedGatewayPayment()
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		provider.On("RefundPayment", mock.Anything).Return(domainPayment.GatewayRefund{}, domainPayment.ErrProviderNotSupported)
+
+		_, err := useCase.Execute(SendRefundCommand{PaymentID: p.ID, RefundAddress: testRefundAddress})
+
+		assert.Equal(t, domainPayment.ErrProviderNotSupported, err)
+		paymentRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("no pending refund or unusable address", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		provider := newMockPaymentProvider("nowpayments")
+		useCase := NewSendRefundUseCase(paymentRepo, providersOf(provider), nil)
+
+		confirmed := createGatewayPayment("np-2")
+		_ = confirmed.MarkAsConfirmed()
+		refunded := createRefundedGatewayPayment()
+
+		paymentRepo.On("FindByID", confirmed.ID).Return(confirmed, nil)
+		paymentRepo.On("FindByID", refunded.ID).Return(refunded, nil)
+
+		_, errPending := useCase.Execute(SendRefundCommand{PaymentID: confirmed.ID, RefundAddress: testRefundAddress})
+		_, errAddress := useCase.Execute(SendRefundCommand{PaymentID: refunded.ID, RefundAddress: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"})
+
+		assert.Equal(t, domainPayment.ErrRefundAlreadyProcessed, errPending)
+		assert.Equal(t, domainPayment.ErrInvalidWalletAddress, errAddress)
+		provider.AssertNotCalled(t, "RefundPayment", mock.Anything)
+	})
+}
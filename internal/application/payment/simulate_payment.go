@@ -0,0 +1,118 @@
+package payment
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// SimulatePaymentCommand represents a sequence of gateway updates to play on a sandbox payment
+type SimulatePaymentCommand struct {
+	IdempotencyKey string `json:"-"` // From the Idempotency-Key header
+
+	PaymentID string           `json:"payment_id" validate:"required"`
+	Steps     []SimulationStep `json:"steps" validate:"required,min=1,dive"`
+}
+
+// SimulationStep is one gateway update, as a webhook or status poll would report it
+type SimulationStep struct {
+	Status          string  `json:"status,omitempty"`           // Gateway status: waiting, confirming, confirmed, finished, partially_paid, failed, expired
+	Confirmations   int     `json:"confirmations,omitempty"`    // Confirmations of the transaction
+	TransactionHash string  `json:"transaction_hash,omitempty"` // Defaults to the payment's transaction, or a generated one; a different hash replaces it
+	Dropped         bool    `json:"dropped,omitempty"`          // The transaction disappears from the chain and mempool
+	GatewayFee      float64 `json:"gateway_fee,omitempty" validate:"min=0"`
+	NetworkFee      float64 `json:"network_fee,omitempty" validate:"min=0"`
+}
+
+// SimulatePaymentResponse represents the payment after the simulation
+type SimulatePaymentResponse struct {
+	Payment  *PaymentResponse `json:"payment"`
+	Statuses []string         `json:"statuses"` // Payment status after each step
+}
+
+// SimulatePaymentUseCase drives a sandbox payment through any sequence of
+// gateway updates on demand. Each step is applied through
+// TrackConfirmationsUseCase, like a real webhook, so holds, order updates and
+// ledger postings follow. Live payments are refused. The simulation stops at
+// the first step the payment rejects, returning the steps applied so far with
+// the error.
+type SimulatePaymentUseCase struct {
+	paymentRepo PaymentRepository
+	tracker     *TrackConfirmationsUseCase
+}
+
+// NewSimulatePaymentUseCase creates a new instance of SimulatePaymentUseCase
+func NewSimulatePaymentUseCase(paymentRepo PaymentRepository, tracker *TrackConfirmationsUseCase) *SimulatePaymentUseCase {
+	return &SimulatePaymentUseCase{
+		paymentRepo: paymentRepo,
+		tracker:     tracker,
+	}
+}
+
+// Execute plays the steps in order
+func (uc *SimulatePaymentUseCase) Execute(cmd SimulatePaymentCommand) (*SimulatePaymentResponse, error) {
+	if len(cmd.Steps) == 0 {
+		return nil, domainPayment.ErrInvalidSimulationStep
+	}
+
+	existingPayment, err := uc.paymentRepo.FindByID(cmd.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingPayment == nil {
+		return nil, domainPayment.ErrPaymentNotFound
+	}
+
+	if !existingPayment.IsSandbox() {
+		return nil, domainPayment.ErrSandboxOnly
+	}
+
+	transactionHash := existingPayment.TransactionHash
+	if transactionHash == "" {
+		transactionHash = newSimulatedTransactionHash(existingPayment.CryptoCurrency)
+	}
+
+	response := &SimulatePaymentResponse{
+		Payment:  toPaymentResponse(existingPayment),
+		Statuses: make([]string, 0, len(cmd.Steps)),
+	}
+	for _, step := range cmd.Steps {
+		if step.Status == "" && !step.Dropped {
+			return response, domainPayment.ErrInvalidSimulationStep
+		}
+
+		if step.TransactionHash != "" {
+			transactionHash = step.TransactionHash
+		}
+
+		payment, err := uc.tracker.Execute(TrackConfirmationsCommand{
+			PaymentID:       existingPayment.ID,
+			TransactionHash: transactionHash,
+			Confirmations:   step.Confirmations,
+			Dropped:         step.Dropped,
+			GatewayStatus:   step.Status,
+			GatewayFee:      step.GatewayFee,
+			NetworkFee:      step.NetworkFee,
+		})
+		if err != nil {
+			return response, err
+		}
+
+		response.Payment = payment
+		response.Statuses = append(response.Statuses, payment.Status)
+	}
+
+	return response, nil
+}
+
+// newSimulatedTransactionHash returns a random transaction hash in the coin's format
+func newSimulatedTransactionHash(crypto domainPayment.CryptoCurrency) string {
+	hash := make([]byte, 32)
+	_, _ = rand.Read(hash)
+	if crypto.Network == domainPayment.NetworkEthereum {
+		return "0x" + hex.EncodeToString(hash)
+	}
+	return hex.EncodeToString(hash)
+}
@@ -0,0 +1,125 @@
+package payment
+
+import (
+	"testing"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// createSandboxPayment returns a gateway payment marked as a sandbox payment
+func createSandboxPayment() *domainPayment.Payment {
+	p := createGatewayPayment("5077125051")
+	p.Environment = domainPayment.EnvironmentSandbox
+	return p
+}
+
+// Tests for SimulatePaymentUseCase
+
+func TestSimulatePaymentUseCase(t *testing.T) {
+	t.Run("plays the steps in order", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		p := createSandboxPayment()
+		orderRepo, _ := newAwaitingOrderRepository(p)
+		tracker := NewTrackConfirmationsUseCase(paymentRepo, orderRepo, new(MockOperatorAlerter), nil)
+		useCase := NewSimulatePaymentUseCase(paymentRepo, tracker)
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+
+		response, err := useCase.Execute(SimulatePaymentCommand{
+			PaymentID: p.ID,
+			Steps: []SimulationStep{
+				{Status: "confirming"},
+				{Status: "confirming", Confirmations: 1},
+				{Status: "finished", GatewayFee: 0.00001},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CONFIRMING", "CONFIRMING", "CONFIRMED"}, response.Statuses)
+		assert.Len(t, response.Payment.TransactionHash, 64)
+		assert.Equal(t, "SANDBOX", response.Payment.Environment)
+		assert.Equal(t, 0.00001, response.Payment.GatewayFee)
+	})
+
+	t.Run("a new hash replaces the transaction", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		orderRepo := new(MockOrderRepository)
+		alerter := new(MockOperatorAlerter)
+		tracker := NewTrackConfirmationsUseCase(paymentRepo, orderRepo, alerter, nil)
+		useCase := NewSimulatePaymentUseCase(paymentRepo, tracker)
+
+		o := createPendingPaymentOrder()
+		p := createSandboxPayment()
+		p.OrderID = o.ID.String()
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		alerter.On("AlertPaymentHeld", mock.Anything).Return(nil)
+
+		response, err := useCase.Execute(SimulatePaymentCommand{
+			PaymentID: p.ID,
+			Steps: []SimulationStep{
+				{Status: "confirming", TransactionHash: testTransactionHash},
+				{Status: "confirming", TransactionHash: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CONFIRMING", "UNDER_REVIEW"}, response.Statuses)
+		assert.Equal(t, testTransactionHash, response.Payment.ReplacedTransactionHash)
+		alerter.AssertExpectations(t)
+	})
+
+	t.Run("live payments are refused", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		useCase := NewSimulatePaymentUseCase(paymentRepo, nil)
+
+		p := createGatewayPayment("5077125051")
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+
+		_, err := useCase.Execute(SimulatePaymentCommand{PaymentID: p.ID, Steps: []SimulationStep{{Status: "finished"}}})
+
+		assert.Equal(t, domainPayment.ErrSandboxOnly, err)
+		paymentRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("stops at the first rejected step", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		tracker := NewTrackConfirmationsUseCase(paymentRepo, new(MockOrderRepository), new(MockOperatorAlerter), nil)
+		useCase := NewSimulatePaymentUseCase(paymentRepo, tracker)
+
+		p := createSandboxPayment()
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+
+		response, err := useCase.Execute(SimulatePaymentCommand{
+			PaymentID: p.ID,
+			Steps:     []SimulationStep{{Status: "failed"}, {Status: "confirming"}},
+		})
+
+		assert.Equal(t, domainPayment.ErrInvalidStatusTransition, err)
+		assert.Equal(t, domainPayment.StatusFailed, p.Status)
+		assert.Equal(t, []string{"FAILED"}, response.Statuses)
+		assert.Equal(t, "FAILED", response.Payment.Status)
+	})
+
+	t.Run("invalid steps", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		useCase := NewSimulatePaymentUseCase(paymentRepo, nil)
+
+		p := createSandboxPayment()
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+
+		_, errNoSteps := useCase.Execute(SimulatePaymentCommand{PaymentID: p.ID})
+		_, errEmptyStep := useCase.Execute(SimulatePaymentCommand{PaymentID: p.ID, Steps: []SimulationStep{{Confirmations: 3}}})
+
+		assert.Equal(t, domainPayment.ErrInvalidSimulationStep, errNoSteps)
+		assert.Equal(t, domainPayment.ErrInvalidSimulationStep, errEmptyStep)
+	})
+}
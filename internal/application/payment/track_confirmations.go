@@ -0,0 +1,278 @@
+package payment
+
+import (
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/google/uuid"
+)
+
+// TrackConfirmationsCommand represents a transaction update reported by the payment gateway
+type TrackConfirmationsCommand struct {
+	PaymentID       string `json:"payment_id" validate:"required"`
+	TransactionHash string `json:"transaction_hash"`
+	Confirmations   int    `json:"confirmations"`
+	Dropped         bool   `json:"dropped"`        // Transaction no longer found on chain or in the mempool
+	GatewayStatus   string `json:"gateway_status"` // Gateway payment status, when reported
+
+	// Fees in the payment's cryptocurrency, once the gateway reports them
+	GatewayFee float64 `json:"gateway_fee,omitempty" validate:"min=0"`
+	NetworkFee float64 `json:"network_fee,omitempty" validate:"min=0"`
+}
+
+// PaymentHeldAlert tells operators that a payment was put under review
+type PaymentHeldAlert struct {
+	PaymentID               string `json:"payment_id"`
+	OrderID                 string `json:"order_id"`
+	OrderStatus             string `json:"order_status"`
+	Reason                  string `json:"reason"`
+	TransactionHash         string `json:"transaction_hash"`
+	ReplacedTransactionHash string `json:"replaced_transaction_hash,omitempty"`
+	Confirmations           int    `json:"confirmations"`
+	RequiredConfirmations   int    `json:"required_confirmations"`
+	FulfilmentPaused        bool   `json:"fulfilment_paused"` // false when the goods already shipped
+}
+
+// OrderRepository defines the interface for order persistence used by payment use cases
+type OrderRepository interface {
+	FindByID(id uuid.UUID) (*domainOrder.Order, error)
+	Update(order *domainOrder.Order) error
+}
+
+// OperatorAlerter notifies operators about payments that need attention
+type OperatorAlerter interface {
+	AlertPaymentHeld(alert PaymentHeldAlert) error
+}
+
+// PaymentLedger posts a payment's confirmation and refunds to the merchant ledger.
+// Posting is idempotent, so it can be called after every change to the payment.
+type PaymentLedger interface {
+	RecordPayment(p *domainPayment.Payment) error
+}
+
+// TrackConfirmationsUseCase applies gateway transaction updates to a payment.
+// The order is marked paid once its payment is first confirmed. When a transaction is reorganised away, replaced or dropped the payment is
+// held, operators are alerted and fulfilment of the order is paused until the
+// payment is confirmed again. Reported fees are recorded on the payment, and
+// confirmed payments are posted to the ledger.
+type TrackConfirmationsUseCase struct {
+	paymentRepo PaymentRepository
+	orderRepo   OrderRepository
+	alerter     OperatorAlerter
+	ledger      PaymentLedger // nil disables ledger postings
+}
+
+// NewTrackConfirmationsUseCase creates a new instance of TrackConfirmationsUseCase
+func NewTrackConfirmationsUseCase(paymentRepo PaymentRepository, orderRepo OrderRepository, alerter OperatorAlerter, ledger PaymentLedger) *TrackConfirmationsUseCase {
+	return &TrackConfirmationsUseCase{
+		paymentRepo: paymentRepo,
+		orderRepo:   orderRepo,
+		alerter:     alerter,
+		ledger:      ledger,
+	}
+}
+
+// Execute applies the update
+func (uc *TrackConfirmationsUseCase) Execute(cmd TrackConfirmationsCommand) (*PaymentResponse, error) {
+	if cmd.PaymentID == "" {
+		return nil, domainPayment.ErrEmptyPaymentID
+	}
+
+	existingPayment, err := uc.paymentRepo.FindByID(cmd.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingPayment == nil {
+		return nil, domainPayment.ErrPaymentNotFound
+	}
+
+	wasHeld := existingPayment.IsUnderReview()
+	wasCompleted := existingPayment.IsCompleted()
+
+	feeReports := existingPayment.FeeReportCount()
+
+	if err := applyTransactionUpdate(existingPayment, cmd); err != nil {
+		return nil, err
+	}
+
+	if cmd.GatewayFee > 0 || cmd.NetworkFee > 0 {
+		if err := existingPayment.RecordFees(cmd.GatewayFee, cmd.NetworkFee); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.paymentRepo.Update(existingPayment); err != nil {
+		return nil, err
+	}
+
+	feesChanged := existingPayment.FeeReportCount() > feeReports
+	if existingPayment.IsCompleted() && (!wasCompleted || feesChanged) {
+		if err := recordInLedger(uc.ledger, existingPayment); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case wasHeld:
+		err = releaseOrderHold(uc.orderRepo, existingPayment, domainOrder.SystemActor)
+	case existingPayment.IsUnderReview():
+		err = uc.holdOrder(existingPayment)
+	case !wasCompleted:
+		err = markOrderPaid(uc.orderRepo, existingPayment)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toPaymentResponse(existingPayment), nil
+}
+
+// applyTransactionUpdate moves the payment according to the reported transaction
+func applyTransactionUpdate(p *domainPayment.Payment, cmd TrackConfirmationsCommand) error {
+	if cmd.Dropped {
+		return p.MarkTransactionDropped()
+	}
+
+	status := domainPayment.GatewayStatus(cmd.GatewayStatus)
+	if status != "" && !status.IsValid() {
+		return domainPayment.ErrUnknownGatewayStatus
+	}
+
+	switch status {
+	case domainPayment.GatewayStatusFailed:
+		return p.MarkAsFailed()
+	case domainPayment.GatewayStatusExpired:
+		return p.MarkAsExpired()
+	case domainPayment.GatewayStatusWaiting, domainPayment.GatewayStatusRefunded:
+		// Nothing to apply: refunds are recorded by admins, not by the gateway
+		return nil
+	}
+
+	// A settled status counts as fully confirmed, since not every gateway
+	// reports a confirmation count
+	confirmations := cmd.Confirmations
+	if status.IsSettled() {
+		confirmations = max(confirmations, p.RequiredConfirmations, 1)
+	}
+
+	if p.IsPending() {
+		if err := p.MarkAsConfirming(cmd.TransactionHash); err != nil {
+			return err
+		}
+	} else if cmd.TransactionHash != "" && cmd.TransactionHash != p.TransactionHash {
+		if err := p.MarkTransactionReplaced(cmd.TransactionHash); err != nil {
+			return err
+		}
+	}
+
+	return p.UpdateConfirmations(confirmations)
+}
+
+// holdOrder pauses fulfilment of the payment's order and alerts operators
+func (uc *TrackConfirmationsUseCase) holdOrder(p *domainPayment.Payment) error {
+	existingOrder, err := findPaymentOrder(uc.orderRepo, p.OrderID)
+	if err != nil {
+		return err
+	}
+
+	// Orders still waiting for payment or already shipped have nothing to pause
+	paused := existingOrder.CanTransitionTo(domainOrder.StatusOnHold)
+	if paused {
+		if err := existingOrder.HoldForPaymentReview(string(p.HoldReason)); err != nil {
+			return err
+		}
+
+		if err := uc.orderRepo.Update(existingOrder); err != nil {
+			return err
+		}
+	}
+
+	return uc.alerter.AlertPaymentHeld(PaymentHeldAlert{
+		PaymentID:               p.ID,
+		OrderID:                 p.OrderID,
+		OrderStatus:             string(existingOrder.Status),
+		Reason:                  string(p.HoldReason),
+		TransactionHash:         p.TransactionHash,
+		ReplacedTransactionHash: p.ReplacedTransactionHash,
+		Confirmations:           p.Confirmations,
+		RequiredConfirmations:   p.RequiredConfirmations,
+		FulfilmentPaused:        paused,
+	})
+}
+
+// markOrderPaid marks the order of a payment paid, linking the payment to it,
+// once the payment is first confirmed, whether by the gateway or by an admin.
+// Orders no longer awaiting payment, e.g. cancelled before a late payment
+// arrived, are left as they are.
+func markOrderPaid(orderRepo OrderRepository, p *domainPayment.Payment) error {
+	if !p.IsCompleted() {
+		return nil
+	}
+
+	existingOrder, err := findPaymentOrder(orderRepo, p.OrderID)
+	if err != nil {
+		return err
+	}
+
+	if existingOrder.Status != domainOrder.StatusPendingPayment {
+		return nil
+	}
+
+	if err := existingOrder.MarkAsPaid(p.ID); err != nil {
+		return err
+	}
+
+	return orderRepo.Update(existingOrder)
+}
+
+// releaseOrderHold resumes fulfilment of the order of a payment that was held
+// for review, once the payment is confirmed again, whether by the gateway or
+// by an admin. A held payment that failed or expired instead leaves its order
+// on hold for an admin to cancel.
+func releaseOrderHold(orderRepo OrderRepository, p *domainPayment.Payment, actor string) error {
+	if !p.IsCompleted() {
+		return nil
+	}
+
+	existingOrder, err := findPaymentOrder(orderRepo, p.OrderID)
+	if err != nil {
+		return err
+	}
+
+	if !existingOrder.IsOnHold() {
+		return nil
+	}
+
+	if err := existingOrder.ReleasePaymentHold(actor); err != nil {
+		return err
+	}
+
+	return orderRepo.Update(existingOrder)
+}
+
+// recordInLedger posts the payment to the ledger, when one is configured
+func recordInLedger(ledger PaymentLedger, p *domainPayment.Payment) error {
+	if ledger == nil {
+		return nil
+	}
+	return ledger.RecordPayment(p)
+}
+
+// findPaymentOrder loads the order linked to a payment
+func findPaymentOrder(orderRepo OrderRepository, orderID string) (*domainOrder.Order, error) {
+	id, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, domainOrder.ErrOrderNotFound
+	}
+
+	existingOrder, err := orderRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingOrder == nil {
+		return nil, domainOrder.ErrOrderNotFound
+	}
+
+	return existingOrder, nil
+}
@@ -0,0 +1,350 @@
+package payment
+
+import (
+	"testing"
+
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOrderRepository is a mock implementation of OrderRepository
+type MockOrderRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderRepository) FindByID(id uuid.UUID) (*domainOrder.Order, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainOrder.Order), args.Error(1)
+}
+
+func (m *MockOrderRepository) Update(order *domainOrder.Order) error {
+	args := m.Called(order)
+	return args.Error(0)
+}
+
+// MockOperatorAlerter is a mock implementation of OperatorAlerter
+type MockOperatorAlerter struct {
+	mock.Mock
+}
+
+func (m *MockOperatorAlerter) AlertPaymentHeld(alert PaymentHeldAlert) error {
+	args := m.Called(alert)
+	return args.Error(0)
+}
+
+// MockPaymentLedger is a mock implementation of PaymentLedger
+type MockPaymentLedger struct {
+	mock.Mock
+}
+
+func (m *MockPaymentLedger) RecordPayment(p *domainPayment.Payment) error {
+	args := m.Called(p)
+	return args.Error(0)
+}
+
+const (
+	testTransactionHash        = "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b"
+	testReplacementTransaction = "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16"
+)
+
+// createPaidOrder creates a paid order and its payment with 2 of 2 confirmations
+func createPaidOrder() (*domainOrder.Order, *domainPayment.Payment) {
+	price, _ := domainOrder.NewMoney(100.0, "USD")
+	item, _ := domainOrder.NewOrderItem(uuid.New(), 1, price)
+	o, _ := domainOrder.NewOrder("customer123", []domainOrder.OrderItem{item})
+
+	p, _ := domainPayment.NewPayment(o.ID.String(), 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+	_ = p.UpdateCryptoAmount(0.002)
+	_ = p.MarkAsConfirming(testTransactionHash)
+	_ = p.UpdateConfirmations(2)
+	_ = o.MarkAsPaid(p.ID)
+
+	return o, p
+}
+
+// newAwaitingOrderRepository links the payment to a new order awaiting payment
+// and returns a repository holding that order
+func newAwaitingOrderRepository(p *domainPayment.Payment) (*MockOrderRepository, *domainOrder.Order) {
+	o := createPendingPaymentOrder()
+	p.OrderID = o.ID.String()
+
+	orderRepo := new(MockOrderRepository)
+	orderRepo.On("FindByID", o.ID).Return(o, nil)
+	orderRepo.On("Update", o).Return(nil)
+
+	return orderRepo, o
+}
+
+// Tests for TrackConfirmationsUseCase
+
+func TestTrackConfirmationsUseCase(t *testing.T) {
+	t.Run("reorg holds the payment and pauses the order", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		orderRepo := new(MockOrderRepository)
+		alerter := new(MockOperatorAlerter)
+		useCase := NewTrackConfirmationsUseCase(paymentRepo, orderRepo, alerter, nil)
+
+		o, p := createPaidOrder()
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		orderRepo.On("Update", o).Return(nil)
+		alerter.On("AlertPaymentHeld", mock.MatchedBy(func(a PaymentHeldAlert) bool {
+			return a.PaymentID == p.ID && a.Reason == "CONFIRMATIONS_DROPPED" && a.FulfilmentPaused && a.OrderStatus == "ON_HOLD"
+		})).Return(nil)
+
+		response, err := useCase.Execute(TrackConfirmationsCommand{PaymentID: p.ID, TransactionHash: testTransactionHash, Confirmations: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "UNDER_REVIEW", response.Status)
+		assert.Equal(t, "CONFIRMATIONS_DROPPED", response.HoldReason)
+		assert.Equal(t, domainOrder.StatusOnHold, o.Status)
+		assert.Equal(t, domainOrder.ErrInvalidStatusTransition, o.MarkAsFulfilled())
+		alerter.AssertExpectations(t)
+		orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("confirmation marks the order paid and a later reorg puts it on hold", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		alerter := new(MockOperatorAlerter)
+		p := createTestPayment()
+		_ = p.UpdateCryptoAmount(0.002)
+		orderRepo, o := newAwaitingOrderRepository(p)
+		useCase := NewTrackConfirmationsUseCase(paymentRepo, orderRepo, alerter, nil)
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		alerter.On("AlertPaymentHeld", mock.MatchedBy(func(a PaymentHeldAlert) bool {
+			return a.FulfilmentPaused && a.OrderStatus == "ON_HOLD"
+		})).Return(nil)
+
+		_, errConfirmed := useCase.Execute(TrackConfirmationsCommand{PaymentID: p.ID, TransactionHash: testTransactionHash, Confirmations: p.RequiredConfirmations})
+		assert.NoError(t, errConfirmed)
+		assert.Equal(t, domainOrder.StatusPaid, o.Status)
+		assert.Equal(t, p.ID, *o.PaymentID)
+
+		response, errReorg := useCase.Execute(TrackConfirmationsCommand{PaymentID: p.ID, TransactionHash: testTransactionHash, Confirmations: 0})
+
+		assert.NoError(t, errReorg)
+		assert.Equal(t, "UNDER_REVIEW", response.Status)
+		assert.Equal(t, domainOrder.StatusOnHold, o.Status)
+		alerter.AssertExpectations(t)
+	})
+
+	t.Run("re-confirmation resumes the order", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		orderRepo := new(MockOrderRepository)
+		alerter := new(MockOperatorAlerter)
+		useCase := NewTrackConfirmationsUseCase(paymentRepo, orderRepo, alerter, nil)
+
+		o, p := createPaidOrder()
+		_ = p.UpdateConfirmations(0)
+		_ = o.HoldForPaymentReview("CONFIRMATIONS_DROPPED")
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		orderRepo.On("Update", o).Return(nil)
+
+		response, err := useCase.Execute(TrackConfirmationsCommand{PaymentID: p.ID, TransactionHash: testTransactionHash, Confirmations: 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "CONFIRMED", response.Status)
+		assert.Equal(t, domainOrder.StatusPaid, o.Status)
+		alerter.AssertNotCalled(t, "AlertPaymentHeld", mock.Anything)
+	})
+
+	t.Run("replacement is held and shipped order is only alerted", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		orderRepo := new(MockOrderRepository)
+		alerter := new(MockOperatorAlerter)
+		useCase := NewTrackConfirmationsUseCase(paymentRepo, orderRepo, alerter, nil)
+
+		o, p := createPaidOrder()
+		_ = o.MarkAsFulfilled()
+		_ = o.MarkAsShipped()
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		alerter.On("AlertPaymentHeld", mock.MatchedBy(func(a PaymentHeldAlert) bool {
+			return a.Reason == "TRANSACTION_REPLACED" && !a.FulfilmentPaused && a.ReplacedTransactionHash == testTransactionHash
+		})).Return(nil)
+
+		response, err := useCase.Execute(TrackConfirmationsCommand{PaymentID: p.ID, TransactionHash: testReplacementTransaction, Confirmations: 0})
+
+		assert.NoError(t, err)
+		assert.Equal(t, testReplacementTransaction, response.TransactionHash)
+		assert.Equal(t, domainOrder.StatusShipped, o.Status)
+		orderRepo.AssertNotCalled(t, "Update", mock.Anything)
+		alerter.AssertExpectations(t)
+	})
+
+	t.Run("dropped transaction", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		orderRepo := new(MockOrderRepository)
+		alerter := new(MockOperatorAlerter)
+		useCase := NewTrackConfirmationsUseCase(paymentRepo, orderRepo, alerter, nil)
+
+		o, p := createPaidOrder()
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		orderRepo.On("FindByID", o.ID).Return(o, nil)
+		orderRepo.On("Update", o).Return(nil)
+		alerter.On("AlertPaymentHeld", mock.Anything).Return(nil)
+
+		response, err := useCase.Execute(TrackConfirmationsCommand{PaymentID: p.ID, Dropped: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "TRANSACTION_DROPPED", response.HoldReason)
+		assert.True(t, o.IsOnHold())
+	})
+
+	t.Run("first detection moves the payment to confirming", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		orderRepo := new(MockOrderRepository)
+		useCase := NewTrackConfirmationsUseCase(paymentRepo, orderRepo, new(MockOperatorAlerter), nil)
+
+		p := createTestPayment()
+		_ = p.UpdateCryptoAmount(0.002)
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+
+		response, err := useCase.Execute(TrackConfirmationsCommand{PaymentID: p.ID, TransactionHash: testTransactionHash, Confirmations: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "CONFIRMING", response.Status)
+		assert.Equal(t, 1, response.Confirmations)
+		orderRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+	})
+
+	t.Run("settled gateway status confirms without a count", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		p := createTestPayment()
+		_ = p.UpdateCryptoAmount(0.002)
+		orderRepo, _ := newAwaitingOrderRepository(p)
+		useCase := NewTrackConfirmationsUseCase(paymentRepo, orderRepo, new(MockOperatorAlerter), nil)
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+
+		response, err := useCase.Execute(TrackConfirmationsCommand{PaymentID: p.ID, TransactionHash: testTransactionHash, GatewayStatus: "finished"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "CONFIRMED", response.Status)
+		assert.Equal(t, p.RequiredConfirmations, response.Confirmations)
+	})
+
+	t.Run("confirmation is posted to the ledger once", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		ledger := new(MockPaymentLedger)
+		p := createTestPayment()
+		_ = p.UpdateCryptoAmount(0.002)
+		orderRepo, _ := newAwaitingOrderRepository(p)
+		useCase := NewTrackConfirmationsUseCase(paymentRepo, orderRepo, new(MockOperatorAlerter), ledger)
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		ledger.On("RecordPayment", p).Return(nil)
+
+		_, err := useCase.Execute(TrackConfirmationsCommand{PaymentID: p.ID, TransactionHash: testTransactionHash, GatewayStatus: "finished"})
+		assert.NoError(t, err)
+		_, err = useCase.Execute(TrackConfirmationsCommand{PaymentID: p.ID, TransactionHash: testTransactionHash, Confirmations: 6})
+		assert.NoError(t, err)
+
+		ledger.AssertNumberOfCalls(t, "RecordPayment", 1)
+	})
+
+	t.Run("fees reported after confirmation are recorded and posted", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		ledger := new(MockPaymentLedger)
+		p := createTestPayment()
+		_ = p.UpdateCryptoAmount(0.002)
+		orderRepo, _ := newAwaitingOrderRepository(p)
+		useCase := NewTrackConfirmationsUseCase(paymentRepo, orderRepo, new(MockOperatorAlerter), ledger)
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		ledger.On("RecordPayment", p).Return(nil)
+
+		_, err := useCase.Execute(TrackConfirmationsCommand{PaymentID: p.ID, TransactionHash: testTransactionHash, GatewayStatus: "confirmed"})
+		assert.NoError(t, err)
+		response, err := useCase.Execute(TrackConfirmationsCommand{PaymentID: p.ID, GatewayStatus: "finished", GatewayFee: 0.00001, NetworkFee: 0.000005})
+		assert.NoError(t, err)
+		_, err = useCase.Execute(TrackConfirmationsCommand{PaymentID: p.ID, GatewayStatus: "finished", GatewayFee: 0.00001, NetworkFee: 0.000005})
+		assert.NoError(t, err)
+
+		assert.Equal(t, 0.00001, response.GatewayFee)
+		assert.InDelta(t, 0.001985, response.NetAmount, 1e-12)
+		ledger.AssertNumberOfCalls(t, "RecordPayment", 2)
+	})
+
+	t.Run("later fee report with other amounts adjusts the fees", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		ledger := new(MockPaymentLedger)
+		p := createTestPayment()
+		_ = p.UpdateCryptoAmount(0.002)
+		orderRepo, _ := newAwaitingOrderRepository(p)
+		useCase := NewTrackConfirmationsUseCase(paymentRepo, orderRepo, new(MockOperatorAlerter), ledger)
+
+		paymentRepo.On("FindByID", p.ID).Return(p, nil)
+		paymentRepo.On("Update", p).Return(nil)
+		ledger.On("RecordPayment", p).Return(nil)
+
+		// Deposit and service fees at confirmed, the withdrawal fee added at finished
+		_, errConfirmed := useCase.Execute(TrackConfirmationsCommand{PaymentID: p.ID, TransactionHash: testTransactionHash, GatewayStatus: "confirmed", GatewayFee: 0.00001, NetworkFee: 0.000005})
+		response, errFinished := useCase.Execute(TrackConfirmationsCommand{PaymentID: p.ID, GatewayStatus: "finished", GatewayFee: 0.00001, NetworkFee: 0.000012})
+		_, errRetried := useCase.Execute(TrackConfirmationsCommand{PaymentID: p.ID, GatewayStatus: "finished", GatewayFee: 0.00001, NetworkFee: 0.000012})
+
+		assert.NoError(t, errConfirmed)
+		assert.NoError(t, errFinished)
+		assert.NoError(t, errRetried)
+		assert.Equal(t, string(domainPayment.StatusConfirmed), response.Status)
+		assert.Equal(t, 0.000012, response.NetworkFee)
+		assert.Len(t, p.FeeAdjustments, 1)
+		ledger.AssertNumberOfCalls(t, "RecordPayment", 2)
+	})
+
+	t.Run("gateway failure and expiry", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		useCase := NewTrackConfirmationsUseCase(paymentRepo, new(MockOrderRepository), new(MockOperatorAlerter), nil)
+
+		failed := createTestPayment()
+		expired := createTestPayment()
+
+		paymentRepo.On("FindByID", failed.ID).Return(failed, nil)
+		paymentRepo.On("FindByID", expired.ID).Return(expired, nil)
+		paymentRepo.On("Update", mock.Anything).Return(nil)
+
+		_, errFailed := useCase.Execute(TrackConfirmationsCommand{PaymentID: failed.ID, GatewayStatus: "failed"})
+		_, errExpired := useCase.Execute(TrackConfirmationsCommand{PaymentID: expired.ID, GatewayStatus: "expired"})
+		_, errUnknown := useCase.Execute(TrackConfirmationsCommand{PaymentID: expired.ID, GatewayStatus: "lost"})
+
+		assert.NoError(t, errFailed)
+		assert.NoError(t, errExpired)
+		assert.Equal(t, domainPayment.StatusFailed, failed.Status)
+		assert.Equal(t, domainPayment.StatusExpired, expired.Status)
+		assert.Equal(t, domainPayment.ErrUnknownGatewayStatus, errUnknown)
+	})
+
+	t.Run("payment not found", func(t *testing.T) {
+		paymentRepo := new(MockPaymentRepository)
+		useCase := NewTrackConfirmationsUseCase(paymentRepo, new(MockOrderRepository), new(MockOperatorAlerter), nil)
+
+		paymentRepo.On("FindByID", "missing").Return(nil, nil)
+
+		response, err := useCase.Execute(TrackConfirmationsCommand{PaymentID: "missing"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, domainPayment.ErrPaymentNotFound, err)
+	})
+}
@@ -0,0 +1,76 @@
+package pricing
+
+import (
+	"sync"
+	"time"
+
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+)
+
+// RateProvider supplies fiat exchange rates (e.g. ECB, Open Exchange Rates)
+type RateProvider interface {
+	GetRate(from, to string) (domainOrder.ExchangeRate, error)
+}
+
+// CachedRateProvider caches rates from another provider.
+// Cached rates are refreshed after ttl; if the refresh fails, the cached rate
+// keeps being served until it is older than maxAge.
+type CachedRateProvider struct {
+	provider RateProvider
+	ttl      time.Duration
+	maxAge   time.Duration
+
+	mu    sync.Mutex
+	rates map[string]cachedRate
+}
+
+type cachedRate struct {
+	rate     domainOrder.ExchangeRate
+	cachedAt time.Time
+}
+
+// NewCachedRateProvider creates a new instance of CachedRateProvider
+func NewCachedRateProvider(provider RateProvider, ttl, maxAge time.Duration) *CachedRateProvider {
+	return &CachedRateProvider{
+		provider: provider,
+		ttl:      ttl,
+		maxAge:   maxAge,
+		rates:    make(map[string]cachedRate),
+	}
+}
+
+// GetRate returns a cached rate while it is fresh, otherwise fetches a new one
+func (c *CachedRateProvider) GetRate(from, to string) (domainOrder.ExchangeRate, error) {
+	key := from + "/" + to
+
+	c.mu.Lock()
+	cached, ok := c.rates[key]
+	c.mu.Unlock()
+
+	if ok && time.Since(cached.cachedAt) < c.ttl && !cached.rate.IsStale(c.maxAge) {
+		return cached.rate, nil
+	}
+
+	rate, err := c.provider.GetRate(from, to)
+	if err != nil {
+		// Fall back to the last known rate while it is within the staleness limit
+		if ok && !cached.rate.IsStale(c.maxAge) {
+			return cached.rate, nil
+		}
+		return domainOrder.ExchangeRate{}, err
+	}
+
+	if rate.From != from || rate.To != to {
+		return domainOrder.ExchangeRate{}, domainOrder.ErrExchangeRateMismatch
+	}
+
+	if rate.IsStale(c.maxAge) {
+		return domainOrder.ExchangeRate{}, domainOrder.ErrStaleExchangeRate
+	}
+
+	c.mu.Lock()
+	c.rates[key] = cachedRate{rate: rate, cachedAt: time.Now()}
+	c.mu.Unlock()
+
+	return rate, nil
+}
@@ -0,0 +1,95 @@
+package pricing
+
+import (
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainProduct "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/product"
+)
+
+// PricingService resolves product prices in a customer's display currency
+type PricingService struct {
+	rates RateProvider
+}
+
+// NewPricingService creates a new instance of PricingService
+func NewPricingService(rates RateProvider) *PricingService {
+	return &PricingService{
+		rates: rates,
+	}
+}
+
+// PriceIn returns the product price in the given currency. An explicit price
+// list entry wins; otherwise the base price is converted and the rate used is
+// returned so it can be recorded on the order.
+func (s *PricingService) PriceIn(p *domainProduct.Product, currency string) (domainOrder.Money, *domainOrder.ExchangeRate, error) {
+	rates, err := s.RatesFor([]*domainProduct.Product{p}, currency)
+	if err != nil {
+		return domainOrder.Money{}, nil, err
+	}
+
+	return priceWithRates(p, currency, rates)
+}
+
+// RatesFor fetches the rates needed to price the products in the given
+// currency, once per source currency, so every line of an order converted
+// from the same currency uses the same rate. Products with a price list entry
+// in the currency need none.
+func (s *PricingService) RatesFor(products []*domainProduct.Product, currency string) (map[string]domainOrder.ExchangeRate, error) {
+	rates := make(map[string]domainOrder.ExchangeRate)
+	for _, p := range products {
+		if _, ok := p.PriceIn(currency); ok {
+			continue
+		}
+
+		if _, ok := rates[p.Price.Currency]; ok {
+			continue
+		}
+
+		if s.rates == nil {
+			return nil, domainOrder.ErrExchangeRateUnavailable
+		}
+
+		rate, err := s.rates.GetRate(p.Price.Currency, currency)
+		if err != nil {
+			return nil, err
+		}
+		rates[p.Price.Currency] = rate
+	}
+
+	return rates, nil
+}
+
+// OrderItemFor creates an order item priced in the given currency with rates
+// from RatesFor, and returns the rate it was converted with, if any
+func (s *PricingService) OrderItemFor(p *domainProduct.Product, quantity int, currency string, rates map[string]domainOrder.ExchangeRate) (domainOrder.OrderItem, *domainOrder.ExchangeRate, error) {
+	price, rate, err := priceWithRates(p, currency, rates)
+	if err != nil {
+		return domainOrder.OrderItem{}, nil, err
+	}
+
+	item, err := domainOrder.NewOrderItemWithSnapshot(p.ID, p.Name, p.SKU, quantity, price)
+	if err != nil {
+		return domainOrder.OrderItem{}, nil, err
+	}
+
+	return item, rate, nil
+}
+
+// priceWithRates returns the product price in the given currency: an explicit
+// price list entry, or the base price converted with the source currency's rate
+func priceWithRates(p *domainProduct.Product, currency string, rates map[string]domainOrder.ExchangeRate) (domainOrder.Money, *domainOrder.ExchangeRate, error) {
+	if price, ok := p.PriceIn(currency); ok {
+		return price, nil, nil
+	}
+
+	rate, ok := rates[p.Price.Currency]
+	if !ok {
+		return domainOrder.Money{}, nil, domainOrder.ErrExchangeRateUnavailable
+	}
+
+	price, err := rate.Convert(p.Price)
+	if err != nil {
+		return domainOrder.Money{}, nil, err
+	}
+
+	return price, &rate, nil
+}
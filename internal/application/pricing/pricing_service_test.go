@@ -0,0 +1,152 @@
+package pricing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	domainOrder "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	domainProduct "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRateProvider is a mock implementation of RateProvider
+type MockRateProvider struct {
+	mock.Mock
+}
+
+func (m *MockRateProvider) GetRate(from, to string) (domainOrder.ExchangeRate, error) {
+	args := m.Called(from, to)
+	return args.Get(0).(domainOrder.ExchangeRate), args.Error(1)
+}
+
+// Test helper functions
+
+func createTestProduct() *domainProduct.Product {
+	price, _ := domainOrder.NewMoney(10.0, "USD")
+	category, _ := domainProduct.NewCategory("Electronics", "", nil)
+	inventory, _ := domainProduct.NewInventory(10, 0, 0)
+	p, _ := domainProduct.NewProduct("Headphones", "Wireless", "HP-001", price, category, inventory)
+	_ = p.Activate()
+	return p
+}
+
+func createTestRate(rate float64, fetchedAt time.Time) domainOrder.ExchangeRate {
+	r, _ := domainOrder.NewExchangeRate("USD", "EUR", rate, "test", fetchedAt)
+	return r
+}
+
+// Tests for PricingService
+
+func TestPricingService(t *testing.T) {
+	t.Run("base currency needs no conversion", func(t *testing.T) {
+		service := NewPricingService(nil)
+
+		price, rate, err := service.PriceIn(createTestProduct(), "USD")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 10.0, price.Amount)
+		assert.Nil(t, rate)
+	})
+
+	t.Run("price list wins over conversion", func(t *testing.T) {
+		rates := new(MockRateProvider)
+		service := NewPricingService(rates)
+		p := createTestProduct()
+		_ = p.SetPriceIn(domainOrder.Money{Amount: 9.5, Currency: "EUR"})
+
+		price, rate, err := service.PriceIn(p, "EUR")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domainOrder.Money{Amount: 9.5, Currency: "EUR"}, price)
+		assert.Nil(t, rate)
+		rates.AssertNotCalled(t, "GetRate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("convert through rate provider, once per source currency", func(t *testing.T) {
+		rates := new(MockRateProvider)
+		service := NewPricingService(rates)
+		fxRate := createTestRate(0.9, time.Now())
+
+		rates.On("GetRate", "USD", "EUR").Return(fxRate, nil)
+
+		p := createTestProduct()
+		fetched, errRates := service.RatesFor([]*domainProduct.Product{p, createTestProduct()}, "EUR")
+		item, rate, err := service.OrderItemFor(p, 2, "EUR", fetched)
+
+		assert.NoError(t, errRates)
+		rates.AssertNumberOfCalls(t, "GetRate", 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domainOrder.Money{Amount: 9.0, Currency: "EUR"}, item.UnitPrice)
+		assert.Equal(t, domainOrder.Money{Amount: 18.0, Currency: "EUR"}, item.Subtotal)
+		assert.Equal(t, "Headphones", item.ProductName)
+		assert.Equal(t, fxRate, *rate)
+	})
+
+	t.Run("no provider and no price list", func(t *testing.T) {
+		service := NewPricingService(nil)
+
+		_, _, err := service.PriceIn(createTestProduct(), "EUR")
+
+		assert.Equal(t, domainOrder.ErrExchangeRateUnavailable, err)
+	})
+}
+
+// Tests for CachedRateProvider
+
+func TestCachedRateProvider(t *testing.T) {
+	t.Run("serves cached rate within ttl", func(t *testing.T) {
+		upstream := new(MockRateProvider)
+		provider := NewCachedRateProvider(upstream, time.Hour, 24*time.Hour)
+
+		upstream.On("GetRate", "USD", "EUR").Return(createTestRate(0.9, time.Now()), nil).Once()
+
+		first, err1 := provider.GetRate("USD", "EUR")
+		second, err2 := provider.GetRate("USD", "EUR")
+
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.Equal(t, first, second)
+		upstream.AssertNumberOfCalls(t, "GetRate", 1)
+	})
+
+	t.Run("falls back to cached rate when refresh fails", func(t *testing.T) {
+		upstream := new(MockRateProvider)
+		provider := NewCachedRateProvider(upstream, 0, 24*time.Hour)
+		cached := createTestRate(0.9, time.Now())
+
+		upstream.On("GetRate", "USD", "EUR").Return(cached, nil).Once()
+		upstream.On("GetRate", "USD", "EUR").Return(domainOrder.ExchangeRate{}, errors.New("provider down"))
+
+		_, _ = provider.GetRate("USD", "EUR")
+		rate, err := provider.GetRate("USD", "EUR")
+
+		assert.NoError(t, err)
+		assert.Equal(t, cached, rate)
+	})
+
+	t.Run("rejects rates beyond the staleness limit", func(t *testing.T) {
+		upstream := new(MockRateProvider)
+		provider := NewCachedRateProvider(upstream, time.Hour, 24*time.Hour)
+
+		upstream.On("GetRate", "USD", "EUR").Return(createTestRate(0.9, time.Now().Add(-48*time.Hour)), nil)
+
+		_, err := provider.GetRate("USD", "EUR")
+
+		assert.Equal(t, domainOrder.ErrStaleExchangeRate, err)
+	})
+
+	t.Run("no cached rate and provider down", func(t *testing.T) {
+		upstream := new(MockRateProvider)
+		provider := NewCachedRateProvider(upstream, time.Hour, 24*time.Hour)
+		providerErr := errors.New("provider down")
+
+		upstream.On("GetRate", "USD", "EUR").Return(domainOrder.ExchangeRate{}, providerErr)
+
+		_, err := provider.GetRate("USD", "EUR")
+
+		assert.Equal(t, providerErr, err)
+	})
+}
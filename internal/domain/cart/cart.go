@@ -0,0 +1,286 @@
+package cart
+
+import (
+	"time"
+
+	"github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	"github.com/google/uuid"
+)
+
+// CartStatus represents the lifecycle state of a cart
+type CartStatus string
+
+const (
+	StatusActive    CartStatus = "ACTIVE"
+	StatusMerged    CartStatus = "MERGED"    // Guest cart folded into a customer cart
+	StatusConverted CartStatus = "CONVERTED" // Checked out into an order
+	StatusExpired   CartStatus = "EXPIRED"
+)
+
+// IsValid checks if the cart status is valid
+func (cs CartStatus) IsValid() bool {
+	switch cs {
+	case StatusActive, StatusMerged, StatusConverted, StatusExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// How long a cart lives after its last change
+const (
+	GuestCartTTL    = 7 * 24 * time.Hour
+	CustomerCartTTL = 30 * 24 * time.Hour
+)
+
+// Cart represents a shopper's browsing intent, kept apart from committed orders (Aggregate Root).
+// A cart belongs either to an anonymous session (guest cart) or to a customer.
+type Cart struct {
+	// Identity
+	ID         uuid.UUID
+	CustomerID string // Empty for guest carts
+	SessionID  string // Anonymous session the guest cart belongs to
+
+	Items []CartItem
+
+	// Lifecycle
+	Status           CartStatus
+	ConvertedOrderID *uuid.UUID
+	ExpiresAt        time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// NewGuestCart creates an anonymous cart for a browsing session
+func NewGuestCart(sessionID string) (*Cart, error) {
+	if sessionID == "" {
+		return nil, ErrEmptySessionID
+	}
+
+	return newCart("", sessionID, GuestCartTTL), nil
+}
+
+// NewCustomerCart creates a cart for a registered customer
+func NewCustomerCart(customerID string) (*Cart, error) {
+	if customerID == "" {
+		return nil, ErrEmptyCustomerID
+	}
+
+	return newCart(customerID, "", CustomerCartTTL), nil
+}
+
+func newCart(customerID, sessionID string, ttl time.Duration) *Cart {
+	now := time.Now()
+	return &Cart{
+		ID:         uuid.New(),
+		CustomerID: customerID,
+		SessionID:  sessionID,
+		Items:      []CartItem{},
+		Status:     StatusActive,
+		ExpiresAt:  now.Add(ttl),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// IsGuest checks if the cart belongs to an anonymous session
+func (c *Cart) IsGuest() bool {
+	return c.CustomerID == ""
+}
+
+// IsExpired checks if the cart has passed its expiry time
+func (c *Cart) IsExpired() bool {
+	return c.Status == StatusExpired || time.Now().After(c.ExpiresAt)
+}
+
+// IsEmpty checks if the cart has no items
+func (c *Cart) IsEmpty() bool {
+	return len(c.Items) == 0
+}
+
+// AddItem adds a product to the cart, combining it with an existing line for the same product.
+// The reference price is refreshed to the latest one seen.
+func (c *Cart) AddItem(productID uuid.UUID, productName string, quantity int, referencePrice order.Money) error {
+	if err := c.ensureModifiable(); err != nil {
+		return err
+	}
+
+	item, err := NewCartItem(productID, productName, quantity, referencePrice)
+	if err != nil {
+		return err
+	}
+
+	c.addLine(item)
+	c.touch()
+	return nil
+}
+
+// UpdateItemQuantity sets the quantity of a cart line, removing the line at zero
+func (c *Cart) UpdateItemQuantity(productID uuid.UUID, quantity int) error {
+	if err := c.ensureModifiable(); err != nil {
+		return err
+	}
+
+	if quantity < 0 {
+		return ErrInvalidQuantity
+	}
+
+	if quantity == 0 {
+		return c.RemoveItem(productID)
+	}
+
+	for i := range c.Items {
+		if c.Items[i].ProductID == productID {
+			c.Items[i].Quantity = quantity
+			c.touch()
+			return nil
+		}
+	}
+	return ErrItemNotFound
+}
+
+// RemoveItem removes a product from the cart
+func (c *Cart) RemoveItem(productID uuid.UUID) error {
+	if err := c.ensureModifiable(); err != nil {
+		return err
+	}
+
+	for i, item := range c.Items {
+		if item.ProductID == productID {
+			c.Items = append(c.Items[:i], c.Items[i+1:]...)
+			c.touch()
+			return nil
+		}
+	}
+	return ErrItemNotFound
+}
+
+// Merge folds an active guest cart into this cart. Lines for the same product
+// are combined; the guest cart is marked as merged.
+func (c *Cart) Merge(guest *Cart) error {
+	if err := c.ensureModifiable(); err != nil {
+		return err
+	}
+
+	if !guest.IsGuest() {
+		return ErrNotGuestCart
+	}
+
+	if err := guest.ensureModifiable(); err != nil {
+		return err
+	}
+
+	for _, item := range guest.Items {
+		c.addLine(item)
+	}
+
+	guest.Status = StatusMerged
+	guest.UpdatedAt = time.Now()
+	c.touch()
+	return nil
+}
+
+// AssignToCustomer turns a guest cart into the customer's cart, used when
+// the customer has no cart of their own to merge into
+func (c *Cart) AssignToCustomer(customerID string) error {
+	if customerID == "" {
+		return ErrEmptyCustomerID
+	}
+
+	if err := c.ensureModifiable(); err != nil {
+		return err
+	}
+
+	if !c.IsGuest() {
+		return ErrNotGuestCart
+	}
+
+	c.CustomerID = customerID
+	c.SessionID = ""
+	c.touch()
+	return nil
+}
+
+// MarkAsConverted records the order the cart was checked out into
+func (c *Cart) MarkAsConverted(orderID uuid.UUID) error {
+	if err := c.ensureModifiable(); err != nil {
+		return err
+	}
+
+	if c.IsGuest() {
+		return ErrGuestCartCheckout
+	}
+
+	if c.IsEmpty() {
+		return ErrEmptyCart
+	}
+
+	c.Status = StatusConverted
+	c.ConvertedOrderID = &orderID
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// Expire marks the cart as expired
+func (c *Cart) Expire() {
+	c.Status = StatusExpired
+	c.UpdatedAt = time.Now()
+}
+
+// EstimatedTotal sums the lines at their reference prices
+func (c *Cart) EstimatedTotal() (order.Money, error) {
+	if c.IsEmpty() {
+		return order.Money{}, ErrEmptyCart
+	}
+
+	currency := c.Items[0].ReferencePrice.Currency
+	total := 0.0
+	for _, item := range c.Items {
+		if item.ReferencePrice.Currency != currency {
+			return order.Money{}, order.ErrInconsistentCurrency
+		}
+		total += item.EstimatedSubtotal().Amount
+	}
+
+	return order.Money{Amount: total, Currency: currency}, nil
+}
+
+// addLine adds a line or combines it with the existing line for the same product
+func (c *Cart) addLine(item CartItem) {
+	for i, existing := range c.Items {
+		if existing.ProductID == item.ProductID {
+			c.Items[i].Quantity += item.Quantity
+			if item.AddedAt.After(existing.AddedAt) {
+				c.Items[i].ReferencePrice = item.ReferencePrice
+				c.Items[i].AddedAt = item.AddedAt
+			}
+			return
+		}
+	}
+	c.Items = append(c.Items, item)
+}
+
+// ensureModifiable checks the cart is still active and not expired
+func (c *Cart) ensureModifiable() error {
+	if c.Status != StatusActive {
+		return ErrCartNotActive
+	}
+
+	if c.IsExpired() {
+		return ErrCartExpired
+	}
+
+	return nil
+}
+
+// touch records a change and pushes back the expiry
+func (c *Cart) touch() {
+	now := time.Now()
+	ttl := CustomerCartTTL
+	if c.IsGuest() {
+		ttl = GuestCartTTL
+	}
+
+	c.UpdatedAt = now
+	c.ExpiresAt = now.Add(ttl)
+}
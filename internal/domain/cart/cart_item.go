@@ -0,0 +1,46 @@
+package cart
+
+import (
+	"time"
+
+	"github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	"github.com/google/uuid"
+)
+
+// CartItem represents a product the shopper intends to buy.
+// ReferencePrice is only a soft reference for display: the binding price
+// is taken from the product when the cart is converted to an order.
+type CartItem struct {
+	ProductID      uuid.UUID
+	ProductName    string
+	Quantity       int
+	ReferencePrice order.Money
+	AddedAt        time.Time
+}
+
+// NewCartItem creates a new cart item with validation
+func NewCartItem(productID uuid.UUID, productName string, quantity int, referencePrice order.Money) (CartItem, error) {
+	if quantity <= 0 {
+		return CartItem{}, ErrInvalidQuantity
+	}
+
+	if referencePrice.Amount <= 0 || referencePrice.Currency == "" {
+		return CartItem{}, ErrInvalidPrice
+	}
+
+	return CartItem{
+		ProductID:      productID,
+		ProductName:    productName,
+		Quantity:       quantity,
+		ReferencePrice: referencePrice,
+		AddedAt:        time.Now(),
+	}, nil
+}
+
+// EstimatedSubtotal returns the line subtotal at the reference price
+func (item CartItem) EstimatedSubtotal() order.Money {
+	return order.Money{
+		Amount:   item.ReferencePrice.Amount * float64(item.Quantity),
+		Currency: item.ReferencePrice.Currency,
+	}
+}
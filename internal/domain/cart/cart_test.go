@@ -0,0 +1,175 @@
+package cart
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/order"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test helper functions
+
+func createTestMoney(amount float64) order.Money {
+	return order.Money{Amount: amount, Currency: "USD"}
+}
+
+func createTestGuestCart() *Cart {
+	c, _ := NewGuestCart("session-123")
+	return c
+}
+
+func createTestCustomerCart() *Cart {
+	c, _ := NewCustomerCart("customer123")
+	return c
+}
+
+func TestNewCart(t *testing.T) {
+	t.Run("create guest cart", func(t *testing.T) {
+		c, err := NewGuestCart("session-123")
+
+		assert.NoError(t, err)
+		assert.True(t, c.IsGuest())
+		assert.Equal(t, StatusActive, c.Status)
+		assert.WithinDuration(t, time.Now().Add(GuestCartTTL), c.ExpiresAt, time.Second)
+	})
+
+	t.Run("create customer cart", func(t *testing.T) {
+		c, err := NewCustomerCart("customer123")
+
+		assert.NoError(t, err)
+		assert.False(t, c.IsGuest())
+		assert.WithinDuration(t, time.Now().Add(CustomerCartTTL), c.ExpiresAt, time.Second)
+	})
+
+	t.Run("cart needs an owner", func(t *testing.T) {
+		_, guestErr := NewGuestCart("")
+		_, customerErr := NewCustomerCart("")
+
+		assert.Equal(t, ErrEmptySessionID, guestErr)
+		assert.Equal(t, ErrEmptyCustomerID, customerErr)
+	})
+}
+
+func TestCartItems(t *testing.T) {
+	t.Run("add combines lines for the same product", func(t *testing.T) {
+		c := createTestGuestCart()
+		productID := uuid.New()
+
+		_ = c.AddItem(productID, "Headphones", 1, createTestMoney(10.0))
+		err := c.AddItem(productID, "Headphones", 2, createTestMoney(12.0))
+
+		assert.NoError(t, err)
+		assert.Len(t, c.Items, 1)
+		assert.Equal(t, 3, c.Items[0].Quantity)
+		assert.Equal(t, createTestMoney(12.0), c.Items[0].ReferencePrice)
+	})
+
+	t.Run("add rejects invalid lines", func(t *testing.T) {
+		c := createTestGuestCart()
+
+		assert.Equal(t, ErrInvalidQuantity, c.AddItem(uuid.New(), "Headphones", 0, createTestMoney(10.0)))
+		assert.Equal(t, ErrInvalidPrice, c.AddItem(uuid.New(), "Headphones", 1, createTestMoney(0)))
+	})
+
+	t.Run("update quantity and remove at zero", func(t *testing.T) {
+		c := createTestCustomerCart()
+		productID := uuid.New()
+		_ = c.AddItem(productID, "Headphones", 1, createTestMoney(10.0))
+
+		assert.NoError(t, c.UpdateItemQuantity(productID, 4))
+		assert.Equal(t, 4, c.Items[0].Quantity)
+
+		assert.NoError(t, c.UpdateItemQuantity(productID, 0))
+		assert.True(t, c.IsEmpty())
+		assert.Equal(t, ErrItemNotFound, c.RemoveItem(productID))
+	})
+
+	t.Run("estimated total uses reference prices", func(t *testing.T) {
+		c := createTestCustomerCart()
+		_ = c.AddItem(uuid.New(), "Headphones", 2, createTestMoney(10.0))
+		_ = c.AddItem(uuid.New(), "Speaker", 1, createTestMoney(25.0))
+
+		total, err := c.EstimatedTotal()
+
+		assert.NoError(t, err)
+		assert.Equal(t, createTestMoney(45.0), total)
+	})
+
+	t.Run("expired cart cannot be changed", func(t *testing.T) {
+		c := createTestGuestCart()
+		c.ExpiresAt = time.Now().Add(-time.Minute)
+
+		err := c.AddItem(uuid.New(), "Headphones", 1, createTestMoney(10.0))
+
+		assert.True(t, c.IsExpired())
+		assert.Equal(t, ErrCartExpired, err)
+	})
+}
+
+func TestCartMerge(t *testing.T) {
+	t.Run("merge combines lines with the same product", func(t *testing.T) {
+		shared := uuid.New()
+		guest := createTestGuestCart()
+		_ = guest.AddItem(shared, "Headphones", 1, createTestMoney(10.0))
+		_ = guest.AddItem(uuid.New(), "Speaker", 1, createTestMoney(25.0))
+		customerCart := createTestCustomerCart()
+		_ = customerCart.AddItem(shared, "Headphones", 2, createTestMoney(10.0))
+
+		err := customerCart.Merge(guest)
+
+		assert.NoError(t, err)
+		assert.Len(t, customerCart.Items, 2)
+		assert.Equal(t, 3, customerCart.Items[0].Quantity)
+		assert.Equal(t, StatusMerged, guest.Status)
+	})
+
+	t.Run("only guest carts can be merged", func(t *testing.T) {
+		err := createTestCustomerCart().Merge(createTestCustomerCart())
+
+		assert.Equal(t, ErrNotGuestCart, err)
+	})
+
+	t.Run("merged guest cart cannot be merged again", func(t *testing.T) {
+		guest := createTestGuestCart()
+		_ = createTestCustomerCart().Merge(guest)
+
+		err := createTestCustomerCart().Merge(guest)
+
+		assert.Equal(t, ErrCartNotActive, err)
+	})
+
+	t.Run("assign guest cart to customer", func(t *testing.T) {
+		guest := createTestGuestCart()
+
+		err := guest.AssignToCustomer("customer123")
+
+		assert.NoError(t, err)
+		assert.False(t, guest.IsGuest())
+		assert.Empty(t, guest.SessionID)
+	})
+}
+
+func TestCartConversion(t *testing.T) {
+	t.Run("mark as converted", func(t *testing.T) {
+		c := createTestCustomerCart()
+		_ = c.AddItem(uuid.New(), "Headphones", 1, createTestMoney(10.0))
+		orderID := uuid.New()
+
+		err := c.MarkAsConverted(orderID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, StatusConverted, c.Status)
+		assert.Equal(t, orderID, *c.ConvertedOrderID)
+		assert.Equal(t, ErrCartNotActive, c.AddItem(uuid.New(), "Speaker", 1, createTestMoney(25.0)))
+	})
+
+	t.Run("guest and empty carts cannot be converted", func(t *testing.T) {
+		guest := createTestGuestCart()
+		_ = guest.AddItem(uuid.New(), "Headphones", 1, createTestMoney(10.0))
+
+		assert.Equal(t, ErrGuestCartCheckout, guest.MarkAsConverted(uuid.New()))
+		assert.Equal(t, ErrEmptyCart, createTestCustomerCart().MarkAsConverted(uuid.New()))
+	})
+}
@@ -0,0 +1,28 @@
+package cart
+
+import "errors"
+
+// Cart domain errors organized by category
+
+// === Validation Errors ===
+var (
+	ErrEmptySessionID  = errors.New("session ID cannot be empty")
+	ErrEmptyCustomerID = errors.New("customer ID cannot be empty")
+	ErrInvalidQuantity = errors.New("quantity must be greater than zero")
+	ErrInvalidPrice    = errors.New("reference price must be positive")
+)
+
+// === Business Rule Errors ===
+var (
+	ErrCartNotFound      = errors.New("cart not found")
+	ErrItemNotFound      = errors.New("item not found in cart")
+	ErrEmptyCart         = errors.New("cart has no items")
+	ErrNotGuestCart      = errors.New("only guest carts can be merged")
+	ErrGuestCartCheckout = errors.New("guest carts must be merged into a customer cart before checkout")
+)
+
+// === State Transition Errors ===
+var (
+	ErrCartNotActive = errors.New("cart is no longer active")
+	ErrCartExpired   = errors.New("cart has expired")
+)
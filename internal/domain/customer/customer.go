@@ -25,6 +25,25 @@ func (cs CustomerStatus) IsValid() bool {
 	}
 }
 
+// TrustLevel represents how much the merchant trusts a customer's payments
+type TrustLevel string
+
+const (
+	TrustLevelNew      TrustLevel = "NEW"      // No payment history yet
+	TrustLevelStandard TrustLevel = "STANDARD"
+	TrustLevelTrusted  TrustLevel = "TRUSTED"  // Long history without disputes or double-spends
+)
+
+// IsValid checks if the trust level is valid
+func (tl TrustLevel) IsValid() bool {
+	switch tl {
+	case TrustLevelNew, TrustLevelStandard, TrustLevelTrusted:
+		return true
+	default:
+		return false
+	}
+}
+
 // Customer represents a customer entity (Aggregate Root)
 type Customer struct {
 	// Identity
@@ -38,6 +57,7 @@ type Customer struct {
 	
 	// Account Status
 	Status    CustomerStatus
+	TrustLevel TrustLevel // Used to pick payment confirmation overrides
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	
@@ -76,6 +96,7 @@ func NewCustomer(email, firstName, lastName, phone string) (*Customer, error) {
 		LastName:          lastName,
 		Phone:             phone,
 		Status:            StatusActive,
+		TrustLevel:        TrustLevelNew,
 		CreatedAt:         now,
 		UpdatedAt:         now,
 		ShippingAddresses: make([]ShippingAddress, 0),
@@ -155,6 +176,18 @@ func (c *Customer) Suspend() error {
 	return nil
 }
 
+// SetTrustLevel changes the customer's trust level (admin action)
+func (c *Customer) SetTrustLevel(level TrustLevel) error {
+	if !level.IsValid() {
+		return ErrInvalidTrustLevel
+	}
+	
+	c.TrustLevel = level
+	c.UpdatedAt = time.Now()
+	
+	return nil
+}
+
 // AddShippingAddress adds a new shipping address to the customer
 func (c *Customer) AddShippingAddress(label, firstName, lastName, company, addressLine1, addressLine2, city, state, postalCode, country, phone string, isDefault bool) error {
 	// If this is set as default, ensure no other address is default
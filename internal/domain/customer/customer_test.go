@@ -502,6 +502,32 @@ func TestCustomerStatusTransitions(t *testing.T) {
 	})
 }
 
+func TestCustomerTrustLevel(t *testing.T) {
+	t.Run("new customer starts untrusted", func(t *testing.T) {
+		customer := createTestCustomer()
+		
+		assert.Equal(t, TrustLevelNew, customer.TrustLevel)
+	})
+	
+	t.Run("promote customer", func(t *testing.T) {
+		customer := createTestCustomer()
+		
+		err := customer.SetTrustLevel(TrustLevelTrusted)
+		
+		assert.NoError(t, err)
+		assert.Equal(t, TrustLevelTrusted, customer.TrustLevel)
+	})
+	
+	t.Run("invalid trust level", func(t *testing.T) {
+		customer := createTestCustomer()
+		
+		err := customer.SetTrustLevel(TrustLevel("VIP"))
+		
+		assert.Equal(t, ErrInvalidTrustLevel, err)
+		assert.Equal(t, TrustLevelNew, customer.TrustLevel)
+	})
+}
+
 func TestCustomerShippingAddresses(t *testing.T) {
 	t.Run("add first shipping address", func(t *testing.T) {
 		customer := createTestCustomer()
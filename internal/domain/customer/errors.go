@@ -12,6 +12,7 @@ var (
 	ErrEmptyLastName      = errors.New("last name cannot be empty")
 	ErrInvalidPhone       = errors.New("phone number is invalid")
 	ErrEmptyCustomerID    = errors.New("customer ID cannot be empty")
+	ErrInvalidTrustLevel  = errors.New("trust level is invalid")
 )
 
 // === Address Validation Errors ===
@@ -0,0 +1,56 @@
+package ledger
+
+// Account is a ledger account. Every currency has its own set of accounts;
+// balances of different currencies are never added up.
+type Account string
+
+const (
+	AccountCustomerReceipts Account = "CUSTOMER_RECEIPTS" // Gross amounts paid by customers, less refunds
+	AccountGatewayFees      Account = "GATEWAY_FEES"      // Service fees kept by the payment gateway
+	AccountRefundsPayable   Account = "REFUNDS_PAYABLE"   // Refunds issued but not sent on-chain yet
+	AccountMerchantBalance  Account = "MERCHANT_BALANCE"  // What the merchant is owed and can withdraw
+	AccountNetworkFees      Account = "NETWORK_FEES"      // Miner and gas fees of refunds and payouts
+	AccountMerchantPayouts  Account = "MERCHANT_PAYOUTS"  // Amounts withdrawn to the merchant's wallets
+)
+
+// Side is the side of an account a posting goes to
+type Side string
+
+const (
+	Debit  Side = "DEBIT"
+	Credit Side = "CREDIT"
+)
+
+// Accounts returns every ledger account in reporting order
+func Accounts() []Account {
+	return []Account{
+		AccountCustomerReceipts,
+		AccountGatewayFees,
+		AccountRefundsPayable,
+		AccountMerchantBalance,
+		AccountNetworkFees,
+		AccountMerchantPayouts,
+	}
+}
+
+// IsValid checks if the account is supported
+func (a Account) IsValid() bool {
+	switch a {
+	case AccountCustomerReceipts, AccountGatewayFees, AccountRefundsPayable,
+		AccountMerchantBalance, AccountNetworkFees, AccountMerchantPayouts:
+		return true
+	default:
+		return false
+	}
+}
+
+// NormalSide returns the side that increases the account's balance: debit for
+// what the merchant holds or spent, credit for what it received or owes
+func (a Account) NormalSide() Side {
+	switch a {
+	case AccountCustomerReceipts, AccountRefundsPayable:
+		return Credit
+	default:
+		return Debit
+	}
+}
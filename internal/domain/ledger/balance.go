@@ -0,0 +1,52 @@
+package ledger
+
+import (
+	"strings"
+	"time"
+)
+
+// Balance is the state of a currency's accounts at a point in time
+type Balance struct {
+	Currency string
+	AsOf     time.Time // Exclusive: entries that occurred before AsOf are included
+	Accounts map[Account]float64
+	Entries  int // Number of entries summed up
+}
+
+// NewBalance sums up the entries of a currency that occurred before asOf.
+// Entries of other currencies are ignored.
+func NewBalance(currency string, asOf time.Time, entries []*Entry) *Balance {
+	currency = strings.ToUpper(currency)
+
+	balance := &Balance{
+		Currency: currency,
+		AsOf:     asOf,
+		Accounts: make(map[Account]float64, len(Accounts())),
+	}
+	for _, account := range Accounts() {
+		balance.Accounts[account] = 0
+	}
+
+	for _, entry := range entries {
+		if entry.Currency != currency || !entry.OccurredAt.Before(asOf) {
+			continue
+		}
+		for _, account := range Accounts() {
+			balance.Accounts[account] += entry.Amount(account)
+		}
+		balance.Entries++
+	}
+
+	return balance
+}
+
+// MerchantBalance returns what the merchant is owed and can withdraw
+func (b *Balance) MerchantBalance() float64 {
+	return b.Accounts[AccountMerchantBalance]
+}
+
+// Available returns what can be paid out without leaving refunds issued but
+// not sent uncovered
+func (b *Balance) Available() float64 {
+	return max(b.MerchantBalance()-b.Accounts[AccountRefundsPayable], 0)
+}
@@ -0,0 +1,297 @@
+package ledger
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EntryKind is the business event an entry records
+type EntryKind string
+
+const (
+	EntryPaymentReceived EntryKind = "PAYMENT_RECEIVED" // Payment confirmed
+	EntryFeesCharged     EntryKind = "FEES_CHARGED"     // Gateway and network fees of a payment reported
+	EntryFeesAdjusted    EntryKind = "FEES_ADJUSTED"    // Later report changed the fees of a payment
+	EntryRefundIssued    EntryKind = "REFUND_ISSUED"    // Refund owed to the customer
+	EntryRefundSent      EntryKind = "REFUND_SENT"      // Refund transaction broadcast
+	EntryPayoutSent      EntryKind = "PAYOUT_SENT"      // Withdrawal to the merchant broadcast
+)
+
+// balanceTolerance absorbs floating point error when debits and credits are compared
+const balanceTolerance = 1e-9
+
+// Posting is one line of an entry
+type Posting struct {
+	Account Account
+	Side    Side
+	Amount  float64
+}
+
+// Entry is a balanced journal entry in a single currency (Aggregate Root).
+// Entries are append-only; a mistake is corrected by posting another entry.
+type Entry struct {
+	ID        string
+	Reference string // Unique per business event, so posting the same event twice is a no-op
+	Kind      EntryKind
+	Currency  string // Crypto symbol the amounts are in
+
+	PaymentID   string // Set for payment and refund entries
+	PayoutID    string // Set for payout entries
+	Description string
+	Postings    []Posting
+	OccurredAt  time.Time // When the event happened; balances are taken as of this time
+	PostedAt    time.Time
+}
+
+// NewEntry creates a journal entry. Zero amount postings are dropped, so
+// optional fees can be passed unconditionally.
+func NewEntry(reference string, kind EntryKind, currency, description string, occurredAt time.Time, postings ...Posting) (*Entry, error) {
+	if reference == "" {
+		return nil, ErrEmptyReference
+	}
+
+	if currency == "" {
+		return nil, ErrEmptyCurrency
+	}
+
+	var kept []Posting
+	var debits, credits float64
+	for _, posting := range postings {
+		if !posting.Account.IsValid() {
+			return nil, ErrInvalidAccount
+		}
+		if posting.Amount < 0 {
+			return nil, ErrInvalidAmount
+		}
+		if posting.Amount == 0 {
+			continue
+		}
+
+		switch posting.Side {
+		case Debit:
+			debits += posting.Amount
+		case Credit:
+			credits += posting.Amount
+		default:
+			return nil, ErrInvalidAccount
+		}
+		kept = append(kept, posting)
+	}
+
+	if debits == 0 || credits == 0 {
+		return nil, ErrTooFewPostings
+	}
+
+	if math.Abs(debits-credits) > balanceTolerance*math.Max(1, debits) {
+		return nil, ErrUnbalancedEntry
+	}
+
+	return &Entry{
+		ID:          uuid.New().String(),
+		Reference:   reference,
+		Kind:        kind,
+		Currency:    strings.ToUpper(currency),
+		Description: description,
+		Postings:    kept,
+		OccurredAt:  occurredAt,
+		PostedAt:    time.Now(),
+	}, nil
+}
+
+// NewPaymentReceivedEntry records a confirmed payment: the customer paid amount
+// and the merchant is owed it until the gateway reports its fees
+func NewPaymentReceivedEntry(paymentID, currency string, amount float64, occurredAt time.Time) (*Entry, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	entry, err := NewEntry(PaymentReceivedReference(paymentID), EntryPaymentReceived, currency,
+		fmt.Sprintf("Payment %s confirmed", paymentID), occurredAt,
+		Posting{Account: AccountMerchantBalance, Side: Debit, Amount: amount},
+		Posting{Account: AccountCustomerReceipts, Side: Credit, Amount: amount},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.PaymentID = paymentID
+	return entry, nil
+}
+
+// NewPaymentFeesEntry records the service fee the gateway kept from a payment
+// and the network fee it paid to forward the funds. Gateways report fees after
+// the payment is confirmed, so they are posted apart from the receipt.
+func NewPaymentFeesEntry(paymentID, currency string, gatewayFee, networkFee float64, occurredAt time.Time) (*Entry, error) {
+	if gatewayFee < 0 || networkFee < 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	entry, err := NewEntry(PaymentFeesReference(paymentID), EntryFeesCharged, currency,
+		fmt.Sprintf("Fees of payment %s charged", paymentID), occurredAt,
+		Posting{Account: AccountGatewayFees, Side: Debit, Amount: gatewayFee},
+		Posting{Account: AccountNetworkFees, Side: Debit, Amount: networkFee},
+		Posting{Account: AccountMerchantBalance, Side: Credit, Amount: gatewayFee + networkFee},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.PaymentID = paymentID
+	return entry, nil
+}
+
+// NewPaymentFeesAdjustedEntry records a later fee report that changed a
+// payment's fees by the given amounts, negative when a fee was lowered.
+// index is the adjustment's position on the payment, starting at zero.
+func NewPaymentFeesAdjustedEntry(paymentID string, index int, currency string, gatewayFeeChange, networkFeeChange float64, occurredAt time.Time) (*Entry, error) {
+	if gatewayFeeChange == 0 && networkFeeChange == 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	entry, err := NewEntry(PaymentFeesAdjustedReference(paymentID, index), EntryFeesAdjusted, currency,
+		fmt.Sprintf("Fees of payment %s adjusted", paymentID), occurredAt,
+		signedPosting(AccountGatewayFees, Debit, gatewayFeeChange),
+		signedPosting(AccountNetworkFees, Debit, networkFeeChange),
+		signedPosting(AccountMerchantBalance, Credit, gatewayFeeChange+networkFeeChange),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.PaymentID = paymentID
+	return entry, nil
+}
+
+// signedPosting posts amount on side, or its opposite on the other side when negative
+func signedPosting(account Account, side Side, amount float64) Posting {
+	if amount >= 0 {
+		return Posting{Account: account, Side: side, Amount: amount}
+	}
+	if side == Debit {
+		side = Credit
+	} else {
+		side = Debit
+	}
+	return Posting{Account: account, Side: side, Amount: -amount}
+}
+
+// NewRefundIssuedEntry records a refund owed to the customer of a payment.
+// index is the refund's position on the payment, starting at zero.
+func NewRefundIssuedEntry(paymentID string, index int, currency string, amount float64, occurredAt time.Time) (*Entry, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	entry, err := NewEntry(RefundIssuedReference(paymentID, index), EntryRefundIssued, currency,
+		fmt.Sprintf("Refund %d of payment %s issued", index+1, paymentID), occurredAt,
+		Posting{Account: AccountCustomerReceipts, Side: Debit, Amount: amount},
+		Posting{Account: AccountRefundsPayable, Side: Credit, Amount: amount},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.PaymentID = paymentID
+	return entry, nil
+}
+
+// NewRefundSentEntry records a refund leaving the merchant balance on-chain,
+// together with the network fee of its transaction
+func NewRefundSentEntry(paymentID string, index int, currency string, amount, networkFee float64, occurredAt time.Time) (*Entry, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	if networkFee < 0 {
+		return nil, ErrInvalidNetworkFee
+	}
+
+	entry, err := NewEntry(RefundSentReference(paymentID, index), EntryRefundSent, currency,
+		fmt.Sprintf("Refund %d of payment %s sent", index+1, paymentID), occurredAt,
+		Posting{Account: AccountRefundsPayable, Side: Debit, Amount: amount},
+		Posting{Account: AccountNetworkFees, Side: Debit, Amount: networkFee},
+		Posting{Account: AccountMerchantBalance, Side: Credit, Amount: amount + networkFee},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.PaymentID = paymentID
+	return entry, nil
+}
+
+// NewPayoutSentEntry records a withdrawal of a payout batch leaving the merchant balance
+func NewPayoutSentEntry(batch *PayoutBatch, index int) (*Entry, error) {
+	if index < 0 || index >= len(batch.Withdrawals) {
+		return nil, ErrWithdrawalNotFound
+	}
+
+	withdrawal := batch.Withdrawals[index]
+	if !withdrawal.IsSent() {
+		return nil, ErrEmptyTransactionHash
+	}
+
+	entry, err := NewEntry(PayoutSentReference(batch.ID, index), EntryPayoutSent, batch.Currency,
+		fmt.Sprintf("Payout %s to %s sent in %s", batch.ID, withdrawal.DestinationAddress, withdrawal.TransactionHash), *withdrawal.SentAt,
+		Posting{Account: AccountMerchantPayouts, Side: Debit, Amount: withdrawal.Amount},
+		Posting{Account: AccountNetworkFees, Side: Debit, Amount: withdrawal.NetworkFee},
+		Posting{Account: AccountMerchantBalance, Side: Credit, Amount: withdrawal.Amount + withdrawal.NetworkFee},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.PayoutID = batch.ID
+	return entry, nil
+}
+
+// PaymentReceivedReference returns the reference of a payment's receipt
+func PaymentReceivedReference(paymentID string) string {
+	return "payment:" + paymentID + ":received"
+}
+
+// PaymentFeesReference returns the reference of a payment's fees
+func PaymentFeesReference(paymentID string) string {
+	return "payment:" + paymentID + ":fees"
+}
+
+// PaymentFeesAdjustedReference returns the reference of an adjustment of a payment's fees
+func PaymentFeesAdjustedReference(paymentID string, index int) string {
+	return fmt.Sprintf("payment:%s:fees:%d:adjusted", paymentID, index)
+}
+
+// RefundIssuedReference returns the reference of a payment refund being issued
+func RefundIssuedReference(paymentID string, index int) string {
+	return fmt.Sprintf("payment:%s:refund:%d:issued", paymentID, index)
+}
+
+// RefundSentReference returns the reference of a payment refund being sent
+func RefundSentReference(paymentID string, index int) string {
+	return fmt.Sprintf("payment:%s:refund:%d:sent", paymentID, index)
+}
+
+// PayoutSentReference returns the reference of a payout withdrawal being sent
+func PayoutSentReference(batchID string, index int) string {
+	return fmt.Sprintf("payout:%s:%d:sent", batchID, index)
+}
+
+// Amount returns the signed effect of the entry on an account: positive when
+// it moves the account towards its normal side
+func (e *Entry) Amount(account Account) float64 {
+	total := 0.0
+	for _, posting := range e.Postings {
+		if posting.Account != account {
+			continue
+		}
+		if posting.Side == account.NormalSide() {
+			total += posting.Amount
+		} else {
+			total -= posting.Amount
+		}
+	}
+	return total
+}
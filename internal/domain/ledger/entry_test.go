@@ -0,0 +1,141 @@
+package ledger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Entry
+
+func TestNewEntry(t *testing.T) {
+	t.Run("balanced entry drops zero postings", func(t *testing.T) {
+		entry, err := NewEntry("ref-1", EntryPaymentReceived, "btc", "", time.Now(),
+			Posting{Account: AccountMerchantBalance, Side: Debit, Amount: 0.002},
+			Posting{Account: AccountGatewayFees, Side: Debit, Amount: 0},
+			Posting{Account: AccountCustomerReceipts, Side: Credit, Amount: 0.002},
+		)
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, entry.ID)
+		assert.Equal(t, "BTC", entry.Currency)
+		assert.Len(t, entry.Postings, 2)
+	})
+
+	t.Run("unbalanced entry", func(t *testing.T) {
+		_, err := NewEntry("ref-1", EntryPaymentReceived, "BTC", "", time.Now(),
+			Posting{Account: AccountMerchantBalance, Side: Debit, Amount: 0.002},
+			Posting{Account: AccountCustomerReceipts, Side: Credit, Amount: 0.001},
+		)
+
+		assert.Equal(t, ErrUnbalancedEntry, err)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		debit := Posting{Account: AccountMerchantBalance, Side: Debit, Amount: 1}
+		credit := Posting{Account: AccountCustomerReceipts, Side: Credit, Amount: 1}
+
+		_, errReference := NewEntry("", EntryPaymentReceived, "BTC", "", time.Now(), debit, credit)
+		_, errCurrency := NewEntry("ref-1", EntryPaymentReceived, "", "", time.Now(), debit, credit)
+		_, errAccount := NewEntry("ref-1", EntryPaymentReceived, "BTC", "", time.Now(), debit, Posting{Account: "CASH", Side: Credit, Amount: 1})
+		_, errNegative := NewEntry("ref-1", EntryPaymentReceived, "BTC", "", time.Now(), debit, Posting{Account: AccountCustomerReceipts, Side: Credit, Amount: -1})
+		_, errOneSided := NewEntry("ref-1", EntryPaymentReceived, "BTC", "", time.Now(), debit)
+
+		assert.Equal(t, ErrEmptyReference, errReference)
+		assert.Equal(t, ErrEmptyCurrency, errCurrency)
+		assert.Equal(t, ErrInvalidAccount, errAccount)
+		assert.Equal(t, ErrInvalidAmount, errNegative)
+		assert.Equal(t, ErrTooFewPostings, errOneSided)
+	})
+}
+
+func TestPaymentEntries(t *testing.T) {
+	t.Run("receipt and fees", func(t *testing.T) {
+		receipt, errReceipt := NewPaymentReceivedEntry("pay-1", "ETH", 0.05, time.Now())
+		fees, errFees := NewPaymentFeesEntry("pay-1", "ETH", 0.00025, 0.0001, time.Now())
+
+		require.NoError(t, errReceipt)
+		require.NoError(t, errFees)
+		assert.Equal(t, "payment:pay-1:received", receipt.Reference)
+		assert.Equal(t, "pay-1", receipt.PaymentID)
+		assert.Equal(t, 0.05, receipt.Amount(AccountMerchantBalance))
+		assert.Equal(t, 0.05, receipt.Amount(AccountCustomerReceipts))
+		assert.Equal(t, "payment:pay-1:fees", fees.Reference)
+		assert.Equal(t, 0.00025, fees.Amount(AccountGatewayFees))
+		assert.Equal(t, 0.0001, fees.Amount(AccountNetworkFees))
+		assert.InDelta(t, -0.00035, fees.Amount(AccountMerchantBalance), 1e-12)
+	})
+
+	t.Run("fee adjustments", func(t *testing.T) {
+		raised, errRaised := NewPaymentFeesAdjustedEntry("pay-1", 0, "ETH", 0, 0.00002, time.Now())
+		lowered, errLowered := NewPaymentFeesAdjustedEntry("pay-1", 1, "ETH", -0.00005, 0.00002, time.Now())
+		_, errNone := NewPaymentFeesAdjustedEntry("pay-1", 2, "ETH", 0, 0, time.Now())
+
+		require.NoError(t, errRaised)
+		require.NoError(t, errLowered)
+		assert.Equal(t, "payment:pay-1:fees:0:adjusted", raised.Reference)
+		assert.Equal(t, 0.00002, raised.Amount(AccountNetworkFees))
+		assert.Equal(t, -0.00002, raised.Amount(AccountMerchantBalance))
+		assert.Equal(t, -0.00005, lowered.Amount(AccountGatewayFees))
+		assert.Equal(t, 0.00002, lowered.Amount(AccountNetworkFees))
+		assert.InDelta(t, 0.00003, lowered.Amount(AccountMerchantBalance), 1e-12)
+		assert.Equal(t, ErrInvalidAmount, errNone)
+	})
+
+	t.Run("invalid amounts", func(t *testing.T) {
+		_, errReceipt := NewPaymentReceivedEntry("pay-1", "ETH", 0, time.Now())
+		_, errFee := NewPaymentFeesEntry("pay-1", "ETH", -0.00025, 0, time.Now())
+		_, errNoFees := NewPaymentFeesEntry("pay-1", "ETH", 0, 0, time.Now())
+
+		assert.Equal(t, ErrInvalidAmount, errReceipt)
+		assert.Equal(t, ErrInvalidAmount, errFee)
+		assert.Equal(t, ErrTooFewPostings, errNoFees)
+	})
+
+	t.Run("refund issued and sent", func(t *testing.T) {
+		issued, errIssued := NewRefundIssuedEntry("pay-1", 0, "BTC", 0.001, time.Now())
+		sent, errSent := NewRefundSentEntry("pay-1", 0, "BTC", 0.001, 0.00002, time.Now())
+
+		require.NoError(t, errIssued)
+		require.NoError(t, errSent)
+		assert.Equal(t, "payment:pay-1:refund:0:issued", issued.Reference)
+		assert.Equal(t, -0.001, issued.Amount(AccountCustomerReceipts))
+		assert.Equal(t, 0.001, issued.Amount(AccountRefundsPayable))
+		assert.Equal(t, -0.001, sent.Amount(AccountRefundsPayable))
+		assert.Equal(t, 0.00002, sent.Amount(AccountNetworkFees))
+		assert.Equal(t, -0.00102, sent.Amount(AccountMerchantBalance))
+	})
+}
+
+// Tests for Balance
+
+func TestNewBalance(t *testing.T) {
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	receipt, _ := NewPaymentReceivedEntry("pay-1", "BTC", 0.002, day)
+	fees, _ := NewPaymentFeesEntry("pay-1", "BTC", 0.00001, 0, day)
+	issued, _ := NewRefundIssuedEntry("pay-1", 0, "BTC", 0.0005, day.Add(time.Hour))
+	sent, _ := NewRefundSentEntry("pay-1", 0, "BTC", 0.0005, 0.00002, day.AddDate(0, 0, 1))
+	other, _ := NewPaymentReceivedEntry("pay-2", "ETH", 0.05, day)
+	entries := []*Entry{receipt, fees, issued, sent, other}
+
+	t.Run("refund owed but not sent is held back", func(t *testing.T) {
+		balance := NewBalance("btc", day.AddDate(0, 0, 1), entries)
+
+		assert.Equal(t, 3, balance.Entries)
+		assert.InDelta(t, 0.00199, balance.MerchantBalance(), 1e-12)
+		assert.InDelta(t, 0.0015, balance.Accounts[AccountCustomerReceipts], 1e-12)
+		assert.InDelta(t, 0.00149, balance.Available(), 1e-12)
+	})
+
+	t.Run("sent refund leaves the merchant balance", func(t *testing.T) {
+		balance := NewBalance("BTC", day.AddDate(0, 0, 2), entries)
+
+		assert.Equal(t, 4, balance.Entries)
+		assert.InDelta(t, 0.00147, balance.MerchantBalance(), 1e-12)
+		assert.Equal(t, 0.0, balance.Accounts[AccountRefundsPayable])
+		assert.InDelta(t, 0.00147, balance.Available(), 1e-12)
+	})
+}
@@ -0,0 +1,33 @@
+package ledger
+
+import "errors"
+
+// Ledger domain errors organized by category
+
+// === Validation Errors ===
+var (
+	ErrEmptyCurrency        = errors.New("ledger currency cannot be empty")
+	ErrEmptyReference       = errors.New("ledger entry reference cannot be empty")
+	ErrInvalidAccount       = errors.New("ledger account is invalid")
+	ErrInvalidAmount        = errors.New("ledger amount must be positive")
+	ErrTooFewPostings       = errors.New("ledger entry needs at least one debit and one credit")
+	ErrUnbalancedEntry      = errors.New("ledger entry debits and credits do not balance")
+	ErrNoWithdrawals        = errors.New("payout batch needs at least one withdrawal")
+	ErrEmptyDestination     = errors.New("payout destination address cannot be empty")
+	ErrEmptyTransactionHash = errors.New("payout transaction hash cannot be empty")
+	ErrInvalidNetworkFee    = errors.New("network fee cannot be negative")
+	ErrInvalidBalanceDate   = errors.New("balance date must be formatted as YYYY-MM-DD")
+)
+
+// === Business Rule Errors ===
+var (
+	ErrPayoutBatchNotFound = errors.New("payout batch not found")
+	ErrWithdrawalNotFound  = errors.New("payout withdrawal not found")
+	ErrInsufficientBalance = errors.New("merchant balance is insufficient for the payout")
+)
+
+// === State Transition Errors ===
+var (
+	ErrWithdrawalAlreadySent = errors.New("payout withdrawal was already sent")
+	ErrPayoutBatchClosed     = errors.New("payout batch is no longer open")
+)
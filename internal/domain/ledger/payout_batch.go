@@ -0,0 +1,158 @@
+package ledger
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PayoutBatchStatus represents the lifecycle state of a payout batch
+type PayoutBatchStatus string
+
+const (
+	PayoutBatchOpen      PayoutBatchStatus = "OPEN"      // Withdrawals waiting to be sent
+	PayoutBatchCompleted PayoutBatchStatus = "COMPLETED" // Every withdrawal is on-chain
+	PayoutBatchCancelled PayoutBatchStatus = "CANCELLED" // Dropped before anything was sent
+)
+
+// Withdrawal is one on-chain transfer of a payout batch
+type Withdrawal struct {
+	DestinationAddress string
+	Amount             float64 // Crypto amount the merchant receives
+	NetworkFee         float64 // Paid on top of Amount, known once sent
+	TransactionHash    string  // Set once the withdrawal is broadcast
+	SentAt             *time.Time
+}
+
+// IsSent checks if the withdrawal transaction has been broadcast
+func (w Withdrawal) IsSent() bool {
+	return w.TransactionHash != ""
+}
+
+// PayoutBatch groups withdrawals of the merchant balance in one currency
+// (Aggregate Root). Each withdrawal is posted to the ledger once it is sent.
+type PayoutBatch struct {
+	ID          string
+	Currency    string
+	Withdrawals []Withdrawal
+	Status      PayoutBatchStatus
+	CreatedBy   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// NewPayoutBatch creates an open payout batch. Destination addresses are
+// expected to be validated for the currency by the caller.
+func NewPayoutBatch(currency string, withdrawals []Withdrawal, createdBy string) (*PayoutBatch, error) {
+	if currency == "" {
+		return nil, ErrEmptyCurrency
+	}
+
+	if len(withdrawals) == 0 {
+		return nil, ErrNoWithdrawals
+	}
+
+	kept := make([]Withdrawal, 0, len(withdrawals))
+	for _, withdrawal := range withdrawals {
+		if withdrawal.DestinationAddress == "" {
+			return nil, ErrEmptyDestination
+		}
+		if withdrawal.Amount <= 0 {
+			return nil, ErrInvalidAmount
+		}
+		kept = append(kept, Withdrawal{
+			DestinationAddress: withdrawal.DestinationAddress,
+			Amount:             withdrawal.Amount,
+		})
+	}
+
+	now := time.Now()
+
+	return &PayoutBatch{
+		ID:          uuid.New().String(),
+		Currency:    strings.ToUpper(currency),
+		Withdrawals: kept,
+		Status:      PayoutBatchOpen,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// Total returns the sum of the batch's withdrawal amounts
+func (b *PayoutBatch) Total() float64 {
+	total := 0.0
+	for _, withdrawal := range b.Withdrawals {
+		total += withdrawal.Amount
+	}
+	return total
+}
+
+// Unsent returns the sum of the withdrawal amounts not sent yet
+func (b *PayoutBatch) Unsent() float64 {
+	total := 0.0
+	for _, withdrawal := range b.Withdrawals {
+		if !withdrawal.IsSent() {
+			total += withdrawal.Amount
+		}
+	}
+	return total
+}
+
+// RecordWithdrawalSent records the on-chain transaction of a withdrawal and
+// completes the batch once every withdrawal is sent
+func (b *PayoutBatch) RecordWithdrawalSent(index int, transactionHash string, networkFee float64) error {
+	if b.Status != PayoutBatchOpen {
+		return ErrPayoutBatchClosed
+	}
+
+	if index < 0 || index >= len(b.Withdrawals) {
+		return ErrWithdrawalNotFound
+	}
+
+	if transactionHash == "" {
+		return ErrEmptyTransactionHash
+	}
+
+	if networkFee < 0 {
+		return ErrInvalidNetworkFee
+	}
+
+	withdrawal := &b.Withdrawals[index]
+	if withdrawal.IsSent() {
+		return ErrWithdrawalAlreadySent
+	}
+
+	now := time.Now()
+	withdrawal.TransactionHash = transactionHash
+	withdrawal.NetworkFee = networkFee
+	withdrawal.SentAt = &now
+
+	if b.Unsent() == 0 {
+		b.Status = PayoutBatchCompleted
+		b.CompletedAt = &now
+	}
+	b.UpdatedAt = now
+
+	return nil
+}
+
+// Cancel drops the batch; only possible before any withdrawal was sent
+func (b *PayoutBatch) Cancel() error {
+	if b.Status != PayoutBatchOpen {
+		return ErrPayoutBatchClosed
+	}
+
+	for _, withdrawal := range b.Withdrawals {
+		if withdrawal.IsSent() {
+			return ErrWithdrawalAlreadySent
+		}
+	}
+
+	b.Status = PayoutBatchCancelled
+	b.UpdatedAt = time.Now()
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package ledger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDestination = "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh"
+
+// createTestPayoutBatch creates an open BTC batch of two withdrawals
+func createTestPayoutBatch() *PayoutBatch {
+	batch, _ := NewPayoutBatch("btc", []Withdrawal{
+		{DestinationAddress: testDestination, Amount: 0.01},
+		{DestinationAddress: testDestination, Amount: 0.005},
+	}, "admin-1")
+	return batch
+}
+
+// Tests for PayoutBatch
+
+func TestNewPayoutBatch(t *testing.T) {
+	t.Run("create valid batch", func(t *testing.T) {
+		batch := createTestPayoutBatch()
+
+		assert.NotEmpty(t, batch.ID)
+		assert.Equal(t, "BTC", batch.Currency)
+		assert.Equal(t, PayoutBatchOpen, batch.Status)
+		assert.Equal(t, 0.015, batch.Total())
+		assert.Equal(t, 0.015, batch.Unsent())
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, errCurrency := NewPayoutBatch("", []Withdrawal{{DestinationAddress: testDestination, Amount: 1}}, "admin-1")
+		_, errEmpty := NewPayoutBatch("BTC", nil, "admin-1")
+		_, errDestination := NewPayoutBatch("BTC", []Withdrawal{{Amount: 1}}, "admin-1")
+		_, errAmount := NewPayoutBatch("BTC", []Withdrawal{{DestinationAddress: testDestination}}, "admin-1")
+
+		assert.Equal(t, ErrEmptyCurrency, errCurrency)
+		assert.Equal(t, ErrNoWithdrawals, errEmpty)
+		assert.Equal(t, ErrEmptyDestination, errDestination)
+		assert.Equal(t, ErrInvalidAmount, errAmount)
+	})
+}
+
+func TestPayoutBatchWithdrawals(t *testing.T) {
+	t.Run("completes once every withdrawal is sent", func(t *testing.T) {
+		batch := createTestPayoutBatch()
+
+		require.NoError(t, batch.RecordWithdrawalSent(1, "tx-2", 0.00003))
+		assert.Equal(t, PayoutBatchOpen, batch.Status)
+		assert.Equal(t, 0.01, batch.Unsent())
+
+		require.NoError(t, batch.RecordWithdrawalSent(0, "tx-1", 0.00002))
+		assert.Equal(t, PayoutBatchCompleted, batch.Status)
+		assert.NotNil(t, batch.CompletedAt)
+	})
+
+	t.Run("withdrawal cannot be sent twice", func(t *testing.T) {
+		batch := createTestPayoutBatch()
+		_ = batch.RecordWithdrawalSent(0, "tx-1", 0)
+
+		assert.Equal(t, ErrWithdrawalAlreadySent, batch.RecordWithdrawalSent(0, "tx-3", 0))
+		assert.Equal(t, ErrWithdrawalNotFound, batch.RecordWithdrawalSent(2, "tx-3", 0))
+		assert.Equal(t, ErrEmptyTransactionHash, batch.RecordWithdrawalSent(1, "", 0))
+		assert.Equal(t, ErrInvalidNetworkFee, batch.RecordWithdrawalSent(1, "tx-3", -1))
+	})
+
+	t.Run("sent withdrawal entry", func(t *testing.T) {
+		batch := createTestPayoutBatch()
+		_ = batch.RecordWithdrawalSent(0, "tx-1", 0.00002)
+
+		entry, err := NewPayoutSentEntry(batch, 0)
+		_, errUnsent := NewPayoutSentEntry(batch, 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, PayoutSentReference(batch.ID, 0), entry.Reference)
+		assert.Equal(t, batch.ID, entry.PayoutID)
+		assert.Equal(t, 0.01, entry.Amount(AccountMerchantPayouts))
+		assert.Equal(t, 0.00002, entry.Amount(AccountNetworkFees))
+		assert.Equal(t, -0.01002, entry.Amount(AccountMerchantBalance))
+		assert.Equal(t, ErrEmptyTransactionHash, errUnsent)
+	})
+
+	t.Run("cancel before anything was sent", func(t *testing.T) {
+		batch := createTestPayoutBatch()
+		sent := createTestPayoutBatch()
+		_ = sent.RecordWithdrawalSent(0, "tx-1", 0)
+
+		assert.NoError(t, batch.Cancel())
+		assert.Equal(t, PayoutBatchCancelled, batch.Status)
+		assert.Equal(t, ErrPayoutBatchClosed, batch.RecordWithdrawalSent(0, "tx-1", 0))
+		assert.Equal(t, ErrWithdrawalAlreadySent, sent.Cancel())
+	})
+}
@@ -13,4 +13,23 @@ var (
 	ErrOrderMustHaveItems      = errors.New("order must have at least one item")
 	ErrInconsistentCurrency    = errors.New("all items must have the same currency")
 	ErrCannotCancelFulfilledOrder = errors.New("cannot cancel a fulfilled order")
+	ErrEmptyPaymentID          = errors.New("payment ID cannot be empty")
+	ErrCancellationRequiresRefund = errors.New("paid order must be cancelled through a refund")
+	ErrOrderNotFound           = errors.New("order not found")
+	ErrInvalidOrderID          = errors.New("order ID is invalid")
+	ErrOrderNotReturnable      = errors.New("order items cannot be returned")
+	ErrEmptyReturnItems        = errors.New("return must have at least one item")
+	ErrEmptyReturnReason       = errors.New("return reason cannot be empty")
+	ErrReturnQuantityExceeded  = errors.New("return quantity exceeds returnable quantity")
+	ErrInvalidReturnTransition = errors.New("invalid return status transition")
+	ErrInvalidRefundAmount     = errors.New("refund amount must be positive")
+	ErrReturnNotFound          = errors.New("return request not found")
+	ErrReturnOrderMismatch     = errors.New("return request belongs to another order")
+	ErrDuplicateItem           = errors.New("order cannot contain the same product twice")
+	ErrItemNoteTooLong         = errors.New("item note is too long")
+	ErrPriceConfirmationRequired = errors.New("order prices have changed and must be confirmed")
+	ErrInvalidExchangeRate     = errors.New("exchange rate is invalid")
+	ErrExchangeRateMismatch    = errors.New("exchange rate does not match the currencies involved")
+	ErrExchangeRateUnavailable = errors.New("exchange rate is unavailable")
+	ErrStaleExchangeRate       = errors.New("exchange rate is too old to use")
 )
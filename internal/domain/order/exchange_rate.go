@@ -0,0 +1,51 @@
+package order
+
+import (
+	"math"
+	"time"
+)
+
+// ExchangeRate is a fiat conversion rate between two currencies at a point in time
+type ExchangeRate struct {
+	From      string    // Currency converted from (e.g. USD)
+	To        string    // Currency converted to (e.g. EUR)
+	Rate      float64   // Units of To per unit of From
+	Source    string    // Provider the rate came from
+	FetchedAt time.Time // When the provider published the rate
+}
+
+// NewExchangeRate creates a new exchange rate with validation
+func NewExchangeRate(from, to string, rate float64, source string, fetchedAt time.Time) (ExchangeRate, error) {
+	if from == "" || to == "" || from == to {
+		return ExchangeRate{}, ErrInvalidExchangeRate
+	}
+
+	if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return ExchangeRate{}, ErrInvalidExchangeRate
+	}
+
+	return ExchangeRate{
+		From:      from,
+		To:        to,
+		Rate:      rate,
+		Source:    source,
+		FetchedAt: fetchedAt,
+	}, nil
+}
+
+// Convert converts money in the From currency to the To currency, rounded to cents
+func (r ExchangeRate) Convert(m Money) (Money, error) {
+	if m.Currency != r.From {
+		return Money{}, ErrExchangeRateMismatch
+	}
+
+	return Money{
+		Amount:   math.Round(m.Amount*r.Rate*100) / 100,
+		Currency: r.To,
+	}, nil
+}
+
+// IsStale checks if the rate is older than maxAge
+func (r ExchangeRate) IsStale(maxAge time.Duration) bool {
+	return time.Since(r.FetchedAt) > maxAge
+}
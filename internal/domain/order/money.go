@@ -1,6 +1,9 @@
 package order
 
-import "errors"
+import (
+	"errors"
+	"math"
+)
 
 // Money represents a monetary value with currency
 type Money struct {
@@ -20,3 +23,9 @@ func NewMoney(amount float64, currency string) (Money, error) {
 	
 	return Money{Amount: amount, Currency: currency}, nil
 }
+
+// HasAmount checks if the money amounts to the given value in minor units
+// (cents), so float rounding in sums does not make equal totals differ
+func (m Money) HasAmount(amount float64) bool {
+	return math.Round(m.Amount*100) == math.Round(amount*100)
+}
@@ -2,6 +2,7 @@ package order
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,6 +20,10 @@ type Order struct {
     UpdatedAt     time.Time
     PaymentID     *string // Optional, set when payment is created
     CompletedAt   *time.Time
+    StatusHistory []StatusChange // Every status transition, oldest first
+    ExchangeRates []ExchangeRate // Rates used to convert prices into the order currency, one per source currency
+    Sandbox       bool           // Test order; only sandbox (testnet) payments can pay it
+    StockReserved bool           // Stock is reserved for every line; set at checkout, cleared once released
 }
 
 // - NewOrder creates a new order with the given customer ID and items
@@ -49,6 +54,40 @@ func NewOrder(customerID string, items []OrderItem)(*Order,error){
 }
 
 
+// Currency returns the currency the order was locked to at creation
+func (o *Order) Currency() string {
+    return o.TotalAmount.Currency
+}
+
+// RecordExchangeRate records the rate used to convert prices into the order currency.
+// Only one rate is kept per source currency, so every line converted from it uses the same rate.
+func (o *Order) RecordExchangeRate(rate ExchangeRate) error {
+    if rate.To != o.Currency() {
+        return ErrExchangeRateMismatch
+    }
+
+    if existing, ok := o.ExchangeRateFrom(rate.From); ok {
+        if existing.Rate != rate.Rate {
+            return ErrExchangeRateMismatch
+        }
+        return nil
+    }
+
+    o.ExchangeRates = append(o.ExchangeRates, rate)
+    o.UpdatedAt = time.Now()
+    return nil
+}
+
+// ExchangeRateFrom returns the recorded rate for converting from the given currency
+func (o *Order) ExchangeRateFrom(currency string) (ExchangeRate, bool) {
+    for _, rate := range o.ExchangeRates {
+        if rate.From == currency {
+            return rate, true
+        }
+    }
+    return ExchangeRate{}, false
+}
+
 //calculateTotalAmount calculates the total amount of the order
 func calculateTotalAmount(items []OrderItem) (Money, error) {
     if len(items) == 0 {
@@ -72,56 +111,161 @@ func calculateTotalAmount(items []OrderItem) (Money, error) {
 }
 
 
+// TransitionTo moves the order to the given status through the state machine,
+// recording who triggered the change and why
+func (o *Order) TransitionTo(status OrderStatus, actor, reason string) error {
+    return DefaultStateMachine.Apply(o, status, actor, reason)
+}
+
+// CanTransitionTo checks if the order can currently move to the given status
+func (o *Order) CanTransitionTo(status OrderStatus) bool {
+    return DefaultStateMachine.CanTransition(o, status)
+}
+
+// - MarkAsPendingPayment
+func (o *Order) MarkAsPendingPayment() error {
+    return o.TransitionTo(StatusPendingPayment, SystemActor, "checkout started")
+}
+
 // - MarkAsPaid
 func (o *Order) MarkAsPaid(paymentID string) error {
  if paymentID == "" {
-        return errors.New("payment ID cannot be empty")
+        return ErrEmptyPaymentID
     }
     
-    // Only allow transition from Created to Paid
-    if o.Status != StatusCreated {
+    // Only allow transition from Created or PendingPayment to Paid
+    if !DefaultStateMachine.IsDeclared(o.Status, StatusPaid) {
         return ErrInvalidStatusTransition
     }
     
     // Update order state
-    o.Status = StatusPaid
     o.PaymentID = &paymentID
-    o.UpdatedAt = time.Now()
+    return o.TransitionTo(StatusPaid, SystemActor, "payment received")
+}
+// LinkPayment records the payment opened for an order awaiting payment. A
+// later payment, e.g. in another coin, replaces the link until one is paid.
+func (o *Order) LinkPayment(paymentID string) error {
+    if paymentID == "" {
+        return ErrEmptyPaymentID
+    }
     
+    if o.Status != StatusPendingPayment {
+        return ErrCannotModifyOrder
+    }
+    
+    o.PaymentID = &paymentID
+    o.UpdatedAt = time.Now()
     return nil
 }
+
 // - MarkAsFulfilled
 func (o *Order) MarkAsFulfilled() error {
-
     //Only allow transition from Paid to Fulfilled
-    if o.Status != StatusPaid {
-        return ErrInvalidStatusTransition
+    if err := o.TransitionTo(StatusFulfilled, SystemActor, ""); err != nil {
+        return err
     }
-    // Update order state
-    o.Status = StatusFulfilled
-    now := time.Now()
+    now := o.UpdatedAt
     o.CompletedAt = &now
-    o.UpdatedAt = now
     return nil
 }
+// - MarkAsShipped
+func (o *Order) MarkAsShipped() error {
+    return o.TransitionTo(StatusShipped, SystemActor, "")
+}
+// - MarkAsDelivered
+func (o *Order) MarkAsDelivered() error {
+    return o.TransitionTo(StatusDelivered, SystemActor, "")
+}
 // - Cancel
 func (o*Order)Cancel()error{
     // Cannot cancel fulfilled orders (already shipped/completed)
-    if o.Status == StatusFulfilled {
+    if o.Status.IsFulfilled() {
+        return ErrCannotCancelFulfilledOrder
+    }
+    
+    // Paid orders must refund the customer first (see RequestCancellation)
+    if o.Status == StatusPaid {
+        return ErrCancellationRequiresRefund
+    }
+    
+    // Only allow cancellation where the state machine declares it
+    return o.TransitionTo(StatusCancelled, SystemActor, "")
+}
+
+// RequestCancellation cancels a paid order pending the customer's refund
+func (o *Order) RequestCancellation(actor, reason string) error {
+    if o.Status.IsFulfilled() {
         return ErrCannotCancelFulfilledOrder
     }
     
-    // Only allow cancellation from Created or Paid status
-    if o.Status != StatusCreated && o.Status != StatusPaid {
+    return o.TransitionTo(StatusCancellationPendingRefund, actor, reason)
+}
+
+// CompleteCancellation finishes a cancellation once the refund has been sent
+func (o *Order) CompleteCancellation(actor string) error {
+    if o.Status != StatusCancellationPendingRefund {
         return ErrInvalidStatusTransition
     }
     
-    // Update order state
-    o.Status = StatusCancelled
-    o.UpdatedAt = time.Now()
+    return o.TransitionTo(StatusCancelled, actor, "refund sent")
+}
+
+// HoldForPaymentReview pauses fulfilment while the order's payment is under review
+// (e.g. its transaction was reorganised out of the chain or replaced)
+func (o *Order) HoldForPaymentReview(reason string) error {
+    return o.TransitionTo(StatusOnHold, SystemActor, reason)
+}
+
+// ReleasePaymentHold resumes the order once its payment is confirmed again
+func (o *Order) ReleasePaymentHold(actor string) error {
+    if o.Status != StatusOnHold {
+        return ErrInvalidStatusTransition
+    }
     
+    return o.TransitionTo(o.StatusBeforeHold(), actor, "payment re-confirmed")
+}
+
+// MarkAsSandbox makes the order a test order, before any payment is opened for it
+func (o *Order) MarkAsSandbox() error {
+    if o.PaymentID != nil || (o.Status != StatusCreated && o.Status != StatusPendingPayment) {
+        return ErrCannotModifyOrder
+    }
+
+    o.Sandbox = true
+    o.UpdatedAt = time.Now()
     return nil
+}
+
+// MarkStockReserved records that the stock of every line was reserved for the order
+func (o *Order) MarkStockReserved() {
+    o.StockReserved = true
+    o.UpdatedAt = time.Now()
+}
+
+// ClearStockReservation records that the order's reserved stock was released
+func (o *Order) ClearStockReservation() {
+    o.StockReserved = false
+    o.UpdatedAt = time.Now()
+}
 
+// IsOnHold checks if fulfilment is paused for a payment review
+func (o *Order) IsOnHold() bool {
+    return o.Status == StatusOnHold
+}
+
+// StatusBeforeHold returns the status the order had when it was last put on hold
+func (o *Order) StatusBeforeHold() OrderStatus {
+    for i := len(o.StatusHistory) - 1; i >= 0; i-- {
+        if o.StatusHistory[i].To == StatusOnHold {
+            return o.StatusHistory[i].From
+        }
+    }
+    return ""
+}
+
+// IsAwaitingCancellationRefund checks if the order is cancelled but the refund is still outstanding
+func (o *Order) IsAwaitingCancellationRefund() bool {
+    return o.Status == StatusCancellationPendingRefund
 }
 
 // AddItem adds an item to the order if the order can be modified
@@ -198,4 +342,181 @@ func (o*Order) RemoveItem(productID uuid.UUID) error{
         }
     }
     return ErrItemNotFound
-}
\ No newline at end of file
+}
+
+// UpdateItemQuantity sets the quantity of an order line, removing the line at zero
+func (o *Order) UpdateItemQuantity(productID uuid.UUID, quantity int) error {
+    if o.Status != StatusCreated {
+        return ErrCannotModifyOrder
+    }
+    
+    if quantity < 0 {
+        return ErrInvalidQuantity
+    }
+    
+    if quantity == 0 {
+        return o.RemoveItem(productID)
+    }
+    
+    for i, existingItem := range o.Items {
+        if existingItem.ProductID == productID {
+            o.Items[i] = existingItem.withQuantity(quantity)
+            return o.recalculateTotal()
+        }
+    }
+    return ErrItemNotFound
+}
+
+// DecrementItemQuantity removes one unit from an order line, removing the line at zero
+func (o *Order) DecrementItemQuantity(productID uuid.UUID) error {
+    item, ok := o.findItem(productID)
+    if !ok {
+        return ErrItemNotFound
+    }
+    return o.UpdateItemQuantity(productID, item.Quantity-1)
+}
+
+// SetItemNote attaches a note to an order line (an empty note clears it)
+func (o *Order) SetItemNote(productID uuid.UUID, note string) error {
+    if o.Status != StatusCreated {
+        return ErrCannotModifyOrder
+    }
+    
+    note = strings.TrimSpace(note)
+    if len(note) > MaxItemNoteLength {
+        return ErrItemNoteTooLong
+    }
+    
+    for i := range o.Items {
+        if o.Items[i].ProductID == productID {
+            o.Items[i].Note = note
+            o.UpdatedAt = time.Now()
+            return nil
+        }
+    }
+    return ErrItemNotFound
+}
+
+// ReplaceItems swaps all order lines in one step.
+// Every line is validated first; on error the order is left unchanged.
+func (o *Order) ReplaceItems(items []OrderItem) error {
+    if o.Status != StatusCreated {
+        return ErrCannotModifyOrder
+    }
+    
+    if len(items) == 0 {
+        return ErrOrderMustHaveItems
+    }
+    
+    seen := make(map[uuid.UUID]bool, len(items))
+    replacement := make([]OrderItem, 0, len(items))
+    for _, item := range items {
+        if item.Quantity <= 0 {
+            return ErrInvalidQuantity
+        }
+        if seen[item.ProductID] {
+            return ErrDuplicateItem
+        }
+        if len(item.Note) > MaxItemNoteLength {
+            return ErrItemNoteTooLong
+        }
+        seen[item.ProductID] = true
+        replacement = append(replacement, item.withQuantity(item.Quantity))
+    }
+    
+    // Recalculate once for the whole set
+    totalAmount, err := calculateTotalAmount(replacement)
+    if err != nil {
+        return err
+    }
+    
+    o.Items = replacement
+    o.TotalAmount = totalAmount
+    o.UpdatedAt = time.Now()
+    
+    return nil
+}
+
+// recalculateTotal refreshes the total amount after a line change
+func (o *Order) recalculateTotal() error {
+    totalAmount, err := calculateTotalAmount(o.Items)
+    if err != nil {
+        return err
+    }
+    
+    o.TotalAmount = totalAmount
+    o.UpdatedAt = time.Now()
+    return nil
+}
+
+// IsReturnable checks if items of the order can be returned
+func (o *Order) IsReturnable() bool {
+    return o.Status.IsFulfilled() || o.Status == StatusPartiallyRefunded
+}
+
+// RecordReturn books a refunded return against the order lines and moves the
+// order to PARTIALLY_REFUNDED, or REFUNDED once every unit has come back
+func (o *Order) RecordReturn(r *ReturnRequest, actor string) error {
+    if r.OrderID != o.ID {
+        return ErrReturnOrderMismatch
+    }
+    
+    if r.Status != ReturnStatusRefunded {
+        return ErrInvalidReturnTransition
+    }
+    
+    // Validate every line before changing anything
+    for _, item := range r.Items {
+        orderItem, ok := o.findItem(item.ProductID)
+        if !ok {
+            return ErrItemNotFound
+        }
+        if item.Quantity > orderItem.ReturnableQuantity() {
+            return ErrReturnQuantityExceeded
+        }
+    }
+    
+    target := StatusPartiallyRefunded
+    if o.returnCompletesOrder(r) {
+        target = StatusRefunded
+    }
+    
+    if !o.CanTransitionTo(target) {
+        return ErrInvalidStatusTransition
+    }
+    
+    for _, item := range r.Items {
+        for i := range o.Items {
+            if o.Items[i].ProductID == item.ProductID {
+                o.Items[i].ReturnedQuantity += item.Quantity
+            }
+        }
+    }
+    
+    return o.TransitionTo(target, actor, "return "+r.ID.String()+" refunded")
+}
+
+// returnCompletesOrder checks if the return brings back every remaining unit
+func (o *Order) returnCompletesOrder(r *ReturnRequest) bool {
+    returned := make(map[uuid.UUID]int)
+    for _, item := range r.Items {
+        returned[item.ProductID] += item.Quantity
+    }
+    
+    for _, item := range o.Items {
+        if item.ReturnableQuantity() > returned[item.ProductID] {
+            return false
+        }
+    }
+    return true
+}
+
+// findItem returns the order line for a product
+func (o *Order) findItem(productID uuid.UUID) (OrderItem, bool) {
+    for _, item := range o.Items {
+        if item.ProductID == productID {
+            return item, true
+        }
+    }
+    return OrderItem{}, false
+}
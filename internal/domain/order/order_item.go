@@ -7,11 +7,18 @@ import (
 // OrderItem represents an item within an order
 type OrderItem struct {
     ProductID  uuid.UUID
+    ProductName string // Product name captured when the item was added
+    ProductSKU string  // Product SKU captured when the item was added
     Quantity   int
     UnitPrice  Money
     Subtotal   Money
+    ReturnedQuantity int // Units already returned by the customer
+    Note       string // Optional line note (e.g. gift message, engraving text)
 }
 
+// MaxItemNoteLength is the maximum length of an order line note
+const MaxItemNoteLength = 500
+
 // NewOrderItem creates a new order item with validation
 func NewOrderItem(productID uuid.UUID, quantity int, unitPrice Money) (OrderItem, error) {
     if quantity <= 0 {
@@ -31,4 +38,32 @@ func NewOrderItem(productID uuid.UUID, quantity int, unitPrice Money) (OrderItem
         UnitPrice: unitPrice,
         Subtotal:  subtotal,
     }, nil
-}
\ No newline at end of file
+}
+
+// NewOrderItemWithSnapshot creates an order item that also records the
+// product's name and SKU as they were when the item was added
+func NewOrderItemWithSnapshot(productID uuid.UUID, productName, productSKU string, quantity int, unitPrice Money) (OrderItem, error) {
+    item, err := NewOrderItem(productID, quantity, unitPrice)
+    if err != nil {
+        return OrderItem{}, err
+    }
+
+    item.ProductName = productName
+    item.ProductSKU = productSKU
+    return item, nil
+}
+
+// withQuantity returns a copy of the item with a new quantity and recalculated subtotal
+func (item OrderItem) withQuantity(quantity int) OrderItem {
+    item.Quantity = quantity
+    item.Subtotal = Money{
+        Amount:   item.UnitPrice.Amount * float64(quantity),
+        Currency: item.UnitPrice.Currency,
+    }
+    return item
+}
+
+// ReturnableQuantity returns how many units of the line can still be returned
+func (item OrderItem) ReturnableQuantity() int {
+    return item.Quantity - item.ReturnedQuantity
+}
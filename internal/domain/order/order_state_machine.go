@@ -0,0 +1,204 @@
+package order
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SystemActor is recorded in the status history when no user triggered the change
+const SystemActor = "system"
+
+// TransitionGuard decides whether an order may take a transition.
+// It returns nil when the transition is allowed, or the reason it is not.
+type TransitionGuard func(o *Order) error
+
+// Transition declares an allowed move between two order statuses
+type Transition struct {
+	From  OrderStatus
+	To    OrderStatus
+	Name  string          // Short label used in diagrams (e.g. "pay", "ship")
+	Guard TransitionGuard // Optional extra check run before the transition
+}
+
+// StatusChange records a single status transition in the order history
+type StatusChange struct {
+	From      OrderStatus
+	To        OrderStatus
+	Actor     string // Who triggered the change (user ID, admin ID or "system")
+	Reason    string // Optional free-text reason
+	ChangedAt time.Time
+}
+
+// StateMachine holds the table of allowed order status transitions
+type StateMachine struct {
+	transitions []Transition
+}
+
+// NewStateMachine creates a state machine from a transition table
+func NewStateMachine(transitions []Transition) *StateMachine {
+	table := make([]Transition, len(transitions))
+	copy(table, transitions)
+
+	return &StateMachine{transitions: table}
+}
+
+// DefaultStateMachine is the transition table used by Order
+var DefaultStateMachine = NewStateMachine([]Transition{
+	{From: StatusCreated, To: StatusPendingPayment, Name: "checkout", Guard: requireItems},
+	{From: StatusCreated, To: StatusPaid, Name: "pay", Guard: requirePayment},
+	{From: StatusCreated, To: StatusCancelled, Name: "cancel"},
+
+	{From: StatusPendingPayment, To: StatusPaid, Name: "pay", Guard: requirePayment},
+	{From: StatusPendingPayment, To: StatusCreated, Name: "payment abandoned"},
+	{From: StatusPendingPayment, To: StatusCancelled, Name: "cancel"},
+
+	{From: StatusPaid, To: StatusFulfilled, Name: "fulfil"},
+	{From: StatusPaid, To: StatusCancellationPendingRefund, Name: "cancel", Guard: requirePayment},
+	{From: StatusPaid, To: StatusPartiallyRefunded, Name: "partial refund", Guard: requirePayment},
+	{From: StatusPaid, To: StatusRefunded, Name: "refund", Guard: requirePayment},
+	{From: StatusPaid, To: StatusOnHold, Name: "payment held", Guard: requirePayment},
+
+	{From: StatusFulfilled, To: StatusShipped, Name: "ship"},
+	{From: StatusFulfilled, To: StatusPartiallyRefunded, Name: "partial refund", Guard: requirePayment},
+	{From: StatusFulfilled, To: StatusRefunded, Name: "refund", Guard: requirePayment},
+	{From: StatusFulfilled, To: StatusOnHold, Name: "payment held", Guard: requirePayment},
+
+	{From: StatusOnHold, To: StatusPaid, Name: "payment re-confirmed", Guard: heldFrom(StatusPaid)},
+	{From: StatusOnHold, To: StatusFulfilled, Name: "payment re-confirmed", Guard: heldFrom(StatusFulfilled)},
+	{From: StatusOnHold, To: StatusCancelled, Name: "payment reversed"},
+
+	{From: StatusShipped, To: StatusDelivered, Name: "deliver"},
+	{From: StatusShipped, To: StatusPartiallyRefunded, Name: "partial refund", Guard: requirePayment},
+	{From: StatusShipped, To: StatusRefunded, Name: "refund", Guard: requirePayment},
+
+	{From: StatusDelivered, To: StatusPartiallyRefunded, Name: "return / partial refund", Guard: requirePayment},
+	{From: StatusDelivered, To: StatusRefunded, Name: "return / refund", Guard: requirePayment},
+
+	{From: StatusPartiallyRefunded, To: StatusPartiallyRefunded, Name: "partial refund", Guard: requirePayment},
+	{From: StatusPartiallyRefunded, To: StatusRefunded, Name: "refund", Guard: requirePayment},
+
+	{From: StatusCancellationPendingRefund, To: StatusCancelled, Name: "refund sent"},
+})
+
+// Guards
+
+// requireItems allows the transition only for orders with at least one item
+func requireItems(o *Order) error {
+	if len(o.Items) == 0 {
+		return ErrOrderMustHaveItems
+	}
+	return nil
+}
+
+// requirePayment allows the transition only for orders linked to a payment
+func requirePayment(o *Order) error {
+	if o.PaymentID == nil || *o.PaymentID == "" {
+		return ErrEmptyPaymentID
+	}
+	return nil
+}
+
+// heldFrom allows leaving ON_HOLD only back to the status the order was held from
+func heldFrom(status OrderStatus) TransitionGuard {
+	return func(o *Order) error {
+		if o.StatusBeforeHold() != status {
+			return ErrInvalidStatusTransition
+		}
+		return nil
+	}
+}
+
+// find returns the transition declared between two statuses
+func (sm *StateMachine) find(from, to OrderStatus) (Transition, bool) {
+	for _, t := range sm.transitions {
+		if t.From == from && t.To == to {
+			return t, true
+		}
+	}
+	return Transition{}, false
+}
+
+// IsDeclared checks if the table contains a transition between two statuses (guards are not run)
+func (sm *StateMachine) IsDeclared(from, to OrderStatus) bool {
+	_, ok := sm.find(from, to)
+	return ok
+}
+
+// CanTransition checks if the order can move to the target status right now
+func (sm *StateMachine) CanTransition(o *Order, to OrderStatus) bool {
+	t, ok := sm.find(o.Status, to)
+	if !ok {
+		return false
+	}
+
+	if t.Guard != nil && t.Guard(o) != nil {
+		return false
+	}
+
+	return true
+}
+
+// AllowedTransitions returns the statuses reachable from the given status
+func (sm *StateMachine) AllowedTransitions(from OrderStatus) []OrderStatus {
+	var targets []OrderStatus
+	for _, t := range sm.transitions {
+		if t.From == from {
+			targets = append(targets, t.To)
+		}
+	}
+	return targets
+}
+
+// Apply moves the order to the target status and records the change in its history
+func (sm *StateMachine) Apply(o *Order, to OrderStatus, actor, reason string) error {
+	t, ok := sm.find(o.Status, to)
+	if !ok {
+		return ErrInvalidStatusTransition
+	}
+
+	if t.Guard != nil {
+		if err := t.Guard(o); err != nil {
+			return err
+		}
+	}
+
+	if actor == "" {
+		actor = SystemActor
+	}
+
+	now := time.Now()
+	o.StatusHistory = append(o.StatusHistory, StatusChange{
+		From:      o.Status,
+		To:        to,
+		Actor:     actor,
+		Reason:    reason,
+		ChangedAt: now,
+	})
+	o.Status = to
+	o.UpdatedAt = now
+
+	return nil
+}
+
+// Diagram exports the transition table as a Mermaid state diagram
+func (sm *StateMachine) Diagram() string {
+	var b strings.Builder
+
+	b.WriteString("stateDiagram-v2\n")
+	b.WriteString(fmt.Sprintf("    [*] --> %s\n", StatusCreated))
+
+	for _, t := range sm.transitions {
+		if t.Name != "" {
+			b.WriteString(fmt.Sprintf("    %s --> %s: %s\n", t.From, t.To, t.Name))
+		} else {
+			b.WriteString(fmt.Sprintf("    %s --> %s\n", t.From, t.To))
+		}
+	}
+
+	for _, status := range []OrderStatus{StatusRefunded, StatusCancelled} {
+		b.WriteString(fmt.Sprintf("    %s --> [*]\n", status))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,200 @@
+package order
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests for the order state machine (order_state_machine.go)
+
+func TestStateMachineTransitions(t *testing.T) {
+	t.Run("full happy path is recorded in history", func(t *testing.T) {
+		// Arrange
+		order, _ := createTestOrder()
+
+		// Act
+		assert.NoError(t, order.MarkAsPendingPayment())
+		assert.NoError(t, order.MarkAsPaid("payment123"))
+		assert.NoError(t, order.MarkAsFulfilled())
+		assert.NoError(t, order.MarkAsShipped())
+		assert.NoError(t, order.MarkAsDelivered())
+
+		// Assert
+		assert.Equal(t, StatusDelivered, order.Status)
+		assert.Len(t, order.StatusHistory, 5)
+		assert.Equal(t, StatusCreated, order.StatusHistory[0].From)
+		assert.Equal(t, StatusPendingPayment, order.StatusHistory[0].To)
+		assert.Equal(t, SystemActor, order.StatusHistory[0].Actor)
+		assert.Equal(t, StatusDelivered, order.StatusHistory[4].To)
+		assert.False(t, order.StatusHistory[4].ChangedAt.IsZero())
+	})
+
+	t.Run("transition records actor and reason", func(t *testing.T) {
+		// Arrange
+		order, _ := createTestOrder()
+
+		// Act
+		err := order.TransitionTo(StatusCancelled, "admin-42", "customer called support")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, StatusCancelled, order.Status)
+		assert.Len(t, order.StatusHistory, 1)
+		assert.Equal(t, "admin-42", order.StatusHistory[0].Actor)
+		assert.Equal(t, "customer called support", order.StatusHistory[0].Reason)
+	})
+
+	t.Run("undeclared transition is rejected without history entry", func(t *testing.T) {
+		// Arrange
+		order, _ := createTestOrder()
+
+		// Act
+		err := order.MarkAsShipped()
+
+		// Assert
+		assert.Equal(t, ErrInvalidStatusTransition, err)
+		assert.Equal(t, StatusCreated, order.Status)
+		assert.Empty(t, order.StatusHistory)
+	})
+
+	t.Run("guard blocks refund without payment", func(t *testing.T) {
+		// Arrange
+		order, _ := createTestOrder()
+		order.Status = StatusPaid // Simulate inconsistent state without payment link
+
+		// Act
+		err := order.TransitionTo(StatusRefunded, "admin-42", "")
+
+		// Assert
+		assert.Equal(t, ErrEmptyPaymentID, err)
+		assert.Equal(t, StatusPaid, order.Status)
+		assert.False(t, order.CanTransitionTo(StatusRefunded))
+	})
+
+	t.Run("partial refunds can repeat before a full refund", func(t *testing.T) {
+		// Arrange
+		order, _ := createTestOrder()
+		_ = order.MarkAsPaid("payment123")
+
+		// Act & Assert
+		assert.NoError(t, order.TransitionTo(StatusPartiallyRefunded, "admin-42", "item 1 returned"))
+		assert.NoError(t, order.TransitionTo(StatusPartiallyRefunded, "admin-42", "item 2 returned"))
+		assert.NoError(t, order.TransitionTo(StatusRefunded, "admin-42", "remaining items returned"))
+		assert.True(t, order.Status.IsFinal())
+	})
+
+	t.Run("cannot cancel shipped order", func(t *testing.T) {
+		// Arrange
+		order, _ := createTestOrder()
+		_ = order.MarkAsPaid("payment123")
+		_ = order.MarkAsFulfilled()
+		_ = order.MarkAsShipped()
+
+		// Act
+		err := order.Cancel()
+
+		// Assert
+		assert.Equal(t, ErrCannotCancelFulfilledOrder, err)
+		assert.Equal(t, StatusShipped, order.Status)
+	})
+}
+
+func TestOrderPaymentHold(t *testing.T) {
+	t.Run("paid order is held and released", func(t *testing.T) {
+		// Arrange
+		order, _ := createTestOrder()
+		_ = order.MarkAsPaid("payment123")
+
+		// Act
+		errHold := order.HoldForPaymentReview("confirmations dropped")
+		errFulfil := order.MarkAsFulfilled()
+		errRelease := order.ReleasePaymentHold("")
+
+		// Assert
+		assert.NoError(t, errHold)
+		assert.Equal(t, ErrInvalidStatusTransition, errFulfil)
+		assert.NoError(t, errRelease)
+		assert.Equal(t, StatusPaid, order.Status)
+		assert.Equal(t, "payment re-confirmed", order.StatusHistory[len(order.StatusHistory)-1].Reason)
+	})
+
+	t.Run("fulfilled order returns to fulfilled", func(t *testing.T) {
+		// Arrange
+		order, _ := createTestOrder()
+		_ = order.MarkAsPaid("payment123")
+		_ = order.MarkAsFulfilled()
+		_ = order.HoldForPaymentReview("transaction replaced")
+
+		// Act
+		errPaid := order.TransitionTo(StatusPaid, "", "")
+		errRelease := order.ReleasePaymentHold("")
+
+		// Assert
+		assert.Equal(t, ErrInvalidStatusTransition, errPaid)
+		assert.NoError(t, errRelease)
+		assert.Equal(t, StatusFulfilled, order.Status)
+	})
+
+	t.Run("shipped order cannot be held", func(t *testing.T) {
+		// Arrange
+		order, _ := createTestOrder()
+		_ = order.MarkAsPaid("payment123")
+		_ = order.MarkAsFulfilled()
+		_ = order.MarkAsShipped()
+
+		// Act
+		err := order.HoldForPaymentReview("confirmations dropped")
+
+		// Assert
+		assert.Equal(t, ErrInvalidStatusTransition, err)
+		assert.Equal(t, StatusShipped, order.Status)
+	})
+
+	t.Run("held order is cancelled when the payment is reversed", func(t *testing.T) {
+		// Arrange
+		order, _ := createTestOrder()
+		_ = order.MarkAsPaid("payment123")
+		_ = order.HoldForPaymentReview("transaction dropped")
+
+		// Act
+		err := order.Cancel()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, StatusCancelled, order.Status)
+	})
+}
+
+func TestCustomStateMachine(t *testing.T) {
+	errBlocked := errors.New("blocked")
+	sm := NewStateMachine([]Transition{
+		{From: StatusCreated, To: StatusPaid, Name: "pay"},
+		{From: StatusPaid, To: StatusShipped, Name: "ship", Guard: func(o *Order) error { return errBlocked }},
+	})
+
+	t.Run("allowed transitions", func(t *testing.T) {
+		assert.Equal(t, []OrderStatus{StatusPaid}, sm.AllowedTransitions(StatusCreated))
+		assert.Empty(t, sm.AllowedTransitions(StatusDelivered))
+	})
+
+	t.Run("guard error is returned", func(t *testing.T) {
+		order, _ := createTestOrder()
+		assert.NoError(t, sm.Apply(order, StatusPaid, "", ""))
+
+		err := sm.Apply(order, StatusShipped, "", "")
+
+		assert.Equal(t, errBlocked, err)
+		assert.Equal(t, StatusPaid, order.Status)
+	})
+
+	t.Run("diagram export", func(t *testing.T) {
+		diagram := sm.Diagram()
+
+		assert.Contains(t, diagram, "stateDiagram-v2")
+		assert.Contains(t, diagram, "[*] --> CREATED")
+		assert.Contains(t, diagram, "CREATED --> PAID: pay")
+		assert.Contains(t, diagram, "PAID --> SHIPPED: ship")
+	})
+}
@@ -4,8 +4,41 @@ package order
 type OrderStatus string
 
 const (
-	StatusCreated   OrderStatus = "CREATED"
-	StatusPaid      OrderStatus = "PAID"
-	StatusFulfilled OrderStatus = "FULFILLED"  
-	StatusCancelled OrderStatus = "CANCELLED"
+	StatusCreated                   OrderStatus = "CREATED"                     // Order created, items can still be changed
+	StatusPendingPayment            OrderStatus = "PENDING_PAYMENT"             // Checkout started, waiting for payment
+	StatusPaid                      OrderStatus = "PAID"                        // Payment confirmed
+	StatusFulfilled                 OrderStatus = "FULFILLED"                   // Items picked and packed
+	StatusShipped                   OrderStatus = "SHIPPED"                     // Handed over to the carrier
+	StatusDelivered                 OrderStatus = "DELIVERED"                   // Received by the customer
+	StatusPartiallyRefunded         OrderStatus = "PARTIALLY_REFUNDED"          // Part of the payment returned (e.g. item return)
+	StatusRefunded                  OrderStatus = "REFUNDED"                    // Whole payment returned
+	StatusCancellationPendingRefund OrderStatus = "CANCELLATION_PENDING_REFUND" // Paid order cancelled, refund not yet sent
+	StatusCancelled                 OrderStatus = "CANCELLED"                   // Order cancelled
+	StatusOnHold                    OrderStatus = "ON_HOLD"                     // Payment under review (e.g. chain reorganisation), fulfilment paused
 )
+
+// IsValid checks if the order status is valid
+func (os OrderStatus) IsValid() bool {
+	switch os {
+	case StatusCreated, StatusPendingPayment, StatusPaid, StatusFulfilled, StatusShipped,
+		StatusDelivered, StatusPartiallyRefunded, StatusRefunded, StatusCancellationPendingRefund, StatusCancelled, StatusOnHold:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFinal checks if the order is in a final state (no further transitions)
+func (os OrderStatus) IsFinal() bool {
+	return os == StatusRefunded || os == StatusCancelled
+}
+
+// IsFulfilled checks if the order's goods have left the warehouse
+func (os OrderStatus) IsFulfilled() bool {
+	switch os {
+	case StatusFulfilled, StatusShipped, StatusDelivered:
+		return true
+	default:
+		return false
+	}
+}
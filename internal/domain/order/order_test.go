@@ -1,6 +1,7 @@
 package order
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -85,6 +86,28 @@ func TestNewOrder(t *testing.T) {
     })
 }
 
+func TestOrderSandbox(t *testing.T) {
+    t.Run("new order becomes a test order", func(t *testing.T) {
+        order, _ := createTestOrder()
+
+        err := order.MarkAsSandbox()
+
+        assert.NoError(t, err)
+        assert.True(t, order.Sandbox)
+    })
+
+    t.Run("paid order cannot become a test order", func(t *testing.T) {
+        order, _ := createTestOrder()
+        _ = order.MarkAsPendingPayment()
+        _ = order.MarkAsPaid("payment123")
+
+        err := order.MarkAsSandbox()
+
+        assert.Equal(t, ErrCannotModifyOrder, err)
+        assert.False(t, order.Sandbox)
+    })
+}
+
 func TestOrderStatusTransitions(t *testing.T) {
     t.Run("mark as paid", func(t *testing.T) {
         // Arrange
@@ -117,6 +140,20 @@ func TestOrderStatusTransitions(t *testing.T) {
         assert.Equal(t, "payment123", *order.PaymentID) // Should remain unchanged
     })
     
+    t.Run("link payment while awaiting payment", func(t *testing.T) {
+        order, _ := createTestOrder()
+        errCreated := order.LinkPayment("payment123")
+        _ = order.MarkAsPendingPayment()
+        
+        err := order.LinkPayment("payment123")
+        
+        assert.Equal(t, ErrCannotModifyOrder, errCreated)
+        assert.NoError(t, err)
+        assert.Equal(t, "payment123", *order.PaymentID)
+        assert.Equal(t, StatusPendingPayment, order.Status)
+        assert.Equal(t, ErrCannotModifyOrder, order.MarkAsSandbox())
+    })
+    
     t.Run("mark as fulfilled", func(t *testing.T) {
         // Arrange
         order, _ := createTestOrder()
@@ -164,7 +201,7 @@ func TestOrderCancel(t *testing.T) {
         assert.True(t, order.UpdatedAt.After(oldUpdateTime))
     })
     
-    t.Run("cancel paid order", func(t *testing.T) {
+    t.Run("cannot cancel paid order without refund", func(t *testing.T) {
         // Arrange
         order, _ := createTestOrder()
         _ = order.MarkAsPaid("payment123")
@@ -173,10 +210,41 @@ func TestOrderCancel(t *testing.T) {
         err := order.Cancel()
         
         // Assert
-        assert.NoError(t, err)
+        assert.Error(t, err)
+        assert.Equal(t, ErrCancellationRequiresRefund, err)
+        assert.Equal(t, StatusPaid, order.Status)
+    })
+    
+    t.Run("cancel paid order pending refund", func(t *testing.T) {
+        // Arrange
+        order, _ := createTestOrder()
+        _ = order.MarkAsPaid("payment123")
+        
+        // Act
+        requestErr := order.RequestCancellation("customer123", "changed my mind")
+        pendingStatus := order.Status
+        completeErr := order.CompleteCancellation(SystemActor)
+        
+        // Assert
+        assert.NoError(t, requestErr)
+        assert.Equal(t, StatusCancellationPendingRefund, pendingStatus)
+        assert.NoError(t, completeErr)
         assert.Equal(t, StatusCancelled, order.Status)
     })
     
+    t.Run("cannot complete cancellation that was not requested", func(t *testing.T) {
+        // Arrange
+        order, _ := createTestOrder()
+        _ = order.MarkAsPaid("payment123")
+        
+        // Act
+        err := order.CompleteCancellation(SystemActor)
+        
+        // Assert
+        assert.Equal(t, ErrInvalidStatusTransition, err)
+        assert.Equal(t, StatusPaid, order.Status)
+    })
+    
     t.Run("cannot cancel fulfilled order", func(t *testing.T) {
         // Arrange
         order, _ := createTestOrder()
@@ -290,6 +358,242 @@ func TestOrderItemManagement(t *testing.T) {
     })
 }
 
+func TestOrderLineOperations(t *testing.T) {
+    t.Run("update item quantity recalculates total", func(t *testing.T) {
+        // Arrange
+        order, _ := createTestOrder() // 2 x $10
+        productID := order.Items[0].ProductID
+        
+        // Act
+        err := order.UpdateItemQuantity(productID, 5)
+        
+        // Assert
+        assert.NoError(t, err)
+        assert.Equal(t, 5, order.Items[0].Quantity)
+        assert.Equal(t, createTestMoney(50.0), order.Items[0].Subtotal)
+        assert.Equal(t, createTestMoney(50.0), order.TotalAmount)
+    })
+    
+    t.Run("update quantity to zero removes line", func(t *testing.T) {
+        // Arrange
+        item1 := createTestItemWithID(uuid.New())
+        item2 := createTestItemWithID(uuid.New())
+        order, _ := NewOrder("customer123", []OrderItem{item1, item2})
+        
+        // Act
+        err := order.UpdateItemQuantity(item1.ProductID, 0)
+        
+        // Assert
+        assert.NoError(t, err)
+        assert.Len(t, order.Items, 1)
+        assert.Equal(t, createTestMoney(15.0), order.TotalAmount)
+    })
+    
+    t.Run("decrement item quantity", func(t *testing.T) {
+        // Arrange
+        order, _ := createTestOrder()
+        productID := order.Items[0].ProductID
+        
+        // Act
+        err := order.DecrementItemQuantity(productID)
+        
+        // Assert
+        assert.NoError(t, err)
+        assert.Equal(t, 1, order.Items[0].Quantity)
+        assert.Equal(t, createTestMoney(10.0), order.TotalAmount)
+    })
+    
+    t.Run("update quantity errors", func(t *testing.T) {
+        // Arrange
+        order, _ := createTestOrder()
+        productID := order.Items[0].ProductID
+        
+        // Act & Assert
+        assert.Equal(t, ErrInvalidQuantity, order.UpdateItemQuantity(productID, -1))
+        assert.Equal(t, ErrItemNotFound, order.UpdateItemQuantity(uuid.New(), 1))
+        _ = order.MarkAsPaid("payment123")
+        assert.Equal(t, ErrCannotModifyOrder, order.UpdateItemQuantity(productID, 3))
+    })
+    
+    t.Run("set item note", func(t *testing.T) {
+        // Arrange
+        order, _ := createTestOrder()
+        productID := order.Items[0].ProductID
+        
+        // Act
+        err := order.SetItemNote(productID, "  Happy birthday!  ")
+        
+        // Assert
+        assert.NoError(t, err)
+        assert.Equal(t, "Happy birthday!", order.Items[0].Note)
+        assert.Equal(t, ErrItemNoteTooLong, order.SetItemNote(productID, strings.Repeat("x", MaxItemNoteLength+1)))
+        assert.Equal(t, ErrItemNotFound, order.SetItemNote(uuid.New(), "note"))
+    })
+    
+    t.Run("replace items recalculates total once", func(t *testing.T) {
+        // Arrange
+        order, _ := createTestOrder()
+        item1 := createTestItemWithID(uuid.New())
+        item2 := createTestItemWithID(uuid.New())
+        item2.Quantity = 3
+        
+        // Act
+        err := order.ReplaceItems([]OrderItem{item1, item2})
+        
+        // Assert
+        assert.NoError(t, err)
+        assert.Len(t, order.Items, 2)
+        assert.Equal(t, createTestMoney(45.0), order.Items[1].Subtotal)
+        assert.Equal(t, createTestMoney(60.0), order.TotalAmount)
+    })
+    
+    t.Run("replace items is atomic", func(t *testing.T) {
+        // Arrange
+        order, _ := createTestOrder()
+        originalItems := append([]OrderItem(nil), order.Items...)
+        originalTotal := order.TotalAmount
+        valid := createTestItemWithID(uuid.New())
+        invalid := createTestItemWithID(uuid.New())
+        invalid.Quantity = 0
+        duplicate := valid
+        
+        // Act
+        invalidErr := order.ReplaceItems([]OrderItem{valid, invalid})
+        duplicateErr := order.ReplaceItems([]OrderItem{valid, duplicate})
+        emptyErr := order.ReplaceItems(nil)
+        
+        // Assert
+        assert.Equal(t, ErrInvalidQuantity, invalidErr)
+        assert.Equal(t, ErrDuplicateItem, duplicateErr)
+        assert.Equal(t, ErrOrderMustHaveItems, emptyErr)
+        assert.Equal(t, originalItems, order.Items)
+        assert.Equal(t, originalTotal, order.TotalAmount)
+    })
+}
+
+func TestOrderPriceChanges(t *testing.T) {
+    t.Run("detect and apply price changes", func(t *testing.T) {
+        // Arrange
+        order, _ := createTestOrder() // 2 x 10 USD
+        productID := order.Items[0].ProductID
+        current := map[uuid.UUID]Money{productID: createTestMoney(12.0)}
+        
+        // Act
+        changes := order.DetectPriceChanges(current)
+        repriced, totalErr := order.RepricedTotal(changes)
+        err := order.ApplyPriceChanges(changes)
+        
+        // Assert
+        assert.Len(t, changes, 1)
+        assert.Equal(t, createTestMoney(10.0), changes[0].OldUnitPrice)
+        assert.Equal(t, createTestMoney(12.0), changes[0].NewUnitPrice)
+        assert.NoError(t, totalErr)
+        assert.Equal(t, createTestMoney(24.0), repriced)
+        assert.NoError(t, err)
+        assert.Equal(t, createTestMoney(24.0), order.Items[0].Subtotal)
+        assert.Equal(t, createTestMoney(24.0), order.TotalAmount)
+        assert.Empty(t, order.DetectPriceChanges(current))
+    })
+    
+    t.Run("unchanged and unknown prices are ignored", func(t *testing.T) {
+        // Arrange
+        order, _ := createTestOrder()
+        current := map[uuid.UUID]Money{
+            order.Items[0].ProductID: createTestMoney(10.0),
+            uuid.New():               createTestMoney(99.0),
+        }
+        
+        // Act & Assert
+        assert.Empty(t, order.DetectPriceChanges(current))
+    })
+    
+    t.Run("cannot reprice after checkout", func(t *testing.T) {
+        // Arrange
+        order, _ := createTestOrder()
+        changes := order.DetectPriceChanges(map[uuid.UUID]Money{order.Items[0].ProductID: createTestMoney(12.0)})
+        _ = order.MarkAsPendingPayment()
+        
+        // Act
+        err := order.ApplyPriceChanges(changes)
+        
+        // Assert
+        assert.Equal(t, ErrCannotModifyOrder, err)
+        assert.Equal(t, createTestMoney(20.0), order.TotalAmount)
+    })
+    
+    t.Run("currency change that mixes currencies leaves order unchanged", func(t *testing.T) {
+        // Arrange
+        order, _ := NewOrder("customer123", []OrderItem{createTestItem(), createTestItemWithID(uuid.New())})
+        originalTotal := order.TotalAmount
+        changes := order.DetectPriceChanges(map[uuid.UUID]Money{order.Items[0].ProductID: {Amount: 9.0, Currency: "EUR"}})
+        
+        // Act
+        err := order.ApplyPriceChanges(changes)
+        
+        // Assert
+        assert.Equal(t, ErrInconsistentCurrency, err)
+        assert.Equal(t, originalTotal, order.TotalAmount)
+        assert.Equal(t, createTestMoney(10.0), order.Items[0].UnitPrice)
+    })
+}
+
+func TestExchangeRates(t *testing.T) {
+    t.Run("convert rounds to cents", func(t *testing.T) {
+        // Arrange
+        rate, err := NewExchangeRate("USD", "EUR", 0.9137, "test", time.Now())
+        
+        // Act
+        converted, convertErr := rate.Convert(createTestMoney(10.0))
+        _, mismatchErr := rate.Convert(Money{Amount: 10.0, Currency: "GBP"})
+        
+        // Assert
+        assert.NoError(t, err)
+        assert.NoError(t, convertErr)
+        assert.Equal(t, Money{Amount: 9.14, Currency: "EUR"}, converted)
+        assert.Equal(t, ErrExchangeRateMismatch, mismatchErr)
+    })
+    
+    t.Run("invalid rates", func(t *testing.T) {
+        _, zeroErr := NewExchangeRate("USD", "EUR", 0, "test", time.Now())
+        _, sameErr := NewExchangeRate("USD", "USD", 1, "test", time.Now())
+        
+        assert.Equal(t, ErrInvalidExchangeRate, zeroErr)
+        assert.Equal(t, ErrInvalidExchangeRate, sameErr)
+    })
+    
+    t.Run("staleness", func(t *testing.T) {
+        rate, _ := NewExchangeRate("USD", "EUR", 0.9, "test", time.Now().Add(-2*time.Hour))
+        
+        assert.True(t, rate.IsStale(time.Hour))
+        assert.False(t, rate.IsStale(3*time.Hour))
+    })
+    
+    t.Run("order records one rate per source currency", func(t *testing.T) {
+        // Arrange
+        order, _ := NewOrder("customer123", []OrderItem{{
+            ProductID: uuid.New(),
+            Quantity:  1,
+            UnitPrice: Money{Amount: 9.0, Currency: "EUR"},
+            Subtotal:  Money{Amount: 9.0, Currency: "EUR"},
+        }})
+        rate, _ := NewExchangeRate("USD", "EUR", 0.9, "test", time.Now())
+        other, _ := NewExchangeRate("USD", "EUR", 0.95, "test", time.Now())
+        wrongTarget, _ := NewExchangeRate("USD", "GBP", 0.8, "test", time.Now())
+        
+        // Act & Assert
+        assert.Equal(t, "EUR", order.Currency())
+        assert.NoError(t, order.RecordExchangeRate(rate))
+        assert.NoError(t, order.RecordExchangeRate(rate))
+        assert.Equal(t, ErrExchangeRateMismatch, order.RecordExchangeRate(other))
+        assert.Equal(t, ErrExchangeRateMismatch, order.RecordExchangeRate(wrongTarget))
+        assert.Len(t, order.ExchangeRates, 1)
+        
+        recorded, ok := order.ExchangeRateFrom("USD")
+        assert.True(t, ok)
+        assert.Equal(t, rate, recorded)
+    })
+}
+
 func TestCalculateTotalAmount(t *testing.T) {
     t.Run("calculate total with multiple items", func(t *testing.T) {
         // Arrange
@@ -336,4 +640,22 @@ func TestCalculateTotalAmount(t *testing.T) {
         assert.Error(t, err)
         assert.Equal(t, ErrInconsistentCurrency, err)
     })
-}
\ No newline at end of file
+}
+
+func TestOrderRecordReturnOfAnotherOrder(t *testing.T) {
+    // Arrange
+    order := createFulfilledOrder()
+    other := createFulfilledOrder()
+    rma, _ := NewReturnRequest(other, []ReturnItem{{ProductID: other.Items[0].ProductID, Quantity: 1, Reason: "damaged"}}, nil)
+    _ = rma.Approve()
+    _ = rma.MarkAsReceived()
+    _ = rma.MarkAsRefunded(0.0002)
+    
+    // Act
+    err := order.RecordReturn(rma, "admin-1")
+    
+    // Assert
+    assert.Equal(t, ErrReturnOrderMismatch, err)
+    assert.Equal(t, StatusFulfilled, order.Status)
+    assert.Equal(t, 0, order.Items[0].ReturnedQuantity)
+}
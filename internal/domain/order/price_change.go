@@ -0,0 +1,87 @@
+package order
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PriceChange describes an order line whose captured unit price no longer
+// matches the current product price
+type PriceChange struct {
+	ProductID    uuid.UUID
+	ProductName  string
+	Quantity     int
+	OldUnitPrice Money
+	NewUnitPrice Money
+}
+
+// DetectPriceChanges compares the captured unit prices with the current
+// product prices. Products missing from currentPrices are left out.
+func (o *Order) DetectPriceChanges(currentPrices map[uuid.UUID]Money) []PriceChange {
+	var changes []PriceChange
+	for _, item := range o.Items {
+		current, ok := currentPrices[item.ProductID]
+		if !ok || current == item.UnitPrice {
+			continue
+		}
+
+		changes = append(changes, PriceChange{
+			ProductID:    item.ProductID,
+			ProductName:  item.ProductName,
+			Quantity:     item.Quantity,
+			OldUnitPrice: item.UnitPrice,
+			NewUnitPrice: current,
+		})
+	}
+	return changes
+}
+
+// RepricedTotal returns what the order total would be after applying the changes
+func (o *Order) RepricedTotal(changes []PriceChange) (Money, error) {
+	return calculateTotalAmount(o.repricedItems(changes))
+}
+
+// ApplyPriceChanges updates the unit prices of the changed lines.
+// Prices can only change before checkout; on error the order is left unchanged.
+func (o *Order) ApplyPriceChanges(changes []PriceChange) error {
+	if o.Status != StatusCreated {
+		return ErrCannotModifyOrder
+	}
+
+	for _, change := range changes {
+		if _, ok := o.findItem(change.ProductID); !ok {
+			return ErrItemNotFound
+		}
+	}
+
+	items := o.repricedItems(changes)
+	totalAmount, err := calculateTotalAmount(items)
+	if err != nil {
+		return err
+	}
+
+	o.Items = items
+	o.TotalAmount = totalAmount
+	o.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// repricedItems returns a copy of the order lines with the new unit prices applied
+func (o *Order) repricedItems(changes []PriceChange) []OrderItem {
+	newPrices := make(map[uuid.UUID]Money, len(changes))
+	for _, change := range changes {
+		newPrices[change.ProductID] = change.NewUnitPrice
+	}
+
+	items := make([]OrderItem, len(o.Items))
+	for i, item := range o.Items {
+		if price, ok := newPrices[item.ProductID]; ok {
+			item.UnitPrice = price
+			item = item.withQuantity(item.Quantity)
+		}
+		items[i] = item
+	}
+	return items
+}
@@ -0,0 +1,193 @@
+package order
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReturnStatus represents the current state of a return request (RMA)
+type ReturnStatus string
+
+const (
+	ReturnStatusRequested ReturnStatus = "REQUESTED" // Customer asked to return items
+	ReturnStatusApproved  ReturnStatus = "APPROVED"  // Merchant accepted, waiting for goods
+	ReturnStatusRejected  ReturnStatus = "REJECTED"  // Merchant refused the return
+	ReturnStatusReceived  ReturnStatus = "RECEIVED"  // Goods are back in the warehouse
+	ReturnStatusRefunded  ReturnStatus = "REFUNDED"  // Refund issued on the payment
+)
+
+// IsOpen checks if the return still holds quantities that may come back
+func (rs ReturnStatus) IsOpen() bool {
+	return rs == ReturnStatusRequested || rs == ReturnStatusApproved || rs == ReturnStatusReceived
+}
+
+// ReturnItem represents a single order line being returned
+type ReturnItem struct {
+	ProductID    uuid.UUID
+	Quantity     int
+	Reason       string
+	RefundAmount Money // Computed from the order's original unit price
+}
+
+// ReturnRequest represents a customer's request to return items of a fulfilled order
+type ReturnRequest struct {
+	ID         uuid.UUID
+	OrderID    uuid.UUID
+	CustomerID string
+	Items      []ReturnItem
+	Status     ReturnStatus
+
+	RefundAmount       Money   // Total fiat value of the returned items
+	CryptoRefundAmount float64 // Amount refunded on the payment (set when refunded)
+	RejectionReason    string
+
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	ReceivedAt *time.Time
+	RefundedAt *time.Time
+}
+
+// NewReturnRequest creates a return request for items of a fulfilled order.
+// openReturns are the order's other returns, so quantities are not returned twice.
+func NewReturnRequest(o *Order, items []ReturnItem, openReturns []*ReturnRequest) (*ReturnRequest, error) {
+	if !o.IsReturnable() {
+		return nil, ErrOrderNotReturnable
+	}
+
+	if len(items) == 0 {
+		return nil, ErrEmptyReturnItems
+	}
+
+	// Quantities already claimed by other open returns
+	claimed := make(map[uuid.UUID]int)
+	for _, r := range openReturns {
+		if r.OrderID != o.ID || !r.Status.IsOpen() {
+			continue
+		}
+		for _, item := range r.Items {
+			claimed[item.ProductID] += item.Quantity
+		}
+	}
+
+	returnItems := make([]ReturnItem, 0, len(items))
+	var total float64
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, ErrInvalidQuantity
+		}
+
+		if strings.TrimSpace(item.Reason) == "" {
+			return nil, ErrEmptyReturnReason
+		}
+
+		orderItem, ok := o.findItem(item.ProductID)
+		if !ok {
+			return nil, ErrItemNotFound
+		}
+
+		claimed[item.ProductID] += item.Quantity
+		if claimed[item.ProductID] > orderItem.ReturnableQuantity() {
+			return nil, ErrReturnQuantityExceeded
+		}
+
+		// Price the return from the order's original pricing
+		refund := Money{
+			Amount:   orderItem.UnitPrice.Amount * float64(item.Quantity),
+			Currency: orderItem.UnitPrice.Currency,
+		}
+		total += refund.Amount
+
+		returnItems = append(returnItems, ReturnItem{
+			ProductID:    item.ProductID,
+			Quantity:     item.Quantity,
+			Reason:       strings.TrimSpace(item.Reason),
+			RefundAmount: refund,
+		})
+	}
+
+	now := time.Now()
+	return &ReturnRequest{
+		ID:           uuid.New(),
+		OrderID:      o.ID,
+		CustomerID:   o.CustomerID,
+		Items:        returnItems,
+		Status:       ReturnStatusRequested,
+		RefundAmount: Money{Amount: total, Currency: o.TotalAmount.Currency},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+// Approve accepts the return; the customer can now send the goods back
+func (r *ReturnRequest) Approve() error {
+	if r.Status != ReturnStatusRequested {
+		return ErrInvalidReturnTransition
+	}
+
+	r.Status = ReturnStatusApproved
+	r.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// Reject refuses the return with a reason for the customer
+func (r *ReturnRequest) Reject(reason string) error {
+	if r.Status != ReturnStatusRequested {
+		return ErrInvalidReturnTransition
+	}
+
+	if strings.TrimSpace(reason) == "" {
+		return ErrEmptyReturnReason
+	}
+
+	r.Status = ReturnStatusRejected
+	r.RejectionReason = strings.TrimSpace(reason)
+	r.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// MarkAsReceived records that the returned goods arrived at the warehouse
+func (r *ReturnRequest) MarkAsReceived() error {
+	if r.Status != ReturnStatusApproved {
+		return ErrInvalidReturnTransition
+	}
+
+	now := time.Now()
+	r.Status = ReturnStatusReceived
+	r.ReceivedAt = &now
+	r.UpdatedAt = now
+
+	return nil
+}
+
+// MarkAsRefunded records the crypto amount refunded for the return
+func (r *ReturnRequest) MarkAsRefunded(cryptoAmount float64) error {
+	if r.Status != ReturnStatusReceived {
+		return ErrInvalidReturnTransition
+	}
+
+	if cryptoAmount <= 0 {
+		return ErrInvalidRefundAmount
+	}
+
+	now := time.Now()
+	r.Status = ReturnStatusRefunded
+	r.CryptoRefundAmount = cryptoAmount
+	r.RefundedAt = &now
+	r.UpdatedAt = now
+
+	return nil
+}
+
+// ProratedCryptoRefund converts the return's fiat value into the crypto amount to refund,
+// using the ratio between what the customer paid in fiat and in crypto
+func (r *ReturnRequest) ProratedCryptoRefund(paidAmount, paidCryptoAmount float64) float64 {
+	if paidAmount <= 0 || paidCryptoAmount <= 0 {
+		return 0
+	}
+
+	return paidCryptoAmount * r.RefundAmount.Amount / paidAmount
+}
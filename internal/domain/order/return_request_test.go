@@ -0,0 +1,177 @@
+package order
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test helper functions for return_request.go
+
+// createFulfilledOrder creates a fulfilled order with two lines (2 x 10 USD and 1 x 15 USD)
+func createFulfilledOrder() *Order {
+	order, _ := NewOrder("customer123", []OrderItem{createTestItem(), createTestItemWithID(uuid.New())})
+	_ = order.MarkAsPaid("payment123")
+	_ = order.MarkAsFulfilled()
+	return order
+}
+
+// Tests for ReturnRequest entity
+
+func TestNewReturnRequest(t *testing.T) {
+	t.Run("create return priced from order", func(t *testing.T) {
+		// Arrange
+		order := createFulfilledOrder()
+		productID := order.Items[0].ProductID
+
+		// Act
+		rma, err := NewReturnRequest(order, []ReturnItem{{ProductID: productID, Quantity: 1, Reason: "damaged"}}, nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, ReturnStatusRequested, rma.Status)
+		assert.Equal(t, order.ID, rma.OrderID)
+		assert.Equal(t, createTestMoney(10.0), rma.Items[0].RefundAmount)
+		assert.Equal(t, createTestMoney(10.0), rma.RefundAmount)
+	})
+
+	t.Run("cannot return unfulfilled order", func(t *testing.T) {
+		order, _ := createTestOrder()
+
+		_, err := NewReturnRequest(order, []ReturnItem{{ProductID: order.Items[0].ProductID, Quantity: 1, Reason: "damaged"}}, nil)
+
+		assert.Equal(t, ErrOrderNotReturnable, err)
+	})
+
+	t.Run("cannot return more than ordered", func(t *testing.T) {
+		order := createFulfilledOrder()
+
+		_, err := NewReturnRequest(order, []ReturnItem{{ProductID: order.Items[0].ProductID, Quantity: 3, Reason: "damaged"}}, nil)
+
+		assert.Equal(t, ErrReturnQuantityExceeded, err)
+	})
+
+	t.Run("open returns reduce returnable quantity", func(t *testing.T) {
+		order := createFulfilledOrder()
+		productID := order.Items[0].ProductID
+		first, _ := NewReturnRequest(order, []ReturnItem{{ProductID: productID, Quantity: 2, Reason: "damaged"}}, nil)
+
+		_, err := NewReturnRequest(order, []ReturnItem{{ProductID: productID, Quantity: 1, Reason: "wrong size"}}, []*ReturnRequest{first})
+
+		assert.Equal(t, ErrReturnQuantityExceeded, err)
+	})
+
+	t.Run("rejected returns do not block new ones", func(t *testing.T) {
+		order := createFulfilledOrder()
+		productID := order.Items[0].ProductID
+		first, _ := NewReturnRequest(order, []ReturnItem{{ProductID: productID, Quantity: 2, Reason: "damaged"}}, nil)
+		_ = first.Reject("outside return window")
+
+		_, err := NewReturnRequest(order, []ReturnItem{{ProductID: productID, Quantity: 2, Reason: "damaged"}}, []*ReturnRequest{first})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		order := createFulfilledOrder()
+		productID := order.Items[0].ProductID
+
+		_, err := NewReturnRequest(order, nil, nil)
+		assert.Equal(t, ErrEmptyReturnItems, err)
+
+		_, err = NewReturnRequest(order, []ReturnItem{{ProductID: productID, Quantity: 1}}, nil)
+		assert.Equal(t, ErrEmptyReturnReason, err)
+
+		_, err = NewReturnRequest(order, []ReturnItem{{ProductID: uuid.New(), Quantity: 1, Reason: "damaged"}}, nil)
+		assert.Equal(t, ErrItemNotFound, err)
+
+		_, err = NewReturnRequest(order, []ReturnItem{{ProductID: productID, Quantity: 0, Reason: "damaged"}}, nil)
+		assert.Equal(t, ErrInvalidQuantity, err)
+	})
+}
+
+func TestReturnRequestLifecycle(t *testing.T) {
+	t.Run("approve receive and refund", func(t *testing.T) {
+		order := createFulfilledOrder()
+		rma, _ := NewReturnRequest(order, []ReturnItem{{ProductID: order.Items[0].ProductID, Quantity: 2, Reason: "damaged"}}, nil)
+
+		assert.NoError(t, rma.Approve())
+		assert.NoError(t, rma.MarkAsReceived())
+		assert.NotNil(t, rma.ReceivedAt)
+		assert.NoError(t, rma.MarkAsRefunded(0.0004))
+
+		assert.Equal(t, ReturnStatusRefunded, rma.Status)
+		assert.Equal(t, 0.0004, rma.CryptoRefundAmount)
+		assert.NotNil(t, rma.RefundedAt)
+	})
+
+	t.Run("cannot receive before approval", func(t *testing.T) {
+		order := createFulfilledOrder()
+		rma, _ := NewReturnRequest(order, []ReturnItem{{ProductID: order.Items[0].ProductID, Quantity: 1, Reason: "damaged"}}, nil)
+
+		assert.Equal(t, ErrInvalidReturnTransition, rma.MarkAsReceived())
+	})
+
+	t.Run("reject requires reason", func(t *testing.T) {
+		order := createFulfilledOrder()
+		rma, _ := NewReturnRequest(order, []ReturnItem{{ProductID: order.Items[0].ProductID, Quantity: 1, Reason: "damaged"}}, nil)
+
+		assert.Equal(t, ErrEmptyReturnReason, rma.Reject(" "))
+		assert.NoError(t, rma.Reject("used item"))
+		assert.Equal(t, ReturnStatusRejected, rma.Status)
+		assert.Equal(t, ErrInvalidReturnTransition, rma.Approve())
+	})
+
+	t.Run("prorated crypto refund", func(t *testing.T) {
+		order := createFulfilledOrder() // Total 35 USD
+		rma, _ := NewReturnRequest(order, []ReturnItem{{ProductID: order.Items[1].ProductID, Quantity: 1, Reason: "damaged"}}, nil)
+
+		// 15 of 35 USD paid with 0.0007 BTC
+		assert.InDelta(t, 0.0003, rma.ProratedCryptoRefund(35.0, 0.0007), 1e-12)
+		assert.Equal(t, 0.0, rma.ProratedCryptoRefund(0, 0.0007))
+	})
+}
+
+func TestOrderRecordReturn(t *testing.T) {
+	t.Run("partial return moves order to partially refunded", func(t *testing.T) {
+		order := createFulfilledOrder()
+		rma, _ := NewReturnRequest(order, []ReturnItem{{ProductID: order.Items[0].ProductID, Quantity: 1, Reason: "damaged"}}, nil)
+		_ = rma.Approve()
+		_ = rma.MarkAsReceived()
+		_ = rma.MarkAsRefunded(0.0002)
+
+		err := order.RecordReturn(rma, "admin-1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, StatusPartiallyRefunded, order.Status)
+		assert.Equal(t, 1, order.Items[0].ReturnedQuantity)
+		assert.Equal(t, 1, order.Items[0].ReturnableQuantity())
+	})
+
+	t.Run("returning everything refunds the order", func(t *testing.T) {
+		order := createFulfilledOrder()
+		rma, _ := NewReturnRequest(order, []ReturnItem{
+			{ProductID: order.Items[0].ProductID, Quantity: 2, Reason: "damaged"},
+			{ProductID: order.Items[1].ProductID, Quantity: 1, Reason: "damaged"},
+		}, nil)
+		_ = rma.Approve()
+		_ = rma.MarkAsReceived()
+		_ = rma.MarkAsRefunded(0.0007)
+
+		err := order.RecordReturn(rma, "admin-1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, StatusRefunded, order.Status)
+	})
+
+	t.Run("return must be refunded first", func(t *testing.T) {
+		order := createFulfilledOrder()
+		rma, _ := NewReturnRequest(order, []ReturnItem{{ProductID: order.Items[0].ProductID, Quantity: 1, Reason: "damaged"}}, nil)
+
+		err := order.RecordReturn(rma, "admin-1")
+
+		assert.Equal(t, ErrInvalidReturnTransition, err)
+		assert.Equal(t, StatusFulfilled, order.Status)
+	})
+}
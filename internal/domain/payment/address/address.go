@@ -0,0 +1,54 @@
+// Package address validates wallet addresses for the supported blockchains.
+package address
+
+import "strings"
+
+// UTXOParams describes the address encodings of a Bitcoin-family chain
+type UTXOParams struct {
+	Base58Versions []byte // Allowed P2PKH/P2SH version bytes
+	Bech32HRP      string // Segwit human-readable part (empty when unsupported)
+	CashAddrPrefix string // CashAddr prefix (empty when unsupported)
+}
+
+// Address parameters per chain and network
+var (
+	BitcoinMainnet     = UTXOParams{Base58Versions: []byte{0x00, 0x05}, Bech32HRP: "bc"}
+	BitcoinTestnet     = UTXOParams{Base58Versions: []byte{0x6f, 0xc4}, Bech32HRP: "tb"}
+	LitecoinMainnet    = UTXOParams{Base58Versions: []byte{0x30, 0x32, 0x05}, Bech32HRP: "ltc"}
+	LitecoinTestnet    = UTXOParams{Base58Versions: []byte{0x6f, 0x3a, 0xc4}, Bech32HRP: "tltc"}
+	BitcoinCashMainnet = UTXOParams{Base58Versions: []byte{0x00, 0x05}, CashAddrPrefix: "bitcoincash"}
+	BitcoinCashTestnet = UTXOParams{Base58Versions: []byte{0x6f, 0xc4}, CashAddrPrefix: "bchtest"}
+	DogecoinMainnet    = UTXOParams{Base58Versions: []byte{0x1e, 0x16}}
+	DogecoinTestnet    = UTXOParams{Base58Versions: []byte{0x71, 0xc4}}
+)
+
+// ValidateUTXO checks a Bitcoin-family address: legacy Base58Check,
+// Bech32/Bech32m segwit or CashAddr, depending on what the chain supports
+func ValidateUTXO(s string, params UTXOParams) error {
+	lower := strings.ToLower(s)
+
+	if params.Bech32HRP != "" && strings.HasPrefix(lower, params.Bech32HRP+"1") {
+		return validateSegwit(s, params.Bech32HRP)
+	}
+
+	if params.CashAddrPrefix != "" && (strings.Contains(s, ":") || strings.HasPrefix(lower, "q") || strings.HasPrefix(lower, "p")) {
+		return validateCashAddr(s, params.CashAddrPrefix)
+	}
+
+	return validateBase58Hash(s, bitcoinAlphabet, params.Base58Versions)
+}
+
+// ValidateEthereum checks an Ethereum (EVM) address, including its EIP-55 checksum
+func ValidateEthereum(s string) error {
+	return validateEthereum(s)
+}
+
+// ValidateTron checks a Tron address (Base58Check, version 0x41)
+func ValidateTron(s string) error {
+	return validateBase58Hash(s, bitcoinAlphabet, []byte{0x41})
+}
+
+// ValidateRipple checks a classic XRP address with an optional "?dt=" destination tag
+func ValidateRipple(s string) error {
+	return validateRipple(s)
+}
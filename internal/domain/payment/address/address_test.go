@@ -0,0 +1,99 @@
+package address
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateUTXO(t *testing.T) {
+	testCases := []struct {
+		name     string
+		address  string
+		params   UTXOParams
+		expected error
+	}{
+		{"btc p2pkh", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", BitcoinMainnet, nil},
+		{"btc p2sh", "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", BitcoinMainnet, nil},
+		{"btc bech32 v0", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", BitcoinMainnet, nil},
+		{"btc bech32 upper case", "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", BitcoinMainnet, nil},
+		{"btc bech32m taproot", "bc1p5d7rjq7g6rdk2yhzks9smlaqtedr4dekq08ge8ztwac72sfr9rusxg3297", BitcoinMainnet, nil},
+		{"btc v1 with bech32 checksum", "bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7k7grplx", BitcoinMainnet, ErrInvalidChecksum},
+		{"btc bech32 mixed case", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kV8F3T4", BitcoinMainnet, ErrInvalidFormat},
+		{"btc bad checksum", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb", BitcoinMainnet, ErrInvalidChecksum},
+		{"btc garbage", "not-an-address", BitcoinMainnet, ErrInvalidFormat},
+		{"btc base58 garbage", "address123", BitcoinMainnet, ErrInvalidChecksum},
+		{"btc testnet on mainnet", "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r", BitcoinMainnet, ErrInvalidVersion},
+		{"btc testnet", "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r", BitcoinTestnet, nil},
+		{"btc testnet bech32", "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", BitcoinTestnet, nil},
+		{"btc testnet bech32 on mainnet", "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", BitcoinMainnet, ErrInvalidFormat},
+		{"ltc legacy", "LVuDpNCSSj6pQ7t9Pv6d6sUkLKoqDEVUnJ", LitecoinMainnet, nil},
+		{"ltc p2sh", "MJaRnao1s62a2zAKSkmG582KbLKianqb7v", LitecoinMainnet, nil},
+		{"ltc bech32", "ltc1qw508d6qejxtdg4y5r3zarvary0c5xw7kgmn4n9", LitecoinMainnet, nil},
+		{"ltc testnet bech32", "tltc1qw508d6qejxtdg4y5r3zarvary0c5xw7klfsuq0", LitecoinTestnet, nil},
+		{"btc address on ltc", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", LitecoinMainnet, ErrInvalidVersion},
+		{"bch cashaddr", "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", BitcoinCashMainnet, nil},
+		{"bch cashaddr without prefix", "qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", BitcoinCashMainnet, nil},
+		{"bch cashaddr p2sh", "bitcoincash:ppm2qsznhks23z7629mms6s4cwef74vcwvn0h829pq", BitcoinCashMainnet, nil},
+		{"bch cashaddr bad checksum", "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6c", BitcoinCashMainnet, ErrInvalidChecksum},
+		{"bch cashaddr on testnet", "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", BitcoinCashTestnet, ErrInvalidVersion},
+		{"bch legacy", "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu", BitcoinCashMainnet, nil},
+		{"doge", "DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L", DogecoinMainnet, nil},
+		{"doge testnet", "nesRpRaAbTDmZHwmzBkLd2AtF7Z9L9z5S2", DogecoinTestnet, nil},
+		{"btc address on doge", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", DogecoinMainnet, ErrInvalidVersion},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ValidateUTXO(tc.address, tc.params))
+		})
+	}
+}
+
+func TestValidateEthereum(t *testing.T) {
+	t.Run("eip-55 checksummed", func(t *testing.T) {
+		assert.NoError(t, ValidateEthereum("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"))
+		assert.NoError(t, ValidateEthereum("0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"))
+		assert.NoError(t, ValidateEthereum("0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB"))
+	})
+
+	t.Run("single case has no checksum", func(t *testing.T) {
+		assert.NoError(t, ValidateEthereum("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"))
+	})
+
+	t.Run("wrong checksum", func(t *testing.T) {
+		assert.Equal(t, ErrInvalidChecksum, ValidateEthereum("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"))
+	})
+
+	t.Run("wrong length", func(t *testing.T) {
+		assert.Equal(t, ErrInvalidFormat, ValidateEthereum("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA"))
+	})
+}
+
+func TestValidateTron(t *testing.T) {
+	assert.NoError(t, ValidateTron("TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t"))
+	assert.Equal(t, ErrInvalidVersion, ValidateTron("1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"))
+}
+
+func TestValidateRipple(t *testing.T) {
+	t.Run("classic address", func(t *testing.T) {
+		assert.NoError(t, ValidateRipple("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"))
+	})
+
+	t.Run("with destination tag", func(t *testing.T) {
+		classic, tag, err := SplitDestinationTag("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh?dt=12345")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", classic)
+		assert.Equal(t, uint32(12345), *tag)
+		assert.NoError(t, ValidateRipple("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh?dt=12345"))
+	})
+
+	t.Run("destination tag out of range", func(t *testing.T) {
+		assert.Equal(t, ErrInvalidDestinationTag, ValidateRipple("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh?dt=4294967296"))
+	})
+
+	t.Run("bitcoin alphabet address", func(t *testing.T) {
+		assert.Error(t, ValidateRipple("1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"))
+	})
+}
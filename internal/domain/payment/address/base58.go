@@ -0,0 +1,79 @@
+package address
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/big"
+	"strings"
+)
+
+const (
+	bitcoinAlphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	rippleAlphabet  = "rpshnaf39wBUDNEGHJKLM4PQRST7VWXYZ2bcdeCg65jkm8oFqi1tuvAxyz"
+)
+
+// base58Decode decodes a Base58 string with the given alphabet
+func base58Decode(s string, alphabet string) ([]byte, error) {
+	if s == "" {
+		return nil, ErrInvalidFormat
+	}
+
+	value := new(big.Int)
+	radix := big.NewInt(58)
+	for _, r := range s {
+		digit := strings.IndexRune(alphabet, r)
+		if digit < 0 {
+			return nil, ErrInvalidFormat
+		}
+		value.Mul(value, radix)
+		value.Add(value, big.NewInt(int64(digit)))
+	}
+
+	// Each leading zero digit encodes a leading zero byte
+	leadingZeros := 0
+	for leadingZeros < len(s) && s[leadingZeros] == alphabet[0] {
+		leadingZeros++
+	}
+
+	return append(make([]byte, leadingZeros), value.Bytes()...), nil
+}
+
+// base58CheckDecode decodes a Base58Check string into its version byte and payload
+func base58CheckDecode(s string, alphabet string) (byte, []byte, error) {
+	decoded, err := base58Decode(s, alphabet)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if len(decoded) < 5 {
+		return 0, nil, ErrInvalidFormat
+	}
+
+	body, checksum := decoded[:len(decoded)-4], decoded[len(decoded)-4:]
+	first := sha256.Sum256(body)
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(second[:4], checksum) {
+		return 0, nil, ErrInvalidChecksum
+	}
+
+	return body[0], body[1:], nil
+}
+
+// validateBase58Hash checks a Base58Check address carrying a 20-byte hash
+// with one of the allowed version bytes
+func validateBase58Hash(s string, alphabet string, versions []byte) error {
+	version, payload, err := base58CheckDecode(s, alphabet)
+	if err != nil {
+		return err
+	}
+
+	if len(payload) != 20 {
+		return ErrInvalidFormat
+	}
+
+	if bytes.IndexByte(versions, version) < 0 {
+		return ErrInvalidVersion
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package address
+
+import "strings"
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+const (
+	bech32Const  = 1          // BIP-173, witness version 0
+	bech32mConst = 0x2bc830a3 // BIP-350, witness version 1 and above
+)
+
+// bech32Polymod computes the BCH checksum over the 5-bit values
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// bech32HRPExpand expands the human-readable part for checksum computation
+func bech32HRPExpand(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+	return expanded
+}
+
+// decodeCharset maps characters to 5-bit values; mixed case is rejected
+func decodeCharset(s string) ([]byte, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return nil, ErrInvalidFormat
+	}
+
+	s = strings.ToLower(s)
+	values := make([]byte, len(s))
+	for i := 0; i < len(s); i++ {
+		v := strings.IndexByte(bech32Charset, s[i])
+		if v < 0 {
+			return nil, ErrInvalidFormat
+		}
+		values[i] = byte(v)
+	}
+	return values, nil
+}
+
+// convertBits regroups a slice of fromBits-wide values into toBits-wide values
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	acc := uint32(0)
+	bits := uint(0)
+	maxValue := uint32(1)<<toBits - 1
+	var out []byte
+
+	for _, v := range data {
+		acc = acc<<fromBits | uint32(v)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte(acc>>bits&maxValue))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(toBits-bits)&maxValue))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxValue != 0 {
+		return nil, ErrInvalidFormat
+	}
+
+	return out, nil
+}
+
+// validateSegwit checks a Bech32 (v0) or Bech32m (v1+) segregated witness address
+func validateSegwit(s string, hrp string) error {
+	if len(s) > 90 {
+		return ErrInvalidFormat
+	}
+
+	separator := strings.LastIndexByte(s, '1')
+	if separator < 1 || separator+7 > len(s) {
+		return ErrInvalidFormat
+	}
+
+	if strings.ToLower(s[:separator]) != hrp {
+		return ErrInvalidVersion
+	}
+
+	values, err := decodeCharset(s[separator+1:])
+	if err != nil {
+		return err
+	}
+
+	data := values[:len(values)-6]
+	if len(data) < 1 {
+		return ErrInvalidFormat
+	}
+
+	witnessVersion := data[0]
+	if witnessVersion > 16 {
+		return ErrInvalidFormat
+	}
+
+	expected := uint32(bech32Const)
+	if witnessVersion > 0 {
+		expected = bech32mConst
+	}
+	if bech32Polymod(append(bech32HRPExpand(hrp), values...)) != expected {
+		return ErrInvalidChecksum
+	}
+
+	program, err := convertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return err
+	}
+
+	if len(program) < 2 || len(program) > 40 {
+		return ErrInvalidFormat
+	}
+
+	if witnessVersion == 0 && len(program) != 20 && len(program) != 32 {
+		return ErrInvalidFormat
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package address
+
+import "strings"
+
+// cashAddrPolymod computes the CashAddr checksum over the 5-bit values
+func cashAddrPolymod(values []byte) uint64 {
+	c := uint64(1)
+	for _, d := range values {
+		c0 := c >> 35
+		c = (c&0x07ffffffff)<<5 ^ uint64(d)
+		if c0&0x01 != 0 {
+			c ^= 0x98f2bc8e61
+		}
+		if c0&0x02 != 0 {
+			c ^= 0x79b76d99e2
+		}
+		if c0&0x04 != 0 {
+			c ^= 0xf33e5fb3c4
+		}
+		if c0&0x08 != 0 {
+			c ^= 0xae2eabe2a8
+		}
+		if c0&0x10 != 0 {
+			c ^= 0x1e4f43e470
+		}
+	}
+	return c ^ 1
+}
+
+// validateCashAddr checks a Bitcoin Cash CashAddr address. The prefix
+// (bitcoincash: or bchtest:) may be omitted.
+func validateCashAddr(s string, prefix string) error {
+	payload := s
+	if separator := strings.IndexByte(s, ':'); separator >= 0 {
+		if strings.ToLower(s[:separator]) != prefix {
+			return ErrInvalidVersion
+		}
+		payload = s[separator+1:]
+	}
+
+	values, err := decodeCharset(payload)
+	if err != nil {
+		return err
+	}
+
+	if len(values) < 9 {
+		return ErrInvalidFormat
+	}
+
+	checked := make([]byte, 0, len(prefix)+1+len(values))
+	for i := 0; i < len(prefix); i++ {
+		checked = append(checked, prefix[i]&31)
+	}
+	checked = append(checked, 0)
+	checked = append(checked, values...)
+	if cashAddrPolymod(checked) != 0 {
+		return ErrInvalidChecksum
+	}
+
+	data, err := convertBits(values[:len(values)-8], 5, 8, false)
+	if err != nil {
+		return err
+	}
+
+	// Version byte: reserved bit, 4 type bits, 3 size bits
+	version := data[0]
+	if version&0x80 != 0 {
+		return ErrInvalidFormat
+	}
+
+	hashSizes := [8]int{20, 24, 28, 32, 40, 48, 56, 64}
+	if len(data)-1 != hashSizes[version&0x07] {
+		return ErrInvalidFormat
+	}
+
+	addressType := version >> 3 & 0x0f
+	if addressType != 0 && addressType != 1 { // P2PKH or P2SH
+		return ErrInvalidVersion
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package address
+
+import (
+	"crypto/sha256"
+	"math/big"
+)
+
+// EncodeP2PKH encodes a 20-byte public key hash as a legacy Base58Check
+// address with the chain's first version byte (e.g. 1… on Bitcoin, L… on Litecoin)
+func EncodeP2PKH(pubKeyHash []byte, params UTXOParams) (string, error) {
+	if len(pubKeyHash) != 20 || len(params.Base58Versions) == 0 {
+		return "", ErrInvalidFormat
+	}
+
+	return base58CheckEncode(append([]byte{params.Base58Versions[0]}, pubKeyHash...), bitcoinAlphabet), nil
+}
+
+// EncodeP2WPKH encodes a 20-byte public key hash as a native segwit (witness
+// version 0) Bech32 address, e.g. bc1q… on Bitcoin
+func EncodeP2WPKH(pubKeyHash []byte, params UTXOParams) (string, error) {
+	if len(pubKeyHash) != 20 || params.Bech32HRP == "" {
+		return "", ErrInvalidFormat
+	}
+
+	program, err := convertBits(pubKeyHash, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	values := append([]byte{0}, program...)
+	checksumInput := append(bech32HRPExpand(params.Bech32HRP), values...)
+	polymod := bech32Polymod(append(checksumInput, 0, 0, 0, 0, 0, 0)) ^ bech32Const
+
+	encoded := []byte(params.Bech32HRP + "1")
+	for _, v := range values {
+		encoded = append(encoded, bech32Charset[v])
+	}
+	for i := 0; i < 6; i++ {
+		encoded = append(encoded, bech32Charset[(polymod>>uint(5*(5-i)))&31])
+	}
+
+	return string(encoded), nil
+}
+
+// DecodeBase58Check decodes a Bitcoin-alphabet Base58Check string, such as an
+// extended public key, and returns its body (version bytes included)
+func DecodeBase58Check(s string) ([]byte, error) {
+	version, payload, err := base58CheckDecode(s, bitcoinAlphabet)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{version}, payload...), nil
+}
+
+// base58CheckEncode appends the double SHA-256 checksum and encodes the result in Base58
+func base58CheckEncode(body []byte, alphabet string) string {
+	first := sha256.Sum256(body)
+	second := sha256.Sum256(first[:])
+	return base58Encode(append(body, second[:4]...), alphabet)
+}
+
+// base58Encode encodes bytes in Base58 with the given alphabet
+func base58Encode(data []byte, alphabet string) string {
+	value := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+
+	var encoded []byte
+	for value.Sign() > 0 {
+		value.DivMod(value, radix, mod)
+		encoded = append(encoded, alphabet[mod.Int64()])
+	}
+
+	// Each leading zero byte encodes as a leading zero digit
+	for i := 0; i < len(data) && data[i] == 0; i++ {
+		encoded = append(encoded, alphabet[0])
+	}
+
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+
+	return string(encoded)
+}
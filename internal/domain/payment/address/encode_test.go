@@ -0,0 +1,72 @@
+package address
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeP2PKH(t *testing.T) {
+	hash, _ := hex.DecodeString("62e907b15cbf27d5425399ebf6f0fb50ebb88f18")
+
+	t.Run("bitcoin", func(t *testing.T) {
+		encoded, err := EncodeP2PKH(hash, BitcoinMainnet)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", encoded)
+	})
+
+	t.Run("litecoin", func(t *testing.T) {
+		encoded, err := EncodeP2PKH(hash, LitecoinMainnet)
+
+		assert.NoError(t, err)
+		assert.NoError(t, ValidateUTXO(encoded, LitecoinMainnet))
+		assert.Equal(t, byte('L'), encoded[0])
+	})
+
+	t.Run("wrong hash length", func(t *testing.T) {
+		_, err := EncodeP2PKH(hash[:19], BitcoinMainnet)
+
+		assert.Equal(t, ErrInvalidFormat, err)
+	})
+}
+
+func TestEncodeP2WPKH(t *testing.T) {
+	hash, _ := hex.DecodeString("751e76e8199196d454941c45d1b3a323f1433bd6")
+
+	testCases := []struct {
+		name     string
+		params   UTXOParams
+		expected string
+	}{
+		{"bitcoin", BitcoinMainnet, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"},
+		{"bitcoin testnet", BitcoinTestnet, "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"},
+		{"litecoin", LitecoinMainnet, "ltc1qw508d6qejxtdg4y5r3zarvary0c5xw7kgmn4n9"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded, err := EncodeP2WPKH(hash, tc.params)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, encoded)
+		})
+	}
+
+	t.Run("chain without segwit", func(t *testing.T) {
+		_, err := EncodeP2WPKH(hash, DogecoinMainnet)
+
+		assert.Equal(t, ErrInvalidFormat, err)
+	})
+}
+
+func TestDecodeBase58Check(t *testing.T) {
+	body, err := DecodeBase58Check("1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "0062e907b15cbf27d5425399ebf6f0fb50ebb88f18", hex.EncodeToString(body))
+
+	_, err = DecodeBase58Check("1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb")
+	assert.Equal(t, ErrInvalidChecksum, err)
+}
@@ -0,0 +1,11 @@
+package address
+
+import "errors"
+
+// Address validation errors
+var (
+	ErrInvalidFormat         = errors.New("address format is invalid")
+	ErrInvalidChecksum       = errors.New("address checksum is invalid")
+	ErrInvalidVersion        = errors.New("address version is not valid for this network")
+	ErrInvalidDestinationTag = errors.New("destination tag is invalid")
+)
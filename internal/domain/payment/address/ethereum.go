@@ -0,0 +1,53 @@
+package address
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// validateEthereum checks a 0x-prefixed 20-byte hex address.
+// Mixed-case addresses must carry a valid EIP-55 checksum; all lower or
+// all upper case addresses carry no checksum and are accepted as is.
+func validateEthereum(s string) error {
+	if len(s) != 42 || (s[:2] != "0x" && s[:2] != "0X") {
+		return ErrInvalidFormat
+	}
+
+	body := s[2:]
+	if _, err := hex.DecodeString(body); err != nil {
+		return ErrInvalidFormat
+	}
+
+	if body == strings.ToLower(body) || body == strings.ToUpper(body) {
+		return nil
+	}
+
+	if eip55(body) != body {
+		return ErrInvalidChecksum
+	}
+
+	return nil
+}
+
+// eip55 returns the checksummed form of a hex address body (without 0x)
+func eip55(body string) string {
+	lower := strings.ToLower(body)
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write([]byte(lower))
+	digest := hash.Sum(nil)
+
+	checksummed := []byte(lower)
+	for i, c := range checksummed {
+		nibble := digest[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if c >= 'a' && c <= 'f' && nibble&0x0f >= 8 {
+			checksummed[i] = c - 32
+		}
+	}
+
+	return string(checksummed)
+}
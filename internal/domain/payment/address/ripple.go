@@ -0,0 +1,39 @@
+package address
+
+import (
+	"strconv"
+	"strings"
+)
+
+const destinationTagParam = "?dt="
+
+// SplitDestinationTag splits an XRP address written as "r...?dt=12345"
+// into the classic address and its destination tag (nil when absent)
+func SplitDestinationTag(s string) (string, *uint32, error) {
+	i := strings.Index(s, destinationTagParam)
+	if i < 0 {
+		return s, nil, nil
+	}
+
+	tag, err := strconv.ParseUint(s[i+len(destinationTagParam):], 10, 32)
+	if err != nil {
+		return "", nil, ErrInvalidDestinationTag
+	}
+
+	value := uint32(tag)
+	return s[:i], &value, nil
+}
+
+// validateRipple checks a classic XRP address with an optional destination tag
+func validateRipple(s string) error {
+	classic, _, err := SplitDestinationTag(s)
+	if err != nil {
+		return err
+	}
+
+	if !strings.HasPrefix(classic, "r") {
+		return ErrInvalidFormat
+	}
+
+	return validateBase58Hash(classic, rippleAlphabet, []byte{0x00})
+}
@@ -0,0 +1,302 @@
+package payment
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AdminAction is a manual action an admin can take on a payment
+type AdminAction string
+
+const (
+	AdminActionConfirm      AdminAction = "CONFIRM"       // Confirm without (enough) on-chain confirmations
+	AdminActionFail         AdminAction = "FAIL"          // Reject the payment
+	AdminActionExpire       AdminAction = "EXPIRE"        // Expire before the deadline
+	AdminActionExtendExpiry AdminAction = "EXTEND_EXPIRY" // Give the customer more time to pay
+	AdminActionRefund       AdminAction = "REFUND"        // Refund all or part of the payment
+)
+
+// IsValid checks if the admin action is supported
+func (a AdminAction) IsValid() bool {
+	switch a {
+	case AdminActionConfirm, AdminActionFail, AdminActionExpire, AdminActionExtendExpiry, AdminActionRefund:
+		return true
+	default:
+		return false
+	}
+}
+
+// MovesFunds checks if the action changes how much the merchant keeps
+func (a AdminAction) MovesFunds() bool {
+	return a == AdminActionConfirm || a == AdminActionRefund
+}
+
+// ReasonCode explains why an admin acted on a payment
+type ReasonCode string
+
+const (
+	ReasonVerifiedOnChain      ReasonCode = "VERIFIED_ON_CHAIN"     // Admin checked the transaction in an explorer
+	ReasonUnderpaymentAccepted ReasonCode = "UNDERPAYMENT_ACCEPTED" // Merchant accepts a small shortfall
+	ReasonLatePayment          ReasonCode = "LATE_PAYMENT"          // Funds arrived after the deadline
+	ReasonCustomerRequest      ReasonCode = "CUSTOMER_REQUEST"
+	ReasonFraudSuspected       ReasonCode = "FRAUD_SUSPECTED"
+	ReasonDuplicatePayment     ReasonCode = "DUPLICATE_PAYMENT"
+	ReasonGatewayError         ReasonCode = "GATEWAY_ERROR" // Gateway reported a wrong status
+	ReasonOther                ReasonCode = "OTHER"         // Requires a note
+)
+
+// IsValid checks if the reason code is supported
+func (r ReasonCode) IsValid() bool {
+	switch r {
+	case ReasonVerifiedOnChain, ReasonUnderpaymentAccepted, ReasonLatePayment, ReasonCustomerRequest,
+		ReasonFraudSuspected, ReasonDuplicatePayment, ReasonGatewayError, ReasonOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// AdminActionStatus represents the state of an admin action request
+type AdminActionStatus string
+
+const (
+	AdminActionPendingApproval AdminActionStatus = "PENDING_APPROVAL" // Waiting for a second admin
+	AdminActionExecuted        AdminActionStatus = "EXECUTED"
+	AdminActionRejected        AdminActionStatus = "REJECTED"
+	AdminActionFailed          AdminActionStatus = "FAILED" // Could not be saved; the payment was left unchanged
+)
+
+// AdminActionParams holds the inputs of actions that need them
+type AdminActionParams struct {
+	RefundAmount float64       // Crypto amount, refunds only
+	NetOfFees    bool          // Refunds only: keep back the payment's fees pro rata
+	Extension    time.Duration // Expiry extensions only
+}
+
+// AdminApprovalPolicy decides which admin actions need a second admin.
+// Actions that move funds (confirm, refund) above the threshold need approval.
+type AdminApprovalPolicy struct {
+	Currency  string  // Fiat currency of the threshold
+	Threshold float64 // 0 disables approvals
+}
+
+// RequiresApproval checks if the action on the payment needs a second admin.
+// Payments in another currency than the policy's always need approval, and so
+// does confirming a failed payment, whatever the threshold.
+func (ap AdminApprovalPolicy) RequiresApproval(p *Payment, action AdminAction, params AdminActionParams) bool {
+	if action == AdminActionConfirm && p.Status == StatusFailed {
+		return true
+	}
+
+	if ap.Threshold <= 0 || !action.MovesFunds() {
+		return false
+	}
+
+	if !strings.EqualFold(ap.Currency, p.Currency) {
+		return true
+	}
+
+	value := p.Amount
+	if action == AdminActionRefund && p.CryptoAmount > 0 {
+		value = p.Amount * params.RefundAmount / p.CryptoAmount
+	}
+
+	return value > ap.Threshold
+}
+
+// AdminActionRequest is a manual action on a payment, executed directly or
+// once a second admin approved it
+type AdminActionRequest struct {
+	ID               string
+	PaymentID        string
+	Action           AdminAction
+	Params           AdminActionParams
+	ReasonCode       ReasonCode
+	Note             string
+	RequestedBy      string
+	RequiresApproval bool
+	ApprovedBy       string // Second admin, when approval was required
+	Status           AdminActionStatus
+	RequestedAt      time.Time
+	DecidedAt        *time.Time
+}
+
+// NewAdminActionRequest creates an admin action request with validation
+func NewAdminActionRequest(paymentID string, action AdminAction, params AdminActionParams, reason ReasonCode, note, actor string, requiresApproval bool) (*AdminActionRequest, error) {
+	if paymentID == "" {
+		return nil, ErrEmptyPaymentID
+	}
+
+	if !action.IsValid() {
+		return nil, ErrInvalidAdminAction
+	}
+
+	if strings.TrimSpace(actor) == "" {
+		return nil, ErrActorRequired
+	}
+
+	if !reason.IsValid() {
+		return nil, ErrInvalidReasonCode
+	}
+
+	note = strings.TrimSpace(note)
+	if reason == ReasonOther && note == "" {
+		return nil, ErrReasonNoteRequired
+	}
+
+	return &AdminActionRequest{
+		ID:               uuid.New().String(),
+		PaymentID:        paymentID,
+		Action:           action,
+		Params:           params,
+		ReasonCode:       reason,
+		Note:             note,
+		RequestedBy:      strings.TrimSpace(actor),
+		RequiresApproval: requiresApproval,
+		Status:           AdminActionPendingApproval,
+		RequestedAt:      time.Now(),
+	}, nil
+}
+
+// Approve records the second admin's approval
+func (r *AdminActionRequest) Approve(approver string) error {
+	if r.Status != AdminActionPendingApproval {
+		return ErrAdminActionDecided
+	}
+
+	approver = strings.TrimSpace(approver)
+	if approver == "" {
+		return ErrActorRequired
+	}
+
+	if approver == r.RequestedBy {
+		return ErrSelfApproval
+	}
+
+	r.ApprovedBy = approver
+	return nil
+}
+
+// Reject closes the request without running it
+func (r *AdminActionRequest) Reject(approver string) error {
+	if r.Status != AdminActionPendingApproval {
+		return ErrAdminActionDecided
+	}
+
+	if strings.TrimSpace(approver) == "" {
+		return ErrActorRequired
+	}
+
+	now := time.Now()
+	r.ApprovedBy = strings.TrimSpace(approver)
+	r.Status = AdminActionRejected
+	r.DecidedAt = &now
+
+	return nil
+}
+
+// Abandon closes a request whose action could not be saved, so it is never run later
+func (r *AdminActionRequest) Abandon() error {
+	if r.Status != AdminActionPendingApproval {
+		return ErrAdminActionDecided
+	}
+
+	now := time.Now()
+	r.Status = AdminActionFailed
+	r.DecidedAt = &now
+
+	return nil
+}
+
+// Execute runs the action on the payment
+func (r *AdminActionRequest) Execute(p *Payment) error {
+	if r.Status != AdminActionPendingApproval {
+		return ErrAdminActionDecided
+	}
+
+	if r.RequiresApproval && r.ApprovedBy == "" {
+		return ErrApprovalRequired
+	}
+
+	if p.ID != r.PaymentID {
+		return ErrPaymentNotFound
+	}
+
+	// The payment may have failed since the request was filed
+	if r.Action == AdminActionConfirm && p.Status == StatusFailed && r.ApprovedBy == "" {
+		return ErrApprovalRequired
+	}
+
+	var err error
+	switch r.Action {
+	case AdminActionConfirm:
+		err = p.MarkAsConfirmed()
+	case AdminActionFail:
+		err = p.MarkAsFailed()
+	case AdminActionExpire:
+		err = p.MarkAsExpired()
+	case AdminActionExtendExpiry:
+		err = p.ExtendExpiry(r.Params.Extension)
+	case AdminActionRefund:
+		if r.Params.NetOfFees {
+			err = p.PartialRefundNetOfFees(r.Params.RefundAmount)
+		} else {
+			err = p.PartialRefund(r.Params.RefundAmount)
+		}
+	default:
+		err = ErrInvalidAdminAction
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	r.Status = AdminActionExecuted
+	r.DecidedAt = &now
+
+	return nil
+}
+
+// PaymentAuditEntry is one line of the append-only payment audit log
+type PaymentAuditEntry struct {
+	ID           string
+	PaymentID    string
+	ActionID     string
+	Action       AdminAction
+	Event        AdminActionStatus // PENDING_APPROVAL when requested, then EXECUTED or REJECTED
+	Actor        string            // Admin who caused this entry
+	RequestedBy  string
+	ApprovedBy   string
+	ReasonCode   ReasonCode
+	Note         string
+	FromStatus   PaymentStatus
+	ToStatus     PaymentStatus
+	RefundAmount float64
+	NetOfFees    bool
+	ExpiresAt    time.Time // Payment deadline after the entry
+	CreatedAt    time.Time
+}
+
+// NewPaymentAuditEntry records the current state of a request and its payment
+func NewPaymentAuditEntry(r *AdminActionRequest, actor string, fromStatus PaymentStatus, p *Payment) PaymentAuditEntry {
+	return PaymentAuditEntry{
+		ID:           uuid.New().String(),
+		PaymentID:    r.PaymentID,
+		ActionID:     r.ID,
+		Action:       r.Action,
+		Event:        r.Status,
+		Actor:        actor,
+		RequestedBy:  r.RequestedBy,
+		ApprovedBy:   r.ApprovedBy,
+		ReasonCode:   r.ReasonCode,
+		Note:         r.Note,
+		FromStatus:   fromStatus,
+		ToStatus:     p.Status,
+		RefundAmount: r.Params.RefundAmount,
+		NetOfFees:    r.Params.NetOfFees,
+		ExpiresAt:    p.ExpiresAt,
+		CreatedAt:    time.Now(),
+	}
+}
@@ -0,0 +1,162 @@
+package payment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test helper functions
+
+func createTestConfirmedPayment() *Payment {
+	payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+	_ = payment.UpdateCryptoAmount(0.002)
+	_ = payment.MarkAsConfirmed()
+	return payment
+}
+
+// Tests for AdminActionRequest
+
+func TestNewAdminActionRequest(t *testing.T) {
+	testCases := []struct {
+		name     string
+		action   AdminAction
+		reason   ReasonCode
+		note     string
+		actor    string
+		expected error
+	}{
+		{"valid request", AdminActionConfirm, ReasonVerifiedOnChain, "", "admin-1", nil},
+		{"missing actor", AdminActionConfirm, ReasonVerifiedOnChain, "", " ", ErrActorRequired},
+		{"unknown action", AdminAction("DELETE"), ReasonVerifiedOnChain, "", "admin-1", ErrInvalidAdminAction},
+		{"unknown reason", AdminActionFail, ReasonCode("BORED"), "", "admin-1", ErrInvalidReasonCode},
+		{"other needs a note", AdminActionFail, ReasonOther, "", "admin-1", ErrReasonNoteRequired},
+		{"other with a note", AdminActionFail, ReasonOther, "customer paid by card", "admin-1", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, err := NewAdminActionRequest("payment-1", tc.action, AdminActionParams{}, tc.reason, tc.note, tc.actor, false)
+
+			assert.Equal(t, tc.expected, err)
+			if tc.expected == nil {
+				assert.Equal(t, AdminActionPendingApproval, request.Status)
+			}
+		})
+	}
+}
+
+func TestAdminActionExecute(t *testing.T) {
+	t.Run("manual confirm", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		request, _ := NewAdminActionRequest(payment.ID, AdminActionConfirm, AdminActionParams{}, ReasonVerifiedOnChain, "", "admin-1", false)
+
+		err := request.Execute(payment)
+
+		assert.NoError(t, err)
+		assert.Equal(t, StatusConfirmed, payment.Status)
+		assert.Equal(t, AdminActionExecuted, request.Status)
+		assert.NotNil(t, request.DecidedAt)
+		assert.Equal(t, ErrAdminActionDecided, request.Execute(payment))
+	})
+
+	t.Run("refund needs approval", func(t *testing.T) {
+		payment := createTestConfirmedPayment()
+		request, _ := NewAdminActionRequest(payment.ID, AdminActionRefund, AdminActionParams{RefundAmount: 0.001}, ReasonCustomerRequest, "", "admin-1", true)
+
+		assert.Equal(t, ErrApprovalRequired, request.Execute(payment))
+		assert.Equal(t, ErrSelfApproval, request.Approve("admin-1"))
+		assert.NoError(t, request.Approve("admin-2"))
+		assert.NoError(t, request.Execute(payment))
+
+		assert.Equal(t, 0.001, payment.RefundedAmount)
+		assert.Equal(t, "admin-2", request.ApprovedBy)
+	})
+
+	t.Run("refund net of fees", func(t *testing.T) {
+		payment := createTestConfirmedPayment()
+		_ = payment.RecordFees(0.00002, 0)
+		request, _ := NewAdminActionRequest(payment.ID, AdminActionRefund, AdminActionParams{RefundAmount: 0.001, NetOfFees: true}, ReasonCustomerRequest, "", "admin-1", false)
+
+		assert.NoError(t, request.Execute(payment))
+
+		assert.Equal(t, 0.001, payment.RefundedAmount)
+		assert.Greater(t, payment.Refunds[0].FeesWithheld, 0.0)
+		assert.True(t, NewPaymentAuditEntry(request, "admin-1", StatusConfirmed, payment).NetOfFees)
+	})
+
+	t.Run("rejected request cannot run", func(t *testing.T) {
+		payment := createTestConfirmedPayment()
+		request, _ := NewAdminActionRequest(payment.ID, AdminActionRefund, AdminActionParams{RefundAmount: 0.002}, ReasonCustomerRequest, "", "admin-1", true)
+
+		assert.NoError(t, request.Reject("admin-2"))
+
+		assert.Equal(t, AdminActionRejected, request.Status)
+		assert.Equal(t, ErrAdminActionDecided, request.Approve("admin-3"))
+		assert.Equal(t, ErrAdminActionDecided, request.Execute(payment))
+		assert.Equal(t, StatusConfirmed, payment.Status)
+	})
+
+	t.Run("abandoned request cannot run", func(t *testing.T) {
+		payment := createTestConfirmedPayment()
+		request, _ := NewAdminActionRequest(payment.ID, AdminActionRefund, AdminActionParams{RefundAmount: 0.002}, ReasonCustomerRequest, "", "admin-1", true)
+
+		assert.NoError(t, request.Abandon())
+
+		assert.Equal(t, AdminActionFailed, request.Status)
+		assert.NotNil(t, request.DecidedAt)
+		assert.Equal(t, ErrAdminActionDecided, request.Approve("admin-2"))
+		assert.Equal(t, ErrAdminActionDecided, request.Abandon())
+	})
+
+	t.Run("failed payment action leaves the request open", func(t *testing.T) {
+		payment := createTestConfirmedPayment()
+		request, _ := NewAdminActionRequest(payment.ID, AdminActionExpire, AdminActionParams{}, ReasonGatewayError, "", "admin-1", false)
+
+		err := request.Execute(payment)
+
+		assert.Equal(t, ErrInvalidStatusTransition, err)
+		assert.Equal(t, AdminActionPendingApproval, request.Status)
+	})
+
+	t.Run("extend expiry of an overdue payment", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		payment.ExpiresAt = time.Now().Add(-time.Hour)
+		request, _ := NewAdminActionRequest(payment.ID, AdminActionExtendExpiry, AdminActionParams{Extension: 15 * time.Minute}, ReasonLatePayment, "", "admin-1", false)
+
+		err := request.Execute(payment)
+
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(15*time.Minute), payment.ExpiresAt, time.Second)
+		assert.False(t, payment.IsExpired())
+	})
+
+	t.Run("extension must be positive", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+
+		assert.Equal(t, ErrInvalidExpiryExtension, payment.ExtendExpiry(0))
+	})
+}
+
+func TestAdminApprovalPolicy(t *testing.T) {
+	policy := AdminApprovalPolicy{Currency: "USD", Threshold: 50}
+	payment := createTestConfirmedPayment() // 100 USD for 0.002 BTC
+
+	assert.True(t, policy.RequiresApproval(payment, AdminActionConfirm, AdminActionParams{}))
+	assert.True(t, policy.RequiresApproval(payment, AdminActionRefund, AdminActionParams{RefundAmount: 0.0015}))
+	assert.False(t, policy.RequiresApproval(payment, AdminActionRefund, AdminActionParams{RefundAmount: 0.0005}))
+	assert.False(t, policy.RequiresApproval(payment, AdminActionFail, AdminActionParams{}))
+	assert.False(t, AdminApprovalPolicy{}.RequiresApproval(payment, AdminActionConfirm, AdminActionParams{}))
+
+	payment.Currency = "EUR"
+	assert.True(t, policy.RequiresApproval(payment, AdminActionRefund, AdminActionParams{RefundAmount: 0.0005}))
+
+	failed, _ := NewPayment("order-123", 10.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+	_ = failed.MarkAsFailed()
+	assert.True(t, AdminApprovalPolicy{}.RequiresApproval(failed, AdminActionConfirm, AdminActionParams{}))
+	assert.True(t, policy.RequiresApproval(failed, AdminActionConfirm, AdminActionParams{}))
+
+	unapproved, _ := NewAdminActionRequest(failed.ID, AdminActionConfirm, AdminActionParams{}, ReasonVerifiedOnChain, "", "admin-1", false)
+	assert.Equal(t, ErrApprovalRequired, unapproved.Execute(failed))
+}
@@ -0,0 +1,193 @@
+package payment
+
+import (
+	"math"
+	"strings"
+	"sync/atomic"
+)
+
+// ConfirmationTier sets the confirmations required for payments up to a fiat value
+type ConfirmationTier struct {
+	UpTo  float64 // Inclusive upper bound of the fiat value, 0 = no bound
+	Scale float64 // Multiplier on the coin's RequiredConfirmations, 0 = zero-conf
+}
+
+// ConfirmationTable is a list of tiers ordered by UpTo; the last tier has no bound
+type ConfirmationTable []ConfirmationTier
+
+// ConfirmationScope describes what a payment is for, used to pick policy overrides
+type ConfirmationScope struct {
+	CategoryIDs []string // Product categories of the order's items
+	TrustLevel  string   // Customer trust level (NEW, STANDARD, TRUSTED)
+
+	// ExchangeRates are the rates the order was priced with: units of the
+	// payment's currency per unit of the keyed currency (e.g. "USD": 0.92 for
+	// an order in EUR). They value the payment in the policy's currency.
+	ExchangeRates map[string]float64
+}
+
+// ConfirmationPolicy decides how many blockchain confirmations a payment needs.
+// The fiat value picks a tier and the tier scales the coin's configured count,
+// so a coffee can be accepted from the mempool while a laptop waits for several blocks.
+//
+// Merchants can override the default table per product category or customer trust
+// level. When several overrides apply, the strictest one wins.
+type ConfirmationPolicy struct {
+	Currency    string                       // Fiat currency the tier bounds are expressed in
+	Default     ConfirmationTable            // Used when no override applies
+	Categories  map[string]ConfirmationTable // Overrides by product category ID
+	TrustLevels map[string]ConfirmationTable // Overrides by customer trust level
+}
+
+// ConfirmationRequirement is the outcome of evaluating a confirmation policy
+type ConfirmationRequirement struct {
+	Confirmations int
+	ZeroConf      bool // Accepted before the transaction is mined; it can still be double-spent
+}
+
+var defaultConfirmationPolicy atomic.Pointer[ConfirmationPolicy]
+
+func init() {
+	defaultConfirmationPolicy.Store(NewFixedConfirmationPolicy())
+}
+
+// NewFixedConfirmationPolicy creates a policy that always requires the coin's configured count
+func NewFixedConfirmationPolicy() *ConfirmationPolicy {
+	return &ConfirmationPolicy{
+		Currency: "USD",
+		Default:  ConfirmationTable{{UpTo: 0, Scale: 1}},
+	}
+}
+
+// DefaultConfirmationPolicy returns the policy applied when a payment's crypto amount is set
+func DefaultConfirmationPolicy() *ConfirmationPolicy {
+	return defaultConfirmationPolicy.Load()
+}
+
+// SetDefaultConfirmationPolicy replaces the policy applied to payments
+func SetDefaultConfirmationPolicy(policy *ConfirmationPolicy) error {
+	if policy == nil {
+		return ErrInvalidConfirmationPolicy
+	}
+
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	defaultConfirmationPolicy.Store(policy)
+	return nil
+}
+
+// Validate checks that every table is ordered and ends with an unbounded tier
+func (cp *ConfirmationPolicy) Validate() error {
+	if len(strings.TrimSpace(cp.Currency)) != 3 {
+		return ErrInvalidConfirmationPolicy
+	}
+
+	if err := cp.Default.Validate(); err != nil {
+		return err
+	}
+
+	for _, table := range cp.Categories {
+		if err := table.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, table := range cp.TrustLevels {
+		if err := table.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Evaluate computes the confirmations required to accept a payment.
+// Payments in another currency than the policy's are converted with the
+// order's exchange rate; without one they get the coin's configured count.
+func (cp *ConfirmationPolicy) Evaluate(crypto CryptoCurrency, fiatAmount float64, fiatCurrency string, scope ConfirmationScope) ConfirmationRequirement {
+	if !strings.EqualFold(cp.Currency, fiatCurrency) {
+		rate := scope.ExchangeRates[strings.ToUpper(cp.Currency)]
+		if rate <= 0 {
+			return ConfirmationRequirement{Confirmations: crypto.RequiredConfirmations}
+		}
+		fiatAmount /= rate
+	}
+
+	tables := cp.overrides(scope)
+	if len(tables) == 0 {
+		tables = []ConfirmationTable{cp.Default}
+	}
+
+	required := 0
+	for _, table := range tables {
+		if confirmations := table.confirmationsFor(fiatAmount, crypto.RequiredConfirmations); confirmations > required {
+			required = confirmations
+		}
+	}
+
+	return ConfirmationRequirement{
+		Confirmations: required,
+		ZeroConf:      required == 0,
+	}
+}
+
+// overrides returns the override tables matching the scope
+func (cp *ConfirmationPolicy) overrides(scope ConfirmationScope) []ConfirmationTable {
+	var tables []ConfirmationTable
+
+	for _, categoryID := range scope.CategoryIDs {
+		if table, ok := cp.Categories[categoryID]; ok {
+			tables = append(tables, table)
+		}
+	}
+
+	if table, ok := cp.TrustLevels[strings.ToUpper(scope.TrustLevel)]; ok {
+		tables = append(tables, table)
+	}
+
+	return tables
+}
+
+// Validate checks that tiers are ordered by bound and the last one is unbounded
+func (t ConfirmationTable) Validate() error {
+	if len(t) == 0 {
+		return ErrInvalidConfirmationPolicy
+	}
+
+	for i, tier := range t {
+		if tier.Scale < 0 || tier.UpTo < 0 {
+			return ErrInvalidConfirmationPolicy
+		}
+
+		last := i == len(t)-1
+		if last != (tier.UpTo == 0) {
+			return ErrInvalidConfirmationPolicy
+		}
+
+		if i > 0 && !last && tier.UpTo <= t[i-1].UpTo {
+			return ErrInvalidConfirmationPolicy
+		}
+	}
+
+	return nil
+}
+
+// confirmationsFor returns the confirmations for a fiat value, scaling the coin's count.
+// Scaled counts are rounded up and never drop below one unless the tier is zero-conf.
+func (t ConfirmationTable) confirmationsFor(fiatAmount float64, base int) int {
+	for _, tier := range t {
+		if tier.UpTo != 0 && fiatAmount > tier.UpTo {
+			continue
+		}
+
+		if tier.Scale == 0 {
+			return 0
+		}
+
+		return max(1, int(math.Ceil(float64(base)*tier.Scale)))
+	}
+
+	return base
+}
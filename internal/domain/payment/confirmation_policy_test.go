@@ -0,0 +1,165 @@
+package payment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test helper functions
+
+func createTestConfirmationPolicy() *ConfirmationPolicy {
+	return &ConfirmationPolicy{
+		Currency: "USD",
+		Default: ConfirmationTable{
+			{UpTo: 50, Scale: 0},
+			{UpTo: 1000, Scale: 0.5},
+			{UpTo: 10000, Scale: 1},
+			{UpTo: 0, Scale: 2},
+		},
+		Categories: map[string]ConfirmationTable{
+			"gift-cards": {{UpTo: 0, Scale: 2}},
+		},
+		TrustLevels: map[string]ConfirmationTable{
+			"TRUSTED": {{UpTo: 1000, Scale: 0}, {UpTo: 0, Scale: 1}},
+		},
+	}
+}
+
+// Tests for ConfirmationPolicy
+
+func TestConfirmationPolicyEvaluate(t *testing.T) {
+	btc := CryptoCurrency{Symbol: "BTC", RequiredConfirmations: 2}
+	eth := CryptoCurrency{Symbol: "ETH", RequiredConfirmations: 12}
+
+	testCases := []struct {
+		name          string
+		crypto        CryptoCurrency
+		amount        float64
+		currency      string
+		scope         ConfirmationScope
+		confirmations int
+		zeroConf      bool
+	}{
+		{"small amount is zero-conf", btc, 20, "USD", ConfirmationScope{}, 0, true},
+		{"tier bound is inclusive", btc, 50, "USD", ConfirmationScope{}, 0, true},
+		{"medium amount halves the count", btc, 500, "USD", ConfirmationScope{}, 1, false},
+		{"medium amount on ethereum", eth, 500, "usd", ConfirmationScope{}, 6, false},
+		{"large amount uses the coin count", btc, 5000, "USD", ConfirmationScope{}, 2, false},
+		{"very large amount doubles the count", btc, 50000, "USD", ConfirmationScope{}, 4, false},
+		{"other currency without a rate uses the coin count", btc, 20, "EUR", ConfirmationScope{}, 2, false},
+		{"other currency is converted with the order's rate", btc, 40, "EUR", ConfirmationScope{ExchangeRates: map[string]float64{"USD": 0.9}}, 0, true},
+		{"converted amount picks its tier", btc, 920, "EUR", ConfirmationScope{ExchangeRates: map[string]float64{"USD": 0.9}}, 2, false},
+		{"rate of another currency is ignored", btc, 20, "EUR", ConfirmationScope{ExchangeRates: map[string]float64{"GBP": 1.15}}, 2, false},
+		{"trusted customer is zero-conf up to 1000", btc, 500, "USD", ConfirmationScope{TrustLevel: "trusted"}, 0, true},
+		{"category override is stricter", btc, 20, "USD", ConfirmationScope{CategoryIDs: []string{"gift-cards"}}, 4, false},
+		{"unknown category uses the default", btc, 20, "USD", ConfirmationScope{CategoryIDs: []string{"books"}}, 0, true},
+		{"strictest override wins", btc, 500, "USD", ConfirmationScope{CategoryIDs: []string{"gift-cards"}, TrustLevel: "TRUSTED"}, 4, false},
+	}
+
+	policy := createTestConfirmationPolicy()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requirement := policy.Evaluate(tc.crypto, tc.amount, tc.currency, tc.scope)
+
+			assert.Equal(t, tc.confirmations, requirement.Confirmations)
+			assert.Equal(t, tc.zeroConf, requirement.ZeroConf)
+		})
+	}
+}
+
+func TestConfirmationPolicyValidate(t *testing.T) {
+	t.Run("valid policy", func(t *testing.T) {
+		assert.NoError(t, createTestConfirmationPolicy().Validate())
+		assert.NoError(t, NewFixedConfirmationPolicy().Validate())
+	})
+
+	testCases := []struct {
+		name  string
+		table ConfirmationTable
+	}{
+		{"empty table", ConfirmationTable{}},
+		{"last tier bounded", ConfirmationTable{{UpTo: 100, Scale: 1}}},
+		{"unbounded tier before the last", ConfirmationTable{{UpTo: 0, Scale: 1}, {UpTo: 0, Scale: 2}}},
+		{"tiers out of order", ConfirmationTable{{UpTo: 1000, Scale: 1}, {UpTo: 100, Scale: 0}, {UpTo: 0, Scale: 2}}},
+		{"negative scale", ConfirmationTable{{UpTo: 0, Scale: -1}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy := createTestConfirmationPolicy()
+			policy.TrustLevels["NEW"] = tc.table
+
+			assert.Equal(t, ErrInvalidConfirmationPolicy, policy.Validate())
+		})
+	}
+
+	t.Run("cannot install invalid policy", func(t *testing.T) {
+		policy := createTestConfirmationPolicy()
+		policy.Currency = ""
+
+		assert.Equal(t, ErrInvalidConfirmationPolicy, SetDefaultConfirmationPolicy(policy))
+		assert.Equal(t, ErrInvalidConfirmationPolicy, SetDefaultConfirmationPolicy(nil))
+	})
+}
+
+func TestPaymentConfirmationPolicy(t *testing.T) {
+	previous := DefaultConfirmationPolicy()
+	defer func() { _ = SetDefaultConfirmationPolicy(previous) }()
+	assert.NoError(t, SetDefaultConfirmationPolicy(createTestConfirmationPolicy()))
+
+	t.Run("required confirmations set with the crypto amount", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 500.0, "USD", "ETH", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", 30)
+
+		err := payment.UpdateCryptoAmount(0.2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 6, payment.RequiredConfirmations)
+		assert.False(t, payment.ZeroConfRisk)
+	})
+
+	t.Run("zero-conf payment confirms on broadcast", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 20.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		_ = payment.UpdateCryptoAmount(0.0003)
+
+		err := payment.MarkAsConfirming("4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")
+
+		assert.NoError(t, err)
+		assert.True(t, payment.ZeroConfRisk)
+		assert.Equal(t, StatusConfirmed, payment.Status)
+	})
+
+	t.Run("scope recomputes the requirement", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 20.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		_ = payment.UpdateCryptoAmount(0.0003)
+
+		err := payment.SetConfirmationScope(ConfirmationScope{CategoryIDs: []string{"gift-cards"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, payment.RequiredConfirmations)
+		assert.False(t, payment.ZeroConfRisk)
+	})
+
+	t.Run("order in another currency is valued with its exchange rate", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 18.0, "EUR", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		_ = payment.UpdateCryptoAmount(0.0003)
+		assert.Equal(t, 2, payment.RequiredConfirmations)
+
+		err := payment.SetConfirmationScope(ConfirmationScope{ExchangeRates: map[string]float64{"USD": 0.9}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, payment.RequiredConfirmations)
+		assert.True(t, payment.ZeroConfRisk)
+	})
+
+	t.Run("cannot change scope once the transaction is seen", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 500.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		_ = payment.UpdateCryptoAmount(0.01)
+		_ = payment.MarkAsConfirming("4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")
+
+		err := payment.SetConfirmationScope(ConfirmationScope{TrustLevel: "TRUSTED"})
+
+		assert.Equal(t, ErrInvalidStatusTransition, err)
+		assert.Equal(t, 1, payment.RequiredConfirmations)
+	})
+}
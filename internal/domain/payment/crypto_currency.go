@@ -4,96 +4,138 @@ import (
 	"strings"
 )
 
-// CryptoCurrency represents a supported cryptocurrency
+// explorerHashPlaceholder is replaced by the transaction hash in explorer URL templates
+const explorerHashPlaceholder = "{hash}"
+
+// CryptoCurrency represents a supported cryptocurrency on one network.
+// The same asset on different networks (USDT on Tron and on Ethereum) are
+// separate currencies with their own symbol, e.g. USDTTRC20 and USDTERC20.
 type CryptoCurrency struct {
-	Symbol      string  // BTC, ETH, LTC, etc.
-	Name        string  // Bitcoin, Ethereum, Litecoin, etc.
-	Decimals    int     // Number of decimal places
-	MinAmount   float64 // Minimum amount for transactions
-	IsActive    bool    // Whether this crypto is currently supported
-}
-
-// Supported cryptocurrencies (based on NowPayments)
-var (
-	Bitcoin = CryptoCurrency{
-		Symbol:    "BTC",
-		Name:      "Bitcoin", 
-		Decimals:  8,
-		MinAmount: 0.0001,
-		IsActive:  true,
+	Symbol                string  // Payable code: BTC, ETH, USDTTRC20, etc.
+	Asset                 string  // Underlying asset: BTC, ETH, USDT, etc.
+	Name                  string  // Bitcoin, Ethereum, Tether (TRC-20), etc.
+	Network               Network // Chain the coin is sent on
+	ContractAddress       string  // Token contract on the network (empty for native coins)
+	Decimals              int     // Number of decimal places
+	MinAmount             float64 // Minimum amount for transactions
+	RequiredConfirmations int     // Confirmations before a payment is final
+	IsStablecoin          bool    // Pegged to a fiat currency
+	PeggedTo              string  // Fiat currency of the peg (USD), stablecoins only
+	Testnet               bool    // Uses the network's test chain (sandbox)
+	ExplorerTxURL         string  // Block explorer link with a {hash} placeholder
+	IsActive              bool    // Whether this crypto is currently supported
+}
+
+// DefaultCryptoCurrencies returns the built-in coin list (based on NowPayments),
+// used to seed the registry when no configuration is stored yet
+func DefaultCryptoCurrencies() []CryptoCurrency {
+	return []CryptoCurrency{
+		{Symbol: "BTC", Asset: "BTC", Name: "Bitcoin", Network: NetworkBitcoin, Decimals: 8, MinAmount: 0.0001, RequiredConfirmations: 2, ExplorerTxURL: "https://mempool.space/tx/{hash}", IsActive: true},
+		{Symbol: "ETH", Asset: "ETH", Name: "Ethereum", Network: NetworkEthereum, Decimals: 18, MinAmount: 0.001, RequiredConfirmations: 12, ExplorerTxURL: "https://etherscan.io/tx/{hash}", IsActive: true},
+		{Symbol: "LTC", Asset: "LTC", Name: "Litecoin", Network: NetworkLitecoin, Decimals: 8, MinAmount: 0.001, RequiredConfirmations: 6, ExplorerTxURL: "https://blockchair.com/litecoin/transaction/{hash}", IsActive: true},
+		{Symbol: "BCH", Asset: "BCH", Name: "Bitcoin Cash", Network: NetworkBitcoinCash, Decimals: 8, MinAmount: 0.001, RequiredConfirmations: 6, ExplorerTxURL: "https://blockchair.com/bitcoin-cash/transaction/{hash}", IsActive: true},
+		{Symbol: "XRP", Asset: "XRP", Name: "Ripple", Network: NetworkRipple, Decimals: 6, MinAmount: 1.0, RequiredConfirmations: 1, ExplorerTxURL: "https://livenet.xrpl.org/transactions/{hash}", IsActive: true},
+		{Symbol: "DOGE", Asset: "DOGE", Name: "Dogecoin", Network: NetworkDogecoin, Decimals: 8, MinAmount: 1.0, RequiredConfirmations: 6, ExplorerTxURL: "https://blockchair.com/dogecoin/transaction/{hash}", IsActive: true},
+		{Symbol: "USDTTRC20", Asset: "USDT", Name: "Tether (TRC-20)", Network: NetworkTron, ContractAddress: "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", Decimals: 6, MinAmount: 1.0, RequiredConfirmations: 20, IsStablecoin: true, PeggedTo: "USD", ExplorerTxURL: "https://tronscan.org/#/transaction/{hash}", IsActive: true},
+		{Symbol: "USDTERC20", Asset: "USDT", Name: "Tether (ERC-20)", Network: NetworkEthereum, ContractAddress: "0xdAC17F958D2ee523a2206206994597C13D831ec7", Decimals: 6, MinAmount: 10.0, RequiredConfirmations: 12, IsStablecoin: true, PeggedTo: "USD", ExplorerTxURL: "https://etherscan.io/tx/{hash}", IsActive: true},
+		{Symbol: "USDC", Asset: "USDC", Name: "USD Coin (ERC-20)", Network: NetworkEthereum, ContractAddress: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", Decimals: 6, MinAmount: 10.0, RequiredConfirmations: 12, IsStablecoin: true, PeggedTo: "USD", ExplorerTxURL: "https://etherscan.io/tx/{hash}", IsActive: true},
+	}
+}
+
+// GetSupportedCryptoCurrencies returns all active cryptocurrencies in the registry
+func GetSupportedCryptoCurrencies() []CryptoCurrency {
+	return DefaultCryptoRegistry().Active()
+}
+
+// GetCryptoCurrencyBySymbol returns an active cryptocurrency from the registry by its symbol
+func GetCryptoCurrencyBySymbol(symbol string) (CryptoCurrency, error) {
+	return DefaultCryptoRegistry().Get(symbol)
+}
+
+// IsSupported checks if a cryptocurrency symbol is supported
+func IsSupported(symbol string) bool {
+	_, err := GetCryptoCurrencyBySymbol(symbol)
+	return err == nil
+}
+
+// Validate checks that the cryptocurrency configuration is usable
+func (c CryptoCurrency) Validate() error {
+	if normalizeSymbol(c.Symbol) == "" || c.Name == "" {
+		return ErrInvalidCryptoConfig
 	}
 	
-	Ethereum = CryptoCurrency{
-		Symbol:    "ETH",
-		Name:      "Ethereum",
-		Decimals:  18,
-		MinAmount: 0.001,
-		IsActive:  true,
+	if c.Decimals < 0 || c.Decimals > 18 {
+		return ErrInvalidCryptoConfig
 	}
 	
-	Litecoin = CryptoCurrency{
-		Symbol:    "LTC", 
-		Name:      "Litecoin",
-		Decimals:  8,
-		MinAmount: 0.001,
-		IsActive:  true,
+	if c.MinAmount <= 0 || c.RequiredConfirmations < 1 {
+		return ErrInvalidCryptoConfig
 	}
 	
-	BitcoinCash = CryptoCurrency{
-		Symbol:    "BCH",
-		Name:      "Bitcoin Cash",
-		Decimals:  8,
-		MinAmount: 0.001,
-		IsActive:  true,
+	if !c.Network.IsValid() {
+		return ErrUnsupportedNetwork
 	}
 	
-	Ripple = CryptoCurrency{
-		Symbol:    "XRP",
-		Name:      "Ripple",
-		Decimals:  6,
-		MinAmount: 1.0,
-		IsActive:  true,
+	// Tokens live on a contract; only some networks have them
+	if c.ContractAddress != "" && !c.Network.SupportsTokens() {
+		return ErrInvalidCryptoConfig
 	}
 	
-	Dogecoin = CryptoCurrency{
-		Symbol:    "DOGE",
-		Name:      "Dogecoin",
-		Decimals:  8,
-		MinAmount: 1.0,
-		IsActive:  true,
+	if c.IsStablecoin && c.PeggedTo == "" {
+		return ErrInvalidCryptoConfig
 	}
-)
-
-// GetSupportedCryptoCurrencies returns all supported cryptocurrencies
-func GetSupportedCryptoCurrencies() []CryptoCurrency {
-	return []CryptoCurrency{
-		Bitcoin,
-		Ethereum,
-		Litecoin,
-		BitcoinCash,
-		Ripple,
-		Dogecoin,
+	
+	if c.ExplorerTxURL != "" && !strings.Contains(c.ExplorerTxURL, explorerHashPlaceholder) {
+		return ErrInvalidCryptoConfig
 	}
+	
+	return nil
 }
 
-// GetCryptoCurrencyBySymbol returns a cryptocurrency by its symbol
-func GetCryptoCurrencyBySymbol(symbol string) (CryptoCurrency, error) {
-	symbol = strings.ToUpper(strings.TrimSpace(symbol))
-	
-	cryptos := GetSupportedCryptoCurrencies()
-	for _, crypto := range cryptos {
-		if crypto.Symbol == symbol && crypto.IsActive {
-			return crypto, nil
-		}
+// ValidateAddress checks that a wallet address can receive this currency
+func (c CryptoCurrency) ValidateAddress(walletAddress string) error {
+	if strings.TrimSpace(walletAddress) == "" {
+		return ErrInvalidWalletAddress
 	}
 	
-	return CryptoCurrency{}, ErrUnsupportedCrypto
+	return c.Network.ValidateAddress(walletAddress, c.Testnet)
 }
 
-// IsSupported checks if a cryptocurrency symbol is supported
-func IsSupported(symbol string) bool {
-	_, err := GetCryptoCurrencyBySymbol(symbol)
-	return err == nil
+// ValidateTransactionHash checks that a transaction hash has this currency's network format
+func (c CryptoCurrency) ValidateTransactionHash(hash string) error {
+	return c.Network.ValidateTransactionHash(hash)
+}
+
+// TransactionURL returns the block explorer link for a transaction (empty if not configured)
+func (c CryptoCurrency) TransactionURL(hash string) string {
+	if c.ExplorerTxURL == "" || hash == "" {
+		return ""
+	}
+	return strings.ReplaceAll(c.ExplorerTxURL, explorerHashPlaceholder, hash)
+}
+
+// IsToken checks if the currency is a token issued on another chain's contract
+func (c CryptoCurrency) IsToken() bool {
+	return c.ContractAddress != ""
+}
+
+// IsPeggedTo checks if the currency is a stablecoin pegged to the given fiat currency.
+// Such currencies need no protection against rate volatility.
+func (c CryptoCurrency) IsPeggedTo(fiatCurrency string) bool {
+	return c.IsStablecoin && strings.EqualFold(c.PeggedTo, fiatCurrency)
+}
+
+// GetAsset returns the underlying asset symbol
+func (c CryptoCurrency) GetAsset() string {
+	if c.Asset == "" {
+		return c.Symbol
+	}
+	return c.Asset
+}
+
+// GetNetwork returns the network the currency is sent on
+func (c CryptoCurrency) GetNetwork() Network {
+	return c.Network
 }
 
 // ValidateAmount checks if the amount meets minimum requirements
@@ -134,4 +176,9 @@ func (c CryptoCurrency) GetName() string {
 // GetMinAmount returns the minimum transaction amount
 func (c CryptoCurrency) GetMinAmount() float64 {
 	return c.MinAmount
+}
+
+// normalizeSymbol upper-cases and trims a coin symbol for lookups
+func normalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
 }
\ No newline at end of file
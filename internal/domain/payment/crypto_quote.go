@@ -0,0 +1,66 @@
+package payment
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CryptoQuote is a locked fiat-to-crypto price for a payment (Value Object)
+type CryptoQuote struct {
+	ID             string
+	FiatAmount     float64
+	FiatCurrency   string
+	CryptoAmount   float64
+	CryptoCurrency string  // Crypto symbol
+	Rate           float64 // Fiat units per one unit of crypto
+	Source         string  // Provider the rate came from
+	CreatedAt      time.Time
+	ExpiresAt      time.Time // End of the lock window
+}
+
+// NewCryptoQuote creates a quote locked until lockedUntil
+func NewCryptoQuote(fiatAmount float64, fiatCurrency string, cryptoAmount float64, crypto CryptoCurrency, source string, lockedUntil time.Time) (CryptoQuote, error) {
+	if fiatAmount <= 0 {
+		return CryptoQuote{}, ErrInvalidAmount
+	}
+
+	if fiatCurrency == "" {
+		return CryptoQuote{}, ErrInvalidCurrency
+	}
+
+	if err := crypto.ValidateAmount(cryptoAmount); err != nil {
+		return CryptoQuote{}, err
+	}
+
+	now := time.Now()
+	if !lockedUntil.After(now) {
+		return CryptoQuote{}, ErrQuoteExpired
+	}
+
+	return CryptoQuote{
+		ID:             uuid.New().String(),
+		FiatAmount:     fiatAmount,
+		FiatCurrency:   fiatCurrency,
+		CryptoAmount:   cryptoAmount,
+		CryptoCurrency: crypto.Symbol,
+		Rate:           fiatAmount / cryptoAmount,
+		Source:         source,
+		CreatedAt:      now,
+		ExpiresAt:      lockedUntil,
+	}, nil
+}
+
+// IsExpired checks if the lock window has passed
+func (q CryptoQuote) IsExpired() bool {
+	return !time.Now().Before(q.ExpiresAt)
+}
+
+// SlippageFrom returns the relative rate change against a previous rate (0.01 = 1%)
+func (q CryptoQuote) SlippageFrom(previousRate float64) float64 {
+	if previousRate <= 0 {
+		return 0
+	}
+	return math.Abs(q.Rate-previousRate) / previousRate
+}
@@ -0,0 +1,158 @@
+package payment
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// CryptoRegistry holds the cryptocurrencies the shop knows about, loaded from
+// configuration or the database. Coins can be enabled or disabled at runtime.
+type CryptoRegistry struct {
+	mu      sync.RWMutex
+	symbols []string // Registration order, for stable listings
+	cryptos map[string]CryptoCurrency
+}
+
+var defaultRegistry atomic.Pointer[CryptoRegistry]
+
+func init() {
+	registry, err := NewCryptoRegistry(DefaultCryptoCurrencies())
+	if err != nil {
+		panic(err)
+	}
+	defaultRegistry.Store(registry)
+}
+
+// DefaultCryptoRegistry returns the registry used by GetCryptoCurrencyBySymbol and IsSupported
+func DefaultCryptoRegistry() *CryptoRegistry {
+	return defaultRegistry.Load()
+}
+
+// SetDefaultCryptoRegistry replaces the registry used for coin lookups
+func SetDefaultCryptoRegistry(registry *CryptoRegistry) {
+	if registry != nil {
+		defaultRegistry.Store(registry)
+	}
+}
+
+// NewCryptoRegistry creates a registry from a list of cryptocurrencies
+func NewCryptoRegistry(cryptos []CryptoCurrency) (*CryptoRegistry, error) {
+	registry := &CryptoRegistry{cryptos: make(map[string]CryptoCurrency)}
+
+	for _, crypto := range cryptos {
+		if err := registry.Register(crypto); err != nil {
+			return nil, err
+		}
+	}
+
+	return registry, nil
+}
+
+// Register adds a cryptocurrency or replaces the one with the same symbol
+func (r *CryptoRegistry) Register(crypto CryptoCurrency) error {
+	if err := crypto.Validate(); err != nil {
+		return err
+	}
+
+	crypto.Symbol = normalizeSymbol(crypto.Symbol)
+	crypto.Asset = normalizeSymbol(crypto.GetAsset())
+	crypto.PeggedTo = strings.ToUpper(crypto.PeggedTo)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.cryptos[crypto.Symbol]; !exists {
+		r.symbols = append(r.symbols, crypto.Symbol)
+	}
+	r.cryptos[crypto.Symbol] = crypto
+
+	return nil
+}
+
+// Get returns an active cryptocurrency by its symbol
+func (r *CryptoRegistry) Get(symbol string) (CryptoCurrency, error) {
+	crypto, ok := r.Lookup(symbol)
+	if !ok || !crypto.IsActive {
+		return CryptoCurrency{}, ErrUnsupportedCrypto
+	}
+
+	return crypto, nil
+}
+
+// Lookup returns a cryptocurrency by its symbol, including disabled ones
+func (r *CryptoRegistry) Lookup(symbol string) (CryptoCurrency, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	crypto, ok := r.cryptos[normalizeSymbol(symbol)]
+	return crypto, ok
+}
+
+// All returns every registered cryptocurrency
+func (r *CryptoRegistry) All() []CryptoCurrency {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cryptos := make([]CryptoCurrency, 0, len(r.symbols))
+	for _, symbol := range r.symbols {
+		cryptos = append(cryptos, r.cryptos[symbol])
+	}
+
+	return cryptos
+}
+
+// Active returns the cryptocurrencies currently accepted for payment
+func (r *CryptoRegistry) Active() []CryptoCurrency {
+	var cryptos []CryptoCurrency
+	for _, crypto := range r.All() {
+		if crypto.IsActive {
+			cryptos = append(cryptos, crypto)
+		}
+	}
+
+	return cryptos
+}
+
+// ByAsset returns the active currencies of an asset, one per network
+// (e.g. USDT on Tron and on Ethereum)
+func (r *CryptoRegistry) ByAsset(asset string) []CryptoCurrency {
+	asset = normalizeSymbol(asset)
+
+	var cryptos []CryptoCurrency
+	for _, crypto := range r.Active() {
+		if crypto.Asset == asset {
+			cryptos = append(cryptos, crypto)
+		}
+	}
+
+	return cryptos
+}
+
+// GetOnNetwork returns the active currency of an asset on the given network
+func (r *CryptoRegistry) GetOnNetwork(asset string, network Network) (CryptoCurrency, error) {
+	for _, crypto := range r.ByAsset(asset) {
+		if crypto.Network == network {
+			return crypto, nil
+		}
+	}
+
+	return CryptoCurrency{}, ErrUnsupportedCrypto
+}
+
+// SetActive enables or disables a registered cryptocurrency
+func (r *CryptoRegistry) SetActive(symbol string, active bool) (CryptoCurrency, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	symbol = normalizeSymbol(symbol)
+	crypto, ok := r.cryptos[symbol]
+	if !ok {
+		return CryptoCurrency{}, ErrUnsupportedCrypto
+	}
+
+	crypto.IsActive = active
+	r.cryptos[symbol] = crypto
+
+	return crypto, nil
+}
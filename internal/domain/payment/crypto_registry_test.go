@@ -0,0 +1,172 @@
+package payment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test helper functions
+
+func createTestRegistry() *CryptoRegistry {
+	registry, _ := NewCryptoRegistry(DefaultCryptoCurrencies())
+	return registry
+}
+
+// Tests for CryptoRegistry
+
+func TestCryptoRegistry(t *testing.T) {
+	t.Run("register new coin", func(t *testing.T) {
+		registry := createTestRegistry()
+
+		err := registry.Register(CryptoCurrency{Symbol: "trx", Name: "Tron", Network: NetworkTron, Decimals: 9, MinAmount: 0.01, RequiredConfirmations: 32, IsActive: true})
+
+		assert.NoError(t, err)
+		crypto, err := registry.Get("TRX")
+		assert.NoError(t, err)
+		assert.Equal(t, "TRX", crypto.Symbol)
+		assert.Equal(t, 32, crypto.RequiredConfirmations)
+		assert.Len(t, registry.All(), len(DefaultCryptoCurrencies())+1)
+	})
+
+	t.Run("register replaces existing coin", func(t *testing.T) {
+		registry := createTestRegistry()
+		btc, _ := registry.Get("BTC")
+		btc.RequiredConfirmations = 3
+
+		err := registry.Register(btc)
+
+		assert.NoError(t, err)
+		updated, _ := registry.Get("BTC")
+		assert.Equal(t, 3, updated.RequiredConfirmations)
+		assert.Len(t, registry.All(), len(DefaultCryptoCurrencies()))
+	})
+
+	t.Run("cannot register invalid coin", func(t *testing.T) {
+		registry := createTestRegistry()
+
+		err := registry.Register(CryptoCurrency{Symbol: "BAD", Name: "Bad", Decimals: 8, MinAmount: 0})
+
+		assert.Equal(t, ErrInvalidCryptoConfig, err)
+	})
+
+	t.Run("disabled coin is not supported", func(t *testing.T) {
+		registry := createTestRegistry()
+
+		crypto, err := registry.SetActive("doge", false)
+
+		assert.NoError(t, err)
+		assert.False(t, crypto.IsActive)
+		_, err = registry.Get("DOGE")
+		assert.Equal(t, ErrUnsupportedCrypto, err)
+		assert.Len(t, registry.Active(), len(DefaultCryptoCurrencies())-1)
+		_, found := registry.Lookup("DOGE")
+		assert.True(t, found)
+	})
+
+	t.Run("cannot enable unknown coin", func(t *testing.T) {
+		registry := createTestRegistry()
+
+		_, err := registry.SetActive("XYZ", true)
+
+		assert.Equal(t, ErrUnsupportedCrypto, err)
+	})
+
+	t.Run("package lookups use the default registry", func(t *testing.T) {
+		previous := DefaultCryptoRegistry()
+		defer SetDefaultCryptoRegistry(previous)
+
+		registry := createTestRegistry()
+		_, _ = registry.SetActive("ETH", false)
+		SetDefaultCryptoRegistry(registry)
+
+		assert.False(t, IsSupported("ETH"))
+		assert.True(t, IsSupported("BTC"))
+		_, err := NewPayment("order-123", 100.0, "USD", "ETH", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", 30)
+		assert.Equal(t, ErrUnsupportedCrypto, err)
+	})
+
+	t.Run("same asset on several networks", func(t *testing.T) {
+		registry := createTestRegistry()
+
+		variants := registry.ByAsset("usdt")
+		tron, err := registry.GetOnNetwork("USDT", NetworkTron)
+
+		assert.Len(t, variants, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, "USDTTRC20", tron.Symbol)
+		assert.True(t, tron.IsToken())
+		assert.True(t, tron.IsPeggedTo("usd"))
+		assert.False(t, tron.IsPeggedTo("EUR"))
+		assert.Equal(t, AddressFormatTron, tron.Network.AddressFormat())
+	})
+
+	t.Run("disabled network variant is skipped", func(t *testing.T) {
+		registry := createTestRegistry()
+		_, _ = registry.SetActive("USDTERC20", false)
+
+		_, err := registry.GetOnNetwork("USDT", NetworkEthereum)
+
+		assert.Equal(t, ErrUnsupportedCrypto, err)
+		assert.Len(t, registry.ByAsset("USDT"), 1)
+	})
+
+	t.Run("cannot register token on a network without contracts", func(t *testing.T) {
+		registry := createTestRegistry()
+
+		err := registry.Register(CryptoCurrency{Symbol: "WBTC", Name: "Wrapped", Network: NetworkBitcoin, ContractAddress: "abc", Decimals: 8, MinAmount: 0.001, RequiredConfirmations: 2})
+
+		assert.Equal(t, ErrInvalidCryptoConfig, err)
+	})
+
+	t.Run("stablecoin needs a peg", func(t *testing.T) {
+		registry := createTestRegistry()
+
+		err := registry.Register(CryptoCurrency{Symbol: "DAI", Name: "Dai", Network: NetworkEthereum, ContractAddress: "0x6B175474E89094C44Da98b954EedeAC495271d0F", Decimals: 18, MinAmount: 1, RequiredConfirmations: 12, IsStablecoin: true})
+
+		assert.Equal(t, ErrInvalidCryptoConfig, err)
+	})
+
+	t.Run("unknown network", func(t *testing.T) {
+		_, err := ParseNetwork("solana")
+		network, _ := ParseNetwork(" Tron ")
+
+		assert.Equal(t, ErrUnsupportedNetwork, err)
+		assert.Equal(t, NetworkTron, network)
+	})
+
+	t.Run("explorer template needs a hash placeholder", func(t *testing.T) {
+		registry := createTestRegistry()
+		btc, _ := registry.Get("BTC")
+		btc.ExplorerTxURL = "https://blockstream.info/tx/"
+
+		err := registry.Register(btc)
+
+		assert.Equal(t, ErrInvalidCryptoConfig, err)
+	})
+}
+
+// Tests for Network
+
+func TestNetworkTransactionHash(t *testing.T) {
+	testCases := []struct {
+		name     string
+		network  Network
+		hash     string
+		expected error
+	}{
+		{"bitcoin txid", NetworkBitcoin, "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b", nil},
+		{"bitcoin txid too short", NetworkBitcoin, "4a5e1e4baab89f3a32518a88c31bc87f", ErrInvalidTransactionHash},
+		{"bitcoin txid not hex", NetworkDogecoin, "zz5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b", ErrInvalidTransactionHash},
+		{"ethereum hash", NetworkEthereum, "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b", nil},
+		{"ethereum hash without prefix", NetworkEthereum, "88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b", ErrInvalidTransactionHash},
+		{"xrp hash", NetworkRipple, "E08D6E9754025BA2534A78707605E0601F03ACE063687A0CA1BDDACFCD1698C7", nil},
+		{"tron transaction id", NetworkTron, "c8e3b0f1a3d4e8f0a2b9c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f2a1b0c9d8e7f6", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.network.ValidateTransactionHash(tc.hash))
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package payment
+
+import "strings"
+
+// Environment tells live payments from sandbox ones. Sandbox payments are
+// made in testnet coins and never move real funds.
+type Environment string
+
+const (
+	EnvironmentLive    Environment = "LIVE"
+	EnvironmentSandbox Environment = "SANDBOX"
+)
+
+// ParseEnvironment converts a string to an Environment; an empty string is live
+func ParseEnvironment(s string) (Environment, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" {
+		return EnvironmentLive, nil
+	}
+
+	environment := Environment(s)
+	if !environment.IsValid() {
+		return "", ErrInvalidEnvironment
+	}
+	return environment, nil
+}
+
+// IsValid checks if the environment is known
+func (e Environment) IsValid() bool {
+	return e == EnvironmentLive || e == EnvironmentSandbox
+}
+
+// IsSandbox checks if the environment is the sandbox
+func (e Environment) IsSandbox() bool {
+	return e == EnvironmentSandbox
+}
+
+// EnvironmentOf returns the environment payments in a coin belong to:
+// testnet coins are sandbox coins
+func EnvironmentOf(crypto CryptoCurrency) Environment {
+	if crypto.Testnet {
+		return EnvironmentSandbox
+	}
+	return EnvironmentLive
+}
+
+// testnetExplorerTxURLs are the block explorers of the test networks
+var testnetExplorerTxURLs = map[Network]string{
+	NetworkBitcoin:     "https://mempool.space/testnet/tx/{hash}",
+	NetworkEthereum:    "https://sepolia.etherscan.io/tx/{hash}",
+	NetworkLitecoin:    "https://litecoinspace.org/testnet/tx/{hash}",
+	NetworkBitcoinCash: "https://tbch.loping.net/tx/{hash}",
+	NetworkRipple:      "https://testnet.xrpl.org/transactions/{hash}",
+	NetworkDogecoin:    "https://sochain.com/tx/DOGETEST/{hash}",
+}
+
+// DefaultTestnetCryptoCurrencies returns the built-in coins on their test
+// networks under the same symbols, used to seed the registry in sandbox mode.
+// Tokens are left out: their contracts only exist on mainnet.
+func DefaultTestnetCryptoCurrencies() []CryptoCurrency {
+	var cryptos []CryptoCurrency
+	for _, crypto := range DefaultCryptoCurrencies() {
+		if crypto.IsToken() {
+			continue
+		}
+		crypto.Name += " Testnet"
+		crypto.Testnet = true
+		crypto.ExplorerTxURL = testnetExplorerTxURLs[crypto.Network]
+		cryptos = append(cryptos, crypto)
+	}
+	return cryptos
+}
+
+// DefaultCryptoCurrenciesFor returns the built-in coin list of an environment
+func DefaultCryptoCurrenciesFor(environment Environment) []CryptoCurrency {
+	if environment.IsSandbox() {
+		return DefaultTestnetCryptoCurrencies()
+	}
+	return DefaultCryptoCurrencies()
+}
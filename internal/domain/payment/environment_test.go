@@ -0,0 +1,63 @@
+package payment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEnvironment(t *testing.T) {
+	live, errLive := ParseEnvironment("")
+	sandbox, errSandbox := ParseEnvironment(" sandbox ")
+	_, errInvalid := ParseEnvironment("staging")
+
+	assert.NoError(t, errLive)
+	assert.NoError(t, errSandbox)
+	assert.Equal(t, EnvironmentLive, live)
+	assert.Equal(t, EnvironmentSandbox, sandbox)
+	assert.True(t, sandbox.IsSandbox())
+	assert.Equal(t, ErrInvalidEnvironment, errInvalid)
+}
+
+func TestDefaultTestnetCryptoCurrencies(t *testing.T) {
+	cryptos := DefaultTestnetCryptoCurrencies()
+
+	require.NotEmpty(t, cryptos)
+	for _, crypto := range cryptos {
+		assert.True(t, crypto.Testnet, crypto.Symbol)
+		assert.False(t, crypto.IsToken(), crypto.Symbol)
+		assert.NotEmpty(t, crypto.ExplorerTxURL, crypto.Symbol)
+		assert.Equal(t, EnvironmentSandbox, EnvironmentOf(crypto))
+	}
+
+	assert.Equal(t, DefaultCryptoCurrencies(), DefaultCryptoCurrenciesFor(EnvironmentLive))
+	assert.Equal(t, cryptos, DefaultCryptoCurrenciesFor(EnvironmentSandbox))
+}
+
+func TestPaymentEnvironment(t *testing.T) {
+	t.Run("mainnet coins make live payments", func(t *testing.T) {
+		p, err := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+
+		require.NoError(t, err)
+		assert.Equal(t, EnvironmentLive, p.Environment)
+		assert.False(t, p.IsSandbox())
+	})
+
+	t.Run("testnet coins make sandbox payments with testnet addresses", func(t *testing.T) {
+		previous := DefaultCryptoRegistry()
+		defer SetDefaultCryptoRegistry(previous)
+
+		registry, err := NewCryptoRegistry(DefaultTestnetCryptoCurrencies())
+		require.NoError(t, err)
+		SetDefaultCryptoRegistry(registry)
+
+		p, err := NewPayment("order-123", 100.0, "USD", "BTC", "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", 30)
+		_, errMainnet := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+
+		require.NoError(t, err)
+		assert.Equal(t, EnvironmentSandbox, p.Environment)
+		assert.True(t, p.IsSandbox())
+		assert.Equal(t, ErrInvalidWalletAddress, errMainnet)
+	})
+}
@@ -23,6 +23,16 @@ var (
 	ErrExcessiveAmount         = errors.New("payment amount exceeds expected amount")
 	ErrPaymentExpired          = errors.New("payment has expired")
 	ErrOrderAlreadyPaid        = errors.New("order is already paid")
+	ErrOrderNotAwaitingPayment = errors.New("order is not awaiting payment")
+)
+
+// === Quote Errors ===
+var (
+	ErrQuoteExpired            = errors.New("crypto quote has expired")
+	ErrQuoteMismatch           = errors.New("crypto quote does not match the payment")
+	ErrQuoteNotAllowed         = errors.New("payment can no longer be quoted")
+	ErrSlippageExceeded        = errors.New("exchange rate moved beyond the allowed slippage")
+	ErrRateUnavailable         = errors.New("crypto exchange rate is unavailable")
 )
 
 // === State Transition Errors ===
@@ -36,10 +46,38 @@ var (
 
 // === External Service Errors ===
 var (
-	ErrNowPaymentsAPIError     = errors.New("NowPayments API error")
+	ErrProviderAPIError        = errors.New("payment provider API error")
 	ErrPaymentServiceTimeout   = errors.New("payment service timeout")
 	ErrWebhookValidationFailed = errors.New("webhook signature validation failed")
 	ErrInvalidWebhookPayload   = errors.New("invalid webhook payload")
+	ErrUnknownGatewayStatus    = errors.New("payment gateway reported an unknown status")
+)
+
+// === Provider Errors ===
+var (
+	ErrUnknownProvider         = errors.New("payment provider is not configured")
+	ErrProviderNotSupported    = errors.New("payment provider does not support this operation")
+	ErrInvalidProviderRouting  = errors.New("payment provider routing is invalid")
+)
+
+// === Environment Errors ===
+var (
+	ErrInvalidEnvironment      = errors.New("environment must be LIVE or SANDBOX")
+	ErrEnvironmentMismatch     = errors.New("sandbox and live payments and orders cannot be mixed")
+	ErrSandboxOnly             = errors.New("only sandbox payments can be simulated")
+	ErrInvalidSimulationStep   = errors.New("simulation step is invalid")
+)
+
+// === Self-Custody Errors ===
+var (
+	ErrHDWalletNotSupported    = errors.New("HD wallets are only supported for Bitcoin and Litecoin")
+	ErrHDWalletNotFound        = errors.New("no HD wallet is configured for this cryptocurrency")
+	ErrInvalidExtendedKey      = errors.New("extended public key is invalid")
+	ErrExtendedKeyMismatch     = errors.New("a different extended public key is already configured for this cryptocurrency")
+	ErrInvalidAddressPurpose   = errors.New("address purpose must be 44 or 84")
+	ErrInvalidDerivationIndex  = errors.New("derivation index is invalid")
+	ErrUnknownDepositAddress   = errors.New("deposit address was not derived by this system")
+	ErrTransactionNotFound     = errors.New("transaction not found")
 )
 
 // === Cryptocurrency Errors ===
@@ -48,12 +86,59 @@ var (
 	ErrInvalidCryptoAmount     = errors.New("cryptocurrency amount is invalid")
 	ErrNetworkCongestion       = errors.New("cryptocurrency network is congested")
 	ErrInsufficientConfirmations = errors.New("insufficient blockchain confirmations")
+	ErrInvalidCryptoConfig     = errors.New("cryptocurrency configuration is invalid")
+	ErrUnsupportedNetwork      = errors.New("blockchain network is not supported")
+	ErrInvalidConfirmationPolicy = errors.New("confirmation policy is invalid")
+)
+
+// === Payment Request Errors ===
+var (
+	ErrPaymentURINotSupported  = errors.New("network has no payment URI scheme")
+	ErrUnsupportedQRCodeFormat = errors.New("QR code format must be png or svg")
 )
 
 // === Refund Errors ===
 var (
 	ErrRefundAlreadyProcessed  = errors.New("refund already processed")
+	ErrRefundAlreadyRequested  = errors.New("refund already requested from the provider")
 	ErrPartialRefundNotAllowed = errors.New("partial refund not allowed")
 	ErrRefundAmountExceedsPayment = errors.New("refund amount exceeds original payment")
 	ErrRefundDeadlineExpired   = errors.New("refund deadline has expired")
-)
\ No newline at end of file
+)
+
+// === Fee Errors ===
+var (
+	ErrInvalidFeePolicy         = errors.New("fee policy is invalid")
+	ErrInvalidFee               = errors.New("fees must not be negative or exceed the payment amount")
+)
+
+// === Admin Action Errors ===
+var (
+	ErrInvalidAdminAction      = errors.New("admin action is not supported")
+	ErrActorRequired           = errors.New("admin identity is required")
+	ErrInvalidReasonCode       = errors.New("reason code is invalid")
+	ErrReasonNoteRequired      = errors.New("a note is required for reason OTHER")
+	ErrApprovalRequired        = errors.New("action requires approval from a second admin")
+	ErrSelfApproval            = errors.New("admins cannot approve their own actions")
+	ErrAdminActionDecided      = errors.New("admin action was already executed or rejected")
+	ErrAdminActionNotFound     = errors.New("admin action not found")
+	ErrInvalidExpiryExtension  = errors.New("expiry extension must be positive")
+)
+
+// === Settlement Errors ===
+var (
+	ErrInvalidSettlementPeriod = errors.New("settlement period must end after it starts")
+	ErrInvalidSettlementRecord = errors.New("gateway settlement record is invalid")
+)
+
+// === Invoice Errors ===
+var (
+	ErrInvoiceNotFound         = errors.New("invoice not found")
+	ErrInvoiceExpired          = errors.New("invoice has expired")
+	ErrInvoiceCryptoSelected   = errors.New("a cryptocurrency was already selected for this invoice")
+	ErrInvoicePaymentMismatch  = errors.New("payment does not belong to the invoice's order")
+	ErrCryptoNotAllowed        = errors.New("cryptocurrency is not allowed for this invoice")
+	ErrNoAllowedCryptos        = errors.New("invoice must allow at least one cryptocurrency")
+	ErrInvalidRedirectURL      = errors.New("redirect URL must be an absolute http(s) URL")
+	ErrInvalidExpiration       = errors.New("expiration must be positive")
+)
@@ -0,0 +1,54 @@
+package payment
+
+// FeeBearer identifies who pays the gateway's service fee
+type FeeBearer string
+
+const (
+	FeeBearerMerchant FeeBearer = "MERCHANT" // Fee is deducted from what the merchant receives
+	FeeBearerCustomer FeeBearer = "CUSTOMER" // Fee is added to the amount the customer sends
+)
+
+// IsValid checks if the fee bearer is known
+func (fb FeeBearer) IsValid() bool {
+	return fb == FeeBearerMerchant || fb == FeeBearerCustomer
+}
+
+// FeePolicy decides who bears the gateway's service fee. When the customer
+// bears it, the expected fee is added to the quoted crypto amount, so the
+// merchant still receives the full price once the gateway takes its cut.
+type FeePolicy struct {
+	Bearer         FeeBearer
+	GatewayFeeRate float64 // Expected service fee as a share of the amount (0.005 = 0.5%)
+}
+
+// NewFeePolicy creates a fee policy with validation
+func NewFeePolicy(bearer FeeBearer, gatewayFeeRate float64) (FeePolicy, error) {
+	if !bearer.IsValid() || gatewayFeeRate < 0 || gatewayFeeRate >= 1 {
+		return FeePolicy{}, ErrInvalidFeePolicy
+	}
+
+	return FeePolicy{Bearer: bearer, GatewayFeeRate: gatewayFeeRate}, nil
+}
+
+// DefaultFeePolicy returns the policy where the merchant bears every fee
+func DefaultFeePolicy() FeePolicy {
+	return FeePolicy{Bearer: FeeBearerMerchant}
+}
+
+// Surcharge returns the fee added to a crypto amount for the customer to pay.
+// The gateway charges its rate on the whole amount received, surcharge included.
+func (fp FeePolicy) Surcharge(cryptoAmount float64) float64 {
+	if fp.Bearer != FeeBearerCustomer || fp.GatewayFeeRate <= 0 {
+		return 0
+	}
+	return cryptoAmount*(1/(1-fp.GatewayFeeRate)) - cryptoAmount
+}
+
+// IncludedSurcharge returns the fee within an amount that already includes
+// the surcharge, the inverse of Surcharge
+func (fp FeePolicy) IncludedSurcharge(amount float64) float64 {
+	if fp.Bearer != FeeBearerCustomer || fp.GatewayFeeRate <= 0 {
+		return 0
+	}
+	return amount * fp.GatewayFeeRate
+}
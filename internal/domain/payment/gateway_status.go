@@ -0,0 +1,93 @@
+package payment
+
+// GatewayStatus is a payment status as reported by the payment gateway
+// (e.g. NowPayments `payment_status`)
+type GatewayStatus string
+
+const (
+	GatewayStatusWaiting       GatewayStatus = "waiting"        // Waiting for the customer to send funds
+	GatewayStatusConfirming    GatewayStatus = "confirming"     // Transaction seen, being confirmed
+	GatewayStatusConfirmed     GatewayStatus = "confirmed"      // Confirmed by the blockchain
+	GatewayStatusSending       GatewayStatus = "sending"        // Funds being sent to the merchant wallet
+	GatewayStatusPartiallyPaid GatewayStatus = "partially_paid" // Customer sent less than required
+	GatewayStatusFinished      GatewayStatus = "finished"       // Funds reached the merchant wallet
+	GatewayStatusFailed        GatewayStatus = "failed"
+	GatewayStatusRefunded      GatewayStatus = "refunded"
+	GatewayStatusExpired       GatewayStatus = "expired"
+)
+
+// IsValid checks if the gateway status is known
+func (gs GatewayStatus) IsValid() bool {
+	switch gs {
+	case GatewayStatusWaiting, GatewayStatusConfirming, GatewayStatusConfirmed, GatewayStatusSending,
+		GatewayStatusPartiallyPaid, GatewayStatusFinished, GatewayStatusFailed, GatewayStatusRefunded, GatewayStatusExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsSettled checks if the gateway considers the transaction confirmed
+func (gs GatewayStatus) IsSettled() bool {
+	return gs == GatewayStatusConfirmed || gs == GatewayStatusSending || gs == GatewayStatusFinished
+}
+
+// PaymentStatus returns the payment status matching the gateway status
+func (gs GatewayStatus) PaymentStatus() PaymentStatus {
+	switch gs {
+	case GatewayStatusConfirming, GatewayStatusPartiallyPaid:
+		return StatusConfirming
+	case GatewayStatusConfirmed, GatewayStatusSending, GatewayStatusFinished:
+		return StatusConfirmed
+	case GatewayStatusFailed:
+		return StatusFailed
+	case GatewayStatusRefunded:
+		return StatusRefunded
+	case GatewayStatusExpired:
+		return StatusExpired
+	default:
+		return StatusPending
+	}
+}
+
+// GatewayPaymentStatus is the state of a payment as the gateway sees it
+type GatewayPaymentStatus struct {
+	GatewayPaymentID string
+	Status           GatewayStatus
+	TransactionHash  string  // Empty until the customer's transaction is seen
+	Confirmations    int     // 0 when the gateway does not report a count
+	ActuallyPaid     float64 // Crypto received so far
+	GatewayFee       float64 // Service fee, 0 until the gateway reports it
+	NetworkFee       float64 // Network fees the gateway paid to forward the funds
+}
+
+// GatewayPaymentRequest asks the gateway to open a payment for an order
+type GatewayPaymentRequest struct {
+	OrderID       string
+	PriceAmount   float64 // Fiat amount
+	PriceCurrency string
+	PayCurrency   string  // Crypto symbol the customer pays with
+	FeeSurcharge  float64 // Customer-borne fee in PriceCurrency, paid on top of PriceAmount
+}
+
+// GatewayPayment is a payment opened at the gateway
+type GatewayPayment struct {
+	GatewayPaymentID string
+	PayAddress       string  // Deposit address for the customer
+	PayAmount        float64 // Crypto amount the customer must send
+}
+
+// GatewayRefundRequest asks the gateway to send a refund to the customer
+type GatewayRefundRequest struct {
+	GatewayPaymentID string
+	Address          string  // Customer's refund address
+	Currency         string  // Crypto symbol of the payment
+	Amount           float64 // Crypto amount to send
+}
+
+// GatewayRefund is a refund the gateway accepted to send
+type GatewayRefund struct {
+	GatewayRefundID string
+	TransactionHash string  // Empty until the gateway broadcasts the refund
+	NetworkFee      float64 // Fee paid to send it, when known
+}
@@ -0,0 +1,32 @@
+package payment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGatewayStatus(t *testing.T) {
+	t.Run("maps to payment status", func(t *testing.T) {
+		assert.Equal(t, StatusPending, GatewayStatusWaiting.PaymentStatus())
+		assert.Equal(t, StatusConfirming, GatewayStatusPartiallyPaid.PaymentStatus())
+		assert.Equal(t, StatusConfirmed, GatewayStatusSending.PaymentStatus())
+		assert.Equal(t, StatusConfirmed, GatewayStatusFinished.PaymentStatus())
+		assert.Equal(t, StatusFailed, GatewayStatusFailed.PaymentStatus())
+		assert.Equal(t, StatusExpired, GatewayStatusExpired.PaymentStatus())
+		assert.Equal(t, StatusRefunded, GatewayStatusRefunded.PaymentStatus())
+	})
+
+	t.Run("settled statuses", func(t *testing.T) {
+		assert.True(t, GatewayStatusConfirmed.IsSettled())
+		assert.True(t, GatewayStatusFinished.IsSettled())
+		assert.False(t, GatewayStatusConfirming.IsSettled())
+		assert.False(t, GatewayStatusPartiallyPaid.IsSettled())
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		assert.True(t, GatewayStatusWaiting.IsValid())
+		assert.False(t, GatewayStatus("lost").IsValid())
+		assert.False(t, GatewayStatus("WAITING").IsValid())
+	})
+}
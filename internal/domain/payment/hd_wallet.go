@@ -0,0 +1,215 @@
+package payment
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AddressPurpose is the BIP43 purpose of an HD wallet account, which fixes
+// the kind of address derived from it
+type AddressPurpose int
+
+const (
+	PurposeLegacy       AddressPurpose = 44 // BIP44, P2PKH addresses (1…, L…)
+	PurposeNativeSegwit AddressPurpose = 84 // BIP84, P2WPKH addresses (bc1q…, ltc1q…)
+)
+
+// IsValid checks if the purpose is supported
+func (p AddressPurpose) IsValid() bool {
+	return p == PurposeLegacy || p == PurposeNativeSegwit
+}
+
+// DefaultGapLimit is the BIP44 gap limit: wallets stop looking for funds
+// after this many consecutive unused addresses
+const DefaultGapLimit = 20
+
+// maxDerivationIndex is the last non-hardened BIP32 child index
+const maxDerivationIndex = 1<<31 - 1
+
+// HDWallet is the merchant's self-custody account for one coin. Every payment
+// gets its own deposit address, derived from the account's extended public
+// key on the receive chain (m/purpose'/coin'/account'/0/index); the private
+// keys never reach this system. NextIndex only moves forward, so an address
+// is never handed out twice. Once more than GapLimit addresses sit unused
+// after the last one that received funds, the merchant's wallet may stop
+// scanning before it reaches later deposits: addresses are still handed out,
+// so checkout keeps working, and operators are alerted to raise the gap limit
+// in their wallet and here.
+type HDWallet struct {
+	CryptoSymbol      string
+	ExtendedPublicKey string // Account-level xpub, zpub, Ltub, etc.
+	Purpose           AddressPurpose
+	CoinType          int // SLIP-44 coin type: 0 Bitcoin, 2 Litecoin, 1 on testnets
+	Account           int
+	Testnet           bool
+	GapLimit          int
+	NextIndex         int // Next receive index to hand out
+	LastUsedIndex     int // Highest index that received funds, -1 if none has
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewHDWallet creates a new HD wallet with validation. The extended key is
+// only checked for presence here; deriving from it validates it. A gap limit
+// of zero selects DefaultGapLimit.
+func NewHDWallet(cryptoSymbol string, extendedPublicKey string, purpose AddressPurpose, account int, gapLimit int) (*HDWallet, error) {
+	crypto, err := GetCryptoCurrencyBySymbol(cryptoSymbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var coinType int
+	switch crypto.Network {
+	case NetworkBitcoin:
+		coinType = 0
+	case NetworkLitecoin:
+		coinType = 2
+	default:
+		return nil, ErrHDWalletNotSupported
+	}
+	if crypto.Testnet {
+		coinType = 1
+	}
+
+	if strings.TrimSpace(extendedPublicKey) == "" {
+		return nil, ErrInvalidExtendedKey
+	}
+
+	if !purpose.IsValid() {
+		return nil, ErrInvalidAddressPurpose
+	}
+
+	if account < 0 || account > maxDerivationIndex || gapLimit < 0 {
+		return nil, ErrInvalidDerivationIndex
+	}
+
+	if gapLimit == 0 {
+		gapLimit = DefaultGapLimit
+	}
+
+	now := time.Now()
+
+	return &HDWallet{
+		CryptoSymbol:      crypto.Symbol,
+		ExtendedPublicKey: strings.TrimSpace(extendedPublicKey),
+		Purpose:           purpose,
+		CoinType:          coinType,
+		Account:           account,
+		Testnet:           crypto.Testnet,
+		GapLimit:          gapLimit,
+		NextIndex:         0,
+		LastUsedIndex:     -1,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}, nil
+}
+
+// ReserveIndex hands out the next receive index, past the gap limit too; see
+// ExceedsGapLimit. The wallet must be saved together with the address derived
+// from the index, so that neither is used again.
+func (w *HDWallet) ReserveIndex() (int, error) {
+	if w.NextIndex > maxDerivationIndex {
+		return 0, ErrInvalidDerivationIndex
+	}
+
+	index := w.NextIndex
+	w.NextIndex++
+	w.UpdatedAt = time.Now()
+	return index, nil
+}
+
+// MarkIndexUsed records that the address at index received funds, which
+// makes room under the gap limit
+func (w *HDWallet) MarkIndexUsed(index int) error {
+	if index < 0 || index >= w.NextIndex {
+		return ErrInvalidDerivationIndex
+	}
+
+	if index > w.LastUsedIndex {
+		w.LastUsedIndex = index
+		w.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// UnusedAddresses returns how many addresses were handed out after the last
+// one that received funds
+func (w *HDWallet) UnusedAddresses() int {
+	return w.NextIndex - w.LastUsedIndex - 1
+}
+
+// ExceedsGapLimit checks if more addresses sit unused than the merchant's
+// wallet scans ahead
+func (w *HDWallet) ExceedsGapLimit() bool {
+	return w.UnusedAddresses() > w.GapLimit
+}
+
+// SetGapLimit changes the gap limit, after the merchant changed it in their wallet
+func (w *HDWallet) SetGapLimit(gapLimit int) error {
+	if gapLimit <= 0 {
+		return ErrInvalidDerivationIndex
+	}
+
+	w.GapLimit = gapLimit
+	w.UpdatedAt = time.Now()
+	return nil
+}
+
+// DerivationPath returns the full BIP32 path of a receive address, e.g. m/84'/0'/0'/0/5
+func (w *HDWallet) DerivationPath(index int) string {
+	return fmt.Sprintf("m/%d'/%d'/%d'/0/%d", w.Purpose, w.CoinType, w.Account, index)
+}
+
+// DepositAddress is a receive address derived from an HD wallet for one payment
+type DepositAddress struct {
+	Address      string
+	CryptoSymbol string
+	Index        int
+	OrderID      string // Order the address was derived for
+	CreatedAt    time.Time
+	UsedAt       *time.Time // First time a transaction to the address was seen
+}
+
+// NewDepositAddress creates a new deposit address record for a derived address
+func NewDepositAddress(wallet *HDWallet, index int, address string, orderID string) (*DepositAddress, error) {
+	if address == "" {
+		return nil, ErrInvalidWalletAddress
+	}
+
+	if index < 0 || index >= wallet.NextIndex {
+		return nil, ErrInvalidDerivationIndex
+	}
+
+	return &DepositAddress{
+		Address:      address,
+		CryptoSymbol: wallet.CryptoSymbol,
+		Index:        index,
+		OrderID:      orderID,
+		CreatedAt:    time.Now(),
+	}, nil
+}
+
+// IsUsed checks if a transaction to the address was seen
+func (d *DepositAddress) IsUsed() bool {
+	return d.UsedAt != nil
+}
+
+// MarkUsed records the first transaction seen to the address
+func (d *DepositAddress) MarkUsed() {
+	if d.UsedAt == nil {
+		now := time.Now()
+		d.UsedAt = &now
+	}
+}
+
+// ObservedTransaction is a transaction to a self-custody deposit address as
+// seen on chain by a watcher: a local node, or a fixture feed in tests
+type ObservedTransaction struct {
+	Address         string
+	TransactionHash string
+	Amount          float64 // Crypto amount the transaction pays to the address
+	Confirmations   int
+	Dropped         bool // No longer found on chain or in the mempool
+}
@@ -0,0 +1,125 @@
+package payment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testZpub = "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"
+
+func TestNewHDWallet(t *testing.T) {
+	t.Run("bitcoin", func(t *testing.T) {
+		wallet, err := NewHDWallet("btc", testZpub, PurposeNativeSegwit, 0, 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, "BTC", wallet.CryptoSymbol)
+		assert.Equal(t, 0, wallet.CoinType)
+		assert.Equal(t, DefaultGapLimit, wallet.GapLimit)
+		assert.Equal(t, -1, wallet.LastUsedIndex)
+		assert.Equal(t, "m/84'/0'/0'/0/7", wallet.DerivationPath(7))
+	})
+
+	t.Run("litecoin", func(t *testing.T) {
+		wallet, err := NewHDWallet("LTC", testZpub, PurposeLegacy, 1, 50)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, wallet.CoinType)
+		assert.Equal(t, 50, wallet.GapLimit)
+		assert.Equal(t, "m/44'/2'/1'/0/0", wallet.DerivationPath(0))
+	})
+
+	t.Run("invalid wallets", func(t *testing.T) {
+		_, errCoin := NewHDWallet("ETH", testZpub, PurposeNativeSegwit, 0, 0)
+		_, errKey := NewHDWallet("BTC", " ", PurposeNativeSegwit, 0, 0)
+		_, errPurpose := NewHDWallet("BTC", testZpub, AddressPurpose(49), 0, 0)
+		_, errGap := NewHDWallet("BTC", testZpub, PurposeNativeSegwit, 0, -1)
+
+		assert.Equal(t, ErrHDWalletNotSupported, errCoin)
+		assert.Equal(t, ErrInvalidExtendedKey, errKey)
+		assert.Equal(t, ErrInvalidAddressPurpose, errPurpose)
+		assert.Equal(t, ErrInvalidDerivationIndex, errGap)
+	})
+}
+
+func TestHDWalletGapLimit(t *testing.T) {
+	t.Run("indices are never handed out twice", func(t *testing.T) {
+		wallet, _ := NewHDWallet("BTC", testZpub, PurposeNativeSegwit, 0, 3)
+
+		first, _ := wallet.ReserveIndex()
+		second, _ := wallet.ReserveIndex()
+
+		assert.Equal(t, 0, first)
+		assert.Equal(t, 1, second)
+		assert.Equal(t, 2, wallet.NextIndex)
+	})
+
+	t.Run("keeps handing out indices past the gap limit", func(t *testing.T) {
+		wallet, _ := NewHDWallet("BTC", testZpub, PurposeNativeSegwit, 0, 3)
+		for i := 0; i < 3; i++ {
+			_, err := wallet.ReserveIndex()
+			require.NoError(t, err)
+		}
+		assert.False(t, wallet.ExceedsGapLimit())
+
+		index, err := wallet.ReserveIndex()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, index)
+		assert.Equal(t, 4, wallet.UnusedAddresses())
+		assert.True(t, wallet.ExceedsGapLimit())
+	})
+
+	t.Run("raising the gap limit", func(t *testing.T) {
+		wallet, _ := NewHDWallet("BTC", testZpub, PurposeNativeSegwit, 0, 3)
+		for i := 0; i < 4; i++ {
+			_, _ = wallet.ReserveIndex()
+		}
+
+		assert.Equal(t, ErrInvalidDerivationIndex, wallet.SetGapLimit(0))
+		require.NoError(t, wallet.SetGapLimit(50))
+		assert.False(t, wallet.ExceedsGapLimit())
+	})
+
+	t.Run("funds received make room", func(t *testing.T) {
+		wallet, _ := NewHDWallet("BTC", testZpub, PurposeNativeSegwit, 0, 3)
+		for i := 0; i < 3; i++ {
+			_, _ = wallet.ReserveIndex()
+		}
+
+		require.NoError(t, wallet.MarkIndexUsed(1))
+		require.NoError(t, wallet.MarkIndexUsed(0)) // An older index keeps the highest
+
+		index, err := wallet.ReserveIndex()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, index)
+		assert.Equal(t, 1, wallet.LastUsedIndex)
+	})
+
+	t.Run("index not handed out yet", func(t *testing.T) {
+		wallet, _ := NewHDWallet("BTC", testZpub, PurposeNativeSegwit, 0, 0)
+
+		assert.Equal(t, ErrInvalidDerivationIndex, wallet.MarkIndexUsed(0))
+	})
+}
+
+func TestDepositAddress(t *testing.T) {
+	wallet, _ := NewHDWallet("BTC", testZpub, PurposeNativeSegwit, 0, 0)
+	index, _ := wallet.ReserveIndex()
+
+	deposit, err := NewDepositAddress(wallet, index, "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", "order-1")
+	require.NoError(t, err)
+	assert.False(t, deposit.IsUsed())
+
+	deposit.MarkUsed()
+	usedAt := deposit.UsedAt
+	deposit.MarkUsed()
+
+	assert.True(t, deposit.IsUsed())
+	assert.Same(t, usedAt, deposit.UsedAt)
+
+	_, err = NewDepositAddress(wallet, 5, "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", "order-1")
+	assert.Equal(t, ErrInvalidDerivationIndex, err)
+}
@@ -0,0 +1,225 @@
+package payment
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvoiceStatus is the state of an invoice as shown on the payment page
+type InvoiceStatus string
+
+const (
+	InvoiceAwaitingSelection InvoiceStatus = "AWAITING_SELECTION" // Customer has not picked a coin yet
+	InvoiceAwaitingPayment   InvoiceStatus = "AWAITING_PAYMENT"   // Payment created, no transaction seen
+	InvoiceConfirming        InvoiceStatus = "CONFIRMING"         // Transaction seen, or held for review
+	InvoicePaid              InvoiceStatus = "PAID"
+	InvoiceExpired           InvoiceStatus = "EXPIRED"
+	InvoiceFailed            InvoiceStatus = "FAILED" // Payment failed or was cancelled
+)
+
+// Invoice is a request to pay an order in any of several coins. The customer
+// picks the coin on the payment page, which creates the concrete Payment.
+type Invoice struct {
+	ID      string
+	OrderID string
+
+	Amount         float64 // Fiat amount
+	Currency       string
+	AllowedCryptos []string // Coin symbols the customer can pick from
+
+	PaymentID      string // Set once the customer picked a coin
+	SelectedCrypto string
+
+	SuccessURL string // Where the payment page sends the customer once paid
+	CancelURL  string // Where it sends them when the invoice expires or fails
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ExpiresAt time.Time // Deadline to pick a coin; the payment has its own
+}
+
+// NewInvoice creates a new invoice with validation. Redirect URLs are optional
+// but must be absolute http(s) URLs when set.
+func NewInvoice(orderID string, amount float64, currency string, allowedCryptos []string, successURL, cancelURL string, expirationMinutes int) (*Invoice, error) {
+	if orderID == "" {
+		return nil, ErrEmptyOrderID
+	}
+
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	if currency == "" {
+		return nil, ErrInvalidCurrency
+	}
+
+	if expirationMinutes <= 0 {
+		return nil, ErrInvalidExpiration
+	}
+
+	allowed, err := normalizeAllowedCryptos(allowedCryptos)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, redirect := range []string{successURL, cancelURL} {
+		if !isValidRedirectURL(redirect) {
+			return nil, ErrInvalidRedirectURL
+		}
+	}
+
+	now := time.Now()
+
+	return &Invoice{
+		ID:             uuid.New().String(),
+		OrderID:        orderID,
+		Amount:         amount,
+		Currency:       currency,
+		AllowedCryptos: allowed,
+		SuccessURL:     successURL,
+		CancelURL:      cancelURL,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		ExpiresAt:      now.Add(time.Duration(expirationMinutes) * time.Minute),
+	}, nil
+}
+
+// normalizeAllowedCryptos checks that every coin is supported and removes duplicates
+func normalizeAllowedCryptos(symbols []string) ([]string, error) {
+	if len(symbols) == 0 {
+		return nil, ErrNoAllowedCryptos
+	}
+
+	allowed := make([]string, 0, len(symbols))
+	seen := make(map[string]bool, len(symbols))
+
+	for _, symbol := range symbols {
+		crypto, err := GetCryptoCurrencyBySymbol(symbol)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[crypto.Symbol] {
+			seen[crypto.Symbol] = true
+			allowed = append(allowed, crypto.Symbol)
+		}
+	}
+
+	return allowed, nil
+}
+
+// isValidRedirectURL accepts empty URLs and absolute http(s) URLs
+func isValidRedirectURL(raw string) bool {
+	if raw == "" {
+		return true
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+
+	return (parsed.Scheme == "https" || parsed.Scheme == "http") && parsed.Host != ""
+}
+
+// Allows checks if the customer can pay the invoice in the coin
+func (i *Invoice) Allows(symbol string) bool {
+	symbol = normalizeSymbol(symbol)
+	for _, allowed := range i.AllowedCryptos {
+		if allowed == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPayment checks if the customer already picked a coin
+func (i *Invoice) HasPayment() bool {
+	return i.PaymentID != ""
+}
+
+// CanSelectCrypto checks if the customer can still pick the coin
+func (i *Invoice) CanSelectCrypto(symbol string) error {
+	if i.HasPayment() {
+		return ErrInvoiceCryptoSelected
+	}
+
+	if i.IsExpired() {
+		return ErrInvoiceExpired
+	}
+
+	if !i.Allows(symbol) {
+		return ErrCryptoNotAllowed
+	}
+
+	return nil
+}
+
+// AttachPayment records the payment created for the coin the customer picked
+func (i *Invoice) AttachPayment(p *Payment) error {
+	if p.OrderID != i.OrderID {
+		return ErrInvoicePaymentMismatch
+	}
+
+	if err := i.CanSelectCrypto(p.GetCryptoSymbol()); err != nil {
+		return err
+	}
+
+	i.PaymentID = p.ID
+	i.SelectedCrypto = p.GetCryptoSymbol()
+	i.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// Status derives the invoice status from its payment, nil until a coin is picked
+func (i *Invoice) Status(p *Payment) InvoiceStatus {
+	if p == nil {
+		if i.IsExpired() {
+			return InvoiceExpired
+		}
+		return InvoiceAwaitingSelection
+	}
+
+	switch p.Status {
+	case StatusPending:
+		if p.IsExpired() {
+			return InvoiceExpired
+		}
+		return InvoiceAwaitingPayment
+	case StatusConfirming, StatusUnderReview:
+		return InvoiceConfirming
+	case StatusConfirmed, StatusRefunded:
+		return InvoicePaid
+	case StatusExpired:
+		return InvoiceExpired
+	default:
+		return InvoiceFailed
+	}
+}
+
+// RedirectURL returns where the payment page should send the customer, if anywhere yet
+func (i *Invoice) RedirectURL(status InvoiceStatus) string {
+	switch status {
+	case InvoicePaid:
+		return i.SuccessURL
+	case InvoiceExpired, InvoiceFailed:
+		return i.CancelURL
+	default:
+		return ""
+	}
+}
+
+// IsExpired checks if the deadline to pick a coin has passed
+func (i *Invoice) IsExpired() bool {
+	return time.Now().After(i.ExpiresAt)
+}
+
+// GetTimeUntilExpiry returns time until the deadline to pick a coin
+func (i *Invoice) GetTimeUntilExpiry() time.Duration {
+	if i.IsExpired() {
+		return 0
+	}
+	return time.Until(i.ExpiresAt)
+}
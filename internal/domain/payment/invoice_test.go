@@ -0,0 +1,125 @@
+package payment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// createTestInvoice creates an invoice for order123 payable in BTC or ETH
+func createTestInvoice() *Invoice {
+	inv, _ := NewInvoice("order123", 100.0, "USD", []string{"btc", "ETH"}, "https://shop.example/thanks", "https://shop.example/cart", 60)
+	return inv
+}
+
+func TestNewInvoice(t *testing.T) {
+	t.Run("valid invoice", func(t *testing.T) {
+		inv, err := NewInvoice("order123", 100.0, "USD", []string{"btc", "BTC", "eth"}, "https://shop.example/thanks", "", 60)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, inv.ID)
+		assert.Equal(t, []string{"BTC", "ETH"}, inv.AllowedCryptos)
+		assert.False(t, inv.HasPayment())
+		assert.Equal(t, InvoiceAwaitingSelection, inv.Status(nil))
+		assert.InDelta(t, time.Hour.Seconds(), inv.GetTimeUntilExpiry().Seconds(), 1)
+	})
+
+	t.Run("invalid inputs", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			allowedCryptos []string
+			successURL     string
+			expiration     int
+			expected       error
+		}{
+			{"no coins", nil, "", 60, ErrNoAllowedCryptos},
+			{"unsupported coin", []string{"BTC", "XYZ"}, "", 60, ErrUnsupportedCrypto},
+			{"relative redirect", []string{"BTC"}, "/thanks", 60, ErrInvalidRedirectURL},
+			{"non-http redirect", []string{"BTC"}, "javascript:alert(1)", 60, ErrInvalidRedirectURL},
+			{"no expiration", []string{"BTC"}, "", 0, ErrInvalidExpiration},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := NewInvoice("order123", 100.0, "USD", tt.allowedCryptos, tt.successURL, "", tt.expiration)
+				assert.ErrorIs(t, err, tt.expected)
+			})
+		}
+	})
+}
+
+func TestInvoice_AttachPayment(t *testing.T) {
+	t.Run("attaches allowed coin", func(t *testing.T) {
+		inv := createTestInvoice()
+		p, _ := NewPayment("order123", 100.0, "USD", "ETH", "0x742d35Cc6634C0532925a3b844Bc454e4438f44e", 30)
+
+		err := inv.AttachPayment(p)
+
+		assert.NoError(t, err)
+		assert.Equal(t, p.ID, inv.PaymentID)
+		assert.Equal(t, "ETH", inv.SelectedCrypto)
+		assert.Equal(t, ErrInvoiceCryptoSelected, inv.CanSelectCrypto("BTC"))
+	})
+
+	t.Run("coin not allowed", func(t *testing.T) {
+		inv, _ := NewInvoice("order123", 100.0, "USD", []string{"ETH"}, "", "", 60)
+		p, _ := NewPayment("order123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+
+		assert.Equal(t, ErrCryptoNotAllowed, inv.AttachPayment(p))
+	})
+
+	t.Run("payment for another order", func(t *testing.T) {
+		inv := createTestInvoice()
+		p, _ := NewPayment("order456", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+
+		assert.Equal(t, ErrInvoicePaymentMismatch, inv.AttachPayment(p))
+	})
+
+	t.Run("expired invoice", func(t *testing.T) {
+		inv := createTestInvoice()
+		inv.ExpiresAt = time.Now().Add(-time.Minute)
+
+		assert.Equal(t, ErrInvoiceExpired, inv.CanSelectCrypto("BTC"))
+		assert.Equal(t, InvoiceExpired, inv.Status(nil))
+		assert.Equal(t, "https://shop.example/cart", inv.RedirectURL(inv.Status(nil)))
+	})
+}
+
+func TestInvoice_Status(t *testing.T) {
+	inv := createTestInvoice()
+
+	tests := []struct {
+		status   PaymentStatus
+		expected InvoiceStatus
+		redirect string
+	}{
+		{StatusPending, InvoiceAwaitingPayment, ""},
+		{StatusConfirming, InvoiceConfirming, ""},
+		{StatusUnderReview, InvoiceConfirming, ""},
+		{StatusConfirmed, InvoicePaid, "https://shop.example/thanks"},
+		{StatusRefunded, InvoicePaid, "https://shop.example/thanks"},
+		{StatusExpired, InvoiceExpired, "https://shop.example/cart"},
+		{StatusFailed, InvoiceFailed, "https://shop.example/cart"},
+		{StatusCancelled, InvoiceFailed, "https://shop.example/cart"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			p, _ := NewPayment("order123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+			p.Status = tt.status
+
+			status := inv.Status(p)
+
+			assert.Equal(t, tt.expected, status)
+			assert.Equal(t, tt.redirect, inv.RedirectURL(status))
+		})
+	}
+
+	t.Run("overdue pending payment", func(t *testing.T) {
+		p, _ := NewPayment("order123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		p.ExpiresAt = time.Now().Add(-time.Minute)
+
+		assert.Equal(t, InvoiceExpired, inv.Status(p))
+	})
+}
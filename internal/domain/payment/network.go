@@ -0,0 +1,145 @@
+package payment
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment/address"
+)
+
+// Network represents the blockchain a cryptocurrency is sent on
+type Network string
+
+const (
+	NetworkBitcoin     Network = "bitcoin"
+	NetworkEthereum    Network = "ethereum"
+	NetworkTron        Network = "tron"
+	NetworkLitecoin    Network = "litecoin"
+	NetworkBitcoinCash Network = "bitcoincash"
+	NetworkRipple      Network = "ripple"
+	NetworkDogecoin    Network = "dogecoin"
+)
+
+// AddressFormat represents how wallet addresses are encoded on a network
+type AddressFormat string
+
+const (
+	AddressFormatBitcoin  AddressFormat = "BITCOIN"  // Base58Check P2PKH/P2SH or Bech32/Bech32m
+	AddressFormatCashAddr AddressFormat = "CASHADDR" // Bitcoin Cash CashAddr (legacy Base58 accepted)
+	AddressFormatEVM      AddressFormat = "EVM"      // 0x-prefixed hex, EIP-55 checksum
+	AddressFormatTron     AddressFormat = "TRON"     // Base58Check starting with T
+	AddressFormatRipple   AddressFormat = "RIPPLE"   // Classic r-address with optional destination tag
+	AddressFormatBase58   AddressFormat = "BASE58"   // Base58Check with network version bytes
+)
+
+// ParseNetwork converts a string to a Network
+func ParseNetwork(s string) (Network, error) {
+	network := Network(strings.ToLower(strings.TrimSpace(s)))
+	if !network.IsValid() {
+		return "", ErrUnsupportedNetwork
+	}
+	return network, nil
+}
+
+// IsValid checks if the network is supported
+func (n Network) IsValid() bool {
+	switch n {
+	case NetworkBitcoin, NetworkEthereum, NetworkTron, NetworkLitecoin, NetworkBitcoinCash, NetworkRipple, NetworkDogecoin:
+		return true
+	default:
+		return false
+	}
+}
+
+// AddressFormat returns the wallet address encoding used on the network
+func (n Network) AddressFormat() AddressFormat {
+	switch n {
+	case NetworkBitcoin, NetworkLitecoin:
+		return AddressFormatBitcoin
+	case NetworkBitcoinCash:
+		return AddressFormatCashAddr
+	case NetworkEthereum:
+		return AddressFormatEVM
+	case NetworkTron:
+		return AddressFormatTron
+	case NetworkRipple:
+		return AddressFormatRipple
+	default:
+		return AddressFormatBase58
+	}
+}
+
+// ValidateAddress checks that a wallet address is valid on the network.
+// Testnet selects the test network's address versions and prefixes.
+func (n Network) ValidateAddress(walletAddress string, testnet bool) error {
+	var err error
+
+	switch n {
+	case NetworkBitcoin:
+		err = address.ValidateUTXO(walletAddress, pickParams(testnet, address.BitcoinMainnet, address.BitcoinTestnet))
+	case NetworkLitecoin:
+		err = address.ValidateUTXO(walletAddress, pickParams(testnet, address.LitecoinMainnet, address.LitecoinTestnet))
+	case NetworkBitcoinCash:
+		err = address.ValidateUTXO(walletAddress, pickParams(testnet, address.BitcoinCashMainnet, address.BitcoinCashTestnet))
+	case NetworkDogecoin:
+		err = address.ValidateUTXO(walletAddress, pickParams(testnet, address.DogecoinMainnet, address.DogecoinTestnet))
+	case NetworkEthereum:
+		err = address.ValidateEthereum(walletAddress)
+	case NetworkTron:
+		err = address.ValidateTron(walletAddress)
+	case NetworkRipple:
+		err = address.ValidateRipple(walletAddress)
+	default:
+		return ErrUnsupportedNetwork
+	}
+
+	if err != nil {
+		return ErrInvalidWalletAddress
+	}
+
+	return nil
+}
+
+// ValidateTransactionHash checks that a transaction hash has the network's format:
+// 0x-prefixed 64 hex characters on Ethereum, 64 hex characters elsewhere
+// (Bitcoin-family txids, Tron transaction IDs and XRP ledger hashes)
+func (n Network) ValidateTransactionHash(hash string) error {
+	if !n.IsValid() {
+		return ErrUnsupportedNetwork
+	}
+
+	if n == NetworkEthereum {
+		if !strings.HasPrefix(hash, "0x") {
+			return ErrInvalidTransactionHash
+		}
+		hash = hash[2:]
+	}
+
+	if len(hash) != 64 {
+		return ErrInvalidTransactionHash
+	}
+
+	if _, err := hex.DecodeString(hash); err != nil {
+		return ErrInvalidTransactionHash
+	}
+
+	return nil
+}
+
+// SupportsTokens checks if tokens (contracts) can be issued on the network
+func (n Network) SupportsTokens() bool {
+	return n == NetworkEthereum || n == NetworkTron
+}
+
+// String returns the string representation of the network
+func (n Network) String() string {
+	return string(n)
+}
+
+// pickParams selects mainnet or testnet address parameters
+func pickParams(testnet bool, mainnet, test address.UTXOParams) address.UTXOParams {
+	if testnet {
+		return test
+	}
+	return mainnet
+}
@@ -1,6 +1,7 @@
 package payment
 
 import (
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,8 +10,9 @@ import (
 // Payment represents a payment transaction (Aggregate Root)
 type Payment struct {
 	// Identity
-	ID      string
-	OrderID string
+	ID          string
+	OrderID     string
+	Environment Environment // SANDBOX for testnet coins, LIVE otherwise
 
 	// Payment Details
 	Amount         float64
@@ -18,26 +20,77 @@ type Payment struct {
 	CryptoAmount   float64     // Amount in cryptocurrency
 	CryptoCurrency CryptoCurrency
 	
+	// Quote used to compute CryptoAmount (kept for audit)
+	QuoteID        string
+	QuoteRate      float64    // Fiat units per one unit of crypto
+	QuoteSource    string
+	QuotedAt       *time.Time
+	QuoteExpiresAt *time.Time // End of the quote's lock window
+	
+	// Fees, in the payment's cryptocurrency
+	FeeBearer      FeeBearer  // Who bears the gateway fee, set by the fee policy
+	FeeSurcharge   float64    // Customer-borne fee included in CryptoAmount
+	GatewayFee     float64    // Service fee withheld by the gateway
+	NetworkFee     float64    // Network fee the gateway paid to forward the funds
+	FeesRecordedAt *time.Time // When the gateway first reported the fees
+	FeeAdjustments []FeeAdjustment // Later reports that changed the fees, oldest first
+	
 	// Status and Lifecycle
 	Status    PaymentStatus
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	ExpiresAt time.Time
+	ConfirmedAt *time.Time // First time the payment was confirmed
 	
 	// Transaction Details
 	PaymentMethod    PaymentMethod
 	TransactionHash  string    // Blockchain transaction hash
 	Confirmations    int       // Number of blockchain confirmations
-	RequiredConfirmations int  // Required confirmations for completion
+	RequiredConfirmations int  // Required confirmations for completion, set by the confirmation policy
+	ConfirmationScope ConfirmationScope // Order categories and customer trust level used by the policy
+	ZeroConfRisk     bool      // Accepted without confirmations; a double-spend can still reverse it
+	ReplacedTransactionHash string // Transaction that TransactionHash replaced, if any
+	HoldReason       HoldReason // Why the payment is under review
+	HeldAt           *time.Time
 
 	// External Service Integration
-	NowPaymentsID    string    // NowPayments payment ID
+	Provider         string    // Payment provider that opened the payment
+	ProviderReference string   // Payment ID at the provider
 	CallbackURL      string    // Webhook callback URL
 	
 	// Refund Information
 	RefundedAmount   float64
-	RefundTransactionHash string
+	RefundTransactionHash string    // Hash of the most recent refund
 	RefundedAt       *time.Time
+	Refunds          []RefundRecord // Every refund issued, oldest first
+}
+
+// RefundRecord represents a single (partial) refund issued on a payment
+type RefundRecord struct {
+	Amount          float64    // Crypto amount of the payment refunded
+	FeesWithheld    float64    // Share of the payment's fees kept back from a net-of-fees refund
+	TransactionHash string     // Set once the refund is sent on-chain
+	NetworkFee      float64    // Miner or gas fee the merchant paid to send it
+	ProviderReference string   // Refund ID at the provider, when the provider sends it
+	RequestedAt     time.Time
+	SentAt          *time.Time
+}
+
+// IsSent checks if the refund transaction has been broadcast
+func (r RefundRecord) IsSent() bool {
+	return r.TransactionHash != ""
+}
+
+// NetAmount returns the crypto amount sent back to the customer
+func (r RefundRecord) NetAmount() float64 {
+	return r.Amount - r.FeesWithheld
+}
+
+// FeeAdjustment records a fee report that changed the fees reported before
+type FeeAdjustment struct {
+	GatewayFee float64 // Change of the service fee, negative when lowered
+	NetworkFee float64 // Change of the network fees, negative when lowered
+	RecordedAt time.Time
 }
 
 // NewPayment creates a new payment with validation
@@ -71,13 +124,15 @@ func NewPayment(orderID string, amount float64, currency string, cryptoSymbol st
 	expiresAt := now.Add(time.Duration(expirationMinutes) * time.Minute)
 	
 	payment := &Payment{
-		ID:      uuid.New().String(),
-		OrderID: orderID,
+		ID:          uuid.New().String(),
+		OrderID:     orderID,
+		Environment: EnvironmentOf(crypto),
 		
 		Amount:         amount,
 		Currency:       currency,
 		CryptoAmount:   0, // Will be set when crypto rate is calculated
 		CryptoCurrency: crypto,
+		FeeBearer:      FeeBearerMerchant,
 		
 		Status:    StatusPending,
 		CreatedAt: now,
@@ -87,7 +142,7 @@ func NewPayment(orderID string, amount float64, currency string, cryptoSymbol st
 		PaymentMethod:         paymentMethod,
 		TransactionHash:       "",
 		Confirmations:         0,
-		RequiredConfirmations: getRequiredConfirmations(crypto),
+		RequiredConfirmations: crypto.RequiredConfirmations,
 		
 		RefundedAmount: 0,
 	}
@@ -111,22 +166,222 @@ func (p *Payment) UpdateCryptoAmount(cryptoAmount float64) error {
 	}
 	
 	p.CryptoAmount = cryptoAmount
+	p.FeeSurcharge = 0 // A new amount comes without fees; the fee policy adds them again
+	p.applyConfirmationPolicy()
 	p.UpdatedAt = time.Now()
 	
 	return nil
 }
 
-// SetNowPaymentsID sets the external payment service ID
-func (p *Payment) SetNowPaymentsID(nowPaymentsID string) error {
+// SetConfirmationScope records what the payment is for, so the confirmation policy
+// can apply category and trust level overrides. Only allowed before a transaction is seen.
+func (p *Payment) SetConfirmationScope(scope ConfirmationScope) error {
+	if p.Status != StatusPending {
+		return ErrInvalidStatusTransition
+	}
+	
+	p.ConfirmationScope = scope
+	if p.CryptoAmount > 0 {
+		p.applyConfirmationPolicy()
+	}
+	p.UpdatedAt = time.Now()
+	
+	return nil
+}
+
+// applyConfirmationPolicy computes the required confirmations for the payment's fiat value
+func (p *Payment) applyConfirmationPolicy() {
+	requirement := DefaultConfirmationPolicy().Evaluate(p.CryptoCurrency, p.Amount, p.Currency, p.ConfirmationScope)
+	
+	p.RequiredConfirmations = requirement.Confirmations
+	p.ZeroConfRisk = requirement.ZeroConf
+}
+
+// QuoteLockUntil returns when a quote issued now for the given window must expire.
+// A quote is never locked beyond the payment's own expiry.
+func (p *Payment) QuoteLockUntil(window time.Duration) time.Time {
+	lockedUntil := time.Now().Add(window)
+	if lockedUntil.After(p.ExpiresAt) {
+		return p.ExpiresAt
+	}
+	return lockedUntil
+}
+
+// ApplyQuote sets the crypto amount from a locked quote and records the quote for audit
+func (p *Payment) ApplyQuote(quote CryptoQuote) error {
 	if p.Status.IsFinal() {
 		return ErrCannotUpdateFinalPayment
 	}
 	
-	if nowPaymentsID == "" {
+	if p.Status != StatusPending {
+		return ErrQuoteNotAllowed
+	}
+	
+	if p.IsExpired() {
+		return ErrPaymentExpired
+	}
+	
+	if quote.FiatAmount != p.Amount || quote.FiatCurrency != p.Currency || quote.CryptoCurrency != p.CryptoCurrency.Symbol {
+		return ErrQuoteMismatch
+	}
+	
+	if quote.IsExpired() || quote.ExpiresAt.After(p.ExpiresAt) {
+		return ErrQuoteExpired
+	}
+	
+	if err := p.UpdateCryptoAmount(quote.CryptoAmount); err != nil {
+		return err
+	}
+	
+	quotedAt := quote.CreatedAt
+	expiresAt := quote.ExpiresAt
+	p.QuoteID = quote.ID
+	p.QuoteRate = quote.Rate
+	p.QuoteSource = quote.Source
+	p.QuotedAt = &quotedAt
+	p.QuoteExpiresAt = &expiresAt
+	
+	return nil
+}
+
+// Requote replaces the current quote, rejecting it if the rate moved more than
+// maxSlippage (0.01 = 1%) from the previously quoted rate.
+// Stablecoins pegged to the payment currency skip the slippage check.
+func (p *Payment) Requote(quote CryptoQuote, maxSlippage float64) error {
+	volatile := !p.CryptoCurrency.IsPeggedTo(p.Currency)
+	if volatile && p.HasQuote() && quote.SlippageFrom(p.QuoteRate) > maxSlippage {
+		return ErrSlippageExceeded
+	}
+	
+	return p.ApplyQuote(quote)
+}
+
+// ApplyFeePolicy adds the customer-borne fee to the crypto amount. A surcharge
+// added earlier is replaced, so the policy can be applied again after a re-quote.
+func (p *Payment) ApplyFeePolicy(policy FeePolicy) error {
+	if p.Status != StatusPending {
+		return ErrInvalidStatusTransition
+	}
+	
+	base := p.CryptoAmount - p.FeeSurcharge
+	if base <= 0 {
+		return ErrInvalidAmount
+	}
+	
+	bearer := policy.Bearer
+	if !bearer.IsValid() {
+		bearer = FeeBearerMerchant
+	}
+	
+	surcharge := policy.Surcharge(base)
+	p.FeeBearer = bearer
+	p.FeeSurcharge = surcharge
+	p.CryptoAmount = base + surcharge
+	p.UpdatedAt = time.Now()
+	
+	return nil
+}
+
+// ApplyIncludedFeePolicy records the customer-borne fee already included in
+// the crypto amount, as when the provider priced the surcharge in
+func (p *Payment) ApplyIncludedFeePolicy(policy FeePolicy) error {
+	if p.Status != StatusPending {
+		return ErrInvalidStatusTransition
+	}
+	
+	if p.CryptoAmount <= 0 {
+		return ErrInvalidAmount
+	}
+	
+	bearer := policy.Bearer
+	if !bearer.IsValid() {
+		bearer = FeeBearerMerchant
+	}
+	
+	p.FeeBearer = bearer
+	p.FeeSurcharge = policy.IncludedSurcharge(p.CryptoAmount)
+	p.UpdatedAt = time.Now()
+	
+	return nil
+}
+
+// RecordFees records the service and network fees the gateway reported.
+// Gateways report them again on every status update, and may add fees
+// charged later in the payment's life, such as NowPayments' withdrawal fee
+// once the funds are forwarded. A report with other amounts replaces the fees
+// and is kept as an adjustment, so fees already posted to the ledger are
+// corrected by a further entry.
+func (p *Payment) RecordFees(gatewayFee, networkFee float64) error {
+	if gatewayFee < 0 || networkFee < 0 || gatewayFee+networkFee > p.CryptoAmount {
+		return ErrInvalidFee
+	}
+	
+	now := time.Now()
+	if p.FeesRecordedAt == nil {
+		p.FeesRecordedAt = &now
+	} else {
+		if gatewayFee == p.GatewayFee && networkFee == p.NetworkFee {
+			return nil
+		}
+		p.FeeAdjustments = append(p.FeeAdjustments, FeeAdjustment{
+			GatewayFee: gatewayFee - p.GatewayFee,
+			NetworkFee: networkFee - p.NetworkFee,
+			RecordedAt: now,
+		})
+	}
+	
+	p.GatewayFee = gatewayFee
+	p.NetworkFee = networkFee
+	p.UpdatedAt = now
+	
+	return nil
+}
+
+// FeeReportCount returns how many fee reports changed the payment's fees
+func (p *Payment) FeeReportCount() int {
+	if p.FeesRecordedAt == nil {
+		return 0
+	}
+	return 1 + len(p.FeeAdjustments)
+}
+
+// InitialFees returns the fees of the first report, before any adjustment
+func (p *Payment) InitialFees() (gatewayFee, networkFee float64) {
+	gatewayFee, networkFee = p.GatewayFee, p.NetworkFee
+	for _, adjustment := range p.FeeAdjustments {
+		gatewayFee -= adjustment.GatewayFee
+		networkFee -= adjustment.NetworkFee
+	}
+	return math.Max(gatewayFee, 0), math.Max(networkFee, 0)
+}
+
+// HasQuote checks if the crypto amount was set from a quote
+func (p *Payment) HasQuote() bool {
+	return p.QuoteID != ""
+}
+
+// IsQuoteLocked checks if the current quote is still within its lock window
+func (p *Payment) IsQuoteLocked() bool {
+	return p.QuoteExpiresAt != nil && time.Now().Before(*p.QuoteExpiresAt)
+}
+
+// SetProviderReference records the payment provider that opened the payment
+// and the payment's ID there
+func (p *Payment) SetProviderReference(provider string, reference string) error {
+	if p.Status.IsFinal() {
+		return ErrCannotUpdateFinalPayment
+	}
+	
+	if provider == "" {
+		return ErrUnknownProvider
+	}
+	
+	if reference == "" {
 		return ErrEmptyPaymentID
 	}
 	
-	p.NowPaymentsID = nowPaymentsID
+	p.Provider = provider
+	p.ProviderReference = reference
 	p.UpdatedAt = time.Now()
 	
 	return nil
@@ -154,8 +409,8 @@ func (p *Payment) MarkAsConfirming(transactionHash string) error {
 		return ErrPaymentExpired
 	}
 	
-	if transactionHash == "" {
-		return ErrInvalidTransactionHash
+	if err := p.CryptoCurrency.ValidateTransactionHash(transactionHash); err != nil {
+		return err
 	}
 	
 	p.Status = StatusConfirming
@@ -163,12 +418,20 @@ func (p *Payment) MarkAsConfirming(transactionHash string) error {
 	p.Confirmations = 0
 	p.UpdatedAt = time.Now()
 	
+	// Zero-conf payments are accepted as soon as the transaction is broadcast
+	if p.RequiredConfirmations == 0 {
+		return p.markAsConfirmed()
+	}
+	
 	return nil
 }
 
-// UpdateConfirmations updates the number of blockchain confirmations
+// UpdateConfirmations updates the number of blockchain confirmations.
+// A count lower than the one already seen means the transaction's block was
+// reorganised away: if that leaves it short of the requirement, the payment is
+// put under review, even when it was already confirmed.
 func (p *Payment) UpdateConfirmations(confirmations int) error {
-	if p.Status != StatusConfirming {
+	if !p.isTrackingTransaction() {
 		return ErrInvalidStatusTransition
 	}
 	
@@ -176,26 +439,103 @@ func (p *Payment) UpdateConfirmations(confirmations int) error {
 		return ErrInsufficientConfirmations
 	}
 	
+	dropped := confirmations < p.Confirmations && confirmations < p.RequiredConfirmations
+	
 	p.Confirmations = confirmations
 	p.UpdatedAt = time.Now()
 	
-	// Auto-confirm if we have enough confirmations
-	if confirmations >= p.RequiredConfirmations {
+	if dropped && p.Status != StatusUnderReview {
+		p.hold(HoldReasonConfirmationsDropped)
+		return nil
+	}
+	
+	// Auto-confirm (or re-confirm a held payment) if we have enough confirmations
+	if p.Status != StatusConfirmed && confirmations >= p.confirmationsToRelease() {
 		return p.markAsConfirmed()
 	}
 	
 	return nil
 }
 
+// MarkTransactionReplaced records that the payment's transaction was replaced by
+// another one (fee bump or double-spend) and holds the payment until the replacement confirms
+func (p *Payment) MarkTransactionReplaced(replacementHash string) error {
+	if !p.isTrackingTransaction() {
+		return ErrInvalidStatusTransition
+	}
+	
+	if err := p.CryptoCurrency.ValidateTransactionHash(replacementHash); err != nil {
+		return err
+	}
+	
+	if replacementHash == p.TransactionHash {
+		return nil
+	}
+	
+	p.ReplacedTransactionHash = p.TransactionHash
+	p.TransactionHash = replacementHash
+	p.Confirmations = 0
+	p.UpdatedAt = time.Now()
+	p.hold(HoldReasonTransactionReplaced)
+	
+	return nil
+}
+
+// MarkTransactionDropped holds the payment when its transaction is no longer
+// found on chain or in the mempool
+func (p *Payment) MarkTransactionDropped() error {
+	if !p.isTrackingTransaction() {
+		return ErrInvalidStatusTransition
+	}
+	
+	p.Confirmations = 0
+	p.UpdatedAt = time.Now()
+	p.hold(HoldReasonTransactionDropped)
+	
+	return nil
+}
+
+// isTrackingTransaction checks if the payment has a transaction whose confirmations are followed
+func (p *Payment) isTrackingTransaction() bool {
+	return p.Status == StatusConfirming || p.Status == StatusConfirmed || p.Status == StatusUnderReview
+}
+
+// confirmationsToRelease returns the confirmations needed to (re-)confirm the payment.
+// A held payment needs at least one, even under a zero-conf policy.
+func (p *Payment) confirmationsToRelease() int {
+	if p.Status == StatusUnderReview && p.RequiredConfirmations == 0 {
+		return 1
+	}
+	return p.RequiredConfirmations
+}
+
+// hold puts the payment under review
+func (p *Payment) hold(reason HoldReason) {
+	now := time.Now()
+	
+	p.Status = StatusUnderReview
+	p.HoldReason = reason
+	if p.HeldAt == nil {
+		p.HeldAt = &now
+	}
+}
+
 // markAsConfirmed transitions payment to confirmed status (internal method)
 func (p *Payment) markAsConfirmed() error {
-	// Allow confirming from pending, confirming, or failed status
-	if p.Status != StatusPending && p.Status != StatusConfirming && p.Status != StatusFailed {
+	// Allow confirming from pending, confirming, under review or failed status
+	if p.Status != StatusPending && p.Status != StatusConfirming && p.Status != StatusUnderReview && p.Status != StatusFailed {
 		return ErrInvalidStatusTransition
 	}
 	
+	now := time.Now()
+	
 	p.Status = StatusConfirmed
-	p.UpdatedAt = time.Now()
+	p.HoldReason = ""
+	p.HeldAt = nil
+	if p.ConfirmedAt == nil {
+		p.ConfirmedAt = &now
+	}
+	p.UpdatedAt = now
 	
 	return nil
 }
@@ -206,8 +546,8 @@ func (p *Payment) MarkAsConfirmed() error {
 		return ErrPaymentAlreadyConfirmed
 	}
 	
-	// Allow confirming from pending, confirming, or failed status
-	if p.Status != StatusPending && p.Status != StatusConfirming && p.Status != StatusFailed {
+	// Allow confirming from pending, confirming, under review or failed status
+	if p.Status != StatusPending && p.Status != StatusConfirming && p.Status != StatusUnderReview && p.Status != StatusFailed {
 		return ErrCannotConfirmPayment
 	}
 	
@@ -238,6 +578,28 @@ func (p *Payment) MarkAsExpired() error {
 	return nil
 }
 
+// ExtendExpiry gives the customer more time to pay. An overdue payment is
+// extended from now rather than from its original deadline.
+func (p *Payment) ExtendExpiry(extension time.Duration) error {
+	if p.Status != StatusPending {
+		return ErrInvalidStatusTransition
+	}
+	
+	if extension <= 0 {
+		return ErrInvalidExpiryExtension
+	}
+	
+	now := time.Now()
+	if p.ExpiresAt.Before(now) {
+		p.ExpiresAt = now
+	}
+	
+	p.ExpiresAt = p.ExpiresAt.Add(extension)
+	p.UpdatedAt = now
+	
+	return nil
+}
+
 // Cancel cancels the payment if it's still cancellable
 func (p *Payment) Cancel() error {
 	if !p.Status.CanBeCancelled() {
@@ -255,8 +617,25 @@ func (p *Payment) Refund() error {
 	return p.PartialRefund(p.CryptoAmount)
 }
 
+// RefundNetOfFees processes a full refund, keeping back the payment's fees
+func (p *Payment) RefundNetOfFees() error {
+	return p.PartialRefundNetOfFees(p.CryptoAmount)
+}
+
 // PartialRefund processes a partial refund of the payment
 func (p *Payment) PartialRefund(refundAmount float64) error {
+	return p.issueRefund(refundAmount, false)
+}
+
+// PartialRefundNetOfFees refunds part of the payment, keeping back the same
+// share of the gateway and network fees. The customer receives less than
+// refundAmount, but refundAmount counts against the payment.
+func (p *Payment) PartialRefundNetOfFees(refundAmount float64) error {
+	return p.issueRefund(refundAmount, true)
+}
+
+// issueRefund records a refund of refundAmount, optionally net of fees
+func (p *Payment) issueRefund(refundAmount float64, netOfFees bool) error {
 	if !p.Status.CanBeRefunded() {
 		return ErrCannotRefundPayment
 	}
@@ -269,13 +648,22 @@ func (p *Payment) PartialRefund(refundAmount float64) error {
 		return ErrRefundAmountExceedsPayment
 	}
 	
-	if p.RefundedAmount > 0 {
+	// Only one refund can be in flight at a time
+	if p.HasPendingRefund() {
 		return ErrRefundAlreadyProcessed
 	}
 	
 	p.RefundedAmount += refundAmount
 	now := time.Now()
 	p.RefundedAt = &now
+	refund := RefundRecord{
+		Amount:      refundAmount,
+		RequestedAt: now,
+	}
+	if netOfFees {
+		refund.FeesWithheld = p.FeesShare(refundAmount)
+	}
+	p.Refunds = append(p.Refunds, refund)
 	
 	// If fully refunded, mark as refunded
 	if p.RefundedAmount >= p.CryptoAmount {
@@ -289,20 +677,82 @@ func (p *Payment) PartialRefund(refundAmount float64) error {
 
 // SetRefundTransactionHash sets the blockchain transaction hash for the refund
 func (p *Payment) SetRefundTransactionHash(transactionHash string) error {
+	return p.RecordRefundSent(transactionHash, 0)
+}
+
+// RecordRefundSent records the transaction of the latest refund and the network
+// fee paid to send it
+func (p *Payment) RecordRefundSent(transactionHash string, networkFee float64) error {
+	if networkFee < 0 {
+		return ErrInvalidAmount
+	}
+	
 	if p.RefundedAmount == 0 {
 		return ErrRefundAlreadyProcessed
 	}
 	
-	if transactionHash == "" {
-		return ErrInvalidTransactionHash
+	if err := p.CryptoCurrency.ValidateTransactionHash(transactionHash); err != nil {
+		return err
+	}
+	
+	now := time.Now()
+	if len(p.Refunds) > 0 {
+		latest := &p.Refunds[len(p.Refunds)-1]
+		if latest.IsSent() {
+			return ErrRefundAlreadyProcessed
+		}
+		latest.TransactionHash = transactionHash
+		latest.NetworkFee = networkFee
+		latest.SentAt = &now
 	}
 	
 	p.RefundTransactionHash = transactionHash
+	p.UpdatedAt = now
+	
+	return nil
+}
+
+// RecordRefundRequested records the ID under which the provider sends the
+// latest refund. The transaction is recorded once the provider broadcasts it.
+func (p *Payment) RecordRefundRequested(reference string) error {
+	if !p.HasPendingRefund() {
+		return ErrRefundAlreadyProcessed
+	}
+	
+	if reference == "" {
+		return ErrEmptyPaymentID
+	}
+	
+	if p.HasRequestedRefund() {
+		return ErrRefundAlreadyRequested
+	}
+	
+	p.Refunds[len(p.Refunds)-1].ProviderReference = reference
 	p.UpdatedAt = time.Now()
 	
 	return nil
 }
 
+// ValidateRefundAddress checks that a customer's refund address can receive
+// the payment's cryptocurrency, so refunds are never sent to an unusable address
+func (p *Payment) ValidateRefundAddress(refundAddress string) error {
+	return p.CryptoCurrency.ValidateAddress(refundAddress)
+}
+
+// HasPendingRefund checks if a refund was issued but its transaction is not recorded yet
+func (p *Payment) HasPendingRefund() bool {
+	if len(p.Refunds) == 0 {
+		return false
+	}
+	return !p.Refunds[len(p.Refunds)-1].IsSent()
+}
+
+// HasRequestedRefund checks if the pending refund was already handed to a
+// provider, which sends it later; it must not be requested again
+func (p *Payment) HasRequestedRefund() bool {
+	return p.HasPendingRefund() && p.Refunds[len(p.Refunds)-1].ProviderReference != ""
+}
+
 // ValidateAmount checks if the provided amount matches the expected payment amount
 func (p *Payment) ValidateAmount(receivedAmount float64) error {
 	tolerance := 0.0001 // Small tolerance for crypto amounts (0.01%)
@@ -330,11 +780,21 @@ func (p *Payment) IsCompleted() bool {
 	return p.Status.IsCompleted()
 }
 
+// IsSandbox checks if the payment is a sandbox payment
+func (p *Payment) IsSandbox() bool {
+	return p.Environment.IsSandbox()
+}
+
 // IsPending checks if the payment is still pending
 func (p *Payment) IsPending() bool {
 	return p.Status == StatusPending
 }
 
+// IsUnderReview checks if the payment is held after a reorg or transaction replacement
+func (p *Payment) IsUnderReview() bool {
+	return p.Status == StatusUnderReview
+}
+
 // IsConfirming checks if the payment is confirming
 func (p *Payment) IsConfirming() bool {
 	return p.Status == StatusConfirming
@@ -366,6 +826,24 @@ func (p *Payment) GetRemainingRefundableAmount() float64 {
 	return p.CryptoAmount - p.RefundedAmount
 }
 
+// TotalFees returns the gateway and network fees charged on the payment
+func (p *Payment) TotalFees() float64 {
+	return p.GatewayFee + p.NetworkFee
+}
+
+// NetAmount returns the crypto amount the merchant receives after fees
+func (p *Payment) NetAmount() float64 {
+	return p.CryptoAmount - p.TotalFees()
+}
+
+// FeesShare returns the part of the payment's fees that falls on a crypto amount
+func (p *Payment) FeesShare(cryptoAmount float64) float64 {
+	if p.CryptoAmount <= 0 {
+		return 0
+	}
+	return p.TotalFees() * cryptoAmount / p.CryptoAmount
+}
+
 // IsFullyRefunded checks if the payment has been fully refunded
 func (p *Payment) IsFullyRefunded() bool {
 	return p.RefundedAmount >= p.CryptoAmount && p.RefundedAmount > 0
@@ -376,29 +854,17 @@ func (p *Payment) GetCryptoSymbol() string {
 	return p.CryptoCurrency.Symbol
 }
 
+// TransactionURL returns the block explorer link for the payment transaction
+func (p *Payment) TransactionURL() string {
+	return p.CryptoCurrency.TransactionURL(p.TransactionHash)
+}
+
+// RefundTransactionURL returns the block explorer link for the most recent refund transaction
+func (p *Payment) RefundTransactionURL() string {
+	return p.CryptoCurrency.TransactionURL(p.RefundTransactionHash)
+}
+
 // GetWalletAddress returns the destination wallet address
 func (p *Payment) GetWalletAddress() string {
 	return p.PaymentMethod.WalletAddress
 }
-
-// Helper functions
-
-// getRequiredConfirmations returns the required number of confirmations for a cryptocurrency
-func getRequiredConfirmations(crypto CryptoCurrency) int {
-	switch crypto.Symbol {
-	case "BTC":
-		return 2  // Bitcoin requires 2 confirmations
-	case "ETH":
-		return 12 // Ethereum requires 12 confirmations
-	case "LTC":
-		return 6  // Litecoin requires 6 confirmations
-	case "BCH":
-		return 6  // Bitcoin Cash requires 6 confirmations
-	case "XRP":
-		return 1  // Ripple requires 1 confirmation
-	case "DOGE":
-		return 6  // Dogecoin requires 6 confirmations
-	default:
-		return 6  // Default to 6 confirmations
-	}
-}
\ No newline at end of file
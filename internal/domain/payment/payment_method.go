@@ -37,6 +37,10 @@ func NewPaymentMethod(cryptoSymbol string, walletAddress string, expirationMinut
 		return PaymentMethod{}, ErrUnsupportedCrypto
 	}
 	
+	if err := crypto.ValidateAddress(walletAddress); err != nil {
+		return PaymentMethod{}, err
+	}
+	
 	expiresAt := time.Now().Add(time.Duration(expirationMinutes) * time.Minute)
 	
 	return PaymentMethod{
@@ -11,12 +11,22 @@ const (
 	StatusExpired    PaymentStatus = "EXPIRED"    // Payment expired (timeout)
 	StatusRefunded   PaymentStatus = "REFUNDED"   // Payment refunded
 	StatusCancelled  PaymentStatus = "CANCELLED"  // Payment cancelled by user
+	StatusUnderReview PaymentStatus = "UNDER_REVIEW" // Transaction reorganised away or replaced, held until confirmed again
+)
+
+// HoldReason explains why a payment was put under review
+type HoldReason string
+
+const (
+	HoldReasonConfirmationsDropped HoldReason = "CONFIRMATIONS_DROPPED" // Fewer confirmations reported than before (reorg)
+	HoldReasonTransactionReplaced  HoldReason = "TRANSACTION_REPLACED"  // Replaced by another transaction (fee bump or double-spend)
+	HoldReasonTransactionDropped   HoldReason = "TRANSACTION_DROPPED"   // No longer found on chain or in the mempool
 )
 
 // IsValid checks if the payment status is valid
 func (ps PaymentStatus) IsValid() bool {
 	switch ps {
-	case StatusPending, StatusConfirming, StatusConfirmed, StatusFailed, StatusExpired, StatusRefunded, StatusCancelled:
+	case StatusPending, StatusConfirming, StatusConfirmed, StatusFailed, StatusExpired, StatusRefunded, StatusCancelled, StatusUnderReview:
 		return true
 	default:
 		return false
@@ -116,7 +116,7 @@ func TestCryptoCurrency(t *testing.T) {
 	})
 	
 	t.Run("validate amount", func(t *testing.T) {
-		crypto := Bitcoin
+		crypto, _ := GetCryptoCurrencyBySymbol("BTC")
 		
 		// Valid amount
 		err := crypto.ValidateAmount(0.001)
@@ -134,7 +134,7 @@ func TestCryptoCurrency(t *testing.T) {
 	})
 	
 	t.Run("cryptocurrency properties", func(t *testing.T) {
-		crypto := Bitcoin
+		crypto, _ := GetCryptoCurrencyBySymbol("BTC")
 		
 		assert.Equal(t, "BTC", crypto.GetSymbol())
 		assert.Equal(t, "Bitcoin", crypto.GetName())
@@ -163,6 +163,26 @@ func TestPaymentMethod(t *testing.T) {
 		assert.Equal(t, ErrInvalidWalletAddress, err)
 	})
 	
+	t.Run("cannot create payment method with invalid wallet address", func(t *testing.T) {
+		_, err := NewPaymentMethod("BTC", "address123", 30)
+		
+		assert.Equal(t, ErrInvalidWalletAddress, err)
+	})
+	
+	t.Run("cannot use an address from another chain", func(t *testing.T) {
+		_, err := NewPaymentMethod("LTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		
+		assert.Equal(t, ErrInvalidWalletAddress, err)
+	})
+	
+	t.Run("testnet coin requires testnet address", func(t *testing.T) {
+		crypto, _ := GetCryptoCurrencyBySymbol("BTC")
+		crypto.Testnet = true
+		
+		assert.NoError(t, crypto.ValidateAddress("tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"))
+		assert.Equal(t, ErrInvalidWalletAddress, crypto.ValidateAddress("bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh"))
+	})
+	
 	t.Run("cannot create payment method with unsupported crypto", func(t *testing.T) {
 		_, err := NewPaymentMethod("INVALID", "address123", 30)
 		
@@ -172,7 +192,7 @@ func TestPaymentMethod(t *testing.T) {
 	
 	t.Run("payment method expiration", func(t *testing.T) {
 		// Create method that expires in 0 minutes (immediately)
-		method, _ := NewPaymentMethod("BTC", "address123", 0)
+		method, _ := NewPaymentMethod("BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 0)
 		
 		// Should be expired immediately
 		time.Sleep(1 * time.Millisecond)
@@ -181,7 +201,7 @@ func TestPaymentMethod(t *testing.T) {
 	})
 	
 	t.Run("payment method time until expiry", func(t *testing.T) {
-		method, _ := NewPaymentMethod("BTC", "address123", 30)
+		method, _ := NewPaymentMethod("BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		
 		duration := method.TimeUntilExpiry()
 		assert.Greater(t, duration, 29*time.Minute)
@@ -229,21 +249,21 @@ func TestNewPayment(t *testing.T) {
 	})
 	
 	t.Run("cannot create payment with empty order ID", func(t *testing.T) {
-		_, err := NewPayment("", 100.0, "USD", "BTC", "address123", 30)
+		_, err := NewPayment("", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		
 		assert.Error(t, err)
 		assert.Equal(t, ErrEmptyOrderID, err)
 	})
 	
 	t.Run("cannot create payment with invalid amount", func(t *testing.T) {
-		_, err := NewPayment("order-123", -50.0, "USD", "BTC", "address123", 30)
+		_, err := NewPayment("order-123", -50.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		
 		assert.Error(t, err)
 		assert.Equal(t, ErrInvalidAmount, err)
 	})
 	
 	t.Run("cannot create payment with empty currency", func(t *testing.T) {
-		_, err := NewPayment("order-123", 100.0, "", "BTC", "address123", 30)
+		_, err := NewPayment("order-123", 100.0, "", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		
 		assert.Error(t, err)
 		assert.Equal(t, ErrInvalidCurrency, err)
@@ -259,7 +279,7 @@ func TestNewPayment(t *testing.T) {
 
 func TestPaymentCryptoAmount(t *testing.T) {
 	t.Run("update crypto amount", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		
 		err := payment.UpdateCryptoAmount(0.001)
 		
@@ -268,7 +288,7 @@ func TestPaymentCryptoAmount(t *testing.T) {
 	})
 	
 	t.Run("cannot update crypto amount on final payment", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		payment.Status = StatusConfirmed
 		
 		err := payment.UpdateCryptoAmount(0.001)
@@ -278,7 +298,7 @@ func TestPaymentCryptoAmount(t *testing.T) {
 	})
 	
 	t.Run("cannot update with invalid crypto amount", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		
 		err := payment.UpdateCryptoAmount(0.00001) // Below minimum for BTC
 		
@@ -287,31 +307,130 @@ func TestPaymentCryptoAmount(t *testing.T) {
 	})
 }
 
+func TestPaymentQuotes(t *testing.T) {
+	t.Run("apply quote sets crypto amount and audit fields", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		quote, _ := NewCryptoQuote(100.0, "USD", 0.002, payment.CryptoCurrency, "static", payment.QuoteLockUntil(10*time.Minute))
+		
+		err := payment.ApplyQuote(quote)
+		
+		assert.NoError(t, err)
+		assert.Equal(t, 0.002, payment.CryptoAmount)
+		assert.Equal(t, quote.ID, payment.QuoteID)
+		assert.Equal(t, 50000.0, payment.QuoteRate)
+		assert.Equal(t, "static", payment.QuoteSource)
+		assert.True(t, payment.HasQuote())
+		assert.True(t, payment.IsQuoteLocked())
+	})
+	
+	t.Run("quote lock is capped at payment expiry", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		
+		assert.Equal(t, payment.ExpiresAt, payment.QuoteLockUntil(time.Hour))
+	})
+	
+	t.Run("cannot apply quote for another amount", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		quote, _ := NewCryptoQuote(90.0, "USD", 0.002, payment.CryptoCurrency, "static", payment.QuoteLockUntil(10*time.Minute))
+		
+		err := payment.ApplyQuote(quote)
+		
+		assert.Equal(t, ErrQuoteMismatch, err)
+		assert.False(t, payment.HasQuote())
+	})
+	
+	t.Run("cannot create an already expired quote", func(t *testing.T) {
+		crypto, _ := GetCryptoCurrencyBySymbol("BTC")
+		
+		_, err := NewCryptoQuote(100.0, "USD", 0.002, crypto, "static", time.Now().Add(-time.Minute))
+		
+		assert.Equal(t, ErrQuoteExpired, err)
+	})
+	
+	t.Run("cannot quote a confirming payment", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		payment.Status = StatusConfirming
+		quote, _ := NewCryptoQuote(100.0, "USD", 0.002, payment.CryptoCurrency, "static", payment.QuoteLockUntil(10*time.Minute))
+		
+		err := payment.ApplyQuote(quote)
+		
+		assert.Equal(t, ErrQuoteNotAllowed, err)
+	})
+	
+	t.Run("requote rejected beyond slippage", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		first, _ := NewCryptoQuote(100.0, "USD", 0.002, payment.CryptoCurrency, "static", payment.QuoteLockUntil(10*time.Minute))
+		_ = payment.ApplyQuote(first)
+		second, _ := NewCryptoQuote(100.0, "USD", 0.0022, payment.CryptoCurrency, "static", payment.QuoteLockUntil(10*time.Minute))
+		
+		err := payment.Requote(second, 0.05)
+		
+		assert.Equal(t, ErrSlippageExceeded, err)
+		assert.Equal(t, first.ID, payment.QuoteID)
+		assert.Equal(t, 0.002, payment.CryptoAmount)
+	})
+	
+	t.Run("requote within slippage", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		first, _ := NewCryptoQuote(100.0, "USD", 0.002, payment.CryptoCurrency, "static", payment.QuoteLockUntil(10*time.Minute))
+		_ = payment.ApplyQuote(first)
+		second, _ := NewCryptoQuote(100.0, "USD", 0.00201, payment.CryptoCurrency, "static", payment.QuoteLockUntil(10*time.Minute))
+		
+		err := payment.Requote(second, 0.01)
+		
+		assert.NoError(t, err)
+		assert.Equal(t, second.ID, payment.QuoteID)
+	})
+	
+	t.Run("stablecoin requote skips slippage check", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "USDTTRC20", "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", 30)
+		first, _ := NewCryptoQuote(100.0, "USD", 100.0, payment.CryptoCurrency, "static", payment.QuoteLockUntil(10*time.Minute))
+		_ = payment.ApplyQuote(first)
+		second, _ := NewCryptoQuote(100.0, "USD", 97.0, payment.CryptoCurrency, "static", payment.QuoteLockUntil(10*time.Minute))
+		
+		err := payment.Requote(second, 0.01)
+		
+		assert.NoError(t, err)
+		assert.Equal(t, 97.0, payment.CryptoAmount)
+	})
+	
+	t.Run("stablecoin in another fiat currency keeps slippage check", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "EUR", "USDTTRC20", "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", 30)
+		first, _ := NewCryptoQuote(100.0, "EUR", 108.0, payment.CryptoCurrency, "static", payment.QuoteLockUntil(10*time.Minute))
+		_ = payment.ApplyQuote(first)
+		second, _ := NewCryptoQuote(100.0, "EUR", 104.0, payment.CryptoCurrency, "static", payment.QuoteLockUntil(10*time.Minute))
+		
+		err := payment.Requote(second, 0.01)
+		
+		assert.Equal(t, ErrSlippageExceeded, err)
+	})
+}
+
 func TestPaymentStatusTransitions(t *testing.T) {
 	t.Run("mark as confirming", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		
-		err := payment.MarkAsConfirming("abc123")
+		err := payment.MarkAsConfirming("4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")
 		
 		assert.NoError(t, err)
 		assert.Equal(t, StatusConfirming, payment.Status)
-		assert.Equal(t, "abc123", payment.TransactionHash)
+		assert.Equal(t, "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b", payment.TransactionHash)
 		assert.Equal(t, 0, payment.Confirmations)
 		assert.True(t, payment.IsConfirming())
 	})
 	
 	t.Run("cannot mark as confirming from wrong status", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		payment.Status = StatusConfirmed
 		
-		err := payment.MarkAsConfirming("abc123")
+		err := payment.MarkAsConfirming("4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")
 		
 		assert.Error(t, err)
 		assert.Equal(t, ErrInvalidStatusTransition, err)
 	})
 	
 	t.Run("cannot mark as confirming with empty transaction hash", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		
 		err := payment.MarkAsConfirming("")
 		
@@ -319,9 +438,27 @@ func TestPaymentStatusTransitions(t *testing.T) {
 		assert.Equal(t, ErrInvalidTransactionHash, err)
 	})
 	
+	t.Run("cannot mark as confirming with hash of another chain", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		
+		err := payment.MarkAsConfirming("0x4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")
+		
+		assert.Equal(t, ErrInvalidTransactionHash, err)
+		assert.Equal(t, StatusPending, payment.Status)
+	})
+	
+	t.Run("transaction explorer link", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "ETH", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", 30)
+		assert.Equal(t, "", payment.TransactionURL())
+		
+		_ = payment.MarkAsConfirming("0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b")
+		
+		assert.Equal(t, "https://etherscan.io/tx/0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b", payment.TransactionURL())
+	})
+	
 	t.Run("update confirmations", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
-		payment.MarkAsConfirming("abc123")
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		payment.MarkAsConfirming("4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")
 		
 		err := payment.UpdateConfirmations(1)
 		
@@ -331,8 +468,8 @@ func TestPaymentStatusTransitions(t *testing.T) {
 	})
 	
 	t.Run("auto confirm with enough confirmations", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
-		payment.MarkAsConfirming("abc123")
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		payment.MarkAsConfirming("4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")
 		
 		err := payment.UpdateConfirmations(2) // BTC requires 2 confirmations
 		
@@ -343,7 +480,7 @@ func TestPaymentStatusTransitions(t *testing.T) {
 	})
 	
 	t.Run("manual confirm payment", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		
 		err := payment.MarkAsConfirmed()
 		
@@ -353,7 +490,7 @@ func TestPaymentStatusTransitions(t *testing.T) {
 	})
 	
 	t.Run("cannot confirm already confirmed payment", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		payment.Status = StatusConfirmed
 		
 		err := payment.MarkAsConfirmed()
@@ -363,7 +500,7 @@ func TestPaymentStatusTransitions(t *testing.T) {
 	})
 	
 	t.Run("mark as failed", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		
 		err := payment.MarkAsFailed()
 		
@@ -372,7 +509,7 @@ func TestPaymentStatusTransitions(t *testing.T) {
 	})
 	
 	t.Run("mark as expired", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		
 		err := payment.MarkAsExpired()
 		
@@ -383,7 +520,7 @@ func TestPaymentStatusTransitions(t *testing.T) {
 
 func TestPaymentCancellation(t *testing.T) {
 	t.Run("cancel pending payment", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		
 		err := payment.Cancel()
 		
@@ -392,7 +529,7 @@ func TestPaymentCancellation(t *testing.T) {
 	})
 	
 	t.Run("cancel confirming payment", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		payment.Status = StatusConfirming
 		
 		err := payment.Cancel()
@@ -402,7 +539,7 @@ func TestPaymentCancellation(t *testing.T) {
 	})
 	
 	t.Run("cannot cancel confirmed payment", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		payment.Status = StatusConfirmed
 		
 		err := payment.Cancel()
@@ -414,7 +551,7 @@ func TestPaymentCancellation(t *testing.T) {
 
 func TestPaymentRefunds(t *testing.T) {
 	t.Run("full refund confirmed payment", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		payment.UpdateCryptoAmount(0.001)
 		payment.Status = StatusConfirmed
 		
@@ -428,7 +565,7 @@ func TestPaymentRefunds(t *testing.T) {
 	})
 	
 	t.Run("partial refund confirmed payment", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		payment.UpdateCryptoAmount(0.001)
 		payment.Status = StatusConfirmed
 		
@@ -442,7 +579,7 @@ func TestPaymentRefunds(t *testing.T) {
 	})
 	
 	t.Run("cannot refund non-confirmed payment", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		
 		err := payment.Refund()
 		
@@ -451,7 +588,7 @@ func TestPaymentRefunds(t *testing.T) {
 	})
 	
 	t.Run("cannot refund more than payment amount", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		payment.UpdateCryptoAmount(0.001)
 		payment.Status = StatusConfirmed
 		
@@ -462,21 +599,104 @@ func TestPaymentRefunds(t *testing.T) {
 	})
 	
 	t.Run("set refund transaction hash", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		payment.UpdateCryptoAmount(0.001)
 		payment.Status = StatusConfirmed
 		payment.Refund()
 		
-		err := payment.SetRefundTransactionHash("refund-hash-123")
+		err := payment.SetRefundTransactionHash("f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16")
 		
 		assert.NoError(t, err)
-		assert.Equal(t, "refund-hash-123", payment.RefundTransactionHash)
+		assert.Equal(t, "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16", payment.RefundTransactionHash)
+		assert.Equal(t, "https://mempool.space/tx/f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16", payment.RefundTransactionURL())
+		assert.Len(t, payment.Refunds, 1)
+		assert.True(t, payment.Refunds[0].IsSent())
+		assert.NotNil(t, payment.Refunds[0].SentAt)
+	})
+	
+	t.Run("validate refund address", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "ETH", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", 30)
+		
+		assert.NoError(t, payment.ValidateRefundAddress("0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"))
+		assert.Equal(t, ErrInvalidWalletAddress, payment.ValidateRefundAddress("0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d35A"))
+	})
+	
+	t.Run("second partial refund waits for first to be sent", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		payment.UpdateCryptoAmount(0.001)
+		payment.Status = StatusConfirmed
+		payment.PartialRefund(0.0004)
+		
+		err := payment.PartialRefund(0.0004)
+		
+		assert.Error(t, err)
+		assert.Equal(t, ErrRefundAlreadyProcessed, err)
+		assert.True(t, payment.HasPendingRefund())
+	})
+	
+	t.Run("multiple partial refunds up to full amount", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		payment.UpdateCryptoAmount(0.001)
+		payment.Status = StatusConfirmed
+		
+		assert.NoError(t, payment.PartialRefund(0.0004))
+		assert.NoError(t, payment.SetRefundTransactionHash("a1075db55d416d3ca199f55b6084e2115b9345e16c5cf302fc80e9d5fbf5d48d"))
+		assert.NoError(t, payment.PartialRefund(0.0006))
+		assert.NoError(t, payment.SetRefundTransactionHash("cca7507897abc89628f450e8b1e0c6fca4ec3f7b34cccf55f3f531c659ff4d79"))
+		
+		assert.Len(t, payment.Refunds, 2)
+		assert.Equal(t, "a1075db55d416d3ca199f55b6084e2115b9345e16c5cf302fc80e9d5fbf5d48d", payment.Refunds[0].TransactionHash)
+		assert.Equal(t, "cca7507897abc89628f450e8b1e0c6fca4ec3f7b34cccf55f3f531c659ff4d79", payment.RefundTransactionHash)
+		assert.Equal(t, StatusRefunded, payment.Status)
+		assert.False(t, payment.HasPendingRefund())
+	})
+	
+	t.Run("cannot record refund transaction twice", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		payment.UpdateCryptoAmount(0.001)
+		payment.Status = StatusConfirmed
+		payment.PartialRefund(0.0004)
+		payment.SetRefundTransactionHash("a1075db55d416d3ca199f55b6084e2115b9345e16c5cf302fc80e9d5fbf5d48d")
+		
+		err := payment.SetRefundTransactionHash("cca7507897abc89628f450e8b1e0c6fca4ec3f7b34cccf55f3f531c659ff4d79")
+		
+		assert.Equal(t, ErrRefundAlreadyProcessed, err)
+		assert.Equal(t, "a1075db55d416d3ca199f55b6084e2115b9345e16c5cf302fc80e9d5fbf5d48d", payment.RefundTransactionHash)
+	})
+	
+	t.Run("record refund sent with network fee", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		payment.UpdateCryptoAmount(0.001)
+		payment.Status = StatusConfirmed
+		payment.PartialRefund(0.0004)
+		
+		assert.Equal(t, ErrInvalidAmount, payment.RecordRefundSent("a1075db55d416d3ca199f55b6084e2115b9345e16c5cf302fc80e9d5fbf5d48d", -0.00001))
+		assert.NoError(t, payment.RecordRefundSent("a1075db55d416d3ca199f55b6084e2115b9345e16c5cf302fc80e9d5fbf5d48d", 0.00002))
+		assert.Equal(t, 0.00002, payment.Refunds[0].NetworkFee)
+	})
+	
+	t.Run("record refund requested at the provider", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		payment.UpdateCryptoAmount(0.001)
+		payment.Status = StatusConfirmed
+		
+		assert.Equal(t, ErrRefundAlreadyProcessed, payment.RecordRefundRequested("5000000000"))
+		
+		payment.PartialRefund(0.0004)
+		
+		assert.Equal(t, ErrEmptyPaymentID, payment.RecordRefundRequested(""))
+		assert.NoError(t, payment.RecordRefundRequested("5000000000"))
+		assert.Equal(t, "5000000000", payment.Refunds[0].ProviderReference)
+		assert.True(t, payment.HasPendingRefund())
+		assert.True(t, payment.HasRequestedRefund())
+		assert.Equal(t, ErrRefundAlreadyRequested, payment.RecordRefundRequested("5000000001"))
+		assert.Equal(t, "5000000000", payment.Refunds[0].ProviderReference)
 	})
 }
 
 func TestPaymentValidation(t *testing.T) {
 	t.Run("validate exact amount", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		payment.UpdateCryptoAmount(0.001)
 		
 		err := payment.ValidateAmount(0.001)
@@ -485,7 +705,7 @@ func TestPaymentValidation(t *testing.T) {
 	})
 	
 	t.Run("validate amount within tolerance", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		payment.UpdateCryptoAmount(0.001)
 		
 		err := payment.ValidateAmount(0.0009995) // Slightly less but within tolerance
@@ -494,7 +714,7 @@ func TestPaymentValidation(t *testing.T) {
 	})
 	
 	t.Run("insufficient amount", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		payment.UpdateCryptoAmount(0.001)
 		
 		err := payment.ValidateAmount(0.0005)
@@ -504,7 +724,7 @@ func TestPaymentValidation(t *testing.T) {
 	})
 	
 	t.Run("excessive amount", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		payment.UpdateCryptoAmount(0.001)
 		
 		err := payment.ValidateAmount(0.002)
@@ -515,26 +735,29 @@ func TestPaymentValidation(t *testing.T) {
 }
 
 func TestPaymentExternalService(t *testing.T) {
-	t.Run("set now payments ID", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+	t.Run("set provider reference", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		
-		err := payment.SetNowPaymentsID("np-123456")
+		err := payment.SetProviderReference("nowpayments", "np-123456")
 		
 		assert.NoError(t, err)
-		assert.Equal(t, "np-123456", payment.NowPaymentsID)
+		assert.Equal(t, "nowpayments", payment.Provider)
+		assert.Equal(t, "np-123456", payment.ProviderReference)
 	})
 	
-	t.Run("cannot set empty now payments ID", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+	t.Run("cannot set empty provider or reference", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		
-		err := payment.SetNowPaymentsID("")
+		errProvider := payment.SetProviderReference("", "np-123456")
+		errReference := payment.SetProviderReference("nowpayments", "")
 		
-		assert.Error(t, err)
-		assert.Equal(t, ErrEmptyPaymentID, err)
+		assert.Equal(t, ErrUnknownProvider, errProvider)
+		assert.Equal(t, ErrEmptyPaymentID, errReference)
+		assert.Empty(t, payment.Provider)
 	})
 	
 	t.Run("set callback URL", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		
 		err := payment.SetCallbackURL("https://example.com/webhook")
 		
@@ -546,7 +769,7 @@ func TestPaymentExternalService(t *testing.T) {
 func TestPaymentExpiration(t *testing.T) {
 	t.Run("payment expiration", func(t *testing.T) {
 		// Create payment that expires immediately
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 0)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 0)
 		
 		// Wait a moment for expiration
 		time.Sleep(1 * time.Millisecond)
@@ -556,10 +779,10 @@ func TestPaymentExpiration(t *testing.T) {
 	})
 	
 	t.Run("cannot mark expired payment as confirming", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 0)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 0)
 		time.Sleep(1 * time.Millisecond)
 		
-		err := payment.MarkAsConfirming("abc123")
+		err := payment.MarkAsConfirming("4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")
 		
 		assert.Error(t, err)
 		assert.Equal(t, ErrPaymentExpired, err)
@@ -568,7 +791,7 @@ func TestPaymentExpiration(t *testing.T) {
 
 func TestPaymentQueryMethods(t *testing.T) {
 	t.Run("query methods on pending payment", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		
 		assert.True(t, payment.IsPending())
 		assert.False(t, payment.IsConfirming())
@@ -576,12 +799,12 @@ func TestPaymentQueryMethods(t *testing.T) {
 		assert.True(t, payment.CanBeCancelled())
 		assert.False(t, payment.CanBeRefunded())
 		assert.Equal(t, "BTC", payment.GetCryptoSymbol())
-		assert.Equal(t, "address123", payment.GetWalletAddress())
+		assert.Equal(t, "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", payment.GetWalletAddress())
 		assert.Equal(t, 0.0, payment.GetRemainingRefundableAmount())
 	})
 	
 	t.Run("query methods on confirmed payment", func(t *testing.T) {
-		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "address123", 30)
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
 		payment.UpdateCryptoAmount(0.001)
 		payment.Status = StatusConfirmed
 		
@@ -597,21 +820,236 @@ func TestPaymentQueryMethods(t *testing.T) {
 func TestRequiredConfirmations(t *testing.T) {
 	testCases := []struct {
 		crypto   string
+		address  string
 		expected int
 	}{
-		{"BTC", 2},
-		{"ETH", 12},
-		{"LTC", 6},
-		{"BCH", 6},
-		{"XRP", 1},
-		{"DOGE", 6},
+		{"BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 2},
+		{"ETH", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", 12},
+		{"LTC", "ltc1qw508d6qejxtdg4y5r3zarvary0c5xw7kgmn4n9", 6},
+		{"BCH", "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", 6},
+		{"XRP", "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh?dt=12345", 1},
+		{"DOGE", "DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L", 6},
+		{"USDTTRC20", "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", 20},
+		{"USDTERC20", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", 12},
 	}
 	
 	for _, tc := range testCases {
 		t.Run(tc.crypto+" required confirmations", func(t *testing.T) {
-			payment, _ := NewPayment("order-123", 100.0, "USD", tc.crypto, "address123", 30)
+			payment, _ := NewPayment("order-123", 100.0, "USD", tc.crypto, tc.address, 30)
 			
 			assert.Equal(t, tc.expected, payment.RequiredConfirmations)
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestPaymentReorg(t *testing.T) {
+	createConfirmedPayment := func() *Payment {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		_ = payment.UpdateCryptoAmount(0.002)
+		_ = payment.MarkAsConfirming("4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")
+		_ = payment.UpdateConfirmations(2)
+		return payment
+	}
+	
+	t.Run("confirmed payment is held when confirmations drop", func(t *testing.T) {
+		payment := createConfirmedPayment()
+		
+		err := payment.UpdateConfirmations(1)
+		
+		assert.NoError(t, err)
+		assert.Equal(t, StatusUnderReview, payment.Status)
+		assert.Equal(t, HoldReasonConfirmationsDropped, payment.HoldReason)
+		assert.NotNil(t, payment.HeldAt)
+		assert.False(t, payment.CanBeRefunded())
+	})
+	
+	t.Run("held payment is re-confirmed", func(t *testing.T) {
+		payment := createConfirmedPayment()
+		_ = payment.UpdateConfirmations(0)
+		
+		_ = payment.UpdateConfirmations(1)
+		assert.True(t, payment.IsUnderReview())
+		err := payment.UpdateConfirmations(2)
+		
+		assert.NoError(t, err)
+		assert.Equal(t, StatusConfirmed, payment.Status)
+		assert.Empty(t, payment.HoldReason)
+		assert.Nil(t, payment.HeldAt)
+	})
+	
+	t.Run("drop above the requirement keeps the payment confirmed", func(t *testing.T) {
+		payment := createConfirmedPayment()
+		_ = payment.UpdateConfirmations(6)
+		
+		err := payment.UpdateConfirmations(5)
+		
+		assert.NoError(t, err)
+		assert.Equal(t, StatusConfirmed, payment.Status)
+		assert.Equal(t, 5, payment.Confirmations)
+	})
+	
+	t.Run("confirming payment is held when confirmations drop", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "ETH", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", 30)
+		_ = payment.MarkAsConfirming("0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b")
+		_ = payment.UpdateConfirmations(5)
+		
+		err := payment.UpdateConfirmations(3)
+		
+		assert.NoError(t, err)
+		assert.Equal(t, StatusUnderReview, payment.Status)
+	})
+	
+	t.Run("replaced transaction is held until the replacement confirms", func(t *testing.T) {
+		payment := createConfirmedPayment()
+		
+		err := payment.MarkTransactionReplaced("f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16")
+		
+		assert.NoError(t, err)
+		assert.Equal(t, StatusUnderReview, payment.Status)
+		assert.Equal(t, HoldReasonTransactionReplaced, payment.HoldReason)
+		assert.Equal(t, "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b", payment.ReplacedTransactionHash)
+		assert.Equal(t, "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16", payment.TransactionHash)
+		assert.Equal(t, 0, payment.Confirmations)
+		
+		assert.NoError(t, payment.UpdateConfirmations(2))
+		assert.Equal(t, StatusConfirmed, payment.Status)
+	})
+	
+	t.Run("same transaction is not a replacement", func(t *testing.T) {
+		payment := createConfirmedPayment()
+		
+		err := payment.MarkTransactionReplaced("4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")
+		
+		assert.NoError(t, err)
+		assert.Equal(t, StatusConfirmed, payment.Status)
+	})
+	
+	t.Run("dropped transaction", func(t *testing.T) {
+		payment := createConfirmedPayment()
+		
+		err := payment.MarkTransactionDropped()
+		
+		assert.NoError(t, err)
+		assert.Equal(t, HoldReasonTransactionDropped, payment.HoldReason)
+		assert.Equal(t, 0, payment.Confirmations)
+	})
+	
+	t.Run("held zero-conf payment needs a confirmation", func(t *testing.T) {
+		payment := createConfirmedPayment()
+		payment.RequiredConfirmations = 0
+		_ = payment.MarkTransactionDropped()
+		
+		_ = payment.UpdateConfirmations(0)
+		assert.True(t, payment.IsUnderReview())
+		_ = payment.UpdateConfirmations(1)
+		
+		assert.Equal(t, StatusConfirmed, payment.Status)
+	})
+	
+	t.Run("pending payment has no transaction to track", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		
+		assert.Equal(t, ErrInvalidStatusTransition, payment.MarkTransactionDropped())
+		assert.Equal(t, ErrInvalidStatusTransition, payment.MarkTransactionReplaced("f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16"))
+	})
+}
+
+func TestPaymentFees(t *testing.T) {
+	t.Run("customer-borne fee is added to the crypto amount", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		payment.UpdateCryptoAmount(0.00199)
+		policy, _ := NewFeePolicy(FeeBearerCustomer, 0.005)
+		
+		assert.NoError(t, payment.ApplyFeePolicy(policy))
+		assert.NoError(t, payment.ApplyFeePolicy(policy))
+		assert.Equal(t, FeeBearerCustomer, payment.FeeBearer)
+		assert.InDelta(t, 0.00001, payment.FeeSurcharge, 1e-12)
+		assert.InDelta(t, 0.002, payment.CryptoAmount, 1e-12)
+		
+		payment.UpdateCryptoAmount(0.00199)
+		assert.Equal(t, 0.0, payment.FeeSurcharge)
+	})
+	
+	t.Run("customer-borne fee priced in by the provider is recorded", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		payment.UpdateCryptoAmount(0.002)
+		policy, _ := NewFeePolicy(FeeBearerCustomer, 0.005)
+		
+		assert.NoError(t, payment.ApplyIncludedFeePolicy(policy))
+		assert.Equal(t, FeeBearerCustomer, payment.FeeBearer)
+		assert.InDelta(t, 0.00001, payment.FeeSurcharge, 1e-12)
+		assert.Equal(t, 0.002, payment.CryptoAmount)
+	})
+	
+	t.Run("merchant-borne fee leaves the amount", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		payment.UpdateCryptoAmount(0.002)
+		
+		assert.NoError(t, payment.ApplyFeePolicy(DefaultFeePolicy()))
+		assert.Equal(t, FeeBearerMerchant, payment.FeeBearer)
+		assert.Equal(t, 0.002, payment.CryptoAmount)
+	})
+	
+	t.Run("invalid fee policy", func(t *testing.T) {
+		_, errBearer := NewFeePolicy("NOBODY", 0.005)
+		_, errRate := NewFeePolicy(FeeBearerCustomer, 1)
+		
+		assert.Equal(t, ErrInvalidFeePolicy, errBearer)
+		assert.Equal(t, ErrInvalidFeePolicy, errRate)
+	})
+	
+	t.Run("record gateway fees", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		payment.UpdateCryptoAmount(0.002)
+		
+		assert.Equal(t, ErrInvalidFee, payment.RecordFees(-0.00001, 0))
+		assert.Equal(t, ErrInvalidFee, payment.RecordFees(0.002, 0.0001))
+		assert.NoError(t, payment.RecordFees(0.00001, 0.000005))
+		assert.NoError(t, payment.RecordFees(0.00001, 0.000005))
+		assert.NotNil(t, payment.FeesRecordedAt)
+		assert.Equal(t, 1, payment.FeeReportCount())
+		assert.Empty(t, payment.FeeAdjustments)
+		assert.InDelta(t, 0.001985, payment.NetAmount(), 1e-12)
+	})
+	
+	t.Run("later fee report adjusts the fees", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		payment.UpdateCryptoAmount(0.002)
+		
+		assert.NoError(t, payment.RecordFees(0.00001, 0.000005))
+		assert.NoError(t, payment.RecordFees(0.00001, 0.000012))
+		
+		assert.Equal(t, 2, payment.FeeReportCount())
+		assert.Len(t, payment.FeeAdjustments, 1)
+		assert.Equal(t, 0.0, payment.FeeAdjustments[0].GatewayFee)
+		assert.InDelta(t, 0.000007, payment.FeeAdjustments[0].NetworkFee, 1e-12)
+		assert.Equal(t, 0.000012, payment.NetworkFee)
+		
+		gatewayFee, networkFee := payment.InitialFees()
+		assert.InDelta(t, 0.00001, gatewayFee, 1e-12)
+		assert.InDelta(t, 0.000005, networkFee, 1e-12)
+	})
+	
+	t.Run("refund net of fees keeps back their share", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		payment.UpdateCryptoAmount(0.002)
+		payment.Status = StatusConfirmed
+		payment.RecordFees(0.00001, 0.00001)
+		
+		assert.NoError(t, payment.PartialRefundNetOfFees(0.001))
+		assert.Equal(t, 0.001, payment.RefundedAmount)
+		assert.InDelta(t, 0.00001, payment.Refunds[0].FeesWithheld, 1e-12)
+		assert.InDelta(t, 0.00099, payment.Refunds[0].NetAmount(), 1e-12)
+	})
+	
+	t.Run("full refund net of fees refunds the payment", func(t *testing.T) {
+		payment, _ := NewPayment("order-123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+		payment.UpdateCryptoAmount(0.002)
+		payment.Status = StatusConfirmed
+		payment.RecordFees(0.00002, 0)
+		
+		assert.NoError(t, payment.RefundNetOfFees())
+		assert.Equal(t, StatusRefunded, payment.Status)
+		assert.InDelta(t, 0.00198, payment.Refunds[0].NetAmount(), 1e-12)
+	})
+}
@@ -0,0 +1,151 @@
+package payment
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment/address"
+)
+
+// ethereumTestnetChainID is the chain ID of Sepolia, used by testnet coins on Ethereum
+const ethereumTestnetChainID = "11155111"
+
+// BuildPaymentURI encodes a payment request that wallets can open or scan:
+//   - BIP21 (bitcoin:, litecoin:, dogecoin:, bitcoincash:) with amount and label
+//   - EIP-681 (ethereum:) with the value in wei, or an ERC-20 transfer in token units
+//   - ripple: with the amount and destination tag
+//
+// The amount is left out when it is 0 (not quoted yet). Tron has no URI scheme.
+func BuildPaymentURI(crypto CryptoCurrency, walletAddress string, amount float64, label string) (string, error) {
+	if walletAddress == "" {
+		return "", ErrInvalidWalletAddress
+	}
+
+	switch crypto.Network {
+	case NetworkBitcoin, NetworkLitecoin, NetworkDogecoin:
+		return bip21URI(string(crypto.Network), walletAddress, crypto, amount, label), nil
+	case NetworkBitcoinCash:
+		scheme := "bitcoincash"
+		if crypto.Testnet {
+			scheme = "bchtest"
+		}
+		// CashAddr addresses may already carry the scheme as their prefix
+		if i := strings.LastIndex(walletAddress, ":"); i >= 0 {
+			walletAddress = walletAddress[i+1:]
+		}
+		return bip21URI(scheme, walletAddress, crypto, amount, label), nil
+	case NetworkEthereum:
+		return eip681URI(walletAddress, crypto, amount), nil
+	case NetworkRipple:
+		return rippleURI(walletAddress, crypto, amount)
+	default:
+		return "", ErrPaymentURINotSupported
+	}
+}
+
+// PaymentURI encodes the payment's deposit address and crypto amount as a payment request
+func (p *Payment) PaymentURI(label string) (string, error) {
+	return BuildPaymentURI(p.CryptoCurrency, p.GetWalletAddress(), p.CryptoAmount, label)
+}
+
+// bip21URI builds "<scheme>:<address>?amount=<coins>&label=<label>"
+func bip21URI(scheme, walletAddress string, crypto CryptoCurrency, amount float64, label string) string {
+	var params []string
+	if amount > 0 {
+		params = append(params, "amount="+formatDecimalAmount(amount, crypto.Decimals))
+	}
+	if label != "" {
+		params = append(params, "label="+escapeURIParam(label))
+	}
+
+	return withParams(scheme+":"+walletAddress, params)
+}
+
+// eip681URI builds "ethereum:<address>?value=<wei>" for ether and
+// "ethereum:<contract>/transfer?address=<address>&uint256=<units>" for tokens
+func eip681URI(walletAddress string, crypto CryptoCurrency, amount float64) string {
+	chain := ""
+	if crypto.Testnet {
+		chain = "@" + ethereumTestnetChainID
+	}
+
+	if crypto.IsToken() {
+		params := []string{"address=" + walletAddress}
+		if amount > 0 {
+			params = append(params, "uint256="+toBaseUnits(amount, crypto.Decimals))
+		}
+		return withParams("ethereum:"+crypto.ContractAddress+chain+"/transfer", params)
+	}
+
+	var params []string
+	if amount > 0 {
+		params = append(params, "value="+toBaseUnits(amount, crypto.Decimals))
+	}
+	return withParams("ethereum:"+walletAddress+chain, params)
+}
+
+// rippleURI builds "ripple:<address>?amount=<xrp>&dt=<tag>"
+func rippleURI(walletAddress string, crypto CryptoCurrency, amount float64) (string, error) {
+	classic, tag, err := address.SplitDestinationTag(walletAddress)
+	if err != nil {
+		return "", ErrInvalidWalletAddress
+	}
+
+	var params []string
+	if amount > 0 {
+		params = append(params, "amount="+formatDecimalAmount(amount, crypto.Decimals))
+	}
+	if tag != nil {
+		params = append(params, "dt="+strconv.FormatUint(uint64(*tag), 10))
+	}
+
+	return withParams("ripple:"+classic, params), nil
+}
+
+// withParams appends the query parameters, if any
+func withParams(uri string, params []string) string {
+	if len(params) == 0 {
+		return uri
+	}
+	return uri + "?" + strings.Join(params, "&")
+}
+
+// escapeURIParam percent-encodes a parameter value, spaces as %20 rather than +
+func escapeURIParam(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// formatDecimalAmount writes an amount in whole coins, without exponent or trailing zeros
+func formatDecimalAmount(amount float64, decimals int) string {
+	whole, fraction := splitDecimal(amount, decimals)
+	if fraction = strings.TrimRight(fraction, "0"); fraction == "" {
+		return whole
+	}
+	return whole + "." + fraction
+}
+
+// toBaseUnits writes an amount in the coin's smallest unit (wei for ether)
+func toBaseUnits(amount float64, decimals int) string {
+	whole, fraction := splitDecimal(amount, decimals)
+	fraction += strings.Repeat("0", decimals-len(fraction))
+
+	units := strings.TrimLeft(whole+fraction, "0")
+	if units == "" {
+		return "0"
+	}
+	return units
+}
+
+// splitDecimal splits the shortest decimal form of an amount, so 0.05 ETH is
+// exactly 50000000000000000 wei rather than the float's binary expansion.
+// Amounts with more digits than the coin's decimals are rounded to them.
+func splitDecimal(amount float64, decimals int) (string, string) {
+	s := strconv.FormatFloat(amount, 'f', -1, 64)
+	if _, fraction, _ := strings.Cut(s, "."); len(fraction) > decimals {
+		s = strconv.FormatFloat(amount, 'f', decimals, 64)
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	return whole, fraction
+}
@@ -0,0 +1,115 @@
+package payment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildPaymentURI(t *testing.T) {
+	registry := createTestRegistry()
+	coin := func(symbol string) CryptoCurrency {
+		crypto, _ := registry.Get(symbol)
+		return crypto
+	}
+
+	tests := []struct {
+		name          string
+		crypto        CryptoCurrency
+		walletAddress string
+		amount        float64
+		label         string
+		expected      string
+	}{
+		{
+			name:          "bitcoin with amount and label",
+			crypto:        coin("BTC"),
+			walletAddress: "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh",
+			amount:        0.0015,
+			label:         "Order #123 & co",
+			expected:      "bitcoin:bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh?amount=0.0015&label=Order%20%23123%20%26%20co",
+		},
+		{
+			name:          "bitcoin not quoted yet",
+			crypto:        coin("BTC"),
+			walletAddress: "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh",
+			expected:      "bitcoin:bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh",
+		},
+		{
+			name:          "litecoin",
+			crypto:        coin("LTC"),
+			walletAddress: "ltc1qg42tkwuuxefutzxezdkdel39gfstuap288mfea",
+			amount:        1.25,
+			expected:      "litecoin:ltc1qg42tkwuuxefutzxezdkdel39gfstuap288mfea?amount=1.25",
+		},
+		{
+			name:          "dogecoin rounded to decimals",
+			crypto:        coin("DOGE"),
+			walletAddress: "DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L",
+			amount:        12.123456789,
+			expected:      "dogecoin:DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L?amount=12.12345679",
+		},
+		{
+			name:          "bitcoin cash strips the cashaddr prefix",
+			crypto:        coin("BCH"),
+			walletAddress: "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a",
+			amount:        0.5,
+			expected:      "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a?amount=0.5",
+		},
+		{
+			name:          "ether in wei",
+			crypto:        coin("ETH"),
+			walletAddress: "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+			amount:        0.05,
+			expected:      "ethereum:0x742d35Cc6634C0532925a3b844Bc454e4438f44e?value=50000000000000000",
+		},
+		{
+			name:          "erc-20 transfer in token units",
+			crypto:        coin("USDC"),
+			walletAddress: "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+			amount:        25.5,
+			expected:      "ethereum:0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48/transfer?address=0x742d35Cc6634C0532925a3b844Bc454e4438f44e&uint256=25500000",
+		},
+		{
+			name:          "xrp with destination tag",
+			crypto:        coin("XRP"),
+			walletAddress: "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh?dt=12345",
+			amount:        30,
+			expected:      "ripple:rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh?amount=30&dt=12345",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri, err := BuildPaymentURI(tt.crypto, tt.walletAddress, tt.amount, tt.label)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, uri)
+		})
+	}
+
+	t.Run("testnet ether names the chain", func(t *testing.T) {
+		sepolia := coin("ETH")
+		sepolia.Testnet = true
+
+		uri, _ := BuildPaymentURI(sepolia, "0x742d35Cc6634C0532925a3b844Bc454e4438f44e", 1, "")
+
+		assert.Equal(t, "ethereum:0x742d35Cc6634C0532925a3b844Bc454e4438f44e@11155111?value=1000000000000000000", uri)
+	})
+
+	t.Run("tron has no scheme", func(t *testing.T) {
+		_, err := BuildPaymentURI(coin("USDTTRC20"), "TJRabPrwbZy45sbavfcjinPJC18kjpRTv8", 10, "")
+
+		assert.Equal(t, ErrPaymentURINotSupported, err)
+	})
+}
+
+func TestPayment_PaymentURI(t *testing.T) {
+	p, _ := NewPayment("order123", 100.0, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+	_ = p.UpdateCryptoAmount(0.002)
+
+	uri, err := p.PaymentURI("Order 123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "bitcoin:bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh?amount=0.002&label=Order%20123", uri)
+}
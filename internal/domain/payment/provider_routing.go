@@ -0,0 +1,58 @@
+package payment
+
+import (
+	"sort"
+	"strings"
+)
+
+// ProviderRouting picks the payment provider that opens a new payment. The
+// merchant configures a default provider and may route single
+// cryptocurrencies to another one.
+type ProviderRouting struct {
+	Default    string            // Provider used when no currency route applies
+	Currencies map[string]string // Crypto symbol (upper case) -> provider
+}
+
+// NewProviderRouting creates a provider routing with validation
+func NewProviderRouting(defaultProvider string, currencies map[string]string) (ProviderRouting, error) {
+	if defaultProvider == "" {
+		return ProviderRouting{}, ErrInvalidProviderRouting
+	}
+
+	routes := make(map[string]string, len(currencies))
+	for symbol, provider := range currencies {
+		if symbol == "" || provider == "" {
+			return ProviderRouting{}, ErrInvalidProviderRouting
+		}
+		routes[strings.ToUpper(symbol)] = provider
+	}
+
+	return ProviderRouting{Default: defaultProvider, Currencies: routes}, nil
+}
+
+// SingleProviderRouting returns the routing that sends every payment to one provider
+func SingleProviderRouting(provider string) ProviderRouting {
+	return ProviderRouting{Default: provider}
+}
+
+// ProviderFor returns the provider that opens payments in a cryptocurrency
+func (r ProviderRouting) ProviderFor(cryptoSymbol string) string {
+	if provider, ok := r.Currencies[strings.ToUpper(cryptoSymbol)]; ok {
+		return provider
+	}
+	return r.Default
+}
+
+// Providers returns every provider the routing names, sorted
+func (r ProviderRouting) Providers() []string {
+	seen := map[string]bool{r.Default: true}
+	providers := []string{r.Default}
+	for _, provider := range r.Currencies {
+		if !seen[provider] {
+			seen[provider] = true
+			providers = append(providers, provider)
+		}
+	}
+	sort.Strings(providers)
+	return providers
+}
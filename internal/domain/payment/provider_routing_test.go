@@ -0,0 +1,34 @@
+package payment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderRouting(t *testing.T) {
+	t.Run("currency routes override the default", func(t *testing.T) {
+		routing, err := NewProviderRouting("nowpayments", map[string]string{"usdttrc20": "manual"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "manual", routing.ProviderFor("USDTTRC20"))
+		assert.Equal(t, "nowpayments", routing.ProviderFor("btc"))
+		assert.Equal(t, []string{"manual", "nowpayments"}, routing.Providers())
+	})
+
+	t.Run("single provider", func(t *testing.T) {
+		routing := SingleProviderRouting("manual")
+
+		assert.Equal(t, "manual", routing.ProviderFor("ETH"))
+		assert.Equal(t, []string{"manual"}, routing.Providers())
+	})
+
+	t.Run("invalid routing", func(t *testing.T) {
+		_, errDefault := NewProviderRouting("", nil)
+		_, errRoute := NewProviderRouting("nowpayments", map[string]string{"BTC": ""})
+
+		assert.Equal(t, ErrInvalidProviderRouting, errDefault)
+		assert.Equal(t, ErrInvalidProviderRouting, errRoute)
+	})
+}
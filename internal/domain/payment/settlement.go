@@ -0,0 +1,217 @@
+package payment
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// SettlementResult classifies a payment in a settlement reconciliation
+type SettlementResult string
+
+const (
+	SettlementMatched         SettlementResult = "MATCHED"
+	SettlementMissingLocally  SettlementResult = "MISSING_LOCALLY"  // Gateway payment without a local record
+	SettlementMissingRemotely SettlementResult = "MISSING_REMOTELY" // Confirmed payment the gateway does not list
+	SettlementAmountMismatch  SettlementResult = "AMOUNT_MISMATCH"  // Amount, currency, coin or amount paid differs
+	SettlementStatusMismatch  SettlementResult = "STATUS_MISMATCH"  // Confirmed on one side only
+)
+
+// settlementAmountTolerance absorbs rounding of fiat amounts by the gateway
+const settlementAmountTolerance = 0.005
+
+// settlementCryptoDecimals caps the decimals crypto amounts are compared at.
+// The gateway rounds amounts of 18-decimal coins well before their last digit.
+const settlementCryptoDecimals = 8
+
+// SettlementMatchMargin is how far around a settlement period payments and
+// gateway records are loaded. The gateway dates a payment by its own clock,
+// moments before we record it, so the two can fall on different days.
+const SettlementMatchMargin = time.Hour
+
+// GatewayPaymentRecord is a payment as listed by the gateway for settlement
+type GatewayPaymentRecord struct {
+	GatewayPaymentID string
+	OrderID          string
+	Status           GatewayStatus
+	PriceAmount      float64 // Fiat amount
+	PriceCurrency    string
+	PayAmount        float64 // Crypto amount requested
+	PayCurrency      string
+	ActuallyPaid     float64
+	CreatedAt        time.Time
+}
+
+// SettlementLine is the result for one payment
+type SettlementLine struct {
+	Result           SettlementResult
+	PaymentID        string // Empty when missing locally
+	GatewayPaymentID string
+	LocalStatus      PaymentStatus // Empty when missing locally
+	GatewayStatus    GatewayStatus // Empty when missing remotely
+	LocalAmount      float64
+	LocalCurrency    string
+	GatewayAmount    float64
+	GatewayCurrency  string
+	CryptoCurrency   string  // Currency of the fees, empty when missing locally
+	GatewayFee       float64 // Fees recorded on the local payment
+	NetworkFee       float64
+}
+
+// SettlementSummary counts the lines of a report per result
+type SettlementSummary struct {
+	Total            int
+	Matched          int
+	MissingLocally   int
+	MissingRemotely  int
+	AmountMismatches int
+	StatusMismatches int
+}
+
+// Discrepancies returns the number of lines that need attention
+func (s SettlementSummary) Discrepancies() int {
+	return s.Total - s.Matched
+}
+
+// SettlementReport compares our payments with the gateway's for a period
+type SettlementReport struct {
+	From        time.Time // Inclusive
+	To          time.Time // Exclusive
+	GeneratedAt time.Time
+	Lines       []SettlementLine
+	Summary     SettlementSummary
+}
+
+// ReconcileSettlement matches local payments with gateway records by gateway
+// payment ID. The payments must all come from the provider whose records are
+// given. Every gateway record gets a line; local payments only get one
+// when they are confirmed and the gateway does not list them.
+//
+// Payments and records may extend past the period (see SettlementMatchMargin).
+// A line belongs to the period its gateway record was created in, or its
+// payment when the gateway does not list it, so each payment is reported on
+// exactly one day.
+func ReconcileSettlement(from, to time.Time, payments []*Payment, records []GatewayPaymentRecord) (*SettlementReport, error) {
+	if !from.Before(to) {
+		return nil, ErrInvalidSettlementPeriod
+	}
+
+	byGatewayID := make(map[string]*Payment, len(payments))
+	for _, p := range payments {
+		if p.ProviderReference != "" {
+			byGatewayID[p.ProviderReference] = p
+		}
+	}
+
+	report := &SettlementReport{From: from, To: to, GeneratedAt: time.Now()}
+	listed := make(map[string]bool, len(records))
+
+	inPeriod := func(createdAt time.Time) bool {
+		return !createdAt.Before(from) && createdAt.Before(to)
+	}
+
+	for _, record := range records {
+		listed[record.GatewayPaymentID] = true
+
+		// Records without a creation time were listed for the period
+		if !record.CreatedAt.IsZero() && !inPeriod(record.CreatedAt) {
+			continue
+		}
+		report.add(classifySettlement(byGatewayID[record.GatewayPaymentID], &record))
+	}
+
+	for _, p := range payments {
+		if isSettledLocally(p.Status) && inPeriod(p.CreatedAt) && (p.ProviderReference == "" || !listed[p.ProviderReference]) {
+			report.add(classifySettlement(p, nil))
+		}
+	}
+
+	return report, nil
+}
+
+// classifySettlement compares a local payment with its gateway record; either may be nil
+func classifySettlement(p *Payment, record *GatewayPaymentRecord) SettlementLine {
+	line := SettlementLine{}
+
+	if p != nil {
+		line.PaymentID = p.ID
+		line.GatewayPaymentID = p.ProviderReference
+		line.LocalStatus = p.Status
+		line.LocalAmount = p.Amount
+		line.LocalCurrency = p.Currency
+		line.CryptoCurrency = p.GetCryptoSymbol()
+		line.GatewayFee = p.GatewayFee
+		line.NetworkFee = p.NetworkFee
+	}
+	if record != nil {
+		line.GatewayPaymentID = record.GatewayPaymentID
+		line.GatewayStatus = record.Status
+		line.GatewayAmount = record.PriceAmount
+		line.GatewayCurrency = strings.ToUpper(record.PriceCurrency)
+	}
+
+	switch {
+	case p == nil:
+		line.Result = SettlementMissingLocally
+	case record == nil:
+		line.Result = SettlementMissingRemotely
+	case !strings.EqualFold(p.Currency, record.PriceCurrency) ||
+		math.Abs(p.Amount-record.PriceAmount) > settlementAmountTolerance ||
+		!strings.EqualFold(p.GetCryptoSymbol(), record.PayCurrency):
+		line.Result = SettlementAmountMismatch
+	case !settlementStatusesMatch(p.Status, record.Status):
+		line.Result = SettlementStatusMismatch
+	// Funds settled on both sides: the customer must have paid the whole amount
+	case isSettledLocally(p.Status) &&
+		math.Abs(p.CryptoAmount-record.ActuallyPaid) > settlementCryptoTolerance(p.CryptoCurrency):
+		line.Result = SettlementAmountMismatch
+	default:
+		line.Result = SettlementMatched
+	}
+
+	return line
+}
+
+// settlementCryptoTolerance is one unit of the last decimal crypto amounts of
+// the coin are compared at
+func settlementCryptoTolerance(c CryptoCurrency) float64 {
+	decimals := c.Decimals
+	if decimals <= 0 || decimals > settlementCryptoDecimals {
+		decimals = settlementCryptoDecimals
+	}
+	return math.Pow10(-decimals)
+}
+
+// isSettledLocally checks if a payment's funds were received. Refunds are
+// issued by us after settlement, so refunded payments count too.
+func isSettledLocally(status PaymentStatus) bool {
+	return status == StatusConfirmed || status == StatusRefunded
+}
+
+// settlementStatusesMatch checks if both sides agree on whether the funds settled
+func settlementStatusesMatch(local PaymentStatus, remote GatewayStatus) bool {
+	if isSettledLocally(local) {
+		return remote == GatewayStatusFinished || remote == GatewayStatusRefunded
+	}
+
+	return remote != GatewayStatusFinished && remote.PaymentStatus() == local
+}
+
+// add appends a line and counts it in the summary
+func (r *SettlementReport) add(line SettlementLine) {
+	r.Lines = append(r.Lines, line)
+	r.Summary.Total++
+
+	switch line.Result {
+	case SettlementMatched:
+		r.Summary.Matched++
+	case SettlementMissingLocally:
+		r.Summary.MissingLocally++
+	case SettlementMissingRemotely:
+		r.Summary.MissingRemotely++
+	case SettlementAmountMismatch:
+		r.Summary.AmountMismatches++
+	case SettlementStatusMismatch:
+		r.Summary.StatusMismatches++
+	}
+}
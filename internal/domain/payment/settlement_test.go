@@ -0,0 +1,176 @@
+package payment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// createSettlementPayment creates a 0.002 BTC payment registered with the gateway in the given status, created on 2024-03-01
+func createSettlementPayment(gatewayPaymentID string, amount float64, status PaymentStatus) *Payment {
+	p, _ := NewPayment("order123", amount, "USD", "BTC", "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 30)
+	_ = p.UpdateCryptoAmount(0.002)
+	_ = p.SetProviderReference("nowpayments", gatewayPaymentID)
+	p.Status = status
+	p.CreatedAt = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	return p
+}
+
+func TestReconcileSettlement(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	record := func(gatewayPaymentID string, amount float64, status GatewayStatus) GatewayPaymentRecord {
+		return GatewayPaymentRecord{GatewayPaymentID: gatewayPaymentID, Status: status, PriceAmount: amount, PriceCurrency: "usd", PayCurrency: "BTC", ActuallyPaid: 0.002, CreatedAt: from}
+	}
+
+	t.Run("classifies every record", func(t *testing.T) {
+		payments := []*Payment{
+			createSettlementPayment("np-matched", 100.0, StatusConfirmed),
+			createSettlementPayment("np-amount", 100.0, StatusConfirmed),
+			createSettlementPayment("np-status", 100.0, StatusConfirmed),
+			createSettlementPayment("np-remote", 100.0, StatusConfirmed),
+			createSettlementPayment("np-expired", 100.0, StatusExpired),
+		}
+		records := []GatewayPaymentRecord{
+			record("np-matched", 100.001, GatewayStatusFinished),
+			record("np-amount", 90.0, GatewayStatusFinished),
+			record("np-status", 100.0, GatewayStatusSending),
+			record("np-local", 50.0, GatewayStatusFinished),
+			record("np-expired", 100.0, GatewayStatusExpired),
+		}
+
+		report, err := ReconcileSettlement(from, to, payments, records)
+
+		assert.NoError(t, err)
+		results := make(map[string]SettlementResult)
+		for _, line := range report.Lines {
+			results[line.GatewayPaymentID] = line.Result
+		}
+		assert.Equal(t, map[string]SettlementResult{
+			"np-matched": SettlementMatched,
+			"np-amount":  SettlementAmountMismatch,
+			"np-status":  SettlementStatusMismatch,
+			"np-local":   SettlementMissingLocally,
+			"np-expired": SettlementMatched,
+			"np-remote":  SettlementMissingRemotely,
+		}, results)
+		assert.Equal(t, SettlementSummary{Total: 6, Matched: 2, MissingLocally: 1, MissingRemotely: 1, AmountMismatches: 1, StatusMismatches: 1}, report.Summary)
+		assert.Equal(t, 4, report.Summary.Discrepancies())
+	})
+
+	t.Run("currency mismatch", func(t *testing.T) {
+		mismatched := record("np-1", 100.0, GatewayStatusFinished)
+		mismatched.PriceCurrency = "eur"
+
+		report, _ := ReconcileSettlement(from, to, []*Payment{createSettlementPayment("np-1", 100.0, StatusConfirmed)}, []GatewayPaymentRecord{mismatched})
+
+		assert.Equal(t, SettlementAmountMismatch, report.Lines[0].Result)
+		assert.Equal(t, "EUR", report.Lines[0].GatewayCurrency)
+	})
+
+	t.Run("wrong coin", func(t *testing.T) {
+		wrongCoin := record("np-1", 100.0, GatewayStatusFinished)
+		wrongCoin.PayCurrency = "ltc"
+
+		report, _ := ReconcileSettlement(from, to, []*Payment{createSettlementPayment("np-1", 100.0, StatusConfirmed)}, []GatewayPaymentRecord{wrongCoin})
+
+		assert.Equal(t, SettlementAmountMismatch, report.Lines[0].Result)
+	})
+
+	t.Run("amount actually paid", func(t *testing.T) {
+		testCases := []struct {
+			name         string
+			actuallyPaid float64
+			want         SettlementResult
+		}{
+			{"paid in full", 0.002, SettlementMatched},
+			{"within crypto precision", 0.002000005, SettlementMatched},
+			{"underpaid", 0.0019, SettlementAmountMismatch},
+			{"overpaid", 0.0021, SettlementAmountMismatch},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				paid := record("np-1", 100.0, GatewayStatusFinished)
+				paid.ActuallyPaid = tc.actuallyPaid
+
+				report, _ := ReconcileSettlement(from, to, []*Payment{createSettlementPayment("np-1", 100.0, StatusConfirmed)}, []GatewayPaymentRecord{paid})
+
+				assert.Equal(t, tc.want, report.Lines[0].Result)
+			})
+		}
+	})
+
+	t.Run("unpaid expired payment matches", func(t *testing.T) {
+		unpaid := record("np-1", 100.0, GatewayStatusExpired)
+		unpaid.ActuallyPaid = 0
+
+		report, _ := ReconcileSettlement(from, to, []*Payment{createSettlementPayment("np-1", 100.0, StatusExpired)}, []GatewayPaymentRecord{unpaid})
+
+		assert.Equal(t, SettlementMatched, report.Lines[0].Result)
+	})
+
+	t.Run("refunded payments settled", func(t *testing.T) {
+		report, _ := ReconcileSettlement(from, to, []*Payment{createSettlementPayment("np-1", 100.0, StatusRefunded)}, []GatewayPaymentRecord{record("np-1", 100.0, GatewayStatusFinished)})
+
+		assert.Equal(t, SettlementMatched, report.Lines[0].Result)
+	})
+
+	t.Run("lines carry the recorded fees", func(t *testing.T) {
+		p := createSettlementPayment("np-1", 100.0, StatusConfirmed)
+		p.CryptoAmount = 0.002
+		_ = p.RecordFees(0.00001, 0.000005)
+
+		report, _ := ReconcileSettlement(from, to, []*Payment{p}, []GatewayPaymentRecord{record("np-1", 100.0, GatewayStatusFinished)})
+
+		assert.Equal(t, "BTC", report.Lines[0].CryptoCurrency)
+		assert.Equal(t, 0.00001, report.Lines[0].GatewayFee)
+		assert.Equal(t, 0.000005, report.Lines[0].NetworkFee)
+	})
+
+	t.Run("confirmed payment without gateway id", func(t *testing.T) {
+		report, _ := ReconcileSettlement(from, to, []*Payment{createSettlementPayment("", 100.0, StatusConfirmed)}, nil)
+
+		assert.Equal(t, SettlementMissingRemotely, report.Lines[0].Result)
+	})
+
+	t.Run("unsettled local payment not listed is ignored", func(t *testing.T) {
+		report, _ := ReconcileSettlement(from, to, []*Payment{createSettlementPayment("np-1", 100.0, StatusPending)}, nil)
+
+		assert.Empty(t, report.Lines)
+		assert.Equal(t, 0, report.Summary.Discrepancies())
+	})
+
+	t.Run("payment dated on different days is reported once", func(t *testing.T) {
+		p := createSettlementPayment("np-midnight", 100.0, StatusConfirmed)
+		p.CreatedAt = to.Add(2 * time.Second) // Recorded just after midnight
+		gatewayRecord := record("np-midnight", 100.0, GatewayStatusFinished)
+		gatewayRecord.CreatedAt = to.Add(-time.Second) // Opened at the gateway just before
+
+		first, _ := ReconcileSettlement(from, to, []*Payment{p}, []GatewayPaymentRecord{gatewayRecord})
+		next, _ := ReconcileSettlement(to, to.AddDate(0, 0, 1), []*Payment{p}, []GatewayPaymentRecord{gatewayRecord})
+
+		assert.Len(t, first.Lines, 1)
+		assert.Equal(t, SettlementMatched, first.Lines[0].Result)
+		assert.Empty(t, next.Lines)
+	})
+
+	t.Run("records and payments outside the period are left to their own day", func(t *testing.T) {
+		p := createSettlementPayment("np-earlier", 100.0, StatusConfirmed)
+		p.CreatedAt = from.Add(-time.Minute)
+		gatewayRecord := record("np-other", 50.0, GatewayStatusFinished)
+		gatewayRecord.CreatedAt = to.Add(time.Minute)
+
+		report, _ := ReconcileSettlement(from, to, []*Payment{p}, []GatewayPaymentRecord{gatewayRecord})
+
+		assert.Empty(t, report.Lines)
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		_, err := ReconcileSettlement(to, from, nil, nil)
+
+		assert.Equal(t, ErrInvalidSettlementPeriod, err)
+	})
+}
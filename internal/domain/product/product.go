@@ -9,16 +9,17 @@ import (
 
 // Product represents a product that can be sold
 type Product struct {
-	ID          uuid.UUID     // Unique identifier
-	Name        string        // Product name
-	Description string        // Product description
-	SKU         string        // Stock Keeping Unit (unique)
-	Price       order.Money   // Product price (reusing Money from order domain)
-	Category    Category      // Product category
-	Inventory   Inventory     // Stock information
-	Status      ProductStatus // Current product status
-	CreatedAt   time.Time     // When product was created
-	UpdatedAt   time.Time     // When product was last updated
+	ID          uuid.UUID              // Unique identifier
+	Name        string                 // Product name
+	Description string                 // Product description
+	SKU         string                 // Stock Keeping Unit (unique)
+	Price       order.Money            // Product price (reusing Money from order domain)
+	PriceList   map[string]order.Money // Explicit prices in other currencies, keyed by currency
+	Category    Category               // Product category
+	Inventory   Inventory              // Stock information
+	Status      ProductStatus          // Current product status
+	CreatedAt   time.Time              // When product was created
+	UpdatedAt   time.Time              // When product was last updated
 }
 
 // NewProduct creates a new product with validation
@@ -79,6 +80,45 @@ func (p *Product) UpdatePrice(newPrice order.Money) error {
 	return nil
 }
 
+// SetPriceIn sets an explicit price in another currency, overriding conversion
+func (p *Product) SetPriceIn(price order.Money) error {
+	if price.Amount <= 0 {
+		return ErrInvalidPrice
+	}
+	
+	if price.Currency == "" || price.Currency == p.Price.Currency {
+		return ErrInvalidCurrency
+	}
+	
+	if p.Status == StatusDiscontinued {
+		return ErrCannotUpdateDiscontinued
+	}
+	
+	if p.PriceList == nil {
+		p.PriceList = make(map[string]order.Money)
+	}
+	p.PriceList[price.Currency] = price
+	p.UpdatedAt = time.Now()
+	
+	return nil
+}
+
+// RemovePriceIn removes the explicit price in a currency, falling back to conversion
+func (p *Product) RemovePriceIn(currency string) {
+	delete(p.PriceList, currency)
+	p.UpdatedAt = time.Now()
+}
+
+// PriceIn returns the product price in the given currency, if one is set
+func (p *Product) PriceIn(currency string) (order.Money, bool) {
+	if currency == p.Price.Currency {
+		return p.Price, true
+	}
+	
+	price, ok := p.PriceList[currency]
+	return price, ok
+}
+
 // UpdateDescription updates the product description
 func (p *Product) UpdateDescription(description string) error {
 	// Only allow updates for active/inactive products
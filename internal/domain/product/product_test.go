@@ -549,4 +549,48 @@ func TestProduct(t *testing.T) {
 		assert.Equal(t, "Smartphones", product.Category.Name)
 		assert.True(t, product.UpdatedAt.After(oldUpdateTime))
 	})
-}
\ No newline at end of file
+	
+	t.Run("new order item captures product snapshot", func(t *testing.T) {
+		product := createTestProduct()
+		
+		item, err := order.NewOrderItemWithSnapshot(product.ID, product.Name, product.SKU, 2, product.Price)
+		
+		assert.NoError(t, err)
+		assert.Equal(t, product.ID, item.ProductID)
+		assert.Equal(t, product.Name, item.ProductName)
+		assert.Equal(t, product.SKU, item.ProductSKU)
+		assert.Equal(t, product.Price, item.UnitPrice)
+		
+		// Later price changes do not touch the captured price
+		_ = product.UpdatePrice(createTestMoney(1.0, "USD"))
+		assert.NotEqual(t, product.Price, item.UnitPrice)
+	})
+	
+	t.Run("price list by currency", func(t *testing.T) {
+		product := createTestProduct()
+		eurPrice := order.Money{Amount: 89.99, Currency: "EUR"}
+		
+		err := product.SetPriceIn(eurPrice)
+		price, ok := product.PriceIn("EUR")
+		base, baseOk := product.PriceIn(product.Price.Currency)
+		_, gbpOk := product.PriceIn("GBP")
+		
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, eurPrice, price)
+		assert.True(t, baseOk)
+		assert.Equal(t, product.Price, base)
+		assert.False(t, gbpOk)
+		
+		product.RemovePriceIn("EUR")
+		_, ok = product.PriceIn("EUR")
+		assert.False(t, ok)
+	})
+	
+	t.Run("price list rejects invalid prices", func(t *testing.T) {
+		product := createTestProduct()
+		
+		assert.Equal(t, ErrInvalidPrice, product.SetPriceIn(order.Money{Amount: 0, Currency: "EUR"}))
+		assert.Equal(t, ErrInvalidCurrency, product.SetPriceIn(order.Money{Amount: 10, Currency: product.Price.Currency}))
+	})
+}
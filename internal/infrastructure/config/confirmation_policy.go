@@ -0,0 +1,61 @@
+package config
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// confirmationTierConfig is one tier of a confirmation table
+type confirmationTierConfig struct {
+	UpTo  float64 `json:"up_to"` // 0 = no bound
+	Scale float64 `json:"scale"` // 0 = zero-conf
+}
+
+// confirmationPolicyConfig is the confirmation policy configuration file
+type confirmationPolicyConfig struct {
+	Currency    string                              `json:"currency"`
+	Default     []confirmationTierConfig            `json:"default"`
+	Categories  map[string][]confirmationTierConfig `json:"categories"`
+	TrustLevels map[string][]confirmationTierConfig `json:"trust_levels"`
+}
+
+// LoadConfirmationPolicy reads a JSON confirmation policy and validates it
+func LoadConfirmationPolicy(r io.Reader) (*domainPayment.ConfirmationPolicy, error) {
+	var cfg confirmationPolicyConfig
+	if err := json.NewDecoder(r).Decode(&cfg); err != nil {
+		return nil, err
+	}
+
+	policy := &domainPayment.ConfirmationPolicy{
+		Currency:    strings.ToUpper(cfg.Currency),
+		Default:     toConfirmationTable(cfg.Default),
+		Categories:  make(map[string]domainPayment.ConfirmationTable, len(cfg.Categories)),
+		TrustLevels: make(map[string]domainPayment.ConfirmationTable, len(cfg.TrustLevels)),
+	}
+
+	for categoryID, tiers := range cfg.Categories {
+		policy.Categories[categoryID] = toConfirmationTable(tiers)
+	}
+
+	for level, tiers := range cfg.TrustLevels {
+		policy.TrustLevels[strings.ToUpper(level)] = toConfirmationTable(tiers)
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// toConfirmationTable maps configured tiers to a confirmation table
+func toConfirmationTable(tiers []confirmationTierConfig) domainPayment.ConfirmationTable {
+	table := make(domainPayment.ConfirmationTable, 0, len(tiers))
+	for _, tier := range tiers {
+		table = append(table, domainPayment.ConfirmationTier{UpTo: tier.UpTo, Scale: tier.Scale})
+	}
+	return table
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfirmationPolicy(t *testing.T) {
+	t.Run("load policy from json", func(t *testing.T) {
+		input := `{
+			"currency": "usd",
+			"default": [{"up_to": 50, "scale": 0}, {"up_to": 1000, "scale": 0.5}, {"scale": 1}],
+			"categories": {"gift-cards": [{"scale": 2}]},
+			"trust_levels": {"trusted": [{"up_to": 1000, "scale": 0}, {"scale": 1}]}
+		}`
+
+		policy, err := LoadConfirmationPolicy(strings.NewReader(input))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "USD", policy.Currency)
+		assert.Len(t, policy.Default, 3)
+		btc := domainPayment.CryptoCurrency{Symbol: "BTC", RequiredConfirmations: 2}
+		requirement := policy.Evaluate(btc, 500, "USD", domainPayment.ConfirmationScope{TrustLevel: "TRUSTED"})
+		assert.True(t, requirement.ZeroConf)
+	})
+
+	t.Run("last tier must be unbounded", func(t *testing.T) {
+		input := `{"currency": "USD", "default": [{"up_to": 50, "scale": 0}]}`
+
+		_, err := LoadConfirmationPolicy(strings.NewReader(input))
+
+		assert.Equal(t, domainPayment.ErrInvalidConfirmationPolicy, err)
+	})
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"encoding/json"
+	"io"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// cryptoCurrencyConfig is one entry of the cryptocurrency configuration file
+type cryptoCurrencyConfig struct {
+	Symbol                string  `json:"symbol"`
+	Asset                 string  `json:"asset"`
+	Name                  string  `json:"name"`
+	Network               string  `json:"network"`
+	ContractAddress       string  `json:"contract_address"`
+	Decimals              int     `json:"decimals"`
+	MinAmount             float64 `json:"min_amount"`
+	RequiredConfirmations int     `json:"required_confirmations"`
+	Stablecoin            bool    `json:"stablecoin"`
+	PeggedTo              string  `json:"pegged_to"`
+	Testnet               bool    `json:"testnet"`
+	ExplorerTxURL         string  `json:"explorer_tx_url"`
+	Active                bool    `json:"active"`
+}
+
+// LoadCryptoRegistry reads a JSON array of cryptocurrencies and builds a registry from it
+func LoadCryptoRegistry(r io.Reader) (*domainPayment.CryptoRegistry, error) {
+	var entries []cryptoCurrencyConfig
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	cryptos := make([]domainPayment.CryptoCurrency, 0, len(entries))
+	for _, entry := range entries {
+		network, err := domainPayment.ParseNetwork(entry.Network)
+		if err != nil {
+			return nil, err
+		}
+
+		cryptos = append(cryptos, domainPayment.CryptoCurrency{
+			Symbol:                entry.Symbol,
+			Asset:                 entry.Asset,
+			Name:                  entry.Name,
+			Network:               network,
+			ContractAddress:       entry.ContractAddress,
+			Decimals:              entry.Decimals,
+			MinAmount:             entry.MinAmount,
+			RequiredConfirmations: entry.RequiredConfirmations,
+			IsStablecoin:          entry.Stablecoin,
+			PeggedTo:              entry.PeggedTo,
+			Testnet:               entry.Testnet,
+			ExplorerTxURL:         entry.ExplorerTxURL,
+			IsActive:              entry.Active,
+		})
+	}
+
+	return domainPayment.NewCryptoRegistry(cryptos)
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadCryptoRegistry(t *testing.T) {
+	t.Run("load coins from json", func(t *testing.T) {
+		input := `[
+			{"symbol": "BTC", "name": "Bitcoin", "network": "bitcoin", "decimals": 8, "min_amount": 0.0001, "required_confirmations": 3, "active": true},
+			{"symbol": "DOGE", "name": "Dogecoin", "network": "dogecoin", "decimals": 8, "min_amount": 1, "required_confirmations": 6, "active": false}
+		]`
+
+		registry, err := LoadCryptoRegistry(strings.NewReader(input))
+
+		assert.NoError(t, err)
+		btc, err := registry.Get("BTC")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, btc.RequiredConfirmations)
+		_, err = registry.Get("DOGE")
+		assert.Equal(t, domainPayment.ErrUnsupportedCrypto, err)
+	})
+
+	t.Run("invalid entry", func(t *testing.T) {
+		input := `[{"symbol": "BTC", "name": "Bitcoin", "network": "bitcoin", "decimals": 8, "min_amount": 0, "required_confirmations": 2, "active": true}]`
+
+		_, err := LoadCryptoRegistry(strings.NewReader(input))
+
+		assert.Equal(t, domainPayment.ErrInvalidCryptoConfig, err)
+	})
+}
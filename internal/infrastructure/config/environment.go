@@ -0,0 +1,46 @@
+package config
+
+import (
+	"encoding/json"
+	"io"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// environmentConfig is the payment environment configuration file
+type environmentConfig struct {
+	Environment string `json:"environment"` // LIVE (default) or SANDBOX
+}
+
+// LoadEnvironment reads the JSON payment environment, e.g. {"environment": "SANDBOX"}
+func LoadEnvironment(r io.Reader) (domainPayment.Environment, error) {
+	var cfg environmentConfig
+	if err := json.NewDecoder(r).Decode(&cfg); err != nil {
+		return "", err
+	}
+
+	return domainPayment.ParseEnvironment(cfg.Environment)
+}
+
+// LoadCryptoRegistryFor builds the coin registry of an environment: the
+// built-in coins of the environment when r is nil, otherwise the configured
+// coins, which must all be testnet coins in the sandbox and mainnet coins
+// when live
+func LoadCryptoRegistryFor(environment domainPayment.Environment, r io.Reader) (*domainPayment.CryptoRegistry, error) {
+	if r == nil {
+		return domainPayment.NewCryptoRegistry(domainPayment.DefaultCryptoCurrenciesFor(environment))
+	}
+
+	registry, err := LoadCryptoRegistry(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, crypto := range registry.All() {
+		if domainPayment.EnvironmentOf(crypto) != environment {
+			return nil, domainPayment.ErrEnvironmentMismatch
+		}
+	}
+
+	return registry, nil
+}
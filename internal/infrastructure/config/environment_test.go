@@ -0,0 +1,46 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadEnvironment(t *testing.T) {
+	sandbox, errSandbox := LoadEnvironment(strings.NewReader(`{"environment": "sandbox"}`))
+	live, errLive := LoadEnvironment(strings.NewReader(`{}`))
+	_, errInvalid := LoadEnvironment(strings.NewReader(`{"environment": "staging"}`))
+
+	assert.NoError(t, errSandbox)
+	assert.NoError(t, errLive)
+	assert.Equal(t, domainPayment.EnvironmentSandbox, sandbox)
+	assert.Equal(t, domainPayment.EnvironmentLive, live)
+	assert.Equal(t, domainPayment.ErrInvalidEnvironment, errInvalid)
+}
+
+func TestLoadCryptoRegistryFor(t *testing.T) {
+	t.Run("built-in testnet coins in the sandbox", func(t *testing.T) {
+		registry, err := LoadCryptoRegistryFor(domainPayment.EnvironmentSandbox, nil)
+		require.NoError(t, err)
+
+		btc, err := registry.Get("BTC")
+
+		assert.NoError(t, err)
+		assert.True(t, btc.Testnet)
+		assert.NoError(t, btc.ValidateAddress("tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"))
+	})
+
+	t.Run("configured coins must match the environment", func(t *testing.T) {
+		input := `[{"symbol": "BTC", "name": "Bitcoin", "network": "bitcoin", "decimals": 8, "min_amount": 0.0001, "required_confirmations": 1, "active": true}]`
+
+		_, errSandbox := LoadCryptoRegistryFor(domainPayment.EnvironmentSandbox, strings.NewReader(input))
+		registry, errLive := LoadCryptoRegistryFor(domainPayment.EnvironmentLive, strings.NewReader(input))
+
+		assert.Equal(t, domainPayment.ErrEnvironmentMismatch, errSandbox)
+		assert.NoError(t, errLive)
+		assert.Len(t, registry.All(), 1)
+	})
+}
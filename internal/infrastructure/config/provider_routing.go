@@ -0,0 +1,24 @@
+package config
+
+import (
+	"encoding/json"
+	"io"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// providerRoutingConfig is the payment provider routing configuration file
+type providerRoutingConfig struct {
+	Default    string            `json:"default"`
+	Currencies map[string]string `json:"currencies"` // Crypto symbol -> provider
+}
+
+// LoadProviderRouting reads the merchant's JSON payment provider routing and validates it
+func LoadProviderRouting(r io.Reader) (domainPayment.ProviderRouting, error) {
+	var cfg providerRoutingConfig
+	if err := json.NewDecoder(r).Decode(&cfg); err != nil {
+		return domainPayment.ProviderRouting{}, err
+	}
+
+	return domainPayment.NewProviderRouting(cfg.Default, cfg.Currencies)
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadProviderRouting(t *testing.T) {
+	t.Run("load routing from json", func(t *testing.T) {
+		input := `{"default": "nowpayments", "currencies": {"usdttrc20": "manual"}}`
+
+		routing, err := LoadProviderRouting(strings.NewReader(input))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "manual", routing.ProviderFor("USDTTRC20"))
+		assert.Equal(t, "nowpayments", routing.ProviderFor("BTC"))
+	})
+
+	t.Run("missing default provider", func(t *testing.T) {
+		_, err := LoadProviderRouting(strings.NewReader(`{"currencies": {"BTC": "manual"}}`))
+
+		assert.Equal(t, domainPayment.ErrInvalidProviderRouting, err)
+	})
+}
@@ -0,0 +1,64 @@
+package cryptorates
+
+import (
+	"sync"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// FakeProvider is an in-memory provider for tests and local development.
+// Prices can be moved and failures injected while it is in use.
+type FakeProvider struct {
+	mu     sync.Mutex
+	prices map[string]float64
+	err    error
+	calls  int
+}
+
+// NewFakeProvider creates an empty fake provider
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{prices: make(map[string]float64)}
+}
+
+// Name identifies the rate source recorded on quotes
+func (f *FakeProvider) Name() string {
+	return "fake"
+}
+
+// SetPrice sets the fiat price of one coin
+func (f *FakeProvider) SetPrice(fiatCurrency, cryptoSymbol string, price float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.prices[pairKey(fiatCurrency, cryptoSymbol)] = price
+}
+
+// Fail makes every following estimate return err (nil to recover)
+func (f *FakeProvider) Fail(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+// Calls returns how many estimates were requested
+func (f *FakeProvider) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+// EstimateCryptoAmount converts the fiat amount at the current fake price
+func (f *FakeProvider) EstimateCryptoAmount(fiatAmount float64, fiatCurrency string, cryptoSymbol string) (float64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls++
+	if f.err != nil {
+		return 0, f.err
+	}
+
+	price, ok := f.prices[pairKey(fiatCurrency, cryptoSymbol)]
+	if !ok || price <= 0 {
+		return 0, domainPayment.ErrRateUnavailable
+	}
+	return fiatAmount / price, nil
+}
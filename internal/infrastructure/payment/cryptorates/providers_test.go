@@ -0,0 +1,43 @@
+package cryptorates
+
+import (
+	"errors"
+	"testing"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticProvider(t *testing.T) {
+	provider := NewStaticProvider(map[string]map[string]float64{
+		"USD": {"BTC": 50000.0},
+	})
+
+	t.Run("converts at table price", func(t *testing.T) {
+		amount, err := provider.EstimateCryptoAmount(100.0, "usd", "btc")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0.002, amount)
+	})
+
+	t.Run("unknown pair", func(t *testing.T) {
+		_, err := provider.EstimateCryptoAmount(100.0, "EUR", "BTC")
+
+		assert.Equal(t, domainPayment.ErrRateUnavailable, err)
+	})
+}
+
+func TestFakeProvider(t *testing.T) {
+	provider := NewFakeProvider()
+	provider.SetPrice("USD", "ETH", 2000.0)
+
+	amount, err := provider.EstimateCryptoAmount(100.0, "USD", "ETH")
+	assert.NoError(t, err)
+	assert.Equal(t, 0.05, amount)
+
+	providerErr := errors.New("provider down")
+	provider.Fail(providerErr)
+	_, err = provider.EstimateCryptoAmount(100.0, "USD", "ETH")
+	assert.Equal(t, providerErr, err)
+	assert.Equal(t, 2, provider.Calls())
+}
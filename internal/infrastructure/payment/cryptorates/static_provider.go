@@ -0,0 +1,43 @@
+package cryptorates
+
+import (
+	"strings"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// StaticProvider quotes from a fixed table of fiat prices per coin,
+// e.g. loaded from configuration for environments without a rate API
+type StaticProvider struct {
+	prices map[string]float64 // "USD/BTC" -> fiat units per coin
+}
+
+// NewStaticProvider creates a provider from fiat currency -> crypto symbol -> price per coin
+func NewStaticProvider(prices map[string]map[string]float64) *StaticProvider {
+	table := make(map[string]float64)
+	for fiat, cryptos := range prices {
+		for symbol, price := range cryptos {
+			table[pairKey(fiat, symbol)] = price
+		}
+	}
+	return &StaticProvider{prices: table}
+}
+
+// Name identifies the rate source recorded on quotes
+func (s *StaticProvider) Name() string {
+	return "static"
+}
+
+// EstimateCryptoAmount converts the fiat amount at the table price
+func (s *StaticProvider) EstimateCryptoAmount(fiatAmount float64, fiatCurrency string, cryptoSymbol string) (float64, error) {
+	price, ok := s.prices[pairKey(fiatCurrency, cryptoSymbol)]
+	if !ok || price <= 0 {
+		return 0, domainPayment.ErrRateUnavailable
+	}
+	return fiatAmount / price, nil
+}
+
+// pairKey builds a case-insensitive table key
+func pairKey(fiatCurrency, cryptoSymbol string) string {
+	return strings.ToUpper(fiatCurrency) + "/" + strings.ToUpper(cryptoSymbol)
+}
@@ -0,0 +1,105 @@
+package manual
+
+import (
+	"strings"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/google/uuid"
+)
+
+// ProviderName is the payment provider name of manual transfers
+const ProviderName = "manual"
+
+// Config holds the merchant's own deposit accounts
+type Config struct {
+	DepositAddresses map[string]string // Crypto symbol -> address the merchant controls
+	ReferencePrefix  string            // Prepended to payment references; defaults to "MT"
+}
+
+// RateEstimator estimates how much crypto a fiat amount buys
+type RateEstimator interface {
+	EstimateCryptoAmount(fiatAmount float64, fiatCurrency string, cryptoSymbol string) (float64, error)
+}
+
+// Provider takes payments without a gateway, the way a manual bank transfer
+// works: the customer sends the funds to the merchant's own deposit address
+// for the coin and quotes the payment reference, and an admin confirms the
+// payment (admin action CONFIRM) once the funds arrive. The provider learns
+// nothing after the payment is opened, so it reports no status, sends no
+// refunds and has no webhooks.
+type Provider struct {
+	addresses map[string]string
+	prefix    string
+	rates     RateEstimator
+}
+
+// NewProvider creates a new instance of Provider. Every deposit address must
+// be valid for its coin.
+func NewProvider(config Config, rates RateEstimator) (*Provider, error) {
+	addresses := make(map[string]string, len(config.DepositAddresses))
+	for symbol, address := range config.DepositAddresses {
+		crypto, err := domainPayment.GetCryptoCurrencyBySymbol(symbol)
+		if err != nil {
+			return nil, err
+		}
+		if err := crypto.ValidateAddress(address); err != nil {
+			return nil, err
+		}
+		addresses[crypto.Symbol] = address
+	}
+
+	prefix := config.ReferencePrefix
+	if prefix == "" {
+		prefix = "MT"
+	}
+
+	return &Provider{addresses: addresses, prefix: prefix, rates: rates}, nil
+}
+
+// Name returns the payment provider name
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// CreatePayment gives the customer the merchant's deposit address for the
+// coin, the amount to send at the current rate (customer-borne fee included)
+// and a reference to quote
+func (p *Provider) CreatePayment(request domainPayment.GatewayPaymentRequest) (domainPayment.GatewayPayment, error) {
+	address, ok := p.addresses[strings.ToUpper(request.PayCurrency)]
+	if !ok {
+		return domainPayment.GatewayPayment{}, domainPayment.ErrUnsupportedCrypto
+	}
+
+	payAmount, err := p.rates.EstimateCryptoAmount(request.PriceAmount+request.FeeSurcharge, request.PriceCurrency, request.PayCurrency)
+	if err != nil {
+		return domainPayment.GatewayPayment{}, err
+	}
+
+	return domainPayment.GatewayPayment{
+		GatewayPaymentID: p.newReference(),
+		PayAddress:       address,
+		PayAmount:        payAmount,
+	}, nil
+}
+
+// GetPaymentStatus is not supported: admins confirm manual transfers
+func (p *Provider) GetPaymentStatus(gatewayPaymentID string) (domainPayment.GatewayPaymentStatus, error) {
+	return domainPayment.GatewayPaymentStatus{}, domainPayment.ErrProviderNotSupported
+}
+
+// RefundPayment is not supported: refunds are sent by hand from the merchant's
+// wallet and recorded with their transaction hash
+func (p *Provider) RefundPayment(request domainPayment.GatewayRefundRequest) (domainPayment.GatewayRefund, error) {
+	return domainPayment.GatewayRefund{}, domainPayment.ErrProviderNotSupported
+}
+
+// VerifyWebhook is not supported: manual transfers have no webhooks
+func (p *Provider) VerifyWebhook(payload []byte, signature string) (domainPayment.GatewayPaymentStatus, error) {
+	return domainPayment.GatewayPaymentStatus{}, domainPayment.ErrProviderNotSupported
+}
+
+// newReference returns a short payment reference customers can type, e.g. MT-3F2A9C1B7D04
+func (p *Provider) newReference() string {
+	id := strings.ReplaceAll(uuid.New().String(), "-", "")
+	return p.prefix + "-" + strings.ToUpper(id[:12])
+}
@@ -0,0 +1,71 @@
+package manual
+
+import (
+	"testing"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/infrastructure/payment/cryptorates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDepositAddress = "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh"
+
+// newTestProvider creates a provider taking BTC at 50,000 USD
+func newTestProvider(t *testing.T) *Provider {
+	rates := cryptorates.NewStaticProvider(map[string]map[string]float64{"USD": {"BTC": 50000}})
+	provider, err := NewProvider(Config{DepositAddresses: map[string]string{"btc": testDepositAddress}}, rates)
+	require.NoError(t, err)
+	return provider
+}
+
+func TestProvider(t *testing.T) {
+	t.Run("payment goes to the merchant's deposit address", func(t *testing.T) {
+		provider := newTestProvider(t)
+
+		payment, err := provider.CreatePayment(domainPayment.GatewayPaymentRequest{OrderID: "order123", PriceAmount: 100, PriceCurrency: "USD", PayCurrency: "BTC"})
+		other, _ := provider.CreatePayment(domainPayment.GatewayPaymentRequest{OrderID: "order124", PriceAmount: 100, PriceCurrency: "USD", PayCurrency: "BTC"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, testDepositAddress, payment.PayAddress)
+		assert.Equal(t, 0.002, payment.PayAmount)
+		assert.Regexp(t, `^MT-[0-9A-F]{12}$`, payment.GatewayPaymentID)
+		assert.NotEqual(t, payment.GatewayPaymentID, other.GatewayPaymentID)
+	})
+
+	t.Run("customer-borne fee is added to the amount to send", func(t *testing.T) {
+		provider := newTestProvider(t)
+
+		payment, err := provider.CreatePayment(domainPayment.GatewayPaymentRequest{OrderID: "order123", PriceAmount: 100, PriceCurrency: "USD", PayCurrency: "BTC", FeeSurcharge: 0.5})
+
+		assert.NoError(t, err)
+		assert.InDelta(t, 0.00201, payment.PayAmount, 1e-12)
+	})
+
+	t.Run("coin without a deposit address", func(t *testing.T) {
+		provider := newTestProvider(t)
+
+		_, err := provider.CreatePayment(domainPayment.GatewayPaymentRequest{OrderID: "order123", PriceAmount: 100, PriceCurrency: "USD", PayCurrency: "ETH"})
+
+		assert.Equal(t, domainPayment.ErrUnsupportedCrypto, err)
+	})
+
+	t.Run("gateway operations are not supported", func(t *testing.T) {
+		provider := newTestProvider(t)
+
+		_, errStatus := provider.GetPaymentStatus("MT-1")
+		_, errRefund := provider.RefundPayment(domainPayment.GatewayRefundRequest{GatewayPaymentID: "MT-1"})
+		_, errWebhook := provider.VerifyWebhook([]byte(`{}`), "sig")
+
+		assert.Equal(t, ProviderName, provider.Name())
+		assert.Equal(t, domainPayment.ErrProviderNotSupported, errStatus)
+		assert.Equal(t, domainPayment.ErrProviderNotSupported, errRefund)
+		assert.Equal(t, domainPayment.ErrProviderNotSupported, errWebhook)
+	})
+
+	t.Run("deposit address must fit its coin", func(t *testing.T) {
+		_, err := NewProvider(Config{DepositAddresses: map[string]string{"BTC": "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"}}, nil)
+
+		assert.Equal(t, domainPayment.ErrInvalidWalletAddress, err)
+	})
+}
@@ -0,0 +1,93 @@
+package nowpayments
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// ProviderName is the payment provider name of payments opened at NowPayments
+const ProviderName = "nowpayments"
+
+// Client calls the NowPayments REST API
+type Client struct {
+	config     Config
+	httpClient *http.Client
+}
+
+// NewClient creates a new instance of Client
+func NewClient(config Config, timeout time.Duration) *Client {
+	return &Client{
+		config:     config,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// get performs an authenticated GET request and decodes the JSON body into out
+func (c *Client) get(path string, query url.Values, out interface{}) error {
+	endpoint := c.config.baseURL() + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.do(req, http.StatusOK, out)
+}
+
+// post performs an authenticated POST request with a JSON body and decodes
+// the JSON response into out
+func (c *Client) post(path string, in interface{}, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.config.baseURL()+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.do(req, http.StatusCreated, out)
+}
+
+// do sends a request with the API credentials and decodes the JSON body into out
+func (c *Client) do(req *http.Request, expectedStatus int, out interface{}) error {
+	req.Header.Set("x-api-key", c.config.APIKey)
+	if c.config.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.AuthToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if isTimeout(err) {
+			return domainPayment.ErrPaymentServiceTimeout
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatus && resp.StatusCode != http.StatusOK {
+		return domainPayment.ErrProviderAPIError
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return domainPayment.ErrProviderAPIError
+	}
+
+	return nil
+}
+
+// isTimeout checks if an HTTP error was caused by the client timeout
+func isTimeout(err error) bool {
+	urlErr, ok := err.(*url.Error)
+	return ok && urlErr.Timeout()
+}
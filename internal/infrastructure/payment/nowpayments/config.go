@@ -0,0 +1,41 @@
+package nowpayments
+
+import (
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+const (
+	// ProductionBaseURL is the NowPayments API base URL
+	ProductionBaseURL = "https://api.nowpayments.io"
+
+	// SandboxBaseURL is the NowPayments sandbox API base URL
+	SandboxBaseURL = "https://api-sandbox.nowpayments.io"
+)
+
+// Config holds the NowPayments API settings
+type Config struct {
+	APIKey    string
+	AuthToken string // Bearer token (POST /v1/auth), needed for payment listings and payouts
+	IPNURL    string // Webhook URL
+	IPNSecret string // Key of the IPN callback signatures
+	BaseURL   string // Overrides the production/sandbox URL when set
+	Sandbox   bool   // For testing
+}
+
+// ForEnvironment returns the configuration for a payment environment: the
+// sandbox environment uses the sandbox API
+func (c Config) ForEnvironment(environment domainPayment.Environment) Config {
+	c.Sandbox = environment.IsSandbox()
+	return c
+}
+
+// baseURL returns the API base URL for the configuration
+func (c Config) baseURL() string {
+	if c.BaseURL != "" {
+		return c.BaseURL
+	}
+	if c.Sandbox {
+		return SandboxBaseURL
+	}
+	return ProductionBaseURL
+}
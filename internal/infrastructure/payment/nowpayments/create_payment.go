@@ -0,0 +1,54 @@
+package nowpayments
+
+import (
+	"encoding/json"
+	"strings"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// createPaymentRequest is the body of POST /v1/payment
+type createPaymentRequest struct {
+	PriceAmount     float64 `json:"price_amount"`
+	PriceCurrency   string  `json:"price_currency"`
+	PayCurrency     string  `json:"pay_currency"`
+	OrderID         string  `json:"order_id"`
+	IPNCallbackURL  string  `json:"ipn_callback_url,omitempty"`
+	IsFeePaidByUser bool    `json:"is_fee_paid_by_user,omitempty"`
+}
+
+// createPaymentResponse is the body returned by POST /v1/payment
+type createPaymentResponse struct {
+	PaymentID  json.Number `json:"payment_id"`
+	PayAddress string      `json:"pay_address"`
+	PayAmount  json.Number `json:"pay_amount"`
+}
+
+// CreatePayment opens a payment at NowPayments and returns its deposit address.
+// A customer-borne fee is left to NowPayments, which adds its own fee to the
+// pay amount, so its status reports the amount the customer was asked for.
+func (c *Client) CreatePayment(request domainPayment.GatewayPaymentRequest) (domainPayment.GatewayPayment, error) {
+	var body createPaymentResponse
+	err := c.post("/v1/payment", createPaymentRequest{
+		PriceAmount:     request.PriceAmount,
+		PriceCurrency:   strings.ToLower(request.PriceCurrency),
+		PayCurrency:     strings.ToLower(request.PayCurrency),
+		OrderID:         request.OrderID,
+		IPNCallbackURL:  c.config.IPNURL,
+		IsFeePaidByUser: request.FeeSurcharge > 0,
+	}, &body)
+	if err != nil {
+		return domainPayment.GatewayPayment{}, err
+	}
+
+	payAmount, err := body.PayAmount.Float64()
+	if err != nil || payAmount <= 0 || body.PayAddress == "" || body.PaymentID == "" {
+		return domainPayment.GatewayPayment{}, domainPayment.ErrProviderAPIError
+	}
+
+	return domainPayment.GatewayPayment{
+		GatewayPaymentID: body.PaymentID.String(),
+		PayAddress:       body.PayAddress,
+		PayAmount:        payAmount,
+	}, nil
+}
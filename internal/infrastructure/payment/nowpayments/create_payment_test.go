@@ -0,0 +1,85 @@
+package nowpayments
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePayment(t *testing.T) {
+	request := domainPayment.GatewayPaymentRequest{OrderID: "order123", PriceAmount: 100, PriceCurrency: "USD", PayCurrency: "BTC"}
+
+	t.Run("payment is opened", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/v1/payment", r.URL.Path)
+			assert.Equal(t, "test-key", r.Header.Get("x-api-key"))
+
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			assert.Equal(t, map[string]interface{}{
+				"price_amount":     100.0,
+				"price_currency":   "usd",
+				"pay_currency":     "btc",
+				"order_id":         "order123",
+				"ipn_callback_url": "https://shop.example/api/v1/webhooks/nowpayments",
+			}, body)
+
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"payment_id":"5524759814","payment_status":"waiting","pay_address":"bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh","pay_amount":0.00152}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(Config{APIKey: "test-key", IPNURL: "https://shop.example/api/v1/webhooks/nowpayments", BaseURL: server.URL}, time.Second)
+
+		payment, err := client.CreatePayment(request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domainPayment.GatewayPayment{
+			GatewayPaymentID: "5524759814",
+			PayAddress:       "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh",
+			PayAmount:        0.00152,
+		}, payment)
+	})
+
+	t.Run("customer-borne fee is left to NowPayments", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			assert.Equal(t, 100.0, body["price_amount"])
+			assert.Equal(t, true, body["is_fee_paid_by_user"])
+
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"payment_id":"5524759814","pay_address":"bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh","pay_amount":0.00153}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(Config{BaseURL: server.URL}, time.Second)
+		withFee := request
+		withFee.FeeSurcharge = 0.5
+
+		payment, err := client.CreatePayment(withFee)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0.00153, payment.PayAmount)
+	})
+
+	t.Run("incomplete response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"payment_id":"5524759814","pay_amount":0.00152}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(Config{BaseURL: server.URL}, time.Second)
+
+		_, err := client.CreatePayment(request)
+
+		assert.Equal(t, domainPayment.ErrProviderAPIError, err)
+	})
+}
@@ -0,0 +1,16 @@
+package nowpayments
+
+// currenciesResponse is the body of GET /v1/currencies
+type currenciesResponse struct {
+	Currencies []string `json:"currencies"`
+}
+
+// ListAvailableCurrencies returns the tickers NowPayments currently accepts (lower case)
+func (c *Client) ListAvailableCurrencies() ([]string, error) {
+	var body currenciesResponse
+	if err := c.get("/v1/currencies", nil, &body); err != nil {
+		return nil, err
+	}
+
+	return body.Currencies, nil
+}
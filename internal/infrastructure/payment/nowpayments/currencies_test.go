@@ -0,0 +1,26 @@
+package nowpayments
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListAvailableCurrencies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/currencies", r.URL.Path)
+		assert.Equal(t, "test-key", r.Header.Get("x-api-key"))
+		_, _ = w.Write([]byte(`{"currencies":["btc","eth","usdttrc20"]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{APIKey: "test-key", BaseURL: server.URL}, time.Second)
+
+	currencies, err := client.ListAvailableCurrencies()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"btc", "eth", "usdttrc20"}, currencies)
+}
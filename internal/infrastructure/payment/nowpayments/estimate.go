@@ -0,0 +1,48 @@
+package nowpayments
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// estimateResponse is the body of GET /v1/estimate.
+// estimated_amount is sent as a number or a numeric string depending on the currency.
+type estimateResponse struct {
+	CurrencyFrom    string      `json:"currency_from"`
+	CurrencyTo      string      `json:"currency_to"`
+	EstimatedAmount json.Number `json:"estimated_amount"`
+}
+
+// Name identifies the rate source recorded on quotes, and the provider of
+// the payments opened through the client
+func (c *Client) Name() string {
+	return ProviderName
+}
+
+// EstimateCryptoAmount returns how much of cryptoSymbol the fiat amount buys
+func (c *Client) EstimateCryptoAmount(fiatAmount float64, fiatCurrency string, cryptoSymbol string) (float64, error) {
+	query := url.Values{}
+	query.Set("amount", strconv.FormatFloat(fiatAmount, 'f', -1, 64))
+	query.Set("currency_from", strings.ToLower(fiatCurrency))
+	query.Set("currency_to", strings.ToLower(cryptoSymbol))
+
+	var body estimateResponse
+	if err := c.get("/v1/estimate", query, &body); err != nil {
+		return 0, err
+	}
+
+	if !strings.EqualFold(body.CurrencyTo, cryptoSymbol) {
+		return 0, domainPayment.ErrProviderAPIError
+	}
+
+	amount, err := body.EstimatedAmount.Float64()
+	if err != nil || amount <= 0 {
+		return 0, domainPayment.ErrRateUnavailable
+	}
+
+	return amount, nil
+}
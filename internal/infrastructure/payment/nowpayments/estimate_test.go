@@ -0,0 +1,65 @@
+package nowpayments
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimate(t *testing.T) {
+	t.Run("estimate returns crypto amount", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/estimate", r.URL.Path)
+			assert.Equal(t, "test-key", r.Header.Get("x-api-key"))
+			assert.Equal(t, "100", r.URL.Query().Get("amount"))
+			assert.Equal(t, "usd", r.URL.Query().Get("currency_from"))
+			assert.Equal(t, "btc", r.URL.Query().Get("currency_to"))
+			_, _ = w.Write([]byte(`{"currency_from":"usd","amount_from":100,"currency_to":"btc","estimated_amount":"0.00152"}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(Config{APIKey: "test-key", BaseURL: server.URL}, time.Second)
+
+		amount, err := client.EstimateCryptoAmount(100.0, "USD", "BTC")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0.00152, amount)
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		client := NewClient(Config{BaseURL: server.URL}, time.Second)
+
+		_, err := client.EstimateCryptoAmount(100.0, "USD", "BTC")
+
+		assert.Equal(t, domainPayment.ErrProviderAPIError, err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(50 * time.Millisecond)
+		}))
+		defer server.Close()
+
+		client := NewClient(Config{BaseURL: server.URL}, 10*time.Millisecond)
+
+		_, err := client.EstimateCryptoAmount(100.0, "USD", "BTC")
+
+		assert.Equal(t, domainPayment.ErrPaymentServiceTimeout, err)
+	})
+
+	t.Run("sandbox base url", func(t *testing.T) {
+		assert.Equal(t, SandboxBaseURL, Config{Sandbox: true}.baseURL())
+		assert.Equal(t, ProductionBaseURL, Config{}.baseURL())
+		assert.Equal(t, SandboxBaseURL, Config{}.ForEnvironment(domainPayment.EnvironmentSandbox).baseURL())
+		assert.Equal(t, ProductionBaseURL, Config{Sandbox: true}.ForEnvironment(domainPayment.EnvironmentLive).baseURL())
+	})
+}
@@ -0,0 +1,87 @@
+package nowpayments
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// csvColumns are the columns read from an exported payment listing. Headers
+// are matched case-insensitively, with spaces read as underscores
+// ("Payment ID" or "payment_id").
+var csvColumns = []string{"payment_id", "order_id", "payment_status", "price_amount", "price_currency", "pay_amount", "pay_currency", "actually_paid", "created_at"}
+
+// CSVPaymentListing is a payment listing imported from a CSV export of the
+// NowPayments dashboard, used instead of the API for settlement
+type CSVPaymentListing struct {
+	records []domainPayment.GatewayPaymentRecord
+}
+
+// NewCSVPaymentListing reads every payment of a CSV export
+func NewCSVPaymentListing(r io.Reader) (*CSVPaymentListing, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, domainPayment.ErrInvalidSettlementRecord
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")] = i
+	}
+	for _, column := range csvColumns {
+		if _, ok := index[column]; !ok {
+			return nil, domainPayment.ErrInvalidSettlementRecord
+		}
+	}
+
+	listing := &CSVPaymentListing{}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return listing, nil
+		}
+		if err != nil {
+			return nil, domainPayment.ErrInvalidSettlementRecord
+		}
+
+		field := func(column string) string {
+			return strings.TrimSpace(row[index[column]])
+		}
+		item := paymentListItem{
+			PaymentID:     json.Number(field("payment_id")),
+			OrderID:       field("order_id"),
+			PaymentStatus: strings.ToLower(field("payment_status")),
+			PriceAmount:   json.Number(field("price_amount")),
+			PriceCurrency: field("price_currency"),
+			PayAmount:     json.Number(field("pay_amount")),
+			PayCurrency:   field("pay_currency"),
+			ActuallyPaid:  json.Number(field("actually_paid")),
+			CreatedAt:     field("created_at"),
+		}
+
+		record, err := item.toRecord()
+		if err != nil {
+			return nil, err
+		}
+		listing.records = append(listing.records, record)
+	}
+}
+
+// ListPayments returns the imported payments created in [from, to)
+func (l *CSVPaymentListing) ListPayments(from, to time.Time) ([]domainPayment.GatewayPaymentRecord, error) {
+	var records []domainPayment.GatewayPaymentRecord
+	for _, record := range l.records {
+		if !record.CreatedAt.Before(from) && record.CreatedAt.Before(to) {
+			records = append(records, record)
+		}
+	}
+
+	return records, nil
+}
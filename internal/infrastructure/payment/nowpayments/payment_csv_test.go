@@ -0,0 +1,48 @@
+package nowpayments
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVPaymentListing(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	t.Run("export is imported and filtered to the period", func(t *testing.T) {
+		export := `Payment ID,Order ID,Payment Status,Price Amount,Price Currency,Pay Amount,Pay Currency,Actually Paid,Created At
+5524759814,order123,Finished,100,usd,0.002,btc,0.002,2024-03-01T10:00:00Z
+5524759815,order124,expired,20.5,usd,0.0004,btc,0,2024-02-29T23:59:00Z
+`
+
+		listing, err := NewCSVPaymentListing(strings.NewReader(export))
+		assert.NoError(t, err)
+
+		records, err := listing.ListPayments(from, to)
+
+		assert.NoError(t, err)
+		assert.Len(t, records, 1)
+		assert.Equal(t, "5524759814", records[0].GatewayPaymentID)
+		assert.Equal(t, domainPayment.GatewayStatusFinished, records[0].Status)
+		assert.Equal(t, 100.0, records[0].PriceAmount)
+		assert.Equal(t, "USD", records[0].PriceCurrency)
+	})
+
+	t.Run("missing column", func(t *testing.T) {
+		_, err := NewCSVPaymentListing(strings.NewReader("payment_id,payment_status\n1,finished\n"))
+
+		assert.Equal(t, domainPayment.ErrInvalidSettlementRecord, err)
+	})
+
+	t.Run("invalid amount", func(t *testing.T) {
+		export := "payment_id,order_id,payment_status,price_amount,price_currency,pay_amount,pay_currency,actually_paid,created_at\n1,o,finished,abc,usd,0,btc,0,2024-03-01T10:00:00Z\n"
+
+		_, err := NewCSVPaymentListing(strings.NewReader(export))
+
+		assert.Equal(t, domainPayment.ErrInvalidSettlementRecord, err)
+	})
+}
@@ -0,0 +1,101 @@
+package nowpayments
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// paymentListPageSize is the largest page GET /v1/payment/ returns
+const paymentListPageSize = 500
+
+// paymentListResponse is the body of GET /v1/payment/
+type paymentListResponse struct {
+	Data       []paymentListItem `json:"data"`
+	PagesCount int               `json:"pagesCount"`
+}
+
+// paymentListItem is one payment of a listing
+type paymentListItem struct {
+	PaymentID     json.Number `json:"payment_id"`
+	OrderID       string      `json:"order_id"`
+	PaymentStatus string      `json:"payment_status"`
+	PriceAmount   json.Number `json:"price_amount"`
+	PriceCurrency string      `json:"price_currency"`
+	PayAmount     json.Number `json:"pay_amount"`
+	PayCurrency   string      `json:"pay_currency"`
+	ActuallyPaid  json.Number `json:"actually_paid"`
+	CreatedAt     string      `json:"created_at"`
+}
+
+// ListPayments returns the payments created in [from, to). The endpoint
+// needs Config.AuthToken.
+func (c *Client) ListPayments(from, to time.Time) ([]domainPayment.GatewayPaymentRecord, error) {
+	var records []domainPayment.GatewayPaymentRecord
+
+	for page := 0; ; page++ {
+		query := url.Values{}
+		query.Set("limit", strconv.Itoa(paymentListPageSize))
+		query.Set("page", strconv.Itoa(page))
+		query.Set("sortBy", "created_at")
+		query.Set("orderBy", "asc")
+		query.Set("dateFrom", from.UTC().Format("2006-01-02"))
+		query.Set("dateTo", to.UTC().Format("2006-01-02"))
+
+		var body paymentListResponse
+		if err := c.get("/v1/payment/", query, &body); err != nil {
+			return nil, err
+		}
+
+		for _, item := range body.Data {
+			record, err := item.toRecord()
+			if err != nil {
+				return nil, err
+			}
+			// The API filters by day only
+			if !record.CreatedAt.Before(from) && record.CreatedAt.Before(to) {
+				records = append(records, record)
+			}
+		}
+
+		if page+1 >= body.PagesCount || len(body.Data) == 0 {
+			return records, nil
+		}
+	}
+}
+
+// toRecord maps a listed payment to a settlement record
+func (item paymentListItem) toRecord() (domainPayment.GatewayPaymentRecord, error) {
+	status := domainPayment.GatewayStatus(item.PaymentStatus)
+	if !status.IsValid() {
+		return domainPayment.GatewayPaymentRecord{}, domainPayment.ErrUnknownGatewayStatus
+	}
+
+	createdAt, err := time.Parse(time.RFC3339, item.CreatedAt)
+	if err != nil {
+		return domainPayment.GatewayPaymentRecord{}, domainPayment.ErrInvalidSettlementRecord
+	}
+
+	priceAmount, err := item.PriceAmount.Float64()
+	if err != nil {
+		return domainPayment.GatewayPaymentRecord{}, domainPayment.ErrInvalidSettlementRecord
+	}
+	payAmount, _ := item.PayAmount.Float64()
+	actuallyPaid, _ := item.ActuallyPaid.Float64()
+
+	return domainPayment.GatewayPaymentRecord{
+		GatewayPaymentID: item.PaymentID.String(),
+		OrderID:          item.OrderID,
+		Status:           status,
+		PriceAmount:      priceAmount,
+		PriceCurrency:    strings.ToUpper(item.PriceCurrency),
+		PayAmount:        payAmount,
+		PayCurrency:      strings.ToUpper(item.PayCurrency),
+		ActuallyPaid:     actuallyPaid,
+		CreatedAt:        createdAt,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package nowpayments
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListPayments(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	t.Run("pages are read and filtered to the period", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/payment/", r.URL.Path)
+			assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+			assert.Equal(t, "2024-03-01", r.URL.Query().Get("dateFrom"))
+			assert.Equal(t, "2024-03-02", r.URL.Query().Get("dateTo"))
+
+			createdAt := "2024-03-01T10:00:00.000Z"
+			if r.URL.Query().Get("page") == "1" {
+				createdAt = "2024-03-02T00:30:00.000Z"
+			}
+			_, _ = fmt.Fprintf(w, `{"data":[{"payment_id":55247598%s0,"order_id":"order123","payment_status":"finished","price_amount":100,"price_currency":"usd","pay_amount":0.002,"pay_currency":"btc","actually_paid":0.002,"created_at":%q}],"limit":500,"page":%s,"pagesCount":2,"total":2}`,
+				r.URL.Query().Get("page"), createdAt, r.URL.Query().Get("page"))
+		}))
+		defer server.Close()
+
+		client := NewClient(Config{APIKey: "test-key", AuthToken: "test-token", BaseURL: server.URL}, time.Second)
+
+		records, err := client.ListPayments(from, to)
+
+		assert.NoError(t, err)
+		assert.Len(t, records, 1)
+		assert.Equal(t, domainPayment.GatewayPaymentRecord{
+			GatewayPaymentID: "5524759800",
+			OrderID:          "order123",
+			Status:           domainPayment.GatewayStatusFinished,
+			PriceAmount:      100,
+			PriceCurrency:    "USD",
+			PayAmount:        0.002,
+			PayCurrency:      "BTC",
+			ActuallyPaid:     0.002,
+			CreatedAt:        time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+		}, records[0])
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		client := NewClient(Config{BaseURL: server.URL}, time.Second)
+
+		_, err := client.ListPayments(from, to)
+
+		assert.Equal(t, domainPayment.ErrProviderAPIError, err)
+	})
+}
@@ -0,0 +1,74 @@
+package nowpayments
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// paymentStatusResponse is the body of GET /v1/payment/{id} and of IPN callbacks
+type paymentStatusResponse struct {
+	PaymentID     json.Number `json:"payment_id"`
+	PaymentStatus string      `json:"payment_status"`
+	PayCurrency   string      `json:"pay_currency"`
+	ActuallyPaid  json.Number `json:"actually_paid"`
+	PayinHash     string      `json:"payin_hash"`
+	Fee           paymentFee  `json:"fee"`
+}
+
+// paymentFee is the fee breakdown NowPayments reports once it forwards the funds.
+// The same object is sent in IPN callbacks.
+type paymentFee struct {
+	Currency      string      `json:"currency"`
+	DepositFee    json.Number `json:"depositFee"`
+	WithdrawalFee json.Number `json:"withdrawalFee"`
+	ServiceFee    json.Number `json:"serviceFee"`
+}
+
+// amounts returns the service fee and the network fees (deposit and
+// withdrawal). Fees charged in another currency than the one paid are left
+// out, as a payment's fees are kept in its cryptocurrency.
+func (f paymentFee) amounts(payCurrency string) (serviceFee float64, networkFee float64) {
+	if !strings.EqualFold(f.Currency, payCurrency) {
+		return 0, 0
+	}
+
+	serviceFee, _ = f.ServiceFee.Float64()
+	depositFee, _ := f.DepositFee.Float64()
+	withdrawalFee, _ := f.WithdrawalFee.Float64()
+
+	return serviceFee, depositFee + withdrawalFee
+}
+
+// GetPaymentStatus returns the state of a payment as NowPayments sees it.
+// NowPayments does not report a confirmation count.
+func (c *Client) GetPaymentStatus(gatewayPaymentID string) (domainPayment.GatewayPaymentStatus, error) {
+	var body paymentStatusResponse
+	if err := c.get("/v1/payment/"+url.PathEscape(gatewayPaymentID), nil, &body); err != nil {
+		return domainPayment.GatewayPaymentStatus{}, err
+	}
+
+	return body.toGatewayPaymentStatus()
+}
+
+// toGatewayPaymentStatus maps a payment state, fetched or sent in an IPN callback
+func (body paymentStatusResponse) toGatewayPaymentStatus() (domainPayment.GatewayPaymentStatus, error) {
+	status := domainPayment.GatewayStatus(body.PaymentStatus)
+	if !status.IsValid() {
+		return domainPayment.GatewayPaymentStatus{}, domainPayment.ErrUnknownGatewayStatus
+	}
+
+	actuallyPaid, _ := body.ActuallyPaid.Float64()
+	serviceFee, networkFee := body.Fee.amounts(body.PayCurrency)
+
+	return domainPayment.GatewayPaymentStatus{
+		GatewayPaymentID: body.PaymentID.String(),
+		Status:           status,
+		TransactionHash:  body.PayinHash,
+		ActuallyPaid:     actuallyPaid,
+		GatewayFee:       serviceFee,
+		NetworkFee:       networkFee,
+	}, nil
+}
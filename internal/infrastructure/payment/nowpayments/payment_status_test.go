@@ -0,0 +1,77 @@
+package nowpayments
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPaymentStatus(t *testing.T) {
+	t.Run("payment status is mapped", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/payment/5524759814", r.URL.Path)
+			assert.Equal(t, "test-key", r.Header.Get("x-api-key"))
+			_, _ = w.Write([]byte(`{"payment_id":5524759814,"payment_status":"confirming","pay_currency":"btc","actually_paid":0.00152,"payin_hash":"abc123"}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(Config{APIKey: "test-key", BaseURL: server.URL}, time.Second)
+
+		status, err := client.GetPaymentStatus("5524759814")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "5524759814", status.GatewayPaymentID)
+		assert.Equal(t, domainPayment.GatewayStatusConfirming, status.Status)
+		assert.Equal(t, "abc123", status.TransactionHash)
+		assert.Equal(t, 0.00152, status.ActuallyPaid)
+	})
+
+	t.Run("fees in the paid currency are reported", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"payment_id":5524759814,"payment_status":"finished","pay_currency":"btc","actually_paid":0.002,` +
+				`"fee":{"currency":"BTC","depositFee":0.000003,"withdrawalFee":0.000002,"serviceFee":0.00001}}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(Config{BaseURL: server.URL}, time.Second)
+
+		status, err := client.GetPaymentStatus("5524759814")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0.00001, status.GatewayFee)
+		assert.InDelta(t, 0.000005, status.NetworkFee, 1e-12)
+	})
+
+	t.Run("fees in another currency are ignored", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"payment_id":1,"payment_status":"finished","pay_currency":"btc","actually_paid":0.002,` +
+				`"fee":{"currency":"usdttrc20","depositFee":0,"withdrawalFee":1,"serviceFee":0.5}}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(Config{BaseURL: server.URL}, time.Second)
+
+		status, err := client.GetPaymentStatus("1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0.0, status.GatewayFee)
+		assert.Equal(t, 0.0, status.NetworkFee)
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"payment_id":1,"payment_status":"wrong_asset_confirmed","actually_paid":0}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(Config{BaseURL: server.URL}, time.Second)
+
+		_, err := client.GetPaymentStatus("1")
+
+		assert.Equal(t, domainPayment.ErrUnknownGatewayStatus, err)
+	})
+}
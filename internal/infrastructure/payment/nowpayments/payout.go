@@ -0,0 +1,62 @@
+package nowpayments
+
+import (
+	"encoding/json"
+	"strings"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// payoutRequest is the body of POST /v1/payout
+type payoutRequest struct {
+	Withdrawals []payoutWithdrawal `json:"withdrawals"`
+}
+
+// payoutWithdrawal is one transfer of a payout
+type payoutWithdrawal struct {
+	Address        string  `json:"address"`
+	Currency       string  `json:"currency"`
+	Amount         float64 `json:"amount"`
+	IPNCallbackURL string  `json:"ipn_callback_url,omitempty"`
+}
+
+// payoutResponse is the body returned by POST /v1/payout
+type payoutResponse struct {
+	ID          json.Number `json:"id"`
+	Withdrawals []struct {
+		ID   json.Number `json:"id"`
+		Hash string      `json:"hash"`
+	} `json:"withdrawals"`
+}
+
+// RefundPayment sends a refund from the NowPayments balance as a payout. The
+// payout API needs the Bearer token, and NowPayments only sends the payout
+// once it is verified with the account's 2FA code, so the transaction hash
+// is recorded later.
+func (c *Client) RefundPayment(request domainPayment.GatewayRefundRequest) (domainPayment.GatewayRefund, error) {
+	if c.config.AuthToken == "" {
+		return domainPayment.GatewayRefund{}, domainPayment.ErrProviderNotSupported
+	}
+
+	var body payoutResponse
+	err := c.post("/v1/payout", payoutRequest{
+		Withdrawals: []payoutWithdrawal{{
+			Address:        request.Address,
+			Currency:       strings.ToLower(request.Currency),
+			Amount:         request.Amount,
+			IPNCallbackURL: c.config.IPNURL,
+		}},
+	}, &body)
+	if err != nil {
+		return domainPayment.GatewayRefund{}, err
+	}
+
+	if len(body.Withdrawals) != 1 || body.Withdrawals[0].ID == "" {
+		return domainPayment.GatewayRefund{}, domainPayment.ErrProviderAPIError
+	}
+
+	return domainPayment.GatewayRefund{
+		GatewayRefundID: body.Withdrawals[0].ID.String(),
+		TransactionHash: body.Withdrawals[0].Hash,
+	}, nil
+}
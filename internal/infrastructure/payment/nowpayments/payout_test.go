@@ -0,0 +1,57 @@
+package nowpayments
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefundPayment(t *testing.T) {
+	request := domainPayment.GatewayRefundRequest{
+		GatewayPaymentID: "5524759814",
+		Address:          "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq",
+		Currency:         "BTC",
+		Amount:           0.001,
+	}
+
+	t.Run("refund is sent as a payout", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/v1/payout", r.URL.Path)
+			assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			assert.Equal(t, map[string]interface{}{
+				"withdrawals": []interface{}{map[string]interface{}{
+					"address":  "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq",
+					"currency": "btc",
+					"amount":   0.001,
+				}},
+			}, body)
+
+			_, _ = w.Write([]byte(`{"id":"5000000713","withdrawals":[{"id":"5000000000","status":"WAITING","hash":null}]}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(Config{APIKey: "test-key", AuthToken: "test-token", BaseURL: server.URL}, time.Second)
+
+		refund, err := client.RefundPayment(request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domainPayment.GatewayRefund{GatewayRefundID: "5000000000"}, refund)
+	})
+
+	t.Run("payouts need the bearer token", func(t *testing.T) {
+		client := NewClient(Config{APIKey: "test-key"}, time.Second)
+
+		_, err := client.RefundPayment(request)
+
+		assert.Equal(t, domainPayment.ErrProviderNotSupported, err)
+	})
+}
@@ -0,0 +1,65 @@
+package nowpayments
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// SignatureHeader is the HTTP header carrying the IPN callback signature
+const SignatureHeader = "x-nowpayments-sig"
+
+// VerifyWebhook checks the signature of an IPN callback and returns the
+// payment state it reports. NowPayments signs the JSON body, re-encoded with
+// its keys sorted, with HMAC-SHA512 keyed by the IPN secret.
+func (c *Client) VerifyWebhook(payload []byte, signature string) (domainPayment.GatewayPaymentStatus, error) {
+	if c.config.IPNSecret == "" || signature == "" {
+		return domainPayment.GatewayPaymentStatus{}, domainPayment.ErrWebhookValidationFailed
+	}
+
+	signed, err := sortedJSON(payload)
+	if err != nil {
+		return domainPayment.GatewayPaymentStatus{}, domainPayment.ErrInvalidWebhookPayload
+	}
+
+	mac := hmac.New(sha512.New, []byte(c.config.IPNSecret))
+	mac.Write(signed)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return domainPayment.GatewayPaymentStatus{}, domainPayment.ErrWebhookValidationFailed
+	}
+
+	var body paymentStatusResponse
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return domainPayment.GatewayPaymentStatus{}, domainPayment.ErrInvalidWebhookPayload
+	}
+
+	return body.toGatewayPaymentStatus()
+}
+
+// sortedJSON re-encodes a JSON object with its keys sorted at every level,
+// keeping numbers as they were sent
+func sortedJSON(payload []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+
+	var body map[string]interface{}
+	if err := decoder.Decode(&body); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(body); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
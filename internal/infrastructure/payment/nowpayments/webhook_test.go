@@ -0,0 +1,57 @@
+package nowpayments
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+// sign signs a JSON body with sorted keys the way NowPayments does
+func sign(sortedBody string, secret string) string {
+	mac := hmac.New(sha512.New, []byte(secret))
+	mac.Write([]byte(sortedBody))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	payload := []byte(`{"payment_status":"finished","payment_id":5077125051,"pay_currency":"btc","actually_paid":0.002,` +
+		`"payin_hash":"abc123","fee":{"serviceFee":0.00001,"currency":"btc","depositFee":0,"withdrawalFee":0.000002}}`)
+	sorted := `{"actually_paid":0.002,"fee":{"currency":"btc","depositFee":0,"serviceFee":0.00001,"withdrawalFee":0.000002},` +
+		`"pay_currency":"btc","payin_hash":"abc123","payment_id":5077125051,"payment_status":"finished"}`
+	client := NewClient(Config{IPNSecret: "ipn-secret"}, time.Second)
+
+	t.Run("signed callback is mapped", func(t *testing.T) {
+		status, err := client.VerifyWebhook(payload, sign(sorted, "ipn-secret"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, domainPayment.GatewayPaymentStatus{
+			GatewayPaymentID: "5077125051",
+			Status:           domainPayment.GatewayStatusFinished,
+			TransactionHash:  "abc123",
+			ActuallyPaid:     0.002,
+			GatewayFee:       0.00001,
+			NetworkFee:       0.000002,
+		}, status)
+	})
+
+	t.Run("forged or missing signature", func(t *testing.T) {
+		_, errForged := client.VerifyWebhook(payload, sign(sorted, "other-secret"))
+		_, errMissing := client.VerifyWebhook(payload, "")
+		_, errNoSecret := NewClient(Config{}, time.Second).VerifyWebhook(payload, sign(sorted, ""))
+
+		assert.Equal(t, domainPayment.ErrWebhookValidationFailed, errForged)
+		assert.Equal(t, domainPayment.ErrWebhookValidationFailed, errMissing)
+		assert.Equal(t, domainPayment.ErrWebhookValidationFailed, errNoSecret)
+	})
+
+	t.Run("malformed payload", func(t *testing.T) {
+		_, err := client.VerifyWebhook([]byte(`not json`), sign(sorted, "ipn-secret"))
+
+		assert.Equal(t, domainPayment.ErrInvalidWebhookPayload, err)
+	})
+}
@@ -0,0 +1,67 @@
+package selfcustody
+
+import (
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment/address"
+)
+
+// DeriveAddress derives the wallet's receive address at index
+// (m/purpose'/coin'/account'/0/index): P2PKH for BIP44 wallets, P2WPKH for
+// BIP84 wallets
+func DeriveAddress(wallet *domainPayment.HDWallet, index int) (string, error) {
+	if index < 0 || index >= hardenedOffset {
+		return "", domainPayment.ErrInvalidDerivationIndex
+	}
+
+	params, err := addressParams(wallet)
+	if err != nil {
+		return "", err
+	}
+
+	account, err := parseExtendedKey(wallet.ExtendedPublicKey)
+	if err != nil {
+		return "", err
+	}
+
+	receive, err := account.child(0)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := receive.child(uint32(index))
+	if err != nil {
+		return "", err
+	}
+
+	if wallet.Purpose == domainPayment.PurposeLegacy {
+		return address.EncodeP2PKH(key.hash160(), params)
+	}
+	return address.EncodeP2WPKH(key.hash160(), params)
+}
+
+// ValidateExtendedKey checks that addresses can be derived from the wallet's extended key
+func ValidateExtendedKey(wallet *domainPayment.HDWallet) error {
+	_, err := DeriveAddress(wallet, 0)
+	return err
+}
+
+// addressParams returns the address encodings of the wallet's chain
+func addressParams(wallet *domainPayment.HDWallet) (address.UTXOParams, error) {
+	crypto, err := domainPayment.GetCryptoCurrencyBySymbol(wallet.CryptoSymbol)
+	if err != nil {
+		return address.UTXOParams{}, err
+	}
+
+	switch {
+	case crypto.Network == domainPayment.NetworkBitcoin && crypto.Testnet:
+		return address.BitcoinTestnet, nil
+	case crypto.Network == domainPayment.NetworkBitcoin:
+		return address.BitcoinMainnet, nil
+	case crypto.Network == domainPayment.NetworkLitecoin && crypto.Testnet:
+		return address.LitecoinTestnet, nil
+	case crypto.Network == domainPayment.NetworkLitecoin:
+		return address.LitecoinMainnet, nil
+	default:
+		return address.UTXOParams{}, domainPayment.ErrHDWalletNotSupported
+	}
+}
@@ -0,0 +1,89 @@
+package selfcustody
+
+import (
+	"strings"
+	"testing"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Account keys of the BIP44/BIP84 test mnemonic "abandon abandon … about"
+const (
+	testXpub = "xpub6BosfCnifzxcFwrSzQiqu2DBVTshkCXacvNsWGYJVVhhawA7d4R5WSWGFNbi8Aw6ZRc1brxMyWMzG3DSSSSoekkudhUd9yLb6qx39T9nMdj"
+	testZpub = "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"
+)
+
+func TestDeriveAddress(t *testing.T) {
+	testCases := []struct {
+		name     string
+		symbol   string
+		key      string
+		purpose  domainPayment.AddressPurpose
+		index    int
+		expected string
+	}{
+		{"bip84 first address", "BTC", testZpub, domainPayment.PurposeNativeSegwit, 0, "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu"},
+		{"bip84 second address", "BTC", testZpub, domainPayment.PurposeNativeSegwit, 1, "bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g"},
+		{"bip44 first address", "BTC", testXpub, domainPayment.PurposeLegacy, 0, "1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabA"},
+		{"bip44 second address", "BTC", testXpub, domainPayment.PurposeLegacy, 1, "1Ak8PffB2meyfYnbXZR9EGfLfFZVpzJvQP"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			wallet, err := domainPayment.NewHDWallet(tc.symbol, tc.key, tc.purpose, 0, 0)
+			require.NoError(t, err)
+
+			derived, err := DeriveAddress(wallet, tc.index)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, derived)
+		})
+	}
+
+	t.Run("litecoin addresses", func(t *testing.T) {
+		segwit, _ := domainPayment.NewHDWallet("LTC", testZpub, domainPayment.PurposeNativeSegwit, 0, 0)
+		legacy, _ := domainPayment.NewHDWallet("LTC", testXpub, domainPayment.PurposeLegacy, 0, 0)
+
+		segwitAddress, errSegwit := DeriveAddress(segwit, 0)
+		legacyAddress, errLegacy := DeriveAddress(legacy, 0)
+
+		require.NoError(t, errSegwit)
+		require.NoError(t, errLegacy)
+		assert.NoError(t, domainPayment.NetworkLitecoin.ValidateAddress(segwitAddress, false))
+		assert.True(t, strings.HasPrefix(segwitAddress, "ltc1qcr8te4kr609gcawutmrza0j4xv80jy8z"), "same key hash as on Bitcoin")
+		assert.NoError(t, domainPayment.NetworkLitecoin.ValidateAddress(legacyAddress, false))
+		assert.Equal(t, byte('L'), legacyAddress[0])
+	})
+
+	t.Run("invalid extended key", func(t *testing.T) {
+		wallet, _ := domainPayment.NewHDWallet("BTC", testZpub[:len(testZpub)-1]+"t", domainPayment.PurposeNativeSegwit, 0, 0)
+
+		assert.Equal(t, domainPayment.ErrInvalidExtendedKey, ValidateExtendedKey(wallet))
+	})
+
+	t.Run("private key rejected", func(t *testing.T) {
+		xprv := "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi"
+		wallet, _ := domainPayment.NewHDWallet("BTC", xprv, domainPayment.PurposeNativeSegwit, 0, 0)
+
+		assert.Equal(t, domainPayment.ErrInvalidExtendedKey, ValidateExtendedKey(wallet))
+	})
+}
+
+func TestExtendedKeyChild(t *testing.T) {
+	// BIP32 test vector 1: m/0H -> m/0H/1
+	parent, err := parseExtendedKey("xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw")
+	require.NoError(t, err)
+	expected, err := parseExtendedKey("xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ")
+	require.NoError(t, err)
+
+	child, err := parent.child(1)
+
+	require.NoError(t, err)
+	assert.Equal(t, expected.publicKey.compress(), child.publicKey.compress())
+	assert.Equal(t, expected.chainCode, child.chainCode)
+
+	_, err = parent.child(hardenedOffset)
+	assert.Equal(t, domainPayment.ErrInvalidDerivationIndex, err)
+}
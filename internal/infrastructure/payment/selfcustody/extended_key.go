@@ -0,0 +1,91 @@
+package selfcustody
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"math/big"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment/address"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// publicKeyVersions are the version prefixes of the extended public keys
+// wallets export; the purpose is configured on the wallet, not read from them
+var publicKeyVersions = map[uint32]string{
+	0x0488b21e: "xpub",
+	0x04b24746: "zpub",
+	0x019da462: "Ltub",
+	0x01b26ef6: "Mtub",
+	0x043587cf: "tpub",
+	0x045f1cf6: "vpub",
+}
+
+// hardenedOffset is the first hardened BIP32 child index
+const hardenedOffset = 1 << 31
+
+// extendedKey is a BIP32 extended public key: a public key and the chain code
+// needed to derive its children
+type extendedKey struct {
+	publicKey point
+	chainCode []byte
+	depth     byte
+}
+
+// parseExtendedKey decodes a Base58Check extended public key
+func parseExtendedKey(s string) (*extendedKey, error) {
+	body, err := address.DecodeBase58Check(s)
+	if err != nil || len(body) != 78 {
+		return nil, domainPayment.ErrInvalidExtendedKey
+	}
+
+	if _, ok := publicKeyVersions[binary.BigEndian.Uint32(body[:4])]; !ok {
+		return nil, domainPayment.ErrInvalidExtendedKey
+	}
+
+	publicKey, ok := decompress(body[45:78])
+	if !ok {
+		return nil, domainPayment.ErrInvalidExtendedKey
+	}
+
+	return &extendedKey{publicKey: publicKey, chainCode: body[13:45], depth: body[4]}, nil
+}
+
+// child derives the non-hardened child key at index (BIP32 CKDpub)
+func (k *extendedKey) child(index uint32) (*extendedKey, error) {
+	if index >= hardenedOffset {
+		return nil, domainPayment.ErrInvalidDerivationIndex
+	}
+
+	data := make([]byte, 37)
+	copy(data, k.publicKey.compress())
+	binary.BigEndian.PutUint32(data[33:], index)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	// The index is unusable, with a chance below 1 in 2^127, when the tweak
+	// falls outside the curve order or the child is the point at infinity
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(curveN) >= 0 {
+		return nil, domainPayment.ErrInvalidDerivationIndex
+	}
+
+	publicKey := add(scalarBaseMult(tweak), k.publicKey)
+	if publicKey.isInfinity() {
+		return nil, domainPayment.ErrInvalidDerivationIndex
+	}
+
+	return &extendedKey{publicKey: publicKey, chainCode: sum[32:], depth: k.depth + 1}, nil
+}
+
+// hash160 returns RIPEMD-160(SHA-256(public key)), the hash addresses pay to
+func (k *extendedKey) hash160() []byte {
+	sha := sha256.Sum256(k.publicKey.compress())
+	hasher := ripemd160.New()
+	hasher.Write(sha[:])
+	return hasher.Sum(nil)
+}
@@ -0,0 +1,205 @@
+// Package selfcustody takes payments to addresses the merchant holds the keys
+// of, derived per payment from HD wallet extended public keys, instead of
+// through a payment gateway.
+package selfcustody
+
+import (
+	"strings"
+	"sync"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// ProviderName is the payment provider name of self-custody payments
+const ProviderName = "selfcustody"
+
+// WalletStore defines the interface for HD wallet and deposit address persistence
+type WalletStore interface {
+	FindWallet(cryptoSymbol string) (*domainPayment.HDWallet, error)
+	CreateWallet(wallet *domainPayment.HDWallet) error
+	UpdateWallet(wallet *domainPayment.HDWallet) error
+	// SaveDerivation stores the wallet's advanced index and the address derived
+	// from it in one transaction, so a crash cannot hand the index out again
+	SaveDerivation(wallet *domainPayment.HDWallet, deposit *domainPayment.DepositAddress) error
+}
+
+// RateEstimator estimates how much crypto a fiat amount buys
+type RateEstimator interface {
+	EstimateCryptoAmount(fiatAmount float64, fiatCurrency string, cryptoSymbol string) (float64, error)
+}
+
+// GapLimitAlert tells operators that more deposit addresses of a coin sit
+// unused than the merchant's wallet scans ahead
+type GapLimitAlert struct {
+	CryptoSymbol    string `json:"crypto_symbol"`
+	UnusedAddresses int    `json:"unused_addresses"`
+	GapLimit        int    `json:"gap_limit"`
+	DerivationPath  string `json:"derivation_path"` // Path of the address just handed out
+}
+
+// GapLimitAlerter notifies operators about wallets past their gap limit
+type GapLimitAlerter interface {
+	AlertGapLimitExceeded(alert GapLimitAlert) error
+}
+
+// Provider opens each payment on a fresh address of the merchant's HD wallet
+// for the coin. The payment's provider reference is its deposit address.
+// Transactions are learnt from a chain watcher (see NodeStub and FixtureFeed)
+// rather than from the provider, so it reports no status, sends no refunds
+// and has no webhooks.
+type Provider struct {
+	store   WalletStore
+	rates   RateEstimator
+	alerter GapLimitAlerter // nil disables gap limit alerts
+	mu      sync.Mutex      // Serialises index reservations within this process
+}
+
+// NewProvider creates a new instance of Provider
+func NewProvider(store WalletStore, rates RateEstimator) *Provider {
+	return &Provider{store: store, rates: rates}
+}
+
+// SetAlerter sets where alerts about wallets past their gap limit go
+func (p *Provider) SetAlerter(alerter GapLimitAlerter) {
+	p.alerter = alerter
+}
+
+// Name returns the payment provider name
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// AddWallet registers the merchant's HD wallet for a coin. Registering the
+// same key again is a no-op, so it can run at every start; the derivation
+// index of a registered wallet is kept.
+func (p *Provider) AddWallet(cryptoSymbol string, extendedPublicKey string, purpose domainPayment.AddressPurpose, account int, gapLimit int) error {
+	wallet, err := domainPayment.NewHDWallet(cryptoSymbol, extendedPublicKey, purpose, account, gapLimit)
+	if err != nil {
+		return err
+	}
+
+	if err := ValidateExtendedKey(wallet); err != nil {
+		return err
+	}
+
+	existing, err := p.store.FindWallet(wallet.CryptoSymbol)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		if existing.ExtendedPublicKey != wallet.ExtendedPublicKey || existing.Purpose != wallet.Purpose || existing.Account != wallet.Account {
+			return domainPayment.ErrExtendedKeyMismatch
+		}
+		return nil
+	}
+
+	return p.store.CreateWallet(wallet)
+}
+
+// SetGapLimit changes the gap limit of a registered wallet, after the
+// merchant raised it in their wallet to scan further ahead
+func (p *Provider) SetGapLimit(cryptoSymbol string, gapLimit int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	wallet, err := p.store.FindWallet(strings.ToUpper(cryptoSymbol))
+	if err != nil {
+		return err
+	}
+
+	if wallet == nil {
+		return domainPayment.ErrHDWalletNotFound
+	}
+
+	if err := wallet.SetGapLimit(gapLimit); err != nil {
+		return err
+	}
+
+	return p.store.UpdateWallet(wallet)
+}
+
+// CreatePayment derives the next unused address of the coin's wallet and
+// quotes the amount to send at the current rate, customer-borne fee included
+func (p *Provider) CreatePayment(request domainPayment.GatewayPaymentRequest) (domainPayment.GatewayPayment, error) {
+	payAmount, err := p.rates.EstimateCryptoAmount(request.PriceAmount+request.FeeSurcharge, request.PriceCurrency, request.PayCurrency)
+	if err != nil {
+		return domainPayment.GatewayPayment{}, err
+	}
+
+	deposit, err := p.deriveDepositAddress(strings.ToUpper(request.PayCurrency), request.OrderID)
+	if err != nil {
+		return domainPayment.GatewayPayment{}, err
+	}
+
+	return domainPayment.GatewayPayment{
+		GatewayPaymentID: deposit.Address,
+		PayAddress:       deposit.Address,
+		PayAmount:        payAmount,
+	}, nil
+}
+
+// deriveDepositAddress reserves the wallet's next index and stores the address
+// derived from it. Past the gap limit the address is still handed out, as
+// unpaid checkouts would otherwise block the coin for good, and operators are
+// alerted instead.
+func (p *Provider) deriveDepositAddress(cryptoSymbol string, orderID string) (*domainPayment.DepositAddress, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	wallet, err := p.store.FindWallet(cryptoSymbol)
+	if err != nil {
+		return nil, err
+	}
+
+	if wallet == nil {
+		return nil, domainPayment.ErrUnsupportedCrypto
+	}
+
+	index, err := wallet.ReserveIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	derived, err := DeriveAddress(wallet, index)
+	if err != nil {
+		return nil, err
+	}
+
+	deposit, err := domainPayment.NewDepositAddress(wallet, index, derived, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.store.SaveDerivation(wallet, deposit); err != nil {
+		return nil, err
+	}
+
+	if wallet.ExceedsGapLimit() && p.alerter != nil {
+		// An alert that cannot be sent must not fail the checkout
+		_ = p.alerter.AlertGapLimitExceeded(GapLimitAlert{
+			CryptoSymbol:    wallet.CryptoSymbol,
+			UnusedAddresses: wallet.UnusedAddresses(),
+			GapLimit:        wallet.GapLimit,
+			DerivationPath:  wallet.DerivationPath(index),
+		})
+	}
+
+	return deposit, nil
+}
+
+// GetPaymentStatus is not supported: the chain watcher reports transactions
+func (p *Provider) GetPaymentStatus(gatewayPaymentID string) (domainPayment.GatewayPaymentStatus, error) {
+	return domainPayment.GatewayPaymentStatus{}, domainPayment.ErrProviderNotSupported
+}
+
+// RefundPayment is not supported: the merchant signs refunds in their own
+// wallet and records them with their transaction hash
+func (p *Provider) RefundPayment(request domainPayment.GatewayRefundRequest) (domainPayment.GatewayRefund, error) {
+	return domainPayment.GatewayRefund{}, domainPayment.ErrProviderNotSupported
+}
+
+// VerifyWebhook is not supported: self-custody payments have no webhooks
+func (p *Provider) VerifyWebhook(payload []byte, signature string) (domainPayment.GatewayPaymentStatus, error) {
+	return domainPayment.GatewayPaymentStatus{}, domainPayment.ErrProviderNotSupported
+}
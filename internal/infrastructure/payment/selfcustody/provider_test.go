@@ -0,0 +1,169 @@
+package selfcustody
+
+import (
+	"errors"
+	"testing"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/infrastructure/payment/cryptorates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWalletStore keeps wallets and deposit addresses in memory
+type fakeWalletStore struct {
+	wallets   map[string]domainPayment.HDWallet
+	addresses map[string]domainPayment.DepositAddress
+	saveErr   error
+}
+
+func newFakeWalletStore() *fakeWalletStore {
+	return &fakeWalletStore{wallets: map[string]domainPayment.HDWallet{}, addresses: map[string]domainPayment.DepositAddress{}}
+}
+
+func (s *fakeWalletStore) FindWallet(cryptoSymbol string) (*domainPayment.HDWallet, error) {
+	wallet, ok := s.wallets[cryptoSymbol]
+	if !ok {
+		return nil, nil
+	}
+	return &wallet, nil
+}
+
+func (s *fakeWalletStore) CreateWallet(wallet *domainPayment.HDWallet) error {
+	s.wallets[wallet.CryptoSymbol] = *wallet
+	return nil
+}
+
+func (s *fakeWalletStore) UpdateWallet(wallet *domainPayment.HDWallet) error {
+	s.wallets[wallet.CryptoSymbol] = *wallet
+	return nil
+}
+
+func (s *fakeWalletStore) SaveDerivation(wallet *domainPayment.HDWallet, deposit *domainPayment.DepositAddress) error {
+	if s.saveErr != nil {
+		return s.saveErr
+	}
+	s.wallets[wallet.CryptoSymbol] = *wallet
+	s.addresses[deposit.Address] = *deposit
+	return nil
+}
+
+// fakeGapLimitAlerter records the alerts it is sent
+type fakeGapLimitAlerter struct {
+	alerts []GapLimitAlert
+}
+
+func (a *fakeGapLimitAlerter) AlertGapLimitExceeded(alert GapLimitAlert) error {
+	a.alerts = append(a.alerts, alert)
+	return errors.New("alerting unavailable")
+}
+
+// newTestProvider creates a provider with a BIP84 Bitcoin wallet taking BTC at 50,000 USD
+func newTestProvider(t *testing.T, gapLimit int) (*Provider, *fakeWalletStore) {
+	store := newFakeWalletStore()
+	rates := cryptorates.NewStaticProvider(map[string]map[string]float64{"USD": {"BTC": 50000, "LTC": 100}})
+	provider := NewProvider(store, rates)
+	require.NoError(t, provider.AddWallet("btc", testZpub, domainPayment.PurposeNativeSegwit, 0, gapLimit))
+	return provider, store
+}
+
+func TestProvider(t *testing.T) {
+	request := domainPayment.GatewayPaymentRequest{OrderID: "order123", PriceAmount: 100, PriceCurrency: "USD", PayCurrency: "BTC"}
+
+	t.Run("each payment gets the next address", func(t *testing.T) {
+		provider, store := newTestProvider(t, 0)
+
+		first, err := provider.CreatePayment(request)
+		second, _ := provider.CreatePayment(request)
+
+		require.NoError(t, err)
+		assert.Equal(t, "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", first.PayAddress)
+		assert.Equal(t, first.PayAddress, first.GatewayPaymentID)
+		assert.Equal(t, 0.002, first.PayAmount)
+		assert.Equal(t, "bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g", second.PayAddress)
+		assert.Equal(t, 2, store.wallets["BTC"].NextIndex)
+		assert.Equal(t, 1, store.addresses[second.PayAddress].Index)
+		assert.Equal(t, "order123", store.addresses[second.PayAddress].OrderID)
+	})
+
+	t.Run("a failed save hands out no address", func(t *testing.T) {
+		provider, store := newTestProvider(t, 0)
+		store.saveErr = errors.New("database unavailable")
+
+		_, err := provider.CreatePayment(request)
+		store.saveErr = nil
+		payment, _ := provider.CreatePayment(request)
+
+		assert.Error(t, err)
+		assert.Equal(t, "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", payment.PayAddress)
+	})
+
+	t.Run("unpaid checkouts past the gap limit alert operators", func(t *testing.T) {
+		provider, store := newTestProvider(t, 2)
+		alerter := &fakeGapLimitAlerter{}
+		provider.SetAlerter(alerter)
+
+		// None of the payments is paid; they all expire
+		var addresses []string
+		for i := 0; i < 3; i++ {
+			payment, err := provider.CreatePayment(request)
+			require.NoError(t, err)
+			addresses = append(addresses, payment.PayAddress)
+		}
+
+		assert.Len(t, store.addresses, 3)
+		assert.NotEqual(t, addresses[1], addresses[2])
+		require.Len(t, alerter.alerts, 1)
+		assert.Equal(t, GapLimitAlert{CryptoSymbol: "BTC", UnusedAddresses: 3, GapLimit: 2, DerivationPath: "m/84'/0'/0'/0/2"}, alerter.alerts[0])
+	})
+
+	t.Run("admin raises the gap limit", func(t *testing.T) {
+		provider, store := newTestProvider(t, 2)
+		alerter := &fakeGapLimitAlerter{}
+		provider.SetAlerter(alerter)
+		_, _ = provider.CreatePayment(request)
+		_, _ = provider.CreatePayment(request)
+
+		err := provider.SetGapLimit("btc", 20)
+		errUnknown := provider.SetGapLimit("LTC", 20)
+		_, _ = provider.CreatePayment(request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domainPayment.ErrHDWalletNotFound, errUnknown)
+		assert.Equal(t, 20, store.wallets["BTC"].GapLimit)
+		assert.Empty(t, alerter.alerts)
+	})
+
+	t.Run("coin without a wallet", func(t *testing.T) {
+		provider, _ := newTestProvider(t, 0)
+
+		_, err := provider.CreatePayment(domainPayment.GatewayPaymentRequest{OrderID: "order123", PriceAmount: 100, PriceCurrency: "USD", PayCurrency: "LTC"})
+
+		assert.Equal(t, domainPayment.ErrUnsupportedCrypto, err)
+	})
+
+	t.Run("registering a wallet again keeps its index", func(t *testing.T) {
+		provider, store := newTestProvider(t, 0)
+		_, _ = provider.CreatePayment(request)
+
+		err := provider.AddWallet("BTC", testZpub, domainPayment.PurposeNativeSegwit, 0, 0)
+		errOther := provider.AddWallet("BTC", testXpub, domainPayment.PurposeLegacy, 0, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domainPayment.ErrExtendedKeyMismatch, errOther)
+		assert.Equal(t, 1, store.wallets["BTC"].NextIndex)
+	})
+
+	t.Run("gateway operations are not supported", func(t *testing.T) {
+		provider, _ := newTestProvider(t, 0)
+
+		_, errStatus := provider.GetPaymentStatus("bc1q")
+		_, errRefund := provider.RefundPayment(domainPayment.GatewayRefundRequest{GatewayPaymentID: "bc1q"})
+		_, errWebhook := provider.VerifyWebhook([]byte(`{}`), "sig")
+
+		assert.Equal(t, ProviderName, provider.Name())
+		assert.Equal(t, domainPayment.ErrProviderNotSupported, errStatus)
+		assert.Equal(t, domainPayment.ErrProviderNotSupported, errRefund)
+		assert.Equal(t, domainPayment.ErrProviderNotSupported, errWebhook)
+	})
+}
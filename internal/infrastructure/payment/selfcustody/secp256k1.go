@@ -0,0 +1,108 @@
+package selfcustody
+
+import "math/big"
+
+// secp256k1 curve parameters (SEC 2, y² = x³ + 7 over the prime field p)
+var (
+	curveP, _  = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F", 16)
+	curveN, _  = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141", 16)
+	curveGx, _ = new(big.Int).SetString("79BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798", 16)
+	curveGy, _ = new(big.Int).SetString("483ADA7726A3C4655DA4FBFC0E1108A8FD17B448A68554199C47D08FFB10D4B8", 16)
+)
+
+// point is an affine point on secp256k1; the zero value (nil coordinates) is the point at infinity
+type point struct {
+	x, y *big.Int
+}
+
+// isInfinity checks if the point is the point at infinity
+func (pt point) isInfinity() bool {
+	return pt.x == nil
+}
+
+// add returns a + b
+func add(a, b point) point {
+	if a.isInfinity() {
+		return b
+	}
+	if b.isInfinity() {
+		return a
+	}
+
+	var slope *big.Int
+	if a.x.Cmp(b.x) == 0 {
+		sum := new(big.Int).Add(a.y, b.y)
+		if sum.Mod(sum, curveP).Sign() == 0 {
+			return point{}
+		}
+		// Tangent: 3x² / 2y
+		numerator := new(big.Int).Mul(a.x, a.x)
+		numerator.Mul(numerator, big.NewInt(3))
+		denominator := new(big.Int).Lsh(a.y, 1)
+		slope = numerator.Mul(numerator, denominator.ModInverse(denominator, curveP))
+	} else {
+		// Chord: (y2 - y1) / (x2 - x1)
+		numerator := new(big.Int).Sub(b.y, a.y)
+		denominator := new(big.Int).Sub(b.x, a.x)
+		denominator.Mod(denominator, curveP)
+		slope = numerator.Mul(numerator, denominator.ModInverse(denominator, curveP))
+	}
+	slope.Mod(slope, curveP)
+
+	x := new(big.Int).Mul(slope, slope)
+	x.Sub(x, a.x).Sub(x, b.x).Mod(x, curveP)
+
+	y := new(big.Int).Sub(a.x, x)
+	y.Mul(y, slope).Sub(y, a.y).Mod(y, curveP)
+
+	return point{x: x, y: y}
+}
+
+// scalarBaseMult returns k·G by double-and-add
+func scalarBaseMult(k *big.Int) point {
+	result := point{}
+	addend := point{x: curveGx, y: curveGy}
+	for i := 0; i < k.BitLen(); i++ {
+		if k.Bit(i) == 1 {
+			result = add(result, addend)
+		}
+		addend = add(addend, addend)
+	}
+	return result
+}
+
+// decompress parses a 33-byte SEC1 compressed public key
+func decompress(key []byte) (point, bool) {
+	if len(key) != 33 || (key[0] != 0x02 && key[0] != 0x03) {
+		return point{}, false
+	}
+
+	x := new(big.Int).SetBytes(key[1:])
+	if x.Cmp(curveP) >= 0 {
+		return point{}, false
+	}
+
+	// y = sqrt(x³ + 7), computed as (x³ + 7)^((p+1)/4) since p ≡ 3 (mod 4)
+	ySquared := new(big.Int).Exp(x, big.NewInt(3), curveP)
+	ySquared.Add(ySquared, big.NewInt(7)).Mod(ySquared, curveP)
+	exponent := new(big.Int).Add(curveP, big.NewInt(1))
+	exponent.Rsh(exponent, 2)
+	y := new(big.Int).Exp(ySquared, exponent, curveP)
+	if new(big.Int).Exp(y, big.NewInt(2), curveP).Cmp(ySquared) != 0 {
+		return point{}, false
+	}
+
+	if y.Bit(0) != uint(key[0]&1) {
+		y.Sub(curveP, y)
+	}
+
+	return point{x: x, y: y}, true
+}
+
+// compress serializes the point as a 33-byte SEC1 compressed public key
+func (pt point) compress() []byte {
+	key := make([]byte, 33)
+	key[0] = 0x02 | byte(pt.y.Bit(0))
+	pt.x.FillBytes(key[1:])
+	return key
+}
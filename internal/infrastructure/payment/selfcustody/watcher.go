@@ -0,0 +1,129 @@
+package selfcustody
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// TransactionObserver receives the transactions a watcher sees; the
+// application's ObserveTransactionUseCase implements it
+type TransactionObserver interface {
+	ObserveTransaction(tx domainPayment.ObservedTransaction) error
+}
+
+// fixtureTransaction is one entry of a fixture feed
+type fixtureTransaction struct {
+	Address         string  `json:"address"`
+	TransactionHash string  `json:"tx_hash"`
+	Amount          float64 `json:"amount"`
+	Confirmations   int     `json:"confirmations"`
+	Dropped         bool    `json:"dropped"`
+}
+
+// FixtureFeed replays recorded transaction updates, e.g. from testdata, in order
+type FixtureFeed struct {
+	transactions []domainPayment.ObservedTransaction
+}
+
+// NewFixtureFeed reads a JSON array of transaction updates
+// ({"address", "tx_hash", "amount", "confirmations", "dropped"})
+func NewFixtureFeed(r io.Reader) (*FixtureFeed, error) {
+	var entries []fixtureTransaction
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	transactions := make([]domainPayment.ObservedTransaction, len(entries))
+	for i, entry := range entries {
+		transactions[i] = domainPayment.ObservedTransaction{
+			Address:         entry.Address,
+			TransactionHash: entry.TransactionHash,
+			Amount:          entry.Amount,
+			Confirmations:   entry.Confirmations,
+			Dropped:         entry.Dropped,
+		}
+	}
+
+	return &FixtureFeed{transactions: transactions}, nil
+}
+
+// Replay delivers every update to the observer. An update the observer
+// rejects does not stop the feed; all errors are returned together.
+func (f *FixtureFeed) Replay(observer TransactionObserver) error {
+	var errs []error
+	for _, tx := range f.transactions {
+		if err := observer.ObserveTransaction(tx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NodeStub stands in for a local node in development: transactions sent to
+// it are reported to the observer at once, and again with one more
+// confirmation for every mined block until they are dropped
+type NodeStub struct {
+	observer TransactionObserver
+	mempool  []*domainPayment.ObservedTransaction
+	mu       sync.Mutex
+}
+
+// NewNodeStub creates a new instance of NodeStub
+func NewNodeStub(observer TransactionObserver) *NodeStub {
+	return &NodeStub{observer: observer}
+}
+
+// Send broadcasts a transaction paying amount to address and returns its hash
+func (n *NodeStub) Send(address string, amount float64) (string, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	tx := &domainPayment.ObservedTransaction{Address: address, TransactionHash: newTransactionHash(), Amount: amount}
+	n.mempool = append(n.mempool, tx)
+	return tx.TransactionHash, n.observer.ObserveTransaction(*tx)
+}
+
+// MineBlocks mines blocks, reporting every transaction with its new confirmation count
+func (n *NodeStub) MineBlocks(blocks int) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var errs []error
+	for i := 0; i < blocks; i++ {
+		for _, tx := range n.mempool {
+			tx.Confirmations++
+			if err := n.observer.ObserveTransaction(*tx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Drop removes a transaction, as after a reorganisation or mempool eviction, and reports it dropped
+func (n *NodeStub) Drop(transactionHash string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for i, tx := range n.mempool {
+		if tx.TransactionHash == transactionHash {
+			n.mempool = append(n.mempool[:i], n.mempool[i+1:]...)
+			tx.Dropped = true
+			return n.observer.ObserveTransaction(*tx)
+		}
+	}
+	return domainPayment.ErrTransactionNotFound
+}
+
+// newTransactionHash returns a random 64-character transaction hash
+func newTransactionHash() string {
+	hash := make([]byte, 32)
+	_, _ = rand.Read(hash)
+	return hex.EncodeToString(hash)
+}
@@ -0,0 +1,88 @@
+package selfcustody
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingObserver keeps every transaction update it receives
+type recordingObserver struct {
+	observed []domainPayment.ObservedTransaction
+	err      error
+}
+
+func (o *recordingObserver) ObserveTransaction(tx domainPayment.ObservedTransaction) error {
+	o.observed = append(o.observed, tx)
+	return o.err
+}
+
+func TestFixtureFeed(t *testing.T) {
+	t.Run("replays updates in order", func(t *testing.T) {
+		file, err := os.Open("testdata/transactions.json")
+		require.NoError(t, err)
+		defer file.Close()
+
+		feed, err := NewFixtureFeed(file)
+		require.NoError(t, err)
+		observer := &recordingObserver{}
+
+		assert.NoError(t, feed.Replay(observer))
+		require.Len(t, observer.observed, 3)
+		assert.Equal(t, "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", observer.observed[0].Address)
+		assert.Equal(t, 1, observer.observed[1].Confirmations)
+		assert.True(t, observer.observed[2].Dropped)
+	})
+
+	t.Run("rejected updates do not stop the feed", func(t *testing.T) {
+		feed, err := NewFixtureFeed(strings.NewReader(`[{"address":"a"},{"address":"b"}]`))
+		require.NoError(t, err)
+		observer := &recordingObserver{err: domainPayment.ErrUnknownDepositAddress}
+
+		err = feed.Replay(observer)
+
+		assert.True(t, errors.Is(err, domainPayment.ErrUnknownDepositAddress))
+		assert.Len(t, observer.observed, 2)
+	})
+
+	t.Run("malformed feed", func(t *testing.T) {
+		_, err := NewFixtureFeed(strings.NewReader(`{"address":`))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestNodeStub(t *testing.T) {
+	t.Run("reports sent transactions and every new block", func(t *testing.T) {
+		observer := &recordingObserver{}
+		node := NewNodeStub(observer)
+
+		hash, err := node.Send("bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", 0.002)
+		require.NoError(t, err)
+		require.NoError(t, node.MineBlocks(2))
+
+		require.Len(t, observer.observed, 3)
+		assert.Len(t, hash, 64)
+		assert.Equal(t, hash, observer.observed[2].TransactionHash)
+		assert.Equal(t, []int{0, 1, 2}, []int{observer.observed[0].Confirmations, observer.observed[1].Confirmations, observer.observed[2].Confirmations})
+		assert.Equal(t, 0.002, observer.observed[2].Amount)
+	})
+
+	t.Run("dropped transactions are reported and no longer mined", func(t *testing.T) {
+		observer := &recordingObserver{}
+		node := NewNodeStub(observer)
+		hash, _ := node.Send("bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", 0.002)
+
+		require.NoError(t, node.Drop(hash))
+		require.NoError(t, node.MineBlocks(1))
+
+		require.Len(t, observer.observed, 2)
+		assert.True(t, observer.observed[1].Dropped)
+		assert.Equal(t, domainPayment.ErrTransactionNotFound, node.Drop(hash))
+	})
+}
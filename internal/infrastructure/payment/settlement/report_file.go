@@ -0,0 +1,93 @@
+package settlement
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+)
+
+// FileReportStore writes settlement reports as CSV files into a directory
+type FileReportStore struct {
+	dir string
+}
+
+// NewFileReportStore creates a new instance of FileReportStore
+func NewFileReportStore(dir string) *FileReportStore {
+	return &FileReportStore{dir: dir}
+}
+
+// Save writes the report to settlement_<from>_<to>.csv, replacing an earlier
+// run for the same period, and returns its path
+func (s *FileReportStore) Save(report *domainPayment.SettlementReport) (string, error) {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("settlement_%s_%s.csv", report.From.Format("2006-01-02"), report.To.AddDate(0, 0, -1).Format("2006-01-02"))
+	path := filepath.Join(s.dir, name)
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if err := WriteCSV(file, report); err != nil {
+		return "", err
+	}
+
+	return path, file.Close()
+}
+
+// WriteCSV writes one row per reconciled payment, followed by the summary
+func WriteCSV(w io.Writer, report *domainPayment.SettlementReport) error {
+	writer := csv.NewWriter(w)
+
+	rows := [][]string{{"result", "payment_id", "gateway_payment_id", "local_status", "gateway_status", "local_amount", "local_currency", "gateway_amount", "gateway_currency", "crypto_currency", "gateway_fee", "network_fee"}}
+	for _, line := range report.Lines {
+		rows = append(rows, []string{
+			string(line.Result),
+			line.PaymentID,
+			line.GatewayPaymentID,
+			string(line.LocalStatus),
+			string(line.GatewayStatus),
+			formatAmount(line.LocalAmount),
+			line.LocalCurrency,
+			formatAmount(line.GatewayAmount),
+			line.GatewayCurrency,
+			line.CryptoCurrency,
+			formatAmount(line.GatewayFee),
+			formatAmount(line.NetworkFee),
+		})
+	}
+
+	summary := report.Summary
+	rows = append(rows,
+		[]string{},
+		[]string{"total", strconv.Itoa(summary.Total)},
+		[]string{"matched", strconv.Itoa(summary.Matched)},
+		[]string{"missing_locally", strconv.Itoa(summary.MissingLocally)},
+		[]string{"missing_remotely", strconv.Itoa(summary.MissingRemotely)},
+		[]string{"amount_mismatches", strconv.Itoa(summary.AmountMismatches)},
+		[]string{"status_mismatches", strconv.Itoa(summary.StatusMismatches)},
+	)
+
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+
+	return writer.Error()
+}
+
+// formatAmount leaves amounts of missing records and unreported fees empty
+func formatAmount(amount float64) string {
+	if amount == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
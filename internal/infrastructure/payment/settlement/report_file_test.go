@@ -0,0 +1,38 @@
+package settlement
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domainPayment "github.com/dudedani/Go_NowPayment.io_PaymentSystem/internal/domain/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileReportStore(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	report, _ := domainPayment.ReconcileSettlement(from, from.AddDate(0, 0, 1), nil, []domainPayment.GatewayPaymentRecord{
+		{GatewayPaymentID: "5524759814", Status: domainPayment.GatewayStatusFinished, PriceAmount: 100, PriceCurrency: "usd"},
+	})
+
+	dir := filepath.Join(t.TempDir(), "reports")
+	store := NewFileReportStore(dir)
+
+	path, err := store.Save(report)
+
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "settlement_2024-03-01_2024-03-01.csv"), path)
+
+	content, _ := os.ReadFile(path)
+	assert.Equal(t, `result,payment_id,gateway_payment_id,local_status,gateway_status,local_amount,local_currency,gateway_amount,gateway_currency,crypto_currency,gateway_fee,network_fee
+MISSING_LOCALLY,,5524759814,,finished,,,100,USD,,,
+
+total,1
+matched,0
+missing_locally,1
+missing_remotely,0
+amount_mismatches,0
+status_mismatches,0
+`, string(content))
+}
@@ -0,0 +1,285 @@
+package qrcode
+
+// matrix is a QR code being drawn. Function modules (finder, timing and
+// alignment patterns, format and version information) are never masked.
+type matrix struct {
+	version    int
+	size       int
+	modules    [][]bool // [row][column]
+	isFunction [][]bool
+}
+
+// newMatrix creates a blank matrix for the version
+func newMatrix(version int) *matrix {
+	size := 17 + 4*version
+	m := &matrix{version: version, size: size}
+
+	m.modules = make([][]bool, size)
+	m.isFunction = make([][]bool, size)
+	for row := range m.modules {
+		m.modules[row] = make([]bool, size)
+		m.isFunction[row] = make([]bool, size)
+	}
+
+	return m
+}
+
+// setFunction sets a function module at column x, row y
+func (m *matrix) setFunction(x, y int, dark bool) {
+	m.modules[y][x] = dark
+	m.isFunction[y][x] = true
+}
+
+// drawFunctionPatterns draws everything but the data and the format bits,
+// whose area is reserved
+func (m *matrix) drawFunctionPatterns() {
+	for i := 0; i < m.size; i++ {
+		m.setFunction(6, i, i%2 == 0)
+		m.setFunction(i, 6, i%2 == 0)
+	}
+
+	m.drawFinderPattern(3, 3)
+	m.drawFinderPattern(m.size-4, 3)
+	m.drawFinderPattern(3, m.size-4)
+
+	positions := alignmentPositions[m.version]
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			// Skip the three corners holding finder patterns
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			m.drawAlignmentPattern(x, y)
+		}
+	}
+
+	m.drawFormatBits(LevelLow, 0) // Reserves the area; redrawn once the mask is chosen
+	m.drawVersionBits()
+}
+
+// drawFinderPattern draws a finder pattern and its separator around the centre
+func (m *matrix) drawFinderPattern(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= m.size || yy < 0 || yy >= m.size {
+				continue
+			}
+			distance := max(abs(dx), abs(dy))
+			m.setFunction(xx, yy, distance != 2 && distance != 4)
+		}
+	}
+}
+
+// drawAlignmentPattern draws a 5x5 alignment pattern around the centre
+func (m *matrix) drawAlignmentPattern(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			m.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// drawFormatBits draws both copies of the level and mask, BCH(15,5) protected
+func (m *matrix) drawFormatBits(level Level, mask int) {
+	bits := formatInfo(level, mask)
+
+	// Around the top-left finder
+	for i := 0; i <= 5; i++ {
+		m.setFunction(8, i, bit(bits, i))
+	}
+	m.setFunction(8, 7, bit(bits, 6))
+	m.setFunction(8, 8, bit(bits, 7))
+	m.setFunction(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		m.setFunction(14-i, 8, bit(bits, i))
+	}
+
+	// Split between the top-right and bottom-left finders
+	for i := 0; i < 8; i++ {
+		m.setFunction(m.size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		m.setFunction(8, m.size-15+i, bit(bits, i))
+	}
+	m.setFunction(8, m.size-8, true) // Always dark
+}
+
+// drawVersionBits draws both copies of the version, BCH(18,6) protected, from version 7
+func (m *matrix) drawVersionBits() {
+	if m.version < 7 {
+		return
+	}
+
+	bits := versionInfo(m.version)
+
+	for i := 0; i < 18; i++ {
+		a, b := m.size-11+i%3, i/3
+		m.setFunction(a, b, bit(bits, i))
+		m.setFunction(b, a, bit(bits, i))
+	}
+}
+
+// formatInfo returns the 15 format bits: level and mask, their BCH(15,5) code,
+// XORed with 0x5412 so they are never all light
+func formatInfo(level Level, mask int) int {
+	data := level.formatBits()<<3 | mask
+	remainder := data
+	for i := 0; i < 10; i++ {
+		remainder = (remainder << 1) ^ ((remainder >> 9) * 0x537)
+	}
+	return (data<<10 | remainder) ^ 0x5412
+}
+
+// versionInfo returns the 18 version bits: the version and its BCH(18,6) code
+func versionInfo(version int) int {
+	remainder := version
+	for i := 0; i < 12; i++ {
+		remainder = (remainder << 1) ^ ((remainder >> 11) * 0x1F25)
+	}
+	return version<<12 | remainder
+}
+
+// drawCodewords places the codewords in the zigzag order of the standard:
+// two-module columns from the right, alternately upwards and downwards
+func (m *matrix) drawCodewords(codewords []byte) {
+	i := 0
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 { // Skip the vertical timing pattern
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vertical := 0; vertical < m.size; vertical++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vertical
+				if upward {
+					y = m.size - 1 - vertical
+				}
+				if !m.isFunction[y][x] && i < len(codewords)*8 {
+					m.modules[y][x] = codewords[i/8]>>uint(7-i%8)&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask XORs the data modules with a mask pattern; applying it twice undoes it
+func (m *matrix) applyMask(mask int) {
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			if !m.isFunction[y][x] && maskApplies(mask, x, y) {
+				m.modules[y][x] = !m.modules[y][x]
+			}
+		}
+	}
+}
+
+// maskApplies reports whether the mask pattern inverts the module at column x, row y
+func maskApplies(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// finderLikePatterns are 1:1:3:1:1 runs next to four light modules
+var finderLikePatterns = [2][11]bool{
+	{true, false, true, true, true, false, true, false, false, false, false},
+	{false, false, false, false, true, false, true, true, true, false, true},
+}
+
+// penalty scores the matrix with the standard's four rules; lower scans better
+func (m *matrix) penalty() int {
+	total := 0
+	dark := 0
+
+	for i := 0; i < m.size; i++ {
+		total += m.runPenalty(func(j int) bool { return m.modules[i][j] })
+		total += m.runPenalty(func(j int) bool { return m.modules[j][i] })
+	}
+
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			if m.modules[y][x] {
+				dark++
+			}
+			if x+1 < m.size && y+1 < m.size {
+				c := m.modules[y][x]
+				if c == m.modules[y][x+1] && c == m.modules[y+1][x] && c == m.modules[y+1][x+1] {
+					total += 3
+				}
+			}
+		}
+	}
+
+	// Every 5% the dark share strays from 50% costs 10
+	modules := m.size * m.size
+	total += abs(dark*20-modules*10) / modules * 10
+
+	return total
+}
+
+// runPenalty scores one row or column: runs of five or more modules of the same
+// colour, and patterns that look like a finder
+func (m *matrix) runPenalty(at func(int) bool) int {
+	total := 0
+
+	run := 1
+	for j := 1; j <= m.size; j++ {
+		if j < m.size && at(j) == at(j-1) {
+			run++
+			continue
+		}
+		if run >= 5 {
+			total += 3 + run - 5
+		}
+		run = 1
+	}
+
+	for j := 0; j+11 <= m.size; j++ {
+		for _, pattern := range finderLikePatterns {
+			matches := true
+			for k, dark := range pattern {
+				if at(j+k) != dark {
+					matches = false
+					break
+				}
+			}
+			if matches {
+				total += 40
+			}
+		}
+	}
+
+	return total
+}
+
+// bit returns bit i of value
+func bit(value, i int) bool {
+	return (value>>uint(i))&1 != 0
+}
+
+// abs returns the absolute value of x
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
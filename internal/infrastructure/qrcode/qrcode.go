@@ -0,0 +1,193 @@
+// Package qrcode is a small QR code encoder (ISO/IEC 18004) for payment URIs.
+// It encodes in byte mode, versions 1 to 10, which holds up to 271 bytes at
+// level L and 213 at level M, enough for any payment URI we build.
+package qrcode
+
+import "errors"
+
+// ErrContentTooLong is returned when the content does not fit in version 10
+var ErrContentTooLong = errors.New("content is too long for a QR code")
+
+// Level is the error correction level: how much of the code can be damaged
+type Level int
+
+const (
+	LevelLow      Level = iota // Recovers ~7% of codewords
+	LevelMedium                // ~15%
+	LevelQuartile              // ~25%
+	LevelHigh                  // ~30%
+)
+
+// formatBits returns the level's two-bit indicator in the format information
+func (l Level) formatBits() int {
+	return [...]int{1, 0, 3, 2}[l]
+}
+
+// maxVersion is the largest version supported
+const maxVersion = 10
+
+// blockLayout describes how a version's codewords are split into blocks at one level
+type blockLayout struct {
+	ecPerBlock int    // Error correction codewords in each block
+	groups     [2]int // Number of blocks in each group
+	dataSizes  [2]int // Data codewords per block in each group
+}
+
+// blockLayouts lists the layout of each version (index 1-10) and level
+var blockLayouts = [maxVersion + 1][4]blockLayout{
+	1:  {{7, [2]int{1, 0}, [2]int{19, 0}}, {10, [2]int{1, 0}, [2]int{16, 0}}, {13, [2]int{1, 0}, [2]int{13, 0}}, {17, [2]int{1, 0}, [2]int{9, 0}}},
+	2:  {{10, [2]int{1, 0}, [2]int{34, 0}}, {16, [2]int{1, 0}, [2]int{28, 0}}, {22, [2]int{1, 0}, [2]int{22, 0}}, {28, [2]int{1, 0}, [2]int{16, 0}}},
+	3:  {{15, [2]int{1, 0}, [2]int{55, 0}}, {26, [2]int{1, 0}, [2]int{44, 0}}, {18, [2]int{2, 0}, [2]int{17, 0}}, {22, [2]int{2, 0}, [2]int{13, 0}}},
+	4:  {{20, [2]int{1, 0}, [2]int{80, 0}}, {18, [2]int{2, 0}, [2]int{32, 0}}, {26, [2]int{2, 0}, [2]int{24, 0}}, {16, [2]int{4, 0}, [2]int{9, 0}}},
+	5:  {{26, [2]int{1, 0}, [2]int{108, 0}}, {24, [2]int{2, 0}, [2]int{43, 0}}, {18, [2]int{2, 2}, [2]int{15, 16}}, {22, [2]int{2, 2}, [2]int{11, 12}}},
+	6:  {{18, [2]int{2, 0}, [2]int{68, 0}}, {16, [2]int{4, 0}, [2]int{27, 0}}, {24, [2]int{4, 0}, [2]int{19, 0}}, {28, [2]int{4, 0}, [2]int{15, 0}}},
+	7:  {{20, [2]int{2, 0}, [2]int{78, 0}}, {18, [2]int{4, 0}, [2]int{31, 0}}, {18, [2]int{2, 4}, [2]int{14, 15}}, {26, [2]int{4, 1}, [2]int{13, 14}}},
+	8:  {{24, [2]int{2, 0}, [2]int{97, 0}}, {22, [2]int{2, 2}, [2]int{38, 39}}, {22, [2]int{4, 2}, [2]int{18, 19}}, {26, [2]int{4, 2}, [2]int{14, 15}}},
+	9:  {{30, [2]int{2, 0}, [2]int{116, 0}}, {22, [2]int{3, 2}, [2]int{36, 37}}, {20, [2]int{4, 4}, [2]int{16, 17}}, {24, [2]int{4, 4}, [2]int{12, 13}}},
+	10: {{18, [2]int{2, 2}, [2]int{68, 69}}, {26, [2]int{4, 1}, [2]int{43, 44}}, {24, [2]int{6, 2}, [2]int{19, 20}}, {28, [2]int{6, 2}, [2]int{15, 16}}},
+}
+
+// alignmentPositions lists the row/column centres of alignment patterns per version
+var alignmentPositions = [maxVersion + 1][]int{
+	2: {6, 18}, 3: {6, 22}, 4: {6, 26}, 5: {6, 30}, 6: {6, 34},
+	7: {6, 22, 38}, 8: {6, 24, 42}, 9: {6, 26, 46}, 10: {6, 28, 50},
+}
+
+// dataCodewords returns the number of data codewords the layout holds
+func (b blockLayout) dataCodewords() int {
+	return b.groups[0]*b.dataSizes[0] + b.groups[1]*b.dataSizes[1]
+}
+
+// Code is an encoded QR code
+type Code struct {
+	Version int
+	Size    int      // Modules per side, 17 + 4 * Version
+	modules [][]bool // [row][column], true is dark
+}
+
+// IsDark reports whether the module at the row and column is dark
+func (c *Code) IsDark(row, column int) bool {
+	return c.modules[row][column]
+}
+
+// Encode encodes the content in byte mode at the smallest version that fits
+func Encode(content string, level Level) (*Code, error) {
+	data := []byte(content)
+
+	version := 0
+	for v := 1; v <= maxVersion; v++ {
+		if 4+countBits(v)+8*len(data) <= 8*blockLayouts[v][level].dataCodewords() {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrContentTooLong
+	}
+
+	codewords := addErrorCorrection(encodeData(data, version, level), blockLayouts[version][level])
+
+	m := newMatrix(version)
+	m.drawFunctionPatterns()
+	m.drawCodewords(codewords)
+
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		m.applyMask(mask)
+		m.drawFormatBits(level, mask)
+		if penalty := m.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+		m.applyMask(mask) // XOR again to undo
+	}
+	m.applyMask(bestMask)
+	m.drawFormatBits(level, bestMask)
+
+	return &Code{Version: version, Size: m.size, modules: m.modules}, nil
+}
+
+// countBits returns the length of the byte mode character count for the version
+func countBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// encodeData writes the mode, count and content, then pads to the version's capacity
+func encodeData(data []byte, version int, level Level) []byte {
+	var bits bitBuffer
+	bits.append(0x4, 4) // Byte mode
+	bits.append(len(data), countBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+
+	capacity := 8 * blockLayouts[version][level].dataCodewords()
+	terminator := capacity - len(bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bits.append(0, terminator)
+	bits.append(0, (8-len(bits)%8)%8)
+
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	return bits.bytes()
+}
+
+// addErrorCorrection splits the data into blocks, computes each block's error
+// correction codewords and interleaves them as the standard requires
+func addErrorCorrection(data []byte, layout blockLayout) []byte {
+	divisor := reedSolomonDivisor(layout.ecPerBlock)
+
+	var dataBlocks, ecBlocks [][]byte
+	offset := 0
+	for group := 0; group < 2; group++ {
+		for i := 0; i < layout.groups[group]; i++ {
+			block := data[offset : offset+layout.dataSizes[group]]
+			offset += len(block)
+			dataBlocks = append(dataBlocks, block)
+			ecBlocks = append(ecBlocks, reedSolomonRemainder(block, divisor))
+		}
+	}
+
+	result := make([]byte, 0, len(data)+len(ecBlocks)*layout.ecPerBlock)
+	for i := 0; i < layout.dataSizes[1] || i < layout.dataSizes[0]; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < layout.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			result = append(result, block[i])
+		}
+	}
+
+	return result
+}
+
+// bitBuffer is a sequence of bits, most significant first
+type bitBuffer []bool
+
+// append adds the low length bits of value
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, (value>>uint(i))&1 == 1)
+	}
+}
+
+// bytes packs the bits, whose length is a multiple of 8
+func (b bitBuffer) bytes() []byte {
+	result := make([]byte, len(b)/8)
+	for i, bit := range b {
+		if bit {
+			result[i/8] |= 1 << uint(7-i%8)
+		}
+	}
+	return result
+}
@@ -0,0 +1,261 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReedSolomonRemainder(t *testing.T) {
+	// "HELLO WORLD" at 1-M, the worked example of the standard's tutorials
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+
+	ec := reedSolomonRemainder(data, reedSolomonDivisor(10))
+
+	assert.Equal(t, []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}, ec)
+}
+
+func TestFormatAndVersionInfo(t *testing.T) {
+	assert.Equal(t, 0b111011111000100, formatInfo(LevelLow, 0))
+	assert.Equal(t, 0b101010000010010, formatInfo(LevelMedium, 0))
+	assert.Equal(t, 0b011010101011111, formatInfo(LevelQuartile, 0))
+	assert.Equal(t, 0b001011010001001, formatInfo(LevelHigh, 0))
+	assert.Equal(t, 0b000111110010010100, versionInfo(7))
+	assert.Equal(t, 0b001010010011010011, versionInfo(10))
+}
+
+func TestBlockLayouts(t *testing.T) {
+	totals := []int{0, 26, 44, 70, 100, 134, 172, 196, 242, 292, 346}
+
+	for version := 1; version <= maxVersion; version++ {
+		for level, layout := range blockLayouts[version] {
+			blocks := layout.groups[0] + layout.groups[1]
+			assert.Equal(t, totals[version], layout.dataCodewords()+blocks*layout.ecPerBlock, "version %d level %d", version, level)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	t.Run("picks the smallest version", func(t *testing.T) {
+		fits, err := Encode(strings.Repeat("x", 62), LevelMedium) // Version 4-M holds 64 codewords
+		require.NoError(t, err)
+		overflows, err := Encode(strings.Repeat("x", 63), LevelMedium)
+		require.NoError(t, err)
+
+		assert.Equal(t, 4, fits.Version)
+		assert.Equal(t, 33, fits.Size)
+		assert.Equal(t, 5, overflows.Version)
+	})
+
+	t.Run("content round-trips", func(t *testing.T) {
+		contents := []string{
+			"a",
+			"bitcoin:bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh?amount=0.0015&label=Order%20123",
+			"ethereum:0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48/transfer?address=0x742d35Cc6634C0532925a3b844Bc454e4438f44e&uint256=25500000",
+			strings.Repeat("x", 213),
+		}
+
+		capacities := map[Level]int{LevelLow: 271, LevelMedium: 213, LevelQuartile: 151, LevelHigh: 119}
+
+		for _, content := range contents {
+			for level := LevelLow; level <= LevelHigh; level++ {
+				code, err := Encode(content, level)
+				if len(content) > capacities[level] {
+					assert.Equal(t, ErrContentTooLong, err)
+					continue
+				}
+				require.NoError(t, err)
+				assert.Equal(t, content, decode(t, code), "version %d level %d", code.Version, level)
+			}
+		}
+	})
+
+	t.Run("function patterns", func(t *testing.T) {
+		code, _ := Encode("ripple:rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh?amount=30&dt=12345", LevelMedium)
+
+		for _, corner := range [][2]int{{0, 0}, {0, code.Size - 7}, {code.Size - 7, 0}} {
+			for i := 0; i < 7; i++ {
+				assert.True(t, code.IsDark(corner[0], corner[1]+i))
+				assert.True(t, code.IsDark(corner[0]+6, corner[1]+i))
+			}
+			assert.False(t, code.IsDark(corner[0]+1, corner[1]+1))
+			assert.True(t, code.IsDark(corner[0]+3, corner[1]+3))
+		}
+		for i := 8; i < code.Size-8; i++ {
+			assert.Equal(t, i%2 == 0, code.IsDark(6, i))
+			assert.Equal(t, i%2 == 0, code.IsDark(i, 6))
+		}
+		assert.True(t, code.IsDark(code.Size-8, 8))
+	})
+
+	t.Run("too long", func(t *testing.T) {
+		_, err := Encode(strings.Repeat("x", 272), LevelLow)
+
+		assert.Equal(t, ErrContentTooLong, err)
+	})
+}
+
+func TestRender(t *testing.T) {
+	code, _ := Encode("bitcoin:bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", LevelMedium)
+
+	t.Run("png", func(t *testing.T) {
+		data, err := code.PNG(256)
+		require.NoError(t, err)
+
+		img, err := png.Decode(bytes.NewReader(data))
+		require.NoError(t, err)
+
+		scale := 256 / (code.Size + 8)
+		assert.Equal(t, (code.Size+8)*scale, img.Bounds().Dx())
+		r, _, _, _ := img.At(0, 0).RGBA()
+		assert.Equal(t, uint32(0xffff), r, "quiet zone is light")
+		r, _, _, _ = img.At(4*scale, 4*scale).RGBA()
+		assert.Equal(t, uint32(0), r, "finder corner is dark")
+	})
+
+	t.Run("svg", func(t *testing.T) {
+		svg := string(code.SVG(256))
+
+		assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="256" height="256"`))
+		assert.Contains(t, svg, "M4,4h7v1h-7z") // Top row of the top-left finder
+		assert.True(t, strings.HasSuffix(svg, "</svg>"))
+	})
+
+	t.Run("renderer", func(t *testing.T) {
+		renderer := NewRenderer(LevelMedium)
+
+		_, errPNG := renderer.RenderPNG("bitcoin:bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", 200)
+		_, errSVG := renderer.RenderSVG(strings.Repeat("x", 300), 200)
+
+		assert.NoError(t, errPNG)
+		assert.Equal(t, ErrContentTooLong, errSVG)
+	})
+}
+
+// decode reads a code back the way a scanner would once it has located the
+// modules: format bits, unmasking, zigzag order, de-interleaving and byte mode
+func decode(t *testing.T, code *Code) string {
+	t.Helper()
+
+	// Both copies of the format bits must agree
+	first, second := 0, 0
+	for i := 0; i <= 5; i++ {
+		first |= boolBit(code.IsDark(i, 8)) << i
+	}
+	first |= boolBit(code.IsDark(7, 8))<<6 | boolBit(code.IsDark(8, 8))<<7 | boolBit(code.IsDark(8, 7))<<8
+	for i := 9; i < 15; i++ {
+		first |= boolBit(code.IsDark(8, 14-i)) << i
+	}
+	for i := 0; i < 8; i++ {
+		second |= boolBit(code.IsDark(8, code.Size-1-i)) << i
+	}
+	for i := 8; i < 15; i++ {
+		second |= boolBit(code.IsDark(code.Size-15+i, 8)) << i
+	}
+	require.Equal(t, first, second)
+
+	var level Level
+	mask := -1
+	for l := LevelLow; l <= LevelHigh; l++ {
+		for candidate := 0; candidate < 8; candidate++ {
+			if formatInfo(l, candidate) == first {
+				level, mask = l, candidate
+			}
+		}
+	}
+	require.NotEqual(t, -1, mask, "format bits are not a valid codeword")
+
+	// Unmask a copy and read the codewords in placement order
+	unmasked := newMatrix(code.Version)
+	unmasked.drawFunctionPatterns()
+	for row := range unmasked.modules {
+		copy(unmasked.modules[row], code.modules[row])
+	}
+	unmasked.applyMask(mask)
+
+	layout := blockLayouts[code.Version][level]
+	blocks := layout.groups[0] + layout.groups[1]
+	total := layout.dataCodewords() + blocks*layout.ecPerBlock
+
+	var bits bitBuffer
+	for right := unmasked.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vertical := 0; vertical < unmasked.size; vertical++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vertical
+				if upward {
+					y = unmasked.size - 1 - vertical
+				}
+				if !unmasked.isFunction[y][x] && len(bits) < total*8 {
+					bits = append(bits, unmasked.modules[y][x])
+				}
+			}
+		}
+	}
+	codewords := bits.bytes()
+
+	// De-interleave, checking every block's error correction
+	var sizes []int
+	for group := 0; group < 2; group++ {
+		for i := 0; i < layout.groups[group]; i++ {
+			sizes = append(sizes, layout.dataSizes[group])
+		}
+	}
+	dataBlocks := make([][]byte, blocks)
+	ecBlocks := make([][]byte, blocks)
+	next := 0
+	for i := 0; i < layout.dataSizes[0] || i < layout.dataSizes[1]; i++ {
+		for b := range dataBlocks {
+			if i < sizes[b] {
+				dataBlocks[b] = append(dataBlocks[b], codewords[next])
+				next++
+			}
+		}
+	}
+	for i := 0; i < layout.ecPerBlock; i++ {
+		for b := range ecBlocks {
+			ecBlocks[b] = append(ecBlocks[b], codewords[next])
+			next++
+		}
+	}
+	var data []byte
+	for b := range dataBlocks {
+		require.Equal(t, reedSolomonRemainder(dataBlocks[b], reedSolomonDivisor(layout.ecPerBlock)), ecBlocks[b])
+		data = append(data, dataBlocks[b]...)
+	}
+
+	// Byte mode segment
+	require.Equal(t, byte(0x4), data[0]>>4)
+	var stream bitBuffer
+	for _, b := range data {
+		stream.append(int(b), 8)
+	}
+	read := func(offset, length int) int {
+		value := 0
+		for _, set := range stream[offset : offset+length] {
+			value = value<<1 | boolBit(set)
+		}
+		return value
+	}
+	count := read(4, countBits(code.Version))
+	content := make([]byte, count)
+	for i := range content {
+		content[i] = byte(read(4+countBits(code.Version)+8*i, 8))
+	}
+	return string(content)
+}
+
+// boolBit converts a module to a bit
+func boolBit(dark bool) int {
+	if dark {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,102 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+)
+
+// quietZone is the light border the standard requires around the code, in modules
+const quietZone = 4
+
+// PNG renders the code as a black and white PNG of about size pixels per side.
+// Modules are whole pixels, so the image is rounded down to a multiple of the
+// module count and is never smaller than one pixel per module.
+func (c *Code) PNG(size int) ([]byte, error) {
+	modules := c.Size + 2*quietZone
+	scale := max(size/modules, 1)
+
+	palette := color.Palette{color.White, color.Black}
+	img := image.NewPaletted(image.Rect(0, 0, modules*scale, modules*scale), palette)
+
+	for row := 0; row < c.Size; row++ {
+		for column := 0; column < c.Size; column++ {
+			if !c.modules[row][column] {
+				continue
+			}
+			top, left := (row+quietZone)*scale, (column+quietZone)*scale
+			for y := top; y < top+scale; y++ {
+				for x := left; x < left+scale; x++ {
+					img.SetColorIndex(x, y, 1)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG renders the code as an SVG of size pixels per side. Each row's dark runs
+// are drawn as rectangles of one path, in module units scaled by the viewBox.
+func (c *Code) SVG(size int) []byte {
+	modules := c.Size + 2*quietZone
+
+	var path strings.Builder
+	for row := 0; row < c.Size; row++ {
+		for column := 0; column < c.Size; {
+			if !c.modules[row][column] {
+				column++
+				continue
+			}
+			run := 1
+			for column+run < c.Size && c.modules[row][column+run] {
+				run++
+			}
+			fmt.Fprintf(&path, "M%d,%dh%dv1h-%dz", column+quietZone, row+quietZone, run, run)
+			column += run
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, modules, modules)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/>`, modules, modules)
+	fmt.Fprintf(&buf, `<path d="%s" fill="#000"/></svg>`, path.String())
+	return buf.Bytes()
+}
+
+// Renderer renders payment URIs as QR code images
+type Renderer struct {
+	level Level
+}
+
+// NewRenderer creates a new instance of Renderer
+func NewRenderer(level Level) *Renderer {
+	return &Renderer{
+		level: level,
+	}
+}
+
+// RenderPNG encodes the content and renders it as a PNG
+func (r *Renderer) RenderPNG(content string, size int) ([]byte, error) {
+	code, err := Encode(content, r.level)
+	if err != nil {
+		return nil, err
+	}
+	return code.PNG(size)
+}
+
+// RenderSVG encodes the content and renders it as an SVG
+func (r *Renderer) RenderSVG(content string, size int) ([]byte, error) {
+	code, err := Encode(content, r.level)
+	if err != nil {
+		return nil, err
+	}
+	return code.SVG(size), nil
+}